@@ -7,8 +7,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
+	// the runtime image has no zoneinfo; user timezones need the embedded copy
+	_ "time/tzdata"
 	"github.com/joho/godotenv"
 	
 	"learning-platform/internal/app"
@@ -25,14 +29,12 @@ func main() {
 	if port == "" {
 		port = "8081"
 	}
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("missing JWT_SECRET")
-	}
-
-	container := app.NewContainer(jwtSecret)
+	container := app.NewContainer()
 	router := app.SetupRouter(container)
 
+	go container.QuizService.RunExpiryWorker(context.Background(), 5*time.Second)
+	go container.LeaderboardService.RunRebuildWorker(context.Background(), 3)
+
 	log.Printf("Server running on :%s", port)
 	if err := router.Run(":" + port); err != nil {
 		log.Fatal("Failed to start server:", err)
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"go/build"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Образ alpine без tzdata: без встроенной базы часовых поясов
+// time.LoadLocation("Asia/Almaty") в контейнере не работает, а на машине
+// разработчика тесты этого не заметят.
+func TestMainEmbedsTimezoneDatabase(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	require.NoError(t, err)
+	assert.Contains(t, pkg.Imports, "time/tzdata")
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"log"
 	"os"
+	"strings"
 	"learning-platform/internal/db"
 	"learning-platform/internal/handler"
 	"learning-platform/internal/kafka"
@@ -12,16 +13,36 @@ import (
 )
 
 type Container struct {
-	AuthHandler  *handler.AuthHandler
-	UserHandler  *handler.UserHandler
-	TaskHandler  *handler.TaskHandler
-	TopicHandler *handler.TopicHandler
-	Redis        *redis.Client
-	UserService  *service.UserService
-
+	AuthHandler           *handler.AuthHandler
+	OIDCHandler           *handler.OIDCHandler
+	AccessTokenHandler    *handler.PersonalAccessTokenHandler
+	UserHandler           *handler.UserHandler
+	TaskHandler           *handler.TaskHandler
+	TopicHandler          *handler.TopicHandler
+	SchoolClassHandler    *handler.SchoolClassHandler
+	ProgressHandler       *handler.ProgressHandler
+	CourseHandler         *handler.CourseHandler
+	ClassroomHandler      *handler.ClassroomHandler
+	AssignmentHandler     *handler.AssignmentHandler
+	GradebookHandler      *handler.GradebookHandler
+	QuizHandler           *handler.QuizHandler
+	ReviewHandler         *handler.ReviewHandler
+	LeaderboardHandler    *handler.LeaderboardHandler
+	AchievementHandler    *handler.AchievementHandler
+	DailyChallengeHandler *handler.DailyChallengeHandler
+	GradingHandler        *handler.GradingHandler
+	PeerReviewHandler     *handler.PeerReviewHandler
+	CertificateHandler    *handler.CertificateHandler
+	Redis                 *redis.Client
+	Keys                  *service.KeySet
+	TrustedProxies        []string
+	UserService           *service.UserService
+	AccessTokenService    *service.PersonalAccessTokenService
+	QuizService           *service.QuizService
+	LeaderboardService    *service.LeaderboardService
 }
 
-func NewContainer(jwtSecret string) *Container {
+func NewContainer() *Container {
 	dbConn := db.Connect()
 	host := os.Getenv("REDIS_HOST")
 	port := os.Getenv("REDIS_PORT")
@@ -34,6 +55,11 @@ func NewContainer(jwtSecret string) *Container {
 		DB: 0,
 	})
 
+	keys, err := service.LoadKeySetFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load JWT signing keys: %v", err)
+	}
+
 	s3Service, err := service.NewS3Service()
 	if err != nil {
 		log.Fatalf("failed to init S3 service: %v", err)
@@ -44,25 +70,117 @@ func NewContainer(jwtSecret string) *Container {
 	userRepo := repository.NewUserRepository(dbConn)
 	verifyRepo := repository.NewVerificationRepository(dbConn)
 	tokenRepo := repository.NewTokenRepository(dbConn)
+	securityEventRepo := repository.NewSecurityEventRepository(dbConn)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(dbConn)
+	userIdentityRepo := repository.NewUserIdentityRepository(dbConn)
+	accessTokenRepo := repository.NewPersonalAccessTokenRepository(dbConn)
+	emailChangeRepo := repository.NewEmailChangeRepository(dbConn)
 	topicRepo := repository.NewTopicRepository(dbConn)
 	taskRepo := repository.NewTaskRepository(dbConn)
+	schoolClassRepo := repository.NewSchoolClassRepository(dbConn)
+	submissionRepo := repository.NewSubmissionRepository(dbConn)
+	courseRepo := repository.NewCourseRepository(dbConn)
+	classroomRepo := repository.NewClassroomRepository(dbConn)
+	assignmentRepo := repository.NewAssignmentRepository(dbConn)
+	quizRepo := repository.NewQuizRepository(dbConn)
+	reviewRepo := repository.NewReviewRepository(dbConn)
+	achievementRepo := repository.NewAchievementRepository(dbConn)
+	userStatsRepo := repository.NewUserStatsRepository(dbConn)
+	dailyChallengeRepo := repository.NewDailyChallengeRepository(dbConn)
+	peerReviewRepo := repository.NewPeerReviewRepository(dbConn)
+	certificateRepo := repository.NewCertificateRepository(dbConn)
 
-	authService := service.NewAuthService(userRepo, verifyRepo, tokenRepo, emailProducer, jwtSecret)
-	userService := service.NewUserService(userRepo)
-	topicService := service.NewTopicService(topicRepo, rdb)
-	taskService := service.NewTaskService(taskRepo, rdb)
+	authLimiter := service.NewAuthLimiter(rdb)
+	authService := service.NewAuthService(userRepo, verifyRepo, tokenRepo, securityEventRepo, recoveryCodeRepo, authLimiter, emailProducer, keys)
+	oidcService := service.NewOIDCService(service.OIDCProvidersFromEnv(), userIdentityRepo, userRepo, authService, rdb)
+	accessTokenService := service.NewPersonalAccessTokenService(accessTokenRepo, userRepo)
+	userService := service.NewUserService(userRepo, emailChangeRepo, authLimiter, emailProducer)
+	topicService := service.NewTopicService(topicRepo, schoolClassRepo, rdb)
+	taskService := service.NewTaskService(taskRepo, submissionRepo, rdb)
+	reviewService := service.NewReviewService(reviewRepo)
+	taskService.AddListener(reviewService)
+	leaderboardService := service.NewLeaderboardService(submissionRepo, classroomRepo, userRepo, rdb)
+	taskService.AddListener(leaderboardService)
+	gamificationService := service.NewGamificationService(userStatsRepo, submissionRepo, userRepo)
+	taskService.AddListener(gamificationService)
+	// achievements read the streak, so they are evaluated after it is updated
+	achievementService := service.NewAchievementService(achievementRepo, submissionRepo, userStatsRepo, userRepo, emailProducer)
+	taskService.AddListener(achievementService)
+	schoolClassService := service.NewSchoolClassService(schoolClassRepo, rdb)
+	progressService := service.NewProgressService(topicRepo, taskRepo, submissionRepo)
+	courseService := service.NewCourseService(courseRepo, taskRepo, submissionRepo)
+	classroomService := service.NewClassroomService(classroomRepo, userRepo)
+	assignmentService := service.NewAssignmentService(assignmentRepo, classroomRepo, submissionRepo, taskService)
+	gradebookService := service.NewGradebookService(assignmentRepo, classroomRepo, submissionRepo)
+	quizService := service.NewQuizService(quizRepo, classroomRepo, taskService, rdb)
+	dailyChallengeService := service.NewDailyChallengeService(dailyChallengeRepo, taskRepo, schoolClassRepo, userRepo)
+	gradingService := service.NewGradingService(submissionRepo, assignmentRepo, classroomRepo, taskService)
+	peerReviewService := service.NewPeerReviewService(peerReviewRepo, submissionRepo, taskRepo, gradingService)
+	certificateService := service.NewCertificateService(certificateRepo, userRepo, courseService, progressService, s3Service, emailProducer, os.Getenv("PUBLIC_URL"))
+	taskService.AddListener(certificateService)
+	courseService.AddListener(certificateService)
 
-	authHandler := handler.NewAuthHandler(authService)
-	userHandler := handler.NewUserHandler(userService, s3Service)
+	authHandler := handler.NewAuthHandler(authService, keys)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+	accessTokenHandler := handler.NewPersonalAccessTokenHandler(accessTokenService)
+	userHandler := handler.NewUserHandler(userService, classroomService, achievementService, gamificationService, s3Service)
 	topicHandler := handler.NewTopicHandler(topicService)
 	taskHandler := handler.NewTaskHandler(taskService, s3Service)
+	schoolClassHandler := handler.NewSchoolClassHandler(schoolClassService)
+	progressHandler := handler.NewProgressHandler(progressService)
+	courseHandler := handler.NewCourseHandler(courseService)
+	classroomHandler := handler.NewClassroomHandler(classroomService, progressService)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+	gradebookHandler := handler.NewGradebookHandler(gradebookService)
+	quizHandler := handler.NewQuizHandler(quizService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardService)
+	achievementHandler := handler.NewAchievementHandler(achievementService)
+	dailyChallengeHandler := handler.NewDailyChallengeHandler(dailyChallengeService)
+	gradingHandler := handler.NewGradingHandler(gradingService)
+	peerReviewHandler := handler.NewPeerReviewHandler(peerReviewService)
+	certificateHandler := handler.NewCertificateHandler(certificateService)
 
 	return &Container{
-		AuthHandler:  authHandler,
-		UserHandler:  userHandler,
-		TaskHandler:  taskHandler,
-		TopicHandler: topicHandler,
-		Redis:        rdb,
-		UserService:  userService,
+		AuthHandler:           authHandler,
+		OIDCHandler:           oidcHandler,
+		AccessTokenHandler:    accessTokenHandler,
+		UserHandler:           userHandler,
+		TaskHandler:           taskHandler,
+		TopicHandler:          topicHandler,
+		SchoolClassHandler:    schoolClassHandler,
+		ProgressHandler:       progressHandler,
+		CourseHandler:         courseHandler,
+		ClassroomHandler:      classroomHandler,
+		AssignmentHandler:     assignmentHandler,
+		GradebookHandler:      gradebookHandler,
+		QuizHandler:           quizHandler,
+		ReviewHandler:         reviewHandler,
+		LeaderboardHandler:    leaderboardHandler,
+		AchievementHandler:    achievementHandler,
+		DailyChallengeHandler: dailyChallengeHandler,
+		GradingHandler:        gradingHandler,
+		PeerReviewHandler:     peerReviewHandler,
+		CertificateHandler:    certificateHandler,
+		Redis:                 rdb,
+		Keys:                  keys,
+		TrustedProxies:        trustedProxiesFromEnv(),
+		UserService:           userService,
+		AccessTokenService:    accessTokenService,
+		QuizService:           quizService,
+		LeaderboardService:    leaderboardService,
+	}
+}
+
+// trustedProxiesFromEnv reads TRUSTED_PROXIES, a comma separated list of
+// addresses or CIDRs of the reverse proxies in front of the API. Only their
+// X-Forwarded-For headers are believed when telling client addresses.
+func trustedProxiesFromEnv() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
 	}
+	return proxies
 }
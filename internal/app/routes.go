@@ -1,8 +1,8 @@
 package app
 
 import (
+	"log"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -20,6 +20,12 @@ func SetupRouter(c *Container) *gin.Engine {
 
 	router := gin.Default()
 
+	// the per-address login limits rely on ClientIP, which must not take a
+	// forwarded header from anyone but our own proxies
+	if err := router.SetTrustedProxies(c.TrustedProxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	p := ginprometheus.NewPrometheus("learning_platform")
 	p.Use(router)
 
@@ -36,6 +42,8 @@ func SetupRouter(c *Container) *gin.Engine {
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	router.GET("/.well-known/jwks.json", c.AuthHandler.GetJWKS)
+
 	router.GET("/health", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{"status": "OK"})
 	})
@@ -47,14 +55,46 @@ func SetupRouter(c *Container) *gin.Engine {
 		auth.POST("/register", c.AuthHandler.Register)
 		auth.POST("/verify", c.AuthHandler.Verify)
 		auth.POST("/login", c.AuthHandler.Login)
+		auth.POST("/login/2fa", c.AuthHandler.LoginTwoFactor)
 		auth.POST("/refresh", c.AuthHandler.Refresh)
+		auth.POST("/password/forgot", c.AuthHandler.ForgotPassword)
+		auth.POST("/password/reset", c.AuthHandler.ResetPassword)
+		auth.POST("/logout", c.AuthHandler.Logout)
+		auth.GET("/oidc/providers", c.OIDCHandler.GetProviders)
+		auth.GET("/oidc/:provider/authorize", c.OIDCHandler.Authorize)
+		auth.GET("/oidc/:provider/callback", c.OIDCHandler.Callback)
+
+		sessions := auth.Group("", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService), middleware.SessionOnlyMiddleware())
+		{
+			sessions.POST("/logout/all", c.AuthHandler.LogoutAll)
+			sessions.GET("/sessions", c.AuthHandler.GetSessions)
+			sessions.DELETE("/sessions/:id", c.AuthHandler.RevokeSession)
+			sessions.GET("/security-events", c.AuthHandler.GetSecurityEvents)
+			sessions.POST("/2fa/enroll", c.AuthHandler.EnrollTwoFactor)
+			sessions.POST("/2fa/confirm", c.AuthHandler.ConfirmTwoFactor)
+			sessions.POST("/2fa/disable", c.AuthHandler.DisableTwoFactor)
+			sessions.GET("/tokens", c.AccessTokenHandler.GetTokens)
+			sessions.POST("/tokens", c.AccessTokenHandler.CreateToken)
+			sessions.DELETE("/tokens", c.AccessTokenHandler.RevokeAllTokens)
+			sessions.DELETE("/tokens/:id", c.AccessTokenHandler.RevokeToken)
+		}
 	}
 
-	user := api.Group("/user", middleware.AuthMiddleware(os.Getenv("JWT_SECRET")), middleware.BanMiddleware(c.UserService))
+	user := api.Group("/user", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
 	{
 		user.GET("/profile", c.UserHandler.GetProfile)
 		user.PUT("/profile", c.UserHandler.UpdateProfile)
+		user.PUT("/timezone", c.UserHandler.UpdateTimezone)
+		user.GET("/email", c.UserHandler.GetEmailChange)
+		user.POST("/email", middleware.SessionOnlyMiddleware(), c.UserHandler.RequestEmailChange)
+		user.POST("/email/confirm", middleware.SessionOnlyMiddleware(), c.UserHandler.ConfirmEmailChange)
+		user.DELETE("/email", middleware.SessionOnlyMiddleware(), c.UserHandler.CancelEmailChange)
+		user.GET("/streak/calendar", c.UserHandler.GetStreakCalendar)
 		user.GET("/all", c.UserHandler.GetAllUsers)
+		user.GET("/progress", c.ProgressHandler.GetUserProgress)
+		user.GET("/review-queue", c.ReviewHandler.GetQueue)
+		user.GET("/next-task", c.ProgressHandler.GetNextTask)
+		user.GET("/:id/progress", c.ClassroomHandler.GetStudentProgress)
 
 		protectedUser := user.Group("")
 		protectedUser.Use(middleware.RoleMiddleware("Admin"))
@@ -64,10 +104,11 @@ func SetupRouter(c *Container) *gin.Engine {
 		}
 	}
 
-	topic := api.Group("/topics", middleware.AuthMiddleware(os.Getenv("JWT_SECRET")), middleware.BanMiddleware(c.UserService))
+	topic := api.Group("/topics", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
 	{
 		topic.GET("", c.TopicHandler.GetAll)
 		topic.GET("/:id", c.TopicHandler.GetByID)
+		topic.GET("/:id/progress", c.ProgressHandler.GetTopicProgress)
 
 		protectedTopic := topic.Group("")
 		protectedTopic.Use(middleware.RoleMiddleware("Teacher", "Admin"))
@@ -78,14 +119,32 @@ func SetupRouter(c *Container) *gin.Engine {
 		}
 	}
 
-	tasks := api.Group("/tasks", middleware.AuthMiddleware(os.Getenv("JWT_SECRET")), middleware.BanMiddleware(c.UserService))
+	schoolClasses := api.Group("/school-classes", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		schoolClasses.GET("", c.SchoolClassHandler.GetAll)
+
+		protectedSchoolClasses := schoolClasses.Group("")
+		protectedSchoolClasses.Use(middleware.RoleMiddleware("Admin"))
+		{
+			protectedSchoolClasses.POST("", c.SchoolClassHandler.Create)
+			protectedSchoolClasses.PUT("/:id", c.SchoolClassHandler.Update)
+			protectedSchoolClasses.DELETE("/:id", c.SchoolClassHandler.Delete)
+		}
+	}
+
+	tasks := api.Group("/tasks", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
 	{
 		tasks.GET("", c.TaskHandler.GetAllTasks)
 		tasks.GET("/drafts", c.TaskHandler.GetDraftTasks)
+		tasks.GET("/daily", c.DailyChallengeHandler.GetToday)
+		tasks.GET("/daily/leaderboard", c.DailyChallengeHandler.GetLeaderboard)
+		tasks.GET("/daily/history", c.DailyChallengeHandler.GetHistory)
 		tasks.GET("/topic/:topicId", c.TaskHandler.GetTasksByTopic)
 		tasks.GET("/:id", c.TaskHandler.GetTask)
 		tasks.GET("/my/tasks", c.TaskHandler.GetMyTasks)
 		tasks.POST("/:id/submit", c.TaskHandler.SubmitTaskAnswer)
+		tasks.GET("/:id/submissions", c.GradingHandler.GetMySubmissions)
+		tasks.GET("/:id/rubric", c.PeerReviewHandler.GetRubric)
 
 		protectedTasks := tasks.Group("")
 		protectedTasks.Use(middleware.RoleMiddleware("Teacher", "Admin"))
@@ -94,8 +153,142 @@ func SetupRouter(c *Container) *gin.Engine {
 			protectedTasks.POST("", c.TaskHandler.CreateTask)
 			protectedTasks.PUT("/:id", c.TaskHandler.UpdateTask)
 			protectedTasks.DELETE("/:id", c.TaskHandler.DeleteTask)
+			protectedTasks.PUT("/:id/rubric", c.PeerReviewHandler.SaveRubric)
+			protectedTasks.POST("/:id/peer-reviews/assign", c.PeerReviewHandler.AssignReviewers)
+		}
+
+		adminTasks := tasks.Group("")
+		adminTasks.Use(middleware.RoleMiddleware("Admin"))
+		{
+			adminTasks.PUT("/daily", c.DailyChallengeHandler.Set)
 		}
 	}
 
+	courses := api.Group("/courses", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		courses.GET("", c.CourseHandler.GetAll)
+		courses.GET("/my", c.CourseHandler.GetMy)
+		courses.GET("/:id", c.CourseHandler.GetByID)
+		courses.POST("/:id/enroll", c.CourseHandler.Enroll)
+		courses.DELETE("/:id/enroll", c.CourseHandler.Unenroll)
+		courses.GET("/:id/progress", c.CourseHandler.GetProgress)
+		courses.POST("/:id/items/:itemId/complete", c.CourseHandler.CompleteItem)
+
+		protectedCourses := courses.Group("")
+		protectedCourses.Use(middleware.RoleMiddleware("Teacher", "Admin"))
+		{
+			protectedCourses.POST("", c.CourseHandler.Create)
+			protectedCourses.PUT("/:id", c.CourseHandler.Update)
+			protectedCourses.DELETE("/:id", c.CourseHandler.Delete)
+			protectedCourses.POST("/:id/modules", c.CourseHandler.AddModule)
+			protectedCourses.PUT("/:id/modules/:moduleId", c.CourseHandler.UpdateModule)
+			protectedCourses.DELETE("/:id/modules/:moduleId", c.CourseHandler.DeleteModule)
+			protectedCourses.POST("/:id/modules/:moduleId/items", c.CourseHandler.AddItem)
+			protectedCourses.DELETE("/:id/modules/:moduleId/items/:itemId", c.CourseHandler.DeleteItem)
+		}
+	}
+
+	classrooms := api.Group("/classrooms", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		classrooms.GET("", c.ClassroomHandler.GetMy)
+		classrooms.POST("/join", c.ClassroomHandler.Join)
+		classrooms.GET("/:id", c.ClassroomHandler.GetByID)
+		classrooms.GET("/:id/members", c.ClassroomHandler.GetMembers)
+		classrooms.DELETE("/:id/members/:userId", c.ClassroomHandler.RemoveMember)
+		classrooms.GET("/:id/assignments", c.AssignmentHandler.GetByClassroom)
+		classrooms.GET("/:id/quizzes", c.QuizHandler.GetByClassroom)
+
+		protectedClassrooms := classrooms.Group("")
+		protectedClassrooms.Use(middleware.RoleMiddleware("Teacher", "Admin"))
+		{
+			protectedClassrooms.POST("", c.ClassroomHandler.Create)
+			protectedClassrooms.PUT("/:id", c.ClassroomHandler.Update)
+			protectedClassrooms.DELETE("/:id", c.ClassroomHandler.Delete)
+			protectedClassrooms.POST("/:id/join-code", c.ClassroomHandler.RegenerateCode)
+			protectedClassrooms.POST("/:id/teachers", c.ClassroomHandler.AddTeacher)
+			protectedClassrooms.POST("/:id/transfer", c.ClassroomHandler.TransferOwnership)
+			protectedClassrooms.POST("/:id/assignments", c.AssignmentHandler.Create)
+			protectedClassrooms.GET("/:id/gradebook", c.GradebookHandler.Get)
+			protectedClassrooms.GET("/:id/gradebook/export", c.GradebookHandler.Export)
+			protectedClassrooms.PUT("/:id/gradebook/weights", c.GradebookHandler.UpdateWeights)
+			protectedClassrooms.POST("/:id/quizzes", c.QuizHandler.Create)
+		}
+	}
+
+	assignments := api.Group("/assignments", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		assignments.GET("/my", c.AssignmentHandler.GetMy)
+		assignments.GET("/:id", c.AssignmentHandler.GetByID)
+		assignments.POST("/:id/tasks/:taskId/submit", c.AssignmentHandler.Submit)
+
+		protectedAssignments := assignments.Group("")
+		protectedAssignments.Use(middleware.RoleMiddleware("Teacher", "Admin"))
+		{
+			protectedAssignments.PUT("/:id", c.AssignmentHandler.Update)
+			protectedAssignments.DELETE("/:id", c.AssignmentHandler.Delete)
+			protectedAssignments.GET("/:id/report", c.AssignmentHandler.GetReport)
+		}
+	}
+
+	quizzes := api.Group("/quizzes", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		quizzes.GET("/:id", c.QuizHandler.GetByID)
+		quizzes.POST("/:id/start", c.QuizHandler.Start)
+		quizzes.GET("/:id/session", c.QuizHandler.GetMySession)
+		quizzes.PUT("/:id/session/answers/:taskId", c.QuizHandler.SaveAnswer)
+		quizzes.POST("/:id/session/submit", c.QuizHandler.Submit)
+
+		protectedQuizzes := quizzes.Group("")
+		protectedQuizzes.Use(middleware.RoleMiddleware("Teacher", "Admin"))
+		{
+			protectedQuizzes.PUT("/:id", c.QuizHandler.Update)
+			protectedQuizzes.DELETE("/:id", c.QuizHandler.Delete)
+			protectedQuizzes.POST("/:id/release", c.QuizHandler.Release)
+			protectedQuizzes.GET("/:id/results", c.QuizHandler.GetResults)
+			protectedQuizzes.GET("/:id/sessions/:userId", c.QuizHandler.GetStudentSession)
+		}
+	}
+
+	leaderboards := api.Group("/leaderboards", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		leaderboards.GET("/:scope", c.LeaderboardHandler.Get)
+	}
+
+	achievements := api.Group("/achievements", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		achievements.GET("", c.AchievementHandler.GetCatalog)
+
+		protectedAchievements := achievements.Group("")
+		protectedAchievements.Use(middleware.RoleMiddleware("Admin"))
+		{
+			protectedAchievements.POST("", c.AchievementHandler.Create)
+			protectedAchievements.PUT("/:id", c.AchievementHandler.Update)
+			protectedAchievements.DELETE("/:id", c.AchievementHandler.Delete)
+		}
+	}
+
+	grading := api.Group("/grading", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	grading.Use(middleware.RoleMiddleware("Teacher", "Admin"))
+	{
+		grading.GET("/queue", c.GradingHandler.GetQueue)
+		grading.POST("/submissions/:id", c.GradingHandler.Grade)
+		grading.POST("/submissions/:id/override", c.GradingHandler.Override)
+	}
+
+	peerReviews := api.Group("/peer-reviews", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService))
+	{
+		peerReviews.GET("/my", c.PeerReviewHandler.GetMy)
+		peerReviews.GET("/submissions/:id", c.PeerReviewHandler.GetSubmissionReviews)
+		peerReviews.GET("/:id", c.PeerReviewHandler.GetByID)
+		peerReviews.POST("/:id", c.PeerReviewHandler.Submit)
+	}
+
+	// verification is public: anyone holding a certificate can have it checked
+	certificates := api.Group("/certificates")
+	{
+		certificates.GET("/:code/verify", c.CertificateHandler.Verify)
+		certificates.GET("/my", middleware.AuthMiddleware(c.Keys, c.AccessTokenService), middleware.BanMiddleware(c.UserService), c.CertificateHandler.GetMy)
+	}
+
 	return router
 }
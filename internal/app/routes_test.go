@@ -0,0 +1,58 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/handler"
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// unknownUsers не находит ни одного пользователя: каждый вход неудачен
+type unknownUsers struct {
+	repository.IUserRepository
+}
+
+func (unknownUsers) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return nil, nil
+}
+
+func TestSetupRouter_ForwardedForIsNotTrusted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	limiter := service.NewAuthLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	auth := service.NewAuthService(unknownUsers{}, nil, nil, nil, nil, limiter, nil, nil)
+	router := SetupRouter(&Container{AuthHandler: handler.NewAuthHandler(auth, nil)})
+
+	login := func(i int) int {
+		body, _ := json.Marshal(map[string]string{"email": fmt.Sprintf("user%d@example.com", i), "password": "password"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.7:40000"
+		// каждый запрос выдаёт себя за новый адрес
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// разные почты, один адрес: срабатывает только ограничение по адресу
+	for i := 0; i < 50; i++ {
+		require.Equal(t, http.StatusUnauthorized, login(i))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, login(50))
+}
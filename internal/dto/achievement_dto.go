@@ -0,0 +1,15 @@
+package dto
+
+import "learning-platform/internal/models"
+
+type AchievementRequest struct {
+    Code        string                 `json:"code" binding:"required"`
+    Title       string                 `json:"title" binding:"required"`
+    Description string                 `json:"description"`
+    IconURL     *string                `json:"iconUrl"`
+    Rule        models.AchievementRule `json:"rule" binding:"required,oneof=SOLVED_TASKS TOPIC_SOLVED_TASKS STREAK_DAYS"`
+    Threshold   int                    `json:"threshold" binding:"required,min=1"`
+    Difficulty  *models.Difficulty     `json:"difficulty" binding:"omitempty,oneof=EASY MEDIUM HARD EXTREME"`
+    TopicID     *string                `json:"topicId" binding:"omitempty,uuid"`
+    IsActive    *bool                  `json:"isActive"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type AchievementResponse struct {
+    ID          string  `json:"id"`
+    Code        string  `json:"code"`
+    Title       string  `json:"title"`
+    Description string  `json:"description"`
+    IconURL     *string `json:"iconUrl"`
+    Rule        string  `json:"rule"`
+    Threshold   int     `json:"threshold"`
+    Difficulty  *string `json:"difficulty"`
+    TopicID     *string `json:"topicId"`
+    IsActive    bool    `json:"isActive"`
+}
+
+type BadgeResponse struct {
+    AchievementID string    `json:"achievementId"`
+    Code          string    `json:"code"`
+    Title         string    `json:"title"`
+    Description   string    `json:"description"`
+    IconURL       *string   `json:"iconUrl"`
+    UnlockedAt    time.Time `json:"unlockedAt"`
+}
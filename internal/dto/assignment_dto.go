@@ -0,0 +1,28 @@
+package dto
+
+import (
+    "time"
+
+    "learning-platform/internal/models"
+)
+
+type AssignmentRequest struct {
+    Title       string            `json:"title" binding:"required"`
+    Description string            `json:"description"`
+    OpensAt     *time.Time        `json:"opensAt"`
+    DueAt       time.Time         `json:"dueAt" binding:"required"`
+    ClosesAt    *time.Time        `json:"closesAt"`
+    LatePolicy  models.LatePolicy `json:"latePolicy" binding:"omitempty,oneof=ACCEPT PENALTY REJECT"`
+    LatePenalty int               `json:"latePenalty" binding:"min=0,max=100"`
+    Weight      *float64          `json:"weight" binding:"omitempty,min=0"`
+    TaskIDs     []string          `json:"taskIds" binding:"required,min=1,dive,uuid"`
+}
+
+type AssignmentWeight struct {
+    AssignmentID string  `json:"assignmentId" binding:"required,uuid"`
+    Weight       float64 `json:"weight" binding:"min=0"`
+}
+
+type GradebookWeightsRequest struct {
+    Weights []AssignmentWeight `json:"weights" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type AssignmentTaskResponse struct {
+    TaskID     string `json:"taskId"`
+    Position   int    `json:"position"`
+    Title      string `json:"title,omitempty"`
+    Difficulty string `json:"difficulty,omitempty"`
+}
+
+type AssignmentResponse struct {
+    ID          string                   `json:"id"`
+    ClassroomID string                   `json:"classroomId"`
+    Title       string                   `json:"title"`
+    Description string                   `json:"description"`
+    OpensAt     time.Time                `json:"opensAt"`
+    DueAt       time.Time                `json:"dueAt"`
+    ClosesAt    *time.Time               `json:"closesAt,omitempty"`
+    LatePolicy  string                   `json:"latePolicy"`
+    LatePenalty int                      `json:"latePenalty"`
+    Weight      float64                  `json:"weight"`
+    AuthorID    string                   `json:"authorId"`
+    Tasks       []AssignmentTaskResponse `json:"tasks"`
+}
+
+type AssignmentProgressResponse struct {
+    TotalTasks   int     `json:"totalTasks"`
+    Attempted    int     `json:"attempted"`
+    SolvedOnTime int     `json:"solvedOnTime"`
+    SolvedLate   int     `json:"solvedLate"`
+    Score        float64 `json:"score"`
+    Status       string  `json:"status"`
+}
+
+type StudentAssignmentResponse struct {
+    AssignmentResponse
+    Progress AssignmentProgressResponse `json:"progress"`
+}
+
+type AssignmentSubmitResponse struct {
+    Correct bool `json:"correct"`
+    Late    bool `json:"late"`
+    Pending bool `json:"pending"`
+}
+
+type AssignmentStudentReportResponse struct {
+    UserID      string                     `json:"userId"`
+    DisplayName string                     `json:"displayName"`
+    Email       string                     `json:"email"`
+    Progress    AssignmentProgressResponse `json:"progress"`
+}
+
+type AssignmentReportResponse struct {
+    Assignment AssignmentResponse                `json:"assignment"`
+    Students   []AssignmentStudentReportResponse `json:"students"`
+}
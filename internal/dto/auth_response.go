@@ -1,10 +1,21 @@
 package dto
 
+import "time"
+
 type AuthTokensResponse struct {
     AccessToken  string `json:"accessToken"`
     RefreshToken string `json:"refreshToken"`
 }
 
+// LoginResponse carries the tokens, or only a challenge token when
+// twoFactorRequired is set.
+type LoginResponse struct {
+    AccessToken       string `json:"accessToken,omitempty"`
+    RefreshToken      string `json:"refreshToken,omitempty"`
+    TwoFactorRequired bool   `json:"twoFactorRequired"`
+    ChallengeToken    string `json:"challengeToken,omitempty"`
+}
+
 type MeResponse struct {
     ID          string  `json:"id"`
     Email       string  `json:"email"`
@@ -15,4 +26,66 @@ type MeResponse struct {
 
 type RegisterResponse struct {
     Message string `json:"message"`
+}
+
+type PasswordResetResponse struct {
+    Message string `json:"message"`
+}
+
+type SessionResponse struct {
+    ID         string    `json:"id"`
+    DeviceName string    `json:"deviceName"`
+    IPAddress  string    `json:"ipAddress"`
+    UserAgent  string    `json:"userAgent"`
+    LastUsedAt time.Time `json:"lastUsedAt"`
+    ExpiresAt  time.Time `json:"expiresAt"`
+    Current    bool      `json:"current"`
+}
+
+type LogoutResponse struct {
+    Message string `json:"message"`
+}
+
+type SecurityEventResponse struct {
+    ID        string    `json:"id"`
+    Kind      string    `json:"kind"`
+    SessionID *string   `json:"sessionId"`
+    IPAddress string    `json:"ipAddress"`
+    UserAgent string    `json:"userAgent"`
+    CreatedAt time.Time `json:"createdAt"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+    Secret        string   `json:"secret"`
+    OtpauthURI    string   `json:"otpauthUri"`
+    RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorResponse struct {
+    Message string `json:"message"`
+}
+
+type OIDCProvidersResponse struct {
+    Providers []string `json:"providers"`
+}
+
+type OIDCAuthorizeResponse struct {
+    AuthorizationURL string `json:"authorizationUrl"`
+}
+
+type AccessTokenResponse struct {
+    ID          string     `json:"id"`
+    Name        string     `json:"name"`
+    TokenPrefix string     `json:"tokenPrefix"`
+    Scopes      []string   `json:"scopes"`
+    ExpiresAt   time.Time  `json:"expiresAt"`
+    LastUsedAt  *time.Time `json:"lastUsedAt"`
+    CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// CreatedAccessTokenResponse is the only response that contains the token
+// itself.
+type CreatedAccessTokenResponse struct {
+    AccessTokenResponse
+    Token string `json:"token"`
 }
\ No newline at end of file
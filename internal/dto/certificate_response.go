@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type CertificateResponse struct {
+    ID        string    `json:"id"`
+    Code      string    `json:"code"`
+    Kind      string    `json:"kind"`
+    SubjectID string    `json:"subjectId"`
+    Title     string    `json:"title"`
+    PDFURL    string    `json:"pdfUrl"`
+    VerifyURL string    `json:"verifyUrl"`
+    IssuedAt  time.Time `json:"issuedAt"`
+}
+
+// CertificateVerificationResponse is public, so it leaves out the user id
+// and the PDF link.
+type CertificateVerificationResponse struct {
+    Valid         bool      `json:"valid"`
+    Code          string    `json:"code"`
+    Kind          string    `json:"kind"`
+    Title         string    `json:"title"`
+    RecipientName string    `json:"recipientName"`
+    IssuedAt      time.Time `json:"issuedAt"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type ClassroomRequest struct {
+    Name        string  `json:"name" binding:"required"`
+    Description string  `json:"description"`
+    SchoolClass *string `json:"schoolClass"`
+}
+
+type JoinClassroomRequest struct {
+    Code string `json:"code" binding:"required"`
+}
+
+type ClassroomMemberRequest struct {
+    UserID string `json:"userId" binding:"required,uuid"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type ClassroomResponse struct {
+    ID          string    `json:"id"`
+    Name        string    `json:"name"`
+    Description string    `json:"description"`
+    SchoolClass *string   `json:"schoolClass,omitempty"`
+    JoinCode    string    `json:"joinCode,omitempty"`
+    OwnerID     string    `json:"ownerId"`
+    MyRole      string    `json:"myRole,omitempty"`
+    CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ClassroomMemberResponse struct {
+    UserID      string    `json:"userId"`
+    DisplayName string    `json:"displayName"`
+    Email       string    `json:"email"`
+    Role        string    `json:"role"`
+    JoinedAt    time.Time `json:"joinedAt"`
+}
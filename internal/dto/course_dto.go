@@ -0,0 +1,37 @@
+package dto
+
+import (
+    "time"
+
+    "learning-platform/internal/models"
+)
+
+type CreateCourseRequest struct {
+    Title         string              `json:"title" binding:"required"`
+    DescriptionMD string              `json:"descriptionMd"`
+    SchoolClass   *string             `json:"schoolClass"`
+    Status        models.CourseStatus `json:"status" binding:"omitempty,oneof=DRAFT PUBLISHED ARCHIVED"`
+}
+
+type UpdateCourseRequest struct {
+    Title         string              `json:"title" binding:"required"`
+    DescriptionMD string              `json:"descriptionMd"`
+    SchoolClass   *string             `json:"schoolClass"`
+    Status        models.CourseStatus `json:"status" binding:"required,oneof=DRAFT PUBLISHED ARCHIVED"`
+}
+
+type CourseModuleRequest struct {
+    Title            string     `json:"title" binding:"required"`
+    Position         int        `json:"position"`
+    RequiresPrevious bool       `json:"requiresPrevious"`
+    UnlockAt         *time.Time `json:"unlockAt"`
+}
+
+type CreateCourseItemRequest struct {
+    ItemType models.CourseItemType `json:"itemType" binding:"required,oneof=TOPIC TASK READING"`
+    Title    string                `json:"title"`
+    BodyMD   string                `json:"bodyMd"`
+    TopicID  *string               `json:"topicId"`
+    TaskID   *string               `json:"taskId"`
+    Position int                   `json:"position"`
+}
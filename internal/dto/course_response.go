@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+type CourseItemResponse struct {
+    ID       string  `json:"id"`
+    ItemType string  `json:"itemType"`
+    Title    string  `json:"title"`
+    BodyMD   string  `json:"bodyMd,omitempty"`
+    TopicID  *string `json:"topicId,omitempty"`
+    TaskID   *string `json:"taskId,omitempty"`
+    Position int     `json:"position"`
+}
+
+type CourseModuleResponse struct {
+    ID               string               `json:"id"`
+    Title            string               `json:"title"`
+    Position         int                  `json:"position"`
+    RequiresPrevious bool                 `json:"requiresPrevious"`
+    UnlockAt         *time.Time           `json:"unlockAt,omitempty"`
+    Items            []CourseItemResponse `json:"items"`
+}
+
+type CourseResponse struct {
+    ID            string                 `json:"id"`
+    Title         string                 `json:"title"`
+    DescriptionMD string                 `json:"descriptionMd"`
+    Status        string                 `json:"status"`
+    SchoolClass   *string                `json:"schoolClass,omitempty"`
+    AuthorID      string                 `json:"authorId"`
+    Modules       []CourseModuleResponse `json:"modules,omitempty"`
+    CreatedAt     string                 `json:"createdAt"`
+    UpdatedAt     string                 `json:"updatedAt"`
+}
+
+type CourseItemProgressResponse struct {
+    ItemID    string `json:"itemId"`
+    ItemType  string `json:"itemType"`
+    Completed bool   `json:"completed"`
+}
+
+type CourseModuleProgressResponse struct {
+    ModuleID       string                       `json:"moduleId"`
+    Title          string                       `json:"title"`
+    Position       int                          `json:"position"`
+    Unlocked       bool                         `json:"unlocked"`
+    Completed      bool                         `json:"completed"`
+    UnlockAt       *time.Time                   `json:"unlockAt,omitempty"`
+    TotalItems     int                          `json:"totalItems"`
+    CompletedItems int                          `json:"completedItems"`
+    Items          []CourseItemProgressResponse `json:"items"`
+}
+
+type CourseProgressResponse struct {
+    CourseID       string                         `json:"courseId"`
+    TotalItems     int                            `json:"totalItems"`
+    CompletedItems int                            `json:"completedItems"`
+    Completed      bool                           `json:"completed"`
+    Modules        []CourseModuleProgressResponse `json:"modules"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type SetDailyChallengeRequest struct {
+    SchoolClass string `json:"schoolClass" binding:"required" example:"SEVEN"`
+    Date        string `json:"date" example:"2025-03-10"`
+    TaskID      string `json:"taskId" binding:"required,uuid"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type DailyChallengeResponse struct {
+    ID          string       `json:"id"`
+    SchoolClass string       `json:"schoolClass"`
+    Date        string       `json:"date" example:"2025-03-10"`
+    Automatic   bool         `json:"automatic"`
+    Task        TaskResponse `json:"task"`
+}
+
+type DailyChallengeEntryResponse struct {
+    Rank             int       `json:"rank"`
+    UserID           string    `json:"userId"`
+    DisplayName      string    `json:"displayName"`
+    SolvedAt         time.Time `json:"solvedAt"`
+    SolveTimeSeconds int64     `json:"solveTimeSeconds"`
+    Attempts         int       `json:"attempts"`
+}
+
+type DailyChallengeLeaderboardResponse struct {
+    Challenge DailyChallengeResponse        `json:"challenge"`
+    Entries   []DailyChallengeEntryResponse `json:"entries"`
+    Me        *DailyChallengeEntryResponse  `json:"me"`
+}
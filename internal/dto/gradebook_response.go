@@ -0,0 +1,21 @@
+package dto
+
+type GradebookCellResponse struct {
+    AssignmentID string  `json:"assignmentId"`
+    Score        float64 `json:"score"`
+    Status       string  `json:"status"`
+}
+
+type GradebookRowResponse struct {
+    UserID      string                  `json:"userId"`
+    DisplayName string                  `json:"displayName"`
+    Email       string                  `json:"email"`
+    Scores      []GradebookCellResponse `json:"scores"`
+    Weighted    float64                 `json:"weighted"`
+}
+
+type GradebookResponse struct {
+    ClassroomID string                 `json:"classroomId"`
+    Assignments []AssignmentResponse   `json:"assignments"`
+    Students    []GradebookRowResponse `json:"students"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type GradeSubmissionRequest struct {
+    Score    *int   `json:"score" binding:"required,min=0,max=100" example:"80"`
+    Feedback string `json:"feedback"`
+}
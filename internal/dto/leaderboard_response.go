@@ -0,0 +1,15 @@
+package dto
+
+type LeaderboardEntryResponse struct {
+    Rank        int64  `json:"rank"`
+    UserID      string `json:"userId"`
+    DisplayName string `json:"displayName"`
+    Score       int64  `json:"score"`
+}
+
+type LeaderboardResponse struct {
+    Scope   string                     `json:"scope"`
+    ScopeID string                     `json:"scopeId,omitempty"`
+    Entries []LeaderboardEntryResponse `json:"entries"`
+    Me      *LeaderboardEntryResponse  `json:"me"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+type RubricCriterionRequest struct {
+    Title       string `json:"title" binding:"required,max=255" example:"Every step is justified"`
+    Description string `json:"description"`
+    MaxPoints   int    `json:"maxPoints" binding:"required,min=1,max=100" example:"5"`
+}
+
+type SaveRubricRequest struct {
+    PeerReviews *int                     `json:"peerReviews" binding:"required,min=0,max=10" example:"3"`
+    Criteria    []RubricCriterionRequest `json:"criteria" binding:"required,min=1,max=20,dive"`
+}
+
+type PeerReviewScoreRequest struct {
+    CriterionID string `json:"criterionId" binding:"required"`
+    Points      *int   `json:"points" binding:"required,min=0" example:"4"`
+}
+
+type SubmitPeerReviewRequest struct {
+    Scores  []PeerReviewScoreRequest `json:"scores" binding:"required,min=1,dive"`
+    Comment string                   `json:"comment" binding:"max=5000"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type RubricCriterionResponse struct {
+    ID          string `json:"id"`
+    Title       string `json:"title"`
+    Description string `json:"description"`
+    MaxPoints   int    `json:"maxPoints"`
+}
+
+type RubricResponse struct {
+    TaskID      string                    `json:"taskId"`
+    PeerReviews int                       `json:"peerReviews"`
+    MaxPoints   int                       `json:"maxPoints"`
+    Criteria    []RubricCriterionResponse `json:"criteria"`
+}
+
+type AssignPeerReviewsResponse struct {
+    Assigned int `json:"assigned"`
+}
+
+type PeerReviewScoreResponse struct {
+    CriterionID string `json:"criterionId"`
+    Points      int    `json:"points"`
+}
+
+// PeerReviewResponse never names the author of the reviewed answer; the
+// reviewer is only included for teachers.
+type PeerReviewResponse struct {
+    ID           string                    `json:"id"`
+    SubmissionID string                    `json:"submissionId"`
+    TaskID       string                    `json:"taskId,omitempty"`
+    TaskTitle    string                    `json:"taskTitle,omitempty"`
+    ReviewerID   string                    `json:"reviewerId,omitempty"`
+    ReviewerName string                    `json:"reviewerName,omitempty"`
+    Status       string                    `json:"status" enums:"ASSIGNED,SUBMITTED"`
+    Points       int                       `json:"points"`
+    Scores       []PeerReviewScoreResponse `json:"scores"`
+    Comment      string                    `json:"comment"`
+    AssignedAt   time.Time                 `json:"assignedAt"`
+    SubmittedAt  *time.Time                `json:"submittedAt"`
+}
+
+// PeerReviewTaskResponse is what a reviewer needs to score an answer: the
+// task, the anonymous answer and the rubric.
+type PeerReviewTaskResponse struct {
+    PeerReviewResponse
+    TaskBodyMD string         `json:"taskBodyMd"`
+    Answer     string         `json:"answer"`
+    Rubric     RubricResponse `json:"rubric"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+type TopicProgressResponse struct {
+    TopicID   string  `json:"topicId"`
+    Title     string  `json:"title"`
+    ParentID  *string `json:"parentId,omitempty"`
+    Total     int     `json:"total"`
+    Attempted int     `json:"attempted"`
+    Solved    int     `json:"solved"`
+    Mastery   float64 `json:"mastery"`
+}
+
+type RecommendedTaskResponse struct {
+    ID         string `json:"id"`
+    Title      string `json:"title"`
+    Difficulty string `json:"difficulty"`
+    TopicID    string `json:"topicId"`
+    AnswerType string `json:"answerType"`
+}
+
+type TaskRecommendationResponse struct {
+    Task             RecommendedTaskResponse `json:"task"`
+    TopicTitle       string                  `json:"topicTitle"`
+    TargetDifficulty string                  `json:"targetDifficulty"`
+    TopicMastery     float64                 `json:"topicMastery"`
+    Explanation      string                  `json:"explanation"`
+    Reasons          []string                `json:"reasons"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+    "time"
+
+    "learning-platform/internal/models"
+)
+
+type QuizRequest struct {
+    Title            string          `json:"title" binding:"required"`
+    Description      string          `json:"description"`
+    Mode             models.QuizMode `json:"mode" binding:"omitempty,oneof=FIXED RANDOM"`
+    DrawCount        int             `json:"drawCount" binding:"min=0"`
+    TimeLimitSeconds int             `json:"timeLimitSeconds" binding:"required,min=1"`
+    OpensAt          *time.Time      `json:"opensAt"`
+    ClosesAt         *time.Time      `json:"closesAt"`
+    TaskIDs          []string        `json:"taskIds" binding:"required,min=1,dive,uuid"`
+}
+
+type QuizAnswerRequest struct {
+    Answer string `json:"answer"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+type QuizTaskResponse struct {
+    TaskID     string `json:"taskId"`
+    Position   int    `json:"position"`
+    Title      string `json:"title,omitempty"`
+    Difficulty string `json:"difficulty,omitempty"`
+}
+
+type QuizResponse struct {
+    ID               string             `json:"id"`
+    ClassroomID      string             `json:"classroomId"`
+    Title            string             `json:"title"`
+    Description      string             `json:"description"`
+    Mode             string             `json:"mode"`
+    DrawCount        int                `json:"drawCount"`
+    TimeLimitSeconds int                `json:"timeLimitSeconds"`
+    OpensAt          time.Time          `json:"opensAt"`
+    ClosesAt         *time.Time         `json:"closesAt,omitempty"`
+    ResultsReleased  bool               `json:"resultsReleased"`
+    AuthorID         string             `json:"authorId"`
+    Tasks            []QuizTaskResponse `json:"tasks,omitempty"`
+}
+
+type QuizAnswerResponse struct {
+    TaskID           string `json:"taskId"`
+    Position         int    `json:"position"`
+    Title            string `json:"title"`
+    BodyMD           string `json:"bodyMd"`
+    AnswerType       string `json:"answerType"`
+    ImageURL         string `json:"imageUrl,omitempty"`
+    Answer           string `json:"answer"`
+    IsCorrect        *bool  `json:"isCorrect,omitempty"`
+    CorrectAnswer    string `json:"correctAnswer,omitempty"`
+    OfficialSolution string `json:"officialSolution,omitempty"`
+}
+
+type QuizSessionResponse struct {
+    ID               string               `json:"id"`
+    QuizID           string               `json:"quizId"`
+    UserID           string               `json:"userId"`
+    Status           string               `json:"status"`
+    StartedAt        time.Time            `json:"startedAt"`
+    ExpiresAt        time.Time            `json:"expiresAt"`
+    SubmittedAt      *time.Time           `json:"submittedAt,omitempty"`
+    RemainingSeconds int                  `json:"remainingSeconds"`
+    ResultsAvailable bool                 `json:"resultsAvailable"`
+    CorrectCount     *int                 `json:"correctCount,omitempty"`
+    Score            *float64             `json:"score,omitempty"`
+    Answers          []QuizAnswerResponse `json:"answers"`
+}
+
+type QuizResultResponse struct {
+    SessionID    string     `json:"sessionId"`
+    UserID       string     `json:"userId"`
+    DisplayName  string     `json:"displayName"`
+    Email        string     `json:"email"`
+    Status       string     `json:"status"`
+    StartedAt    time.Time  `json:"startedAt"`
+    SubmittedAt  *time.Time `json:"submittedAt,omitempty"`
+    CorrectCount int        `json:"correctCount"`
+    Score        float64    `json:"score"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type ReviewItemResponse struct {
+    TaskID         string    `json:"taskId"`
+    Title          string    `json:"title"`
+    Difficulty     string    `json:"difficulty"`
+    TopicID        string    `json:"topicId"`
+    DueAt          time.Time `json:"dueAt"`
+    LastReviewedAt time.Time `json:"lastReviewedAt"`
+    IntervalDays   int       `json:"intervalDays"`
+    Repetitions    int       `json:"repetitions"`
+    Lapses         int       `json:"lapses"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type CreateSchoolClassRequest struct {
+    Code            string `json:"code" binding:"required,max=64"`
+    Title           string `json:"title" binding:"required"`
+    EducationSystem string `json:"educationSystem"`
+    SortOrder       int    `json:"sortOrder"`
+    IsActive        *bool  `json:"isActive"`
+}
+
+type UpdateSchoolClassRequest struct {
+    Code            string `json:"code" binding:"required,max=64"`
+    Title           string `json:"title" binding:"required"`
+    EducationSystem string `json:"educationSystem"`
+    SortOrder       int    `json:"sortOrder"`
+    IsActive        *bool  `json:"isActive"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type SchoolClassResponse struct {
+    ID              string `json:"id"`
+    Code            string `json:"code"`
+    Title           string `json:"title"`
+    EducationSystem string `json:"educationSystem"`
+    SortOrder       int    `json:"sortOrder"`
+    IsActive        bool   `json:"isActive"`
+}
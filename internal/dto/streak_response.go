@@ -0,0 +1,21 @@
+package dto
+
+type StreakResponse struct {
+    Current        int     `json:"current"`
+    Longest        int     `json:"longest"`
+    Freezes        int     `json:"freezes"`
+    LastActiveDate *string `json:"lastActiveDate" example:"2025-03-10"`
+}
+
+type StreakDayResponse struct {
+    Date   string `json:"date" example:"2025-03-10"`
+    Status string `json:"status" enums:"ACTIVE,FROZEN"`
+}
+
+type StreakCalendarResponse struct {
+    Month    string              `json:"month" example:"2025-03"`
+    Timezone string              `json:"timezone"`
+    Days     []StreakDayResponse `json:"days"`
+    XP       int64               `json:"xp"`
+    Streak   StreakResponse      `json:"streak"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type SubmissionResponse struct {
+    ID           string     `json:"id"`
+    TaskID       string     `json:"taskId"`
+    AssignmentID *string    `json:"assignmentId,omitempty"`
+    Answer       string     `json:"answer"`
+    Status       string     `json:"status" enums:"GRADED,PENDING_REVIEW"`
+    Correct      *bool      `json:"correct"`
+    Late         bool       `json:"late"`
+    Score        *int       `json:"score"`
+    PeerScore    *int       `json:"peerScore"`
+    Feedback     *string    `json:"feedback"`
+    GradedAt     *time.Time `json:"gradedAt"`
+    CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type GradingQueueItemResponse struct {
+    ID           string    `json:"id"`
+    TaskID       string    `json:"taskId"`
+    TaskTitle    string    `json:"taskTitle"`
+    StudentID    string    `json:"studentId"`
+    StudentName  string    `json:"studentName"`
+    AssignmentID *string   `json:"assignmentId,omitempty"`
+    Answer       string    `json:"answer"`
+    Late         bool      `json:"late"`
+    PeerScore    *int      `json:"peerScore"`
+    CreatedAt    time.Time `json:"createdAt"`
+}
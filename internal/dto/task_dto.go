@@ -3,25 +3,27 @@ package dto
 import "learning-platform/internal/models"
 
 type CreateTaskRequest struct {
-    Title            string            `form:"title" binding:"required"`
-    BodyMD           string            `form:"bodyMd" binding:"required"`
-    Difficulty       models.Difficulty `form:"difficulty" binding:"required"`
-    Status           models.TaskStatus `form:"status" binding:"required"`
-    TopicID          string            `form:"topicId" binding:"required"`
-    OfficialSolution string            `form:"officialSolution"`
-    CorrectAnswer    string            `form:"correctAnswer"`
-    AnswerType       models.AnswerType `form:"answerType" binding:"required"`
+    Title            string             `form:"title" binding:"required"`
+    BodyMD           string             `form:"bodyMd" binding:"required"`
+    Difficulty       models.Difficulty  `form:"difficulty" binding:"required"`
+    Status           models.TaskStatus  `form:"status" binding:"required"`
+    TopicID          string             `form:"topicId" binding:"required"`
+    OfficialSolution string             `form:"officialSolution"`
+    CorrectAnswer    string             `form:"correctAnswer"`
+    AnswerType       models.AnswerType  `form:"answerType" binding:"required"`
+    GradingMode      models.GradingMode `form:"gradingMode" binding:"omitempty,oneof=AUTO MANUAL"`
 }
 
 type UpdateTaskRequest struct {
-    Title            string            `form:"title" binding:"required"`
-    BodyMD           string            `form:"bodyMd" binding:"required"`
-    Difficulty       models.Difficulty `form:"difficulty" binding:"required"`
-    Status           models.TaskStatus `form:"status" binding:"required"`
-    TopicID          string            `form:"topicId" binding:"required"`
-    OfficialSolution string            `form:"officialSolution"`
-    CorrectAnswer    string            `form:"correctAnswer"`
-    AnswerType       models.AnswerType `form:"answerType" binding:"required"`
+    Title            string             `form:"title" binding:"required"`
+    BodyMD           string             `form:"bodyMd" binding:"required"`
+    Difficulty       models.Difficulty  `form:"difficulty" binding:"required"`
+    Status           models.TaskStatus  `form:"status" binding:"required"`
+    TopicID          string             `form:"topicId" binding:"required"`
+    OfficialSolution string             `form:"officialSolution"`
+    CorrectAnswer    string             `form:"correctAnswer"`
+    AnswerType       models.AnswerType  `form:"answerType" binding:"required"`
+    GradingMode      models.GradingMode `form:"gradingMode" binding:"omitempty,oneof=AUTO MANUAL"`
 }
 
 
@@ -30,5 +32,7 @@ type TaskSubmitRequest struct {
 }
 
 type TaskSubmitResponse struct {
-    Correct bool `json:"correct"`
+    SubmissionID string `json:"submissionId"`
+    Correct      bool   `json:"correct"`
+    Status       string `json:"status" enums:"GRADED,PENDING_REVIEW"`
 }
\ No newline at end of file
@@ -9,6 +9,7 @@ type TaskResponse struct {
     TopicID          string  `json:"topicId"`
     AuthorID         string  `json:"authorId"`
     AnswerType       string  `json:"answerType"`
+    GradingMode      string  `json:"gradingMode"`
     ImageURL         string  `json:"imageUrl,omitempty"`
     OfficialSolution string  `json:"officialSolution,omitempty"`
     CorrectAnswer    string  `json:"correctAnswer,omitempty"`
@@ -1,10 +1,11 @@
 package dto
 
 type CreateTopicRequest struct {
-    Title       string  `json:"title" binding:"required"`
-    Slug        string  `json:"slug" binding:"required"`
-    ParentID    *string `json:"parentId"`
-    SchoolClass string  `json:"schoolClass" binding:"required"`
+    Title           string   `json:"title" binding:"required"`
+    Slug            string   `json:"slug" binding:"required"`
+    ParentID        *string  `json:"parentId"`
+    SchoolClass     string   `json:"schoolClass" binding:"required"`
+    PrerequisiteIDs []string `json:"prerequisiteIds" binding:"omitempty,dive,uuid"`
 }
 
 type UpdateTopicRequest struct {
@@ -12,4 +13,6 @@ type UpdateTopicRequest struct {
     Slug        string  `json:"slug" binding:"required"`
     ParentID    *string `json:"parentId"`
     SchoolClass string  `json:"schoolClass" binding:"required"`
+    // PrerequisiteIDs replaces the prerequisites when present; omit it to keep them
+    PrerequisiteIDs []string `json:"prerequisiteIds" binding:"omitempty,dive,uuid"`
 }
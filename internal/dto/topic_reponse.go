@@ -1,9 +1,10 @@
 package dto
 
 type TopicResponse struct {
-    ID          string  `json:"id"`
-    Title       string  `json:"title"`
-    Slug        string  `json:"slug"`
-    ParentID    *string `json:"parentId,omitempty"`
-    SchoolClass string  `json:"schoolClass"`
-}
\ No newline at end of file
+    ID              string   `json:"id"`
+    Title           string   `json:"title"`
+    Slug            string   `json:"slug"`
+    ParentID        *string  `json:"parentId,omitempty"`
+    SchoolClass     string   `json:"schoolClass"`
+    PrerequisiteIDs []string `json:"prerequisiteIds"`
+}
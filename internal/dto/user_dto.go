@@ -3,7 +3,6 @@ package dto
 import "time"
 
 type UpdateProfileRequest struct {
-    Email       *string `form:"email" binding:"required,email"`
     DisplayName *string `form:"displayName" binding:"required"`
 }
 
@@ -11,3 +10,15 @@ type BanProfileRequest struct {
     BannedReason *string     `json:"bannedReason,omitempty"`              
     BannedUntil  *time.Time `json:"bannedUntil,omitempty"`
 }
+
+type UpdateTimezoneRequest struct {
+    Timezone string `json:"timezone" binding:"required" example:"Europe/Moscow"`
+}
+
+type RequestEmailChangeRequest struct {
+    NewEmail string `json:"newEmail" binding:"required,email"`
+}
+
+type ConfirmEmailChangeRequest struct {
+    Code string `json:"code" binding:"required,len=6"`
+}
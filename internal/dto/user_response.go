@@ -9,6 +9,15 @@ type UserResponse struct {
 	Role        string  `json:"role"`
 	AvatarURL   *string `json:"avatarUrl"`
 	IsBanned    string  `json:"isBanned"`
+	Timezone    string  `json:"timezone"`
+}
+
+// ProfileResponse is the current user's own profile.
+type ProfileResponse struct {
+	UserResponse
+	XP     int64           `json:"xp"`
+	Streak StreakResponse  `json:"streak"`
+	Badges []BadgeResponse `json:"badges"`
 }
 
 type BanProfileResponse struct {
@@ -18,3 +27,10 @@ type BanProfileResponse struct {
 	BannedUntil  *time.Time `json:"bannedUntil,omitempty"`
 	BannedReason *string    `json:"bannedReason,omitempty"`
 }
+
+// EmailChangeResponse is a requested email change that waits for the code
+// sent to the new address.
+type EmailChangeResponse struct {
+	NewEmail  string    `json:"newEmail"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AchievementHandler struct {
+	achievementService *service.AchievementService
+}
+
+func NewAchievementHandler(achievementService *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+func achievementError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAchievement):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrAchievementCodeTaken):
+		response.Error(c, http.StatusConflict, err.Error())
+	case err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Achievement not found")
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+func achievementFromRequest(req *dto.AchievementRequest) *models.Achievement {
+	a := &models.Achievement{
+		Code:        req.Code,
+		Title:       req.Title,
+		Description: req.Description,
+		IconURL:     req.IconURL,
+		Rule:        req.Rule,
+		Threshold:   req.Threshold,
+		Difficulty:  req.Difficulty,
+		TopicID:     req.TopicID,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		a.IsActive = *req.IsActive
+	}
+	return a
+}
+
+// GetCatalog godoc
+// @Summary Get achievements catalog
+// @Tags achievements
+// @Description Returns all achievements that can be earned; admins also see inactive ones
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.AchievementResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /achievements [get]
+func (h *AchievementHandler) GetCatalog(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	achievements, err := h.achievementService.GetCatalog(ctx, c.GetString("role"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch achievements")
+		return
+	}
+
+	response.Success(c, mapper.ToAchievementList(achievements))
+}
+
+// Create godoc
+// @Summary Create achievement
+// @Tags achievements
+// @Description Adds an achievement to the catalog. Rules are evaluated on every correct submission, so users who already qualify get the badge with their next solve
+// @Accept json
+// @Produce json
+// @Param request body dto.AchievementRequest true "Achievement payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.AchievementResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /achievements [post]
+func (h *AchievementHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.AchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	achievement := achievementFromRequest(&req)
+	if err := h.achievementService.CreateAchievement(ctx, achievement); err != nil {
+		achievementError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToAchievementResponse(achievement))
+}
+
+// Update godoc
+// @Summary Update achievement
+// @Tags achievements
+// @Description Updates an achievement; users who already earned it keep the badge
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param request body dto.AchievementRequest true "Achievement payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AchievementResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /achievements/{id} [put]
+func (h *AchievementHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.AchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.achievementService.UpdateAchievement(ctx, c.Param("id"), achievementFromRequest(&req))
+	if err != nil {
+		achievementError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToAchievementResponse(updated))
+}
+
+// Delete godoc
+// @Summary Delete achievement
+// @Tags achievements
+// @Description Removes the achievement from the catalog together with all earned badges; deactivate it instead to keep them
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /achievements/{id} [delete]
+func (h *AchievementHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.achievementService.DeleteAchievement(ctx, c.Param("id")); err != nil {
+		achievementError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
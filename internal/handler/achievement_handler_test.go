@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeAchievementRepo реализует только методы, нужные хендлерам в тестах
+type fakeAchievementRepo struct {
+	repository.IAchievementRepository
+	achievements map[string]*models.Achievement
+	unlocked     []models.UserAchievement
+}
+
+func (r *fakeAchievementRepo) Create(ctx context.Context, a *models.Achievement) error {
+	for _, existing := range r.achievements {
+		if existing.Code == a.Code {
+			return errors.New(`ERROR: duplicate key value violates unique constraint "achievements_code_key"`)
+		}
+	}
+	a.ID = fmt.Sprintf("a%d", len(r.achievements)+1)
+	r.achievements[a.ID] = a
+	return nil
+}
+
+func (r *fakeAchievementRepo) FindByID(ctx context.Context, id string) (*models.Achievement, error) {
+	a, ok := r.achievements[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (r *fakeAchievementRepo) FindAll(ctx context.Context, activeOnly bool) ([]models.Achievement, error) {
+	var out []models.Achievement
+	for _, a := range r.achievements {
+		if !activeOnly || a.IsActive {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAchievementRepo) Update(ctx context.Context, a *models.Achievement) error {
+	r.achievements[a.ID] = a
+	return nil
+}
+
+func (r *fakeAchievementRepo) FindByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	return r.unlocked, nil
+}
+
+func setupAchievementRouter(role string) (*gin.Engine, *fakeAchievementRepo) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeAchievementRepo{achievements: map[string]*models.Achievement{}}
+	h := NewAchievementHandler(service.NewAchievementService(repo, nil, nil, nil, nil))
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userId", "admin-1")
+		c.Set("role", role)
+		c.Next()
+	})
+	r.GET("/achievements", h.GetCatalog)
+	r.POST("/achievements", h.Create)
+	r.PUT("/achievements/:id", h.Update)
+
+	return r, repo
+}
+
+func TestAchievementHandler_CreateAndCatalog(t *testing.T) {
+	router, repo := setupAchievementRouter("Admin")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/achievements", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"code":"first-extreme","title":"Fearless","rule":"SOLVED_TASKS","threshold":1,"difficulty":"EXTREME"}`)
+	require.Equal(t, 201, w.Code)
+	assert.Contains(t, w.Body.String(), `"difficulty":"EXTREME"`)
+	assert.Contains(t, w.Body.String(), `"isActive":true`)
+
+	assert.Equal(t, 409, post(`{"code":"first-extreme","title":"Again","rule":"SOLVED_TASKS","threshold":1}`).Code)
+	assert.Equal(t, 400, post(`{"code":"streak","title":"Streak","rule":"STREAK_DAYS","threshold":7,"difficulty":"EASY"}`).Code)
+	assert.Equal(t, 400, post(`{"code":"Bad Code","title":"X","rule":"SOLVED_TASKS","threshold":1}`).Code)
+	assert.Equal(t, 400, post(`{"code":"x","title":"X","rule":"UNKNOWN","threshold":1}`).Code)
+
+	w = post(`{"code":"hidden","title":"Hidden","rule":"STREAK_DAYS","threshold":3,"isActive":false}`)
+	require.Equal(t, 201, w.Code)
+	assert.Len(t, repo.achievements, 2)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/achievements", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"hidden"`)
+
+	// студенты видят только активные достижения
+	studentRouter, studentRepo := setupAchievementRouter("Student")
+	studentRepo.achievements = repo.achievements
+
+	w = httptest.NewRecorder()
+	studentRouter.ServeHTTP(w, httptest.NewRequest("GET", "/achievements", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"first-extreme"`)
+	assert.NotContains(t, w.Body.String(), `"code":"hidden"`)
+}
+
+func TestAchievementHandler_Update(t *testing.T) {
+	router, repo := setupAchievementRouter("Admin")
+	repo.achievements["a1"] = &models.Achievement{ID: "a1", Code: "topic-10", Title: "Explorer", Rule: models.AchievementRuleTopicSolvedTasks, Threshold: 10, IsActive: true}
+
+	put := func(id, body string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("PUT", "/achievements/"+id, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, 200, put("a1", `{"code":"topic-5","title":"Explorer","rule":"TOPIC_SOLVED_TASKS","threshold":5,"isActive":false}`))
+	assert.Equal(t, 5, repo.achievements["a1"].Threshold)
+	assert.False(t, repo.achievements["a1"].IsActive)
+
+	assert.Equal(t, 404, put("missing", `{"code":"x","title":"X","rule":"SOLVED_TASKS","threshold":1}`))
+}
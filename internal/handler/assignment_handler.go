@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssignmentHandler struct {
+	assignmentService *service.AssignmentService
+}
+
+func NewAssignmentHandler(assignmentService *service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{assignmentService: assignmentService}
+}
+
+func assignmentError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrAssignmentForbidden),
+		errors.Is(err, service.ErrAssignmentNotOpen),
+		errors.Is(err, service.ErrAssignmentClosed):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrAssignmentTaskNotFound),
+		err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Not found")
+	case errors.Is(err, service.ErrInvalidAssignment):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+func assignmentFromRequest(req *dto.AssignmentRequest) *models.Assignment {
+	assignment := &models.Assignment{
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		ClosesAt:    req.ClosesAt,
+		LatePolicy:  req.LatePolicy,
+		LatePenalty: req.LatePenalty,
+	}
+	if req.OpensAt != nil {
+		assignment.OpensAt = *req.OpensAt
+	}
+	if req.Weight != nil {
+		assignment.Weight = *req.Weight
+	}
+	return assignment
+}
+
+// Create godoc
+// @Summary Create assignment
+// @Tags assignments
+// @Description Assigns a set of published tasks to the classroom with open/due dates and a late policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.AssignmentRequest true "Assignment payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.AssignmentResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/assignments [post]
+func (h *AssignmentHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.AssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assignment := assignmentFromRequest(&req)
+	assignment.ClassroomID = c.Param("id")
+	if req.Weight == nil {
+		assignment.Weight = 1
+	}
+
+	if err := h.assignmentService.CreateAssignment(ctx, c.GetString("userId"), c.GetString("role"), assignment, req.TaskIDs); err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToAssignmentResponse(assignment))
+}
+
+// GetByClassroom godoc
+// @Summary Get classroom assignments
+// @Tags assignments
+// @Description Returns assignments of the classroom; students only see opened ones
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.AssignmentResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/assignments [get]
+func (h *AssignmentHandler) GetByClassroom(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	assignments, err := h.assignmentService.GetClassroomAssignments(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToAssignmentList(assignments))
+}
+
+// GetMy godoc
+// @Summary Get my assignments
+// @Tags assignments
+// @Description Returns opened assignments from the student's classrooms with progress; filter by status
+// @Produce json
+// @Param status query string false "PENDING, OVERDUE, MISSED, COMPLETED or COMPLETED_LATE"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.StudentAssignmentResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/my [get]
+func (h *AssignmentHandler) GetMy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status := models.AssignmentStatus(c.Query("status"))
+	switch status {
+	case "", models.AssignmentStatusPending, models.AssignmentStatusOverdue, models.AssignmentStatusMissed,
+		models.AssignmentStatusCompleted, models.AssignmentStatusCompletedLate:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	items, err := h.assignmentService.GetStudentAssignments(ctx, c.GetString("userId"), status)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch assignments")
+		return
+	}
+
+	response.Success(c, mapper.ToStudentAssignmentList(items))
+}
+
+// GetByID godoc
+// @Summary Get assignment by ID
+// @Tags assignments
+// @Description Returns assignment with its ordered tasks
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AssignmentResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/{id} [get]
+func (h *AssignmentHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	assignment, err := h.assignmentService.GetAssignment(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToAssignmentResponse(assignment))
+}
+
+// Update godoc
+// @Summary Update assignment
+// @Tags assignments
+// @Description Updates dates, late policy and the task list; classroom teachers only. Weight is managed through the gradebook
+// @Accept json
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Param request body dto.AssignmentRequest true "Assignment payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AssignmentResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/{id} [put]
+func (h *AssignmentHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.AssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assignment := assignmentFromRequest(&req)
+	assignment.ID = c.Param("id")
+
+	if err := h.assignmentService.UpdateAssignment(ctx, c.GetString("userId"), c.GetString("role"), assignment, req.TaskIDs); err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := h.assignmentService.GetAssignment(ctx, c.GetString("userId"), c.GetString("role"), assignment.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to fetch updated assignment")
+		return
+	}
+
+	response.Success(c, mapper.ToAssignmentResponse(updated))
+}
+
+// Delete godoc
+// @Summary Delete assignment
+// @Tags assignments
+// @Description Deletes assignment; submissions are kept as regular practice attempts
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/{id} [delete]
+func (h *AssignmentHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.assignmentService.DeleteAssignment(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id")); err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// Submit godoc
+// @Summary Submit assignment task
+// @Tags assignments
+// @Description Checks the answer and records it against the assignment as on-time or late
+// @Accept json
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Param taskId path string true "Task ID"
+// @Param request body dto.TaskSubmitRequest true "User's answer"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AssignmentSubmitResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/{id}/tasks/{taskId}/submit [post]
+func (h *AssignmentHandler) Submit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.TaskSubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.assignmentService.Submit(ctx, c.Param("id"), c.Param("taskId"), c.GetString("userId"), req.Answer)
+	if err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, dto.AssignmentSubmitResponse{
+		Correct: result.Correct,
+		Late:    result.Late,
+		Pending: result.Pending,
+	})
+}
+
+// GetReport godoc
+// @Summary Get assignment completion report
+// @Tags assignments
+// @Description Returns per-student completion, on-time/late counts and score; classroom teachers only
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AssignmentReportResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /assignments/{id}/report [get]
+func (h *AssignmentHandler) GetReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	report, err := h.assignmentService.GetReport(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		assignmentError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToAssignmentReportResponse(report))
+}
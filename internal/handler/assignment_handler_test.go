@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeAssignmentRepo реализует только методы, нужные хендлеру в тестах
+type fakeAssignmentRepo struct {
+	repository.IAssignmentRepository
+	assignments map[string]*models.Assignment
+}
+
+func (r *fakeAssignmentRepo) FindByID(ctx context.Context, id string) (*models.Assignment, error) {
+	a, ok := r.assignments[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return a, nil
+}
+
+func (r *fakeAssignmentRepo) FindByClassroom(ctx context.Context, classroomID string) ([]models.Assignment, error) {
+	var out []models.Assignment
+	for _, a := range r.assignments {
+		if a.ClassroomID == classroomID {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAssignmentRepo) FindForStudent(ctx context.Context, userID string) ([]models.Assignment, error) {
+	var out []models.Assignment
+	for _, a := range r.assignments {
+		out = append(out, *a)
+	}
+	return out, nil
+}
+
+func setupAssignmentRouter(t *testing.T, userID string) (*gin.Engine, *fakeAssignmentRepo, *fakeClassroomRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	assignments := &fakeAssignmentRepo{assignments: map[string]*models.Assignment{}}
+	classrooms := &fakeClassroomRepo{}
+	tasks := &fakeTaskRepo{tasks: []models.Task{{ID: "t1", CorrectAnswer: "42"}}}
+	submissions := &fakeSubmissionRepo{}
+
+	svc := service.NewAssignmentService(assignments, classrooms, submissions, service.NewTaskService(tasks, submissions, rdb))
+	h := NewAssignmentHandler(svc)
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", "Student")
+		c.Next()
+	}
+	r.GET("/assignments/my", withUser, h.GetMy)
+	r.POST("/assignments/:id/tasks/:taskId/submit", withUser, h.Submit)
+
+	return r, assignments, classrooms
+}
+
+func TestAssignmentHandler_GetMy(t *testing.T) {
+	router, assignments, _ := setupAssignmentRouter(t, "student-1")
+	assignments.assignments["a1"] = &models.Assignment{
+		ID:      "a1",
+		OpensAt: time.Now().Add(-time.Hour),
+		DueAt:   time.Now().Add(time.Hour),
+		Tasks:   []models.AssignmentTask{{TaskID: "t1"}},
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/assignments/my?status=PENDING", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"PENDING"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/assignments/my?status=UNKNOWN", nil))
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestAssignmentHandler_Submit(t *testing.T) {
+	router, assignments, classrooms := setupAssignmentRouter(t, "student-1")
+	assignments.assignments["a1"] = &models.Assignment{
+		ID:          "a1",
+		ClassroomID: "c1",
+		OpensAt:     time.Now().Add(-2 * time.Hour),
+		DueAt:       time.Now().Add(-time.Hour),
+		LatePolicy:  models.LatePolicyReject,
+		Tasks:       []models.AssignmentTask{{TaskID: "t1"}},
+	}
+
+	submit := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/assignments/a1/tasks/t1/submit", bytes.NewBufferString(`{"answer":"42"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, 403, submit(), "not a member of the classroom")
+
+	classrooms.members = []models.ClassroomMember{{ClassroomID: "c1", UserID: "student-1", Role: models.ClassroomRoleStudent}}
+	assert.Equal(t, 403, submit(), "late submissions are rejected")
+
+	assignments.assignments["a1"].LatePolicy = models.LatePolicyAccept
+	assert.Equal(t, 200, submit())
+}
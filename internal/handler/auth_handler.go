@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,21 +14,64 @@ import (
 
 type AuthHandler struct {
 	auth *service.AuthService
+	keys *service.KeySet
 }
 
-func NewAuthHandler(a *service.AuthService) *AuthHandler {
-	return &AuthHandler{auth: a}
+func NewAuthHandler(a *service.AuthService, keys *service.KeySet) *AuthHandler {
+	return &AuthHandler{auth: a, keys: keys}
+}
+
+func clientInfo(c *gin.Context, deviceName string) service.ClientInfo {
+	return service.ClientInfo{
+		DeviceName: deviceName,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+}
+
+// authError answers rate limited requests with 429 and everything else with
+// the given status.
+func authError(c *gin.Context, err error, status int, message string) {
+	var limited *service.RateLimitError
+	if errors.As(err, &limited) {
+		response.TooManyRequests(c, limited.Code, limited.Message, limited.RetryAfter)
+		return
+	}
+	response.Error(c, status, message)
+}
+
+func twoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTwoFactorCode), errors.Is(err, service.ErrTwoFactorNotEnrolled), errors.Is(err, service.ErrTwoFactorNotEnabled):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		response.Error(c, http.StatusForbidden, err.Error())
+	default:
+		authError(c, err, http.StatusInternalServerError, "Failed to update two-factor authentication")
+	}
+}
+
+func toLoginResponse(result *service.LoginResult) dto.LoginResponse {
+	return dto.LoginResponse{
+		AccessToken:       result.AccessToken,
+		RefreshToken:      result.RefreshToken,
+		TwoFactorRequired: result.ChallengeToken != "",
+		ChallengeToken:    result.ChallengeToken,
+	}
 }
 
 // Register godoc
 // @Summary Register a new user
 // @Tags auth
-// @Description Creates a new user and sends email verification code
+// @Description Creates a new user and sends email verification code. Registering an unverified email again resends the code, at most once a minute
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "Register payload"
 // @Success 201 {object} response.SuccessWrapper{data=dto.RegisterResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -39,7 +83,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	if err := h.auth.Register(ctx, req.Email, req.Password, req.DisplayName); err != nil {
-		response.Error(c, http.StatusBadRequest, err.Error())
+		authError(c, err, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -51,12 +95,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // Verify godoc
 // @Summary Verify email
 // @Tags auth
-// @Description Verifies user email via code
+// @Description Verifies user email via code. After 5 wrong codes the code is invalidated and a new one has to be requested
 // @Accept json
 // @Produce json
 // @Param request body dto.VerifyEmailRequest true "Verify email payload"
 // @Success 201 {object} response.SuccessWrapper{data=dto.VerifyResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
 // @Router /auth/verify [post]
 func (h *AuthHandler) Verify(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -69,7 +114,7 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 	}
 
 	if err := h.auth.VerifyEmail(ctx, req.Email, req.Code); err != nil {
-		response.Error(c, 400, err.Error())
+		authError(c, err, 400, err.Error())
 		return
 	}
 
@@ -78,15 +123,77 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 	})
 }
 
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Tags auth
+// @Description Emails a password reset code valid for 15 minutes. Always answers the same way, whether or not the email has an account
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} response.SuccessWrapper{data=dto.PasswordResetResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.auth.ForgotPassword(ctx, req.Email); err != nil {
+		authError(c, err, http.StatusInternalServerError, "Failed to send reset code")
+		return
+	}
+
+	response.Success(c, dto.PasswordResetResponse{
+		Message: "If the email is registered, a reset code has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Tags auth
+// @Description Sets a new password using the emailed reset code and signs the user out of all sessions. After 5 wrong codes the code is invalidated
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset code and new password"
+// @Success 200 {object} response.SuccessWrapper{data=dto.PasswordResetResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.auth.ResetPassword(ctx, req.Email, req.Code, req.NewPassword); err != nil {
+		authError(c, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, dto.PasswordResetResponse{
+		Message: "Password has been reset",
+	})
+}
+
 // Login godoc
 // @Summary Login user
 // @Tags auth
-// @Description Returns access + refresh tokens
+// @Description Returns access + refresh tokens for a new session. deviceName labels the session in the session list; other sessions stay signed in. Repeated wrong passwords lock the email (and the client address) out for a growing period. With two-factor authentication enabled only a challenge token valid for 5 minutes is returned; exchange it at /auth/login/2fa
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Login credentials"
-// @Success 200 {object} response.SuccessWrapper{data=dto.AuthTokensResponse}
+// @Success 200 {object} response.SuccessWrapper{data=dto.LoginResponse}
 // @Failure 401 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -97,22 +204,139 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.auth.Login(ctx, req.Email, req.Password)
+	result, err := h.auth.Login(ctx, req.Email, req.Password, clientInfo(c, req.DeviceName))
 	if err != nil {
-		response.Error(c, http.StatusUnauthorized, err.Error())
+		authError(c, err, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	response.Success(c, dto.AuthTokensResponse{
-		AccessToken:  access,
-		RefreshToken: refresh,
+	response.Success(c, toLoginResponse(result))
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete two-factor login
+// @Tags auth
+// @Description Exchanges the challenge token from /auth/login and a code from the authenticator app (or an unused recovery code) for access + refresh tokens. Wrong codes count towards the login lockout
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginTwoFactorRequest true "Challenge token and code"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LoginResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.auth.LoginTwoFactor(ctx, req.ChallengeToken, req.Code, clientInfo(c, req.DeviceName))
+	if err != nil {
+		authError(c, err, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response.Success(c, toLoginResponse(result))
+}
+
+// EnrollTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Tags auth
+// @Description Creates a TOTP secret and 10 single use recovery codes. Add the otpauth URI to an authenticator app, then confirm with a code. Both are only shown once; enrolling again before confirming replaces them
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=dto.TwoFactorEnrollmentResponse}
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/2fa/enroll [post]
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	enrollment, err := h.auth.EnrollTwoFactor(ctx, c.GetString("userId"))
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, dto.TwoFactorEnrollmentResponse{
+		Secret:        enrollment.Secret,
+		OtpauthURI:    enrollment.URI,
+		RecoveryCodes: enrollment.RecoveryCodes,
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm two-factor enrollment
+// @Tags auth
+// @Description Turns two-factor authentication on with a code from the authenticator app. The current session stays signed in; admins have to sign in again to use admin endpoints
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} response.SuccessWrapper{data=dto.TwoFactorResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Security BearerAuth
+// @Router /auth/2fa/confirm [post]
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.auth.ConfirmTwoFactor(ctx, c.GetString("userId"), req.Code); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, dto.TwoFactorResponse{
+		Message: "Two-factor authentication enabled",
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Tags auth
+// @Description Turns two-factor authentication off with a current code or a recovery code. Not available to admins
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Code from the authenticator app or a recovery code"
+// @Success 200 {object} response.SuccessWrapper{data=dto.TwoFactorResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Security BearerAuth
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.auth.DisableTwoFactor(ctx, c.GetString("userId"), req.Code); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, dto.TwoFactorResponse{
+		Message: "Two-factor authentication disabled",
 	})
 }
 
 // Refresh godoc
 // @Summary Refresh JWT tokens
 // @Tags auth
-// @Description Returns new access + refresh tokens
+// @Description Returns new access + refresh tokens. Each refresh token works once; presenting one that was already used signs its session out and warns the user by email
 // @Accept json
 // @Produce json
 // @Param request body dto.RefreshRequest true "Refresh token payload"
@@ -128,7 +352,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.auth.Refresh(ctx, req.RefreshToken)
+	access, refresh, err := h.auth.Refresh(ctx, req.RefreshToken, clientInfo(c, ""))
 	if err != nil {
 		response.Error(c, http.StatusUnauthorized, err.Error())
 		return
@@ -140,6 +364,123 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	})
 }
 
+// Logout godoc
+// @Summary Logout
+// @Tags auth
+// @Description Ends the session the refresh token belongs to. The access token stays valid until it expires
+// @Accept json
+// @Produce json
+// @Param request body dto.LogoutRequest true "Refresh token of the session"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LogoutResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.auth.Logout(ctx, req.RefreshToken); err != nil {
+		response.Error(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response.Success(c, dto.LogoutResponse{Message: "Logged out"})
+}
+
+// LogoutAll godoc
+// @Summary Logout everywhere
+// @Tags auth
+// @Description Ends every session of the current user, including this one
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=dto.LogoutResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.auth.LogoutAll(ctx, c.GetString("userId")); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to end sessions")
+		return
+	}
+
+	response.Success(c, dto.LogoutResponse{Message: "Logged out of all sessions"})
+}
+
+// GetSessions godoc
+// @Summary Get active sessions
+// @Tags auth
+// @Description Returns the sessions the current user is signed in with, most recently used first. The session of the current access token is marked as current
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.SessionResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sessions, err := h.auth.GetSessions(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	response.Success(c, mapper.ToSessionList(sessions, c.GetString("sessionId")))
+}
+
+// RevokeSession godoc
+// @Summary Revoke session
+// @Tags auth
+// @Description Signs the current user out of one of their sessions
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LogoutResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := h.auth.RevokeSession(ctx, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	response.Success(c, dto.LogoutResponse{Message: "Session revoked"})
+}
+
+// GetSecurityEvents godoc
+// @Summary Get security events
+// @Tags auth
+// @Description Returns the most recent security events of the current user, such as sessions signed out because a refresh token was reused
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.SecurityEventResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/security-events [get]
+func (h *AuthHandler) GetSecurityEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	events, err := h.auth.GetSecurityEvents(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch security events")
+		return
+	}
+
+	response.Success(c, mapper.ToSecurityEventList(events))
+}
+
 // GetMe godoc
 // @Summary Get current user profile
 // @Tags auth
@@ -161,3 +502,11 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 
 	response.Success(c, mapper.ToMeResponse(user))
 }
+
+// GetJWKS serves the public keys for verifying access tokens as a JSON Web
+// Key Set. It is mounted at /.well-known/jwks.json outside the API prefix and
+// is not wrapped in the response envelope, so JWT libraries can read it as is.
+func (h *AuthHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
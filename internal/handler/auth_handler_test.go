@@ -3,13 +3,19 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	miniredis "github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
 	"learning-platform/internal/kafka"
 	"learning-platform/internal/models"
@@ -66,10 +72,32 @@ func (f *fakeUserRepoForHandler) Update(ctx context.Context, id string, updates
 	if avatar, ok := updates["avatarUrl"].(string); ok {
 		u.AvatarURL = &avatar
 	}
+	if tz, ok := updates["timezone"].(string); ok {
+		u.Timezone = tz
+	}
 
 	return nil
 }
 
+func (f *fakeUserRepoForHandler) UseTOTPStep(ctx context.Context, id string, step int64, updates map[string]interface{}) (bool, error) {
+	u, ok := f.users[id]
+	if !ok || u == nil || u.TOTPLastStep >= step {
+		return false, nil
+	}
+	u.TOTPLastStep = step
+	return true, f.Update(ctx, id, updates)
+}
+
+func (f *fakeUserRepoForHandler) FindByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	var out []models.User
+	for _, id := range ids {
+		if u, ok := f.users[id]; ok && u != nil {
+			out = append(out, *u)
+		}
+	}
+	return out, nil
+}
+
 func (f *fakeUserRepoForHandler) GetAll(ctx context.Context) ([]models.User, error) {
 	out := make([]models.User, 0, len(f.users))
 	for _, u := range f.users {
@@ -82,15 +110,27 @@ func (f *fakeUserRepoForHandler) GetAll(ctx context.Context) ([]models.User, err
 
 var _ repository.IUserRepository = (*fakeUserRepoForHandler)(nil)
 
+func newTestKeySet(t *testing.T) *service.KeySet {
+	t.Helper()
+	key, err := service.GenerateSigningKey("test")
+	require.NoError(t, err)
+	keys, err := service.NewKeySet("test-issuer", "test-audience", []*service.SigningKey{key}, "")
+	require.NoError(t, err)
+	return keys
+}
+
 func newTestRouterWithAuthHandler(authSvc *service.AuthService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
-	h := NewAuthHandler(authSvc)
+	h := NewAuthHandler(authSvc, nil)
 
 	r.POST("/auth/register", h.Register)
 	r.POST("/auth/login", h.Login)
+	r.POST("/auth/login/2fa", h.LoginTwoFactor)
 	r.POST("/auth/refresh", h.Refresh)
 	r.POST("/auth/verify", h.Verify)
+	r.POST("/auth/password/forgot", h.ForgotPassword)
+	r.POST("/auth/password/reset", h.ResetPassword)
 	r.GET("/auth/me", func(c *gin.Context) {
 		h.GetMe(c)
 	})
@@ -111,12 +151,12 @@ func TestAuthHandler_GetMe_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	var producer *kafka.EmailProducer = nil
-	authSvc := service.NewAuthService(userRepo, nil, nil, producer, "secret")
+	authSvc := service.NewAuthService(userRepo, nil, nil, nil, nil, nil, producer, newTestKeySet(t))
 
 	router := gin.Default()
 	router.GET("/auth/me", func(c *gin.Context) {
 		c.Set("userId", user.ID.String())
-		NewAuthHandler(authSvc).GetMe(c)
+		NewAuthHandler(authSvc, nil).GetMe(c)
 	})
 
 	w := httptest.NewRecorder()
@@ -156,6 +196,53 @@ func TestAuthHandler_Login_InvalidBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestAuthHandler_Login_TwoFactorChallenge(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	secret := "JBSWY3DPEHPK3PXP"
+	enabledAt := time.Now()
+	userRepo := newFakeUserRepoForHandler()
+	userRepo.Create(context.Background(), &models.User{
+		Email:         "admin@example.com",
+		PasswordHash:  string(hash),
+		Role:          models.UserRoleAdmin,
+		Status:        models.UserStatusActive,
+		TOTPSecret:    &secret,
+		TOTPEnabledAt: &enabledAt,
+	})
+	router := newTestRouterWithAuthHandler(service.NewAuthService(userRepo, nil, nil, nil, nil, nil, nil, newTestKeySet(t)))
+
+	req, err := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(`{"email":"admin@example.com","password":"secret123"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"twoFactorRequired":true`)
+	assert.NotContains(t, w.Body.String(), "accessToken")
+
+	var resp struct {
+		Data struct {
+			ChallengeToken string `json:"challengeToken"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Data.ChallengeToken)
+
+	req, err = http.NewRequest(http.MethodPost, "/auth/login/2fa", bytes.NewBufferString(`{"challengeToken":"`+resp.Data.ChallengeToken+`","code":"abcdef"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid two-factor code")
+}
+
 func TestAuthHandler_Refresh_InvalidBody(t *testing.T) {
 	router := newTestRouterWithAuthHandler(nil)
 
@@ -184,3 +271,202 @@ func TestAuthHandler_Verify_InvalidBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestAuthHandler_ForgotPassword_UnknownEmail(t *testing.T) {
+	authSvc := service.NewAuthService(newFakeUserRepoForHandler(), nil, nil, nil, nil, nil, nil, newTestKeySet(t))
+	router := newTestRouterWithAuthHandler(authSvc)
+
+	req, err := http.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewBufferString(`{"email":"nobody@example.com"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "If the email is registered")
+}
+
+func TestAuthHandler_ForgotPassword_RateLimited(t *testing.T) {
+	mr := miniredis.RunT(t)
+	limiter := service.NewAuthLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	authSvc := service.NewAuthService(newFakeUserRepoForHandler(), nil, nil, nil, nil, limiter, nil, newTestKeySet(t))
+	router := newTestRouterWithAuthHandler(authSvc)
+
+	send := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewBufferString(`{"email":"nobody@example.com"}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send().Code)
+
+	w := send()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"CODE_RESEND_THROTTLED"`)
+	assert.Contains(t, w.Body.String(), `"retryAfterSeconds":60`)
+}
+
+func TestAuthHandler_ResetPassword_InvalidBody(t *testing.T) {
+	router := newTestRouterWithAuthHandler(nil)
+
+	for _, body := range []string{
+		`{}`,
+		`{"email":"user@example.com","code":"123","newPassword":"secret123"}`,
+		`{"email":"user@example.com","code":"123456","newPassword":"short"}`,
+	} {
+		req, err := http.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+// fakeTokenRepoForHandler реализует только методы, нужные хендлеру в тестах
+type fakeTokenRepoForHandler struct {
+	repository.ITokenRepository
+	tokens []models.RefreshToken
+}
+
+func (f *fakeTokenRepoForHandler) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error) {
+	var out []models.RefreshToken
+	for _, t := range f.tokens {
+		if t.UserID == userID && !t.Revoked {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTokenRepoForHandler) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error) {
+	revoked := false
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].SessionID == sessionID && !f.tokens[i].Revoked {
+			f.tokens[i].Revoked = true
+			revoked = true
+		}
+	}
+	return revoked, nil
+}
+
+func TestAuthHandler_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	laptop, phone := uuid.New(), uuid.New()
+	tokens := &fakeTokenRepoForHandler{tokens: []models.RefreshToken{
+		{UserID: userID, SessionID: laptop, DeviceName: "Laptop", IPAddress: "10.0.0.1", LastUsedAt: time.Now()},
+		{UserID: userID, SessionID: phone, DeviceName: "Phone", LastUsedAt: time.Now()},
+		{UserID: uuid.New(), SessionID: uuid.New(), DeviceName: "Someone else"},
+	}}
+	h := NewAuthHandler(service.NewAuthService(newFakeUserRepoForHandler(), nil, tokens, nil, nil, nil, nil, newTestKeySet(t)), nil)
+
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		c.Set("userId", userID.String())
+		c.Set("sessionId", laptop.String())
+		c.Next()
+	})
+	router.GET("/auth/sessions", h.GetSessions)
+	router.DELETE("/auth/sessions/:id", h.RevokeSession)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/sessions", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"id":"`+laptop.String()+`","deviceName":"Laptop","ipAddress":"10.0.0.1"`)
+	assert.Contains(t, body, `"current":true`)
+	assert.Contains(t, body, `"current":false`)
+	assert.NotContains(t, body, "Someone else")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+phone.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+phone.String(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+tokens.tokens[2].SessionID.String(), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAuthHandler_Logout_InvalidBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/auth/logout", NewAuthHandler(nil, nil).Logout)
+
+	req, err := http.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString(`{}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// fakeSecurityEventRepoForHandler реализует только методы, нужные хендлеру в тестах
+type fakeSecurityEventRepoForHandler struct {
+	repository.ISecurityEventRepository
+	events []models.SecurityEvent
+}
+
+func (f *fakeSecurityEventRepoForHandler) FindByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.SecurityEvent, error) {
+	return f.events, nil
+}
+
+func TestAuthHandler_GetSecurityEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID, sessionID := uuid.New(), uuid.New()
+	events := &fakeSecurityEventRepoForHandler{events: []models.SecurityEvent{
+		{ID: uuid.New(), UserID: userID, Kind: models.SecurityEventRefreshTokenReuse, SessionID: &sessionID, IPAddress: "203.0.113.7"},
+	}}
+	h := NewAuthHandler(service.NewAuthService(newFakeUserRepoForHandler(), nil, nil, events, nil, nil, nil, newTestKeySet(t)), nil)
+
+	router := gin.Default()
+	router.GET("/auth/security-events", func(c *gin.Context) {
+		c.Set("userId", userID.String())
+		h.GetSecurityEvents(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/security-events", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"REFRESH_TOKEN_REUSE"`)
+	assert.Contains(t, w.Body.String(), `"sessionId":"`+sessionID.String()+`"`)
+}
+
+func TestAuthHandler_GetJWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", NewAuthHandler(nil, newTestKeySet(t)).GetJWKS)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+
+	// ответ без обёртки success/data, как ожидают JWT-библиотеки
+	var body service.JWKSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Keys, 1)
+	assert.Equal(t, "test", body.Keys[0].Kid)
+	assert.Equal(t, "OKP", body.Keys[0].Kty)
+	assert.Equal(t, "EdDSA", body.Keys[0].Alg)
+	assert.NotEmpty(t, body.Keys[0].X)
+}
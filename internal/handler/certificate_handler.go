@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateHandler struct {
+	certificateService *service.CertificateService
+}
+
+func NewCertificateHandler(certificateService *service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{certificateService: certificateService}
+}
+
+// GetMy godoc
+// @Summary Get my certificates
+// @Tags certificates
+// @Description Returns the certificates issued to the current user for finished courses and mastered topics, newest first
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.CertificateResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/my [get]
+func (h *CertificateHandler) GetMy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	certificates, err := h.certificateService.GetMyCertificates(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch certificates")
+		return
+	}
+
+	result := make([]dto.CertificateResponse, 0, len(certificates))
+	for _, certificate := range certificates {
+		result = append(result, mapper.ToCertificateResponse(&certificate, h.certificateService.VerifyURL(certificate.Code)))
+	}
+
+	response.Success(c, result)
+}
+
+// Verify godoc
+// @Summary Verify certificate
+// @Tags certificates
+// @Description Public check of the verification code printed on a certificate. Returns who it was issued to, for what and when
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CertificateVerificationResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /certificates/{code}/verify [get]
+func (h *CertificateHandler) Verify(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	certificate, err := h.certificateService.Verify(ctx, c.Param("code"))
+	if err != nil {
+		if errors.Is(err, service.ErrCertificateNotFound) {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to verify certificate")
+		return
+	}
+
+	response.Success(c, mapper.ToCertificateVerificationResponse(certificate))
+}
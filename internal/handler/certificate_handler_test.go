@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeCertificateRepo реализует только методы, нужные хендлеру в тестах
+type fakeCertificateRepo struct {
+	repository.ICertificateRepository
+	certificates []models.Certificate
+	err          error
+}
+
+func (r *fakeCertificateRepo) FindByCode(ctx context.Context, code string) (*models.Certificate, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, c := range r.certificates {
+		if c.Code == code {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeCertificateRepo) FindByUser(ctx context.Context, userID string) ([]models.Certificate, error) {
+	var out []models.Certificate
+	for _, c := range r.certificates {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func setupCertificateRouter(userID string) (*gin.Engine, *fakeCertificateRepo) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeCertificateRepo{certificates: []models.Certificate{
+		{ID: "cert-1", Code: "ABCD-EFGH-JKLM-NPQR", UserID: "student-1", Kind: models.CertificateKindCourse,
+			SubjectID: "course-1", Title: "Intro to algebra", RecipientName: "Anna",
+			PDFURL: "https://files.test/certificates/ABCD-EFGH-JKLM-NPQR.pdf", IssuedAt: time.Now()},
+	}}
+	h := NewCertificateHandler(service.NewCertificateService(repo, nil, nil, nil, nil, nil, "https://learn.test"))
+
+	r := gin.Default()
+	r.GET("/certificates/:code/verify", h.Verify)
+	r.GET("/certificates/my", func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Next()
+	}, h.GetMy)
+
+	return r, repo
+}
+
+func TestCertificateHandler_Verify(t *testing.T) {
+	router, repo := setupCertificateRouter("student-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/certificates/abcd-efgh-jklm-npqr/verify", nil))
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"valid":true`)
+	assert.Contains(t, body, `"recipientName":"Anna"`)
+	assert.Contains(t, body, `"title":"Intro to algebra"`)
+	assert.NotContains(t, body, "student-1", "verification is public and does not expose the user")
+	assert.NotContains(t, body, "pdfUrl")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/certificates/NOPE/verify", nil))
+	assert.Equal(t, 404, w.Code)
+
+	repo.err = errors.New("db down")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/certificates/ABCD-EFGH-JKLM-NPQR/verify", nil))
+	assert.Equal(t, 500, w.Code)
+}
+
+func TestCertificateHandler_GetMy(t *testing.T) {
+	router, _ := setupCertificateRouter("student-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/certificates/my", nil))
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"kind":"COURSE"`)
+	assert.Contains(t, body, `"pdfUrl":"https://files.test/certificates/ABCD-EFGH-JKLM-NPQR.pdf"`)
+	assert.Contains(t, body, `"verifyUrl":"https://learn.test/api/v1/certificates/ABCD-EFGH-JKLM-NPQR/verify"`)
+
+	other, _ := setupCertificateRouter("student-2")
+	w = httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest("GET", "/certificates/my", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+}
@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClassroomHandler struct {
+	classroomService *service.ClassroomService
+	progressService  *service.ProgressService
+}
+
+func NewClassroomHandler(classroomService *service.ClassroomService, progressService *service.ProgressService) *ClassroomHandler {
+	return &ClassroomHandler{classroomService: classroomService, progressService: progressService}
+}
+
+func classroomError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrClassroomForbidden):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrClassroomMemberNotFound),
+		err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Not found")
+	case errors.Is(err, service.ErrInvalidJoinCode),
+		errors.Is(err, service.ErrOwnerCannotLeave),
+		errors.Is(err, service.ErrCoTeacherRequired),
+		errors.Is(err, service.ErrCoTeacherMustBeTeacher):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// GetMy godoc
+// @Summary Get my classrooms
+// @Tags classrooms
+// @Description Returns classrooms the current user owns, teaches or studies in
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.ClassroomResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms [get]
+func (h *ClassroomHandler) GetMy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	classrooms, err := h.classroomService.GetMyClassrooms(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch classrooms")
+		return
+	}
+
+	response.Success(c, mapper.ToClassroomList(classrooms))
+}
+
+// GetByID godoc
+// @Summary Get classroom by ID
+// @Tags classrooms
+// @Description Returns classroom details; the join code is visible to teachers only
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.ClassroomResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id} [get]
+func (h *ClassroomHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	classroom, role, err := h.classroomService.GetClassroom(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"))
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := mapper.ToClassroomResponse(classroom)
+	resp.MyRole = string(role)
+
+	response.Success(c, resp)
+}
+
+// Create godoc
+// @Summary Create classroom
+// @Tags classrooms
+// @Description Creates a classroom owned by the current teacher and generates a join code
+// @Accept json
+// @Produce json
+// @Param request body dto.ClassroomRequest true "Classroom payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.ClassroomResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms [post]
+func (h *ClassroomHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ClassroomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	classroom := &models.Classroom{
+		Name:        req.Name,
+		Description: req.Description,
+		SchoolClass: req.SchoolClass,
+		OwnerID:     c.GetString("userId"),
+	}
+
+	if err := h.classroomService.CreateClassroom(ctx, classroom); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create classroom")
+		return
+	}
+
+	resp := mapper.ToClassroomResponse(classroom)
+	resp.MyRole = string(models.ClassroomRoleOwner)
+
+	response.SuccessWithStatus(c, http.StatusCreated, resp)
+}
+
+// Update godoc
+// @Summary Update classroom
+// @Tags classrooms
+// @Description Updates classroom name, description and school class; owner and co-teachers only
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.ClassroomRequest true "Classroom payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.ClassroomResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id} [put]
+func (h *ClassroomHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ClassroomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	classroom := &models.Classroom{
+		ID:          c.Param("id"),
+		Name:        req.Name,
+		Description: req.Description,
+		SchoolClass: req.SchoolClass,
+	}
+
+	if err := h.classroomService.UpdateClassroom(ctx, c.GetString("userId"), c.GetString("role"), classroom); err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToClassroomResponse(classroom))
+}
+
+// Delete godoc
+// @Summary Delete classroom
+// @Tags classrooms
+// @Description Deletes classroom and all memberships; owner only
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id} [delete]
+func (h *ClassroomHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.classroomService.DeleteClassroom(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id")); err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// RegenerateCode godoc
+// @Summary Regenerate join code
+// @Tags classrooms
+// @Description Replaces the join code; the old code stops working immediately
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.ClassroomResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/join-code [post]
+func (h *ClassroomHandler) RegenerateCode(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	classroom, err := h.classroomService.RegenerateJoinCode(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToClassroomResponse(classroom))
+}
+
+// Join godoc
+// @Summary Join classroom
+// @Tags classrooms
+// @Description Joins a classroom as a student using its join code
+// @Accept json
+// @Produce json
+// @Param request body dto.JoinClassroomRequest true "Join code"
+// @Success 200 {object} response.SuccessWrapper{data=dto.ClassroomResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/join [post]
+func (h *ClassroomHandler) Join(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.JoinClassroomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	classroom, err := h.classroomService.Join(ctx, req.Code, c.GetString("userId"))
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToClassroomResponse(classroom))
+}
+
+// GetMembers godoc
+// @Summary Get classroom members
+// @Tags classrooms
+// @Description Returns owner, co-teachers and students; teachers of the classroom only
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.ClassroomMemberResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/members [get]
+func (h *ClassroomHandler) GetMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	members, err := h.classroomService.GetMembers(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"))
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToClassroomMemberList(members))
+}
+
+// RemoveMember godoc
+// @Summary Remove classroom member
+// @Tags classrooms
+// @Description Teachers remove students, the owner removes co-teachers, any non-owner member can remove themselves
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param userId path string true "Member user ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/members/{userId} [delete]
+func (h *ClassroomHandler) RemoveMember(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := h.classroomService.RemoveMember(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"), c.Param("userId"))
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Removed")
+}
+
+// AddTeacher godoc
+// @Summary Add co-teacher
+// @Tags classrooms
+// @Description Adds a teacher to the classroom as co-teacher; owner only
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.ClassroomMemberRequest true "Teacher user ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/teachers [post]
+func (h *ClassroomHandler) AddTeacher(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ClassroomMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := h.classroomService.AddCoTeacher(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"), req.UserID)
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Added")
+}
+
+// TransferOwnership godoc
+// @Summary Transfer classroom ownership
+// @Tags classrooms
+// @Description Makes a co-teacher the owner; the previous owner stays as co-teacher
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.ClassroomMemberRequest true "New owner user ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/transfer [post]
+func (h *ClassroomHandler) TransferOwnership(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ClassroomMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := h.classroomService.TransferOwnership(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"), req.UserID)
+	if err != nil {
+		classroomError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Transferred")
+}
+
+// GetStudentProgress godoc
+// @Summary Get student progress
+// @Tags progress
+// @Description Returns topic progress of a student; teachers may only view students of their classrooms
+// @Produce json
+// @Param id path string true "Student user ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.TopicProgressResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/{id}/progress [get]
+func (h *ClassroomHandler) GetStudentProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	studentID := c.Param("id")
+
+	ok, err := h.classroomService.CanViewStudent(ctx, c.GetString("userId"), c.GetString("role"), studentID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to check access")
+		return
+	}
+
+	if !ok {
+		response.Error(c, http.StatusForbidden, "Student is not in your classrooms")
+		return
+	}
+
+	progress, err := h.progressService.GetUserProgress(ctx, studentID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch progress")
+		return
+	}
+
+	response.Success(c, mapper.ToTopicProgressList(progress))
+}
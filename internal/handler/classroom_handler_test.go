@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/middleware"
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeClassroomRepo реализует только методы, нужные хендлеру в тестах
+type fakeClassroomRepo struct {
+	repository.IClassroomRepository
+	students map[string][]string
+	members  []models.ClassroomMember
+}
+
+func (r *fakeClassroomRepo) FindByID(ctx context.Context, id string) (*models.Classroom, error) {
+	return &models.Classroom{ID: id}, nil
+}
+
+func (r *fakeClassroomRepo) FindMembers(ctx context.Context, classroomID string) ([]models.ClassroomMember, error) {
+	var out []models.ClassroomMember
+	for _, m := range r.members {
+		if m.ClassroomID == classroomID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeClassroomRepo) FindByJoinCode(ctx context.Context, code string) (*models.Classroom, error) {
+	return nil, nil
+}
+
+func (r *fakeClassroomRepo) FindMember(ctx context.Context, classroomID, userID string) (*models.ClassroomMember, error) {
+	for _, m := range r.members {
+		if m.ClassroomID == classroomID && m.UserID == userID {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeClassroomRepo) IsStudentOfTeacher(ctx context.Context, teacherID, studentID string) (bool, error) {
+	for _, id := range r.students[teacherID] {
+		if id == studentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func setupClassroomRouter(userID, role string) (*gin.Engine, *fakeClassroomRepo) {
+	gin.SetMode(gin.TestMode)
+
+	classrooms := &fakeClassroomRepo{students: map[string][]string{}}
+	progress := service.NewProgressService(&fakeTopicRepo{}, &fakeTaskRepo{}, &fakeSubmissionRepo{})
+	h := NewClassroomHandler(service.NewClassroomService(classrooms, newFakeUserRepoForHandler()), progress)
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", role)
+		c.Next()
+	}
+	r.POST("/classrooms/join", withUser, h.Join)
+	r.GET("/user/:id/progress", withUser, h.GetStudentProgress)
+
+	return r, classrooms
+}
+
+func TestClassroomHandler_GetStudentProgress_Scoped(t *testing.T) {
+	router, classrooms := setupClassroomRouter("teacher-1", "Teacher")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/student-1/progress", nil))
+	assert.Equal(t, 403, w.Code)
+
+	classrooms.students["teacher-1"] = []string{"student-1"}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/student-1/progress", nil))
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestClassroomHandler_GetStudentProgress_AdminNeedsTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := newTestKeySet(t)
+	progress := service.NewProgressService(&fakeTopicRepo{}, &fakeTaskRepo{}, &fakeSubmissionRepo{})
+	h := NewClassroomHandler(service.NewClassroomService(&fakeClassroomRepo{students: map[string][]string{}}, newFakeUserRepoForHandler()), progress)
+
+	// маршрут без RoleMiddleware: права админа даёт только сервис
+	r := gin.New()
+	r.GET("/user/:id/progress", middleware.AuthMiddleware(keys, nil), h.GetStudentProgress)
+
+	sign := func(twoFactor bool) string {
+		token, err := keys.Sign(map[string]interface{}{"userId": "admin-1", "role": "Admin", "mfa": twoFactor, "exp": time.Now().Add(time.Minute).Unix()})
+		require.NoError(t, err)
+		return token
+	}
+
+	w := doWithToken(r, "GET", "/user/student-1/progress", sign(false), nil)
+	assert.Equal(t, 403, w.Code)
+
+	w = doWithToken(r, "GET", "/user/student-1/progress", sign(true), nil)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestClassroomHandler_Join_InvalidCode(t *testing.T) {
+	router, _ := setupClassroomRouter("student-1", "Student")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/classrooms/join", bytes.NewBufferString(`{"code":"NOPE1234"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+}
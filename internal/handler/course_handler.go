@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseHandler struct {
+	courseService *service.CourseService
+}
+
+func NewCourseHandler(courseService *service.CourseService) *CourseHandler {
+	return &CourseHandler{courseService: courseService}
+}
+
+func courseError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrCourseForbidden),
+		errors.Is(err, service.ErrCourseNotEnrolled),
+		errors.Is(err, service.ErrModuleLocked):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case err.Error() == "record not found", err.Error() == "item not found":
+		response.Error(c, http.StatusNotFound, "Not found")
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// GetAll godoc
+// @Summary Get courses
+// @Tags courses
+// @Description Returns published courses; teachers and admins also see drafts and archived courses
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.CourseResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses [get]
+func (h *CourseHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	role := c.GetString("role")
+	includeDrafts := role == string(models.UserRoleTeacher) || role == string(models.UserRoleAdmin)
+
+	courses, err := h.courseService.GetCourses(ctx, includeDrafts)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch courses")
+		return
+	}
+
+	response.Success(c, mapper.ToCourseList(courses))
+}
+
+// GetMy godoc
+// @Summary Get enrolled courses
+// @Tags courses
+// @Description Returns courses the current user is enrolled in
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.CourseResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/my [get]
+func (h *CourseHandler) GetMy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	courses, err := h.courseService.GetMyCourses(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch courses")
+		return
+	}
+
+	response.Success(c, mapper.ToCourseList(courses))
+}
+
+// GetByID godoc
+// @Summary Get course by ID
+// @Tags courses
+// @Description Returns course with its ordered modules and items
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CourseResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id} [get]
+func (h *CourseHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	course, err := h.courseService.GetCourseByID(ctx, c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Course not found")
+		return
+	}
+
+	role := c.GetString("role")
+	if course.Status != models.CourseStatusPublished && role == string(models.UserRoleStudent) {
+		response.Error(c, http.StatusNotFound, "Course not found")
+		return
+	}
+
+	response.Success(c, mapper.ToCourseResponse(course))
+}
+
+// Create godoc
+// @Summary Create course
+// @Tags courses
+// @Description Creates a new course owned by the current user
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCourseRequest true "Course payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.CourseResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses [post]
+func (h *CourseHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CreateCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	course := &models.Course{
+		Title:         req.Title,
+		DescriptionMD: req.DescriptionMD,
+		SchoolClass:   req.SchoolClass,
+		Status:        req.Status,
+		AuthorID:      c.GetString("userId"),
+	}
+
+	if err := h.courseService.CreateCourse(ctx, course); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to create course")
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToCourseResponse(course))
+}
+
+// Update godoc
+// @Summary Update course
+// @Tags courses
+// @Description Updates course metadata; only the author or an admin may update
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param request body dto.UpdateCourseRequest true "Course payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CourseResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id} [put]
+func (h *CourseHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	var req dto.UpdateCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	course := &models.Course{
+		ID:            id,
+		Title:         req.Title,
+		DescriptionMD: req.DescriptionMD,
+		SchoolClass:   req.SchoolClass,
+		Status:        req.Status,
+	}
+
+	if err := h.courseService.UpdateCourse(ctx, c.GetString("userId"), c.GetString("role"), course); err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := h.courseService.GetCourseByID(ctx, id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to fetch updated course")
+		return
+	}
+
+	response.Success(c, mapper.ToCourseResponse(updated))
+}
+
+// Delete godoc
+// @Summary Delete course
+// @Tags courses
+// @Description Deletes course with all modules, items and enrollments
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id} [delete]
+func (h *CourseHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.courseService.DeleteCourse(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id")); err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// AddModule godoc
+// @Summary Add course module
+// @Tags courses
+// @Description Adds a module to the course; position defaults to the end
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param request body dto.CourseModuleRequest true "Module payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.CourseModuleResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules [post]
+func (h *CourseHandler) AddModule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CourseModuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	module := &models.CourseModule{
+		CourseID:         c.Param("id"),
+		Title:            req.Title,
+		Position:         req.Position,
+		RequiresPrevious: req.RequiresPrevious,
+		UnlockAt:         req.UnlockAt,
+	}
+
+	if err := h.courseService.AddModule(ctx, c.GetString("userId"), c.GetString("role"), module); err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToCourseModuleResponse(module))
+}
+
+// UpdateModule godoc
+// @Summary Update course module
+// @Tags courses
+// @Description Updates module title, position and unlock rules
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param moduleId path string true "Module ID"
+// @Param request body dto.CourseModuleRequest true "Module payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CourseModuleResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{moduleId} [put]
+func (h *CourseHandler) UpdateModule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CourseModuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	module := &models.CourseModule{
+		ID:               c.Param("moduleId"),
+		CourseID:         c.Param("id"),
+		Title:            req.Title,
+		Position:         req.Position,
+		RequiresPrevious: req.RequiresPrevious,
+		UnlockAt:         req.UnlockAt,
+	}
+
+	if err := h.courseService.UpdateModule(ctx, c.GetString("userId"), c.GetString("role"), module); err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToCourseModuleResponse(module))
+}
+
+// DeleteModule godoc
+// @Summary Delete course module
+// @Tags courses
+// @Description Deletes module and its items
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param moduleId path string true "Module ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{moduleId} [delete]
+func (h *CourseHandler) DeleteModule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.courseService.DeleteModule(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"), c.Param("moduleId")); err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// AddItem godoc
+// @Summary Add module item
+// @Tags courses
+// @Description Adds a topic, task or reading item to a module
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param moduleId path string true "Module ID"
+// @Param request body dto.CreateCourseItemRequest true "Item payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.CourseItemResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{moduleId}/items [post]
+func (h *CourseHandler) AddItem(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CreateCourseItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item := &models.CourseItem{
+		ModuleID: c.Param("moduleId"),
+		ItemType: req.ItemType,
+		Title:    req.Title,
+		BodyMD:   req.BodyMD,
+		TopicID:  req.TopicID,
+		TaskID:   req.TaskID,
+		Position: req.Position,
+	}
+
+	if err := h.courseService.AddItem(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"), item); err != nil {
+		courseError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToCourseItemResponse(item))
+}
+
+// DeleteItem godoc
+// @Summary Delete module item
+// @Tags courses
+// @Description Removes an item from a module
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param moduleId path string true "Module ID"
+// @Param itemId path string true "Item ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{moduleId}/items/{itemId} [delete]
+func (h *CourseHandler) DeleteItem(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := h.courseService.DeleteItem(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"), c.Param("moduleId"), c.Param("itemId"))
+	if err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// Enroll godoc
+// @Summary Enroll in course
+// @Tags courses
+// @Description Enrolls the current user in a published course
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/enroll [post]
+func (h *CourseHandler) Enroll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.courseService.Enroll(ctx, c.Param("id"), c.GetString("userId")); err != nil {
+		courseError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	response.Success(c, "Enrolled")
+}
+
+// Unenroll godoc
+// @Summary Leave course
+// @Tags courses
+// @Description Removes the current user's enrollment
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/enroll [delete]
+func (h *CourseHandler) Unenroll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.courseService.Unenroll(ctx, c.Param("id"), c.GetString("userId")); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to leave course")
+		return
+	}
+
+	response.Success(c, "Unenrolled")
+}
+
+// GetProgress godoc
+// @Summary Get course progress
+// @Tags courses
+// @Description Returns module unlock state and completion for the current enrolled user
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CourseProgressResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/progress [get]
+func (h *CourseHandler) GetProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	progress, err := h.courseService.GetProgress(ctx, c.Param("id"), c.GetString("userId"))
+	if err != nil {
+		courseError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToCourseProgressResponse(progress))
+}
+
+// CompleteItem godoc
+// @Summary Mark reading item as read
+// @Tags courses
+// @Description Marks a reading item in an unlocked module as completed and returns updated progress
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param itemId path string true "Item ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.CourseProgressResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/items/{itemId}/complete [post]
+func (h *CourseHandler) CompleteItem(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	progress, err := h.courseService.CompleteReading(ctx, c.Param("id"), c.Param("itemId"), c.GetString("userId"))
+	if err != nil {
+		courseError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	response.Success(c, mapper.ToCourseProgressResponse(progress))
+}
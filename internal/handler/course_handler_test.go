@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeCourseRepo реализует только методы, нужные хендлеру в тестах
+type fakeCourseRepo struct {
+	repository.ICourseRepository
+	courses     map[string]*models.Course
+	enrollments map[string]bool
+}
+
+func (r *fakeCourseRepo) FindByID(ctx context.Context, id string) (*models.Course, error) {
+	c, ok := r.courses[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return c, nil
+}
+
+func (r *fakeCourseRepo) FindEnrollment(ctx context.Context, courseID, userID string) (*models.CourseEnrollment, error) {
+	if !r.enrollments[courseID+"/"+userID] {
+		return nil, nil
+	}
+	return &models.CourseEnrollment{CourseID: courseID, UserID: userID}, nil
+}
+
+func (r *fakeCourseRepo) GetCompletedItemIDs(ctx context.Context, courseID, userID string) ([]string, error) {
+	return nil, nil
+}
+
+func setupCourseRouter(role string) (*gin.Engine, *fakeCourseRepo) {
+	gin.SetMode(gin.TestMode)
+
+	courses := &fakeCourseRepo{
+		courses:     map[string]*models.Course{},
+		enrollments: map[string]bool{},
+	}
+	h := NewCourseHandler(service.NewCourseService(courses, &fakeTaskRepo{}, &fakeSubmissionRepo{}))
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", "user-1")
+		c.Set("role", role)
+		c.Next()
+	}
+	r.GET("/courses/:id", withUser, h.GetByID)
+	r.GET("/courses/:id/progress", withUser, h.GetProgress)
+
+	return r, courses
+}
+
+func TestCourseHandler_GetByID_HidesDraftFromStudents(t *testing.T) {
+	router, courses := setupCourseRouter("Student")
+	courses.courses["draft"] = &models.Course{ID: "draft", Status: models.CourseStatusDraft}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/courses/draft", nil))
+	assert.Equal(t, 404, w.Code)
+
+	router, courses = setupCourseRouter("Teacher")
+	courses.courses["draft"] = &models.Course{ID: "draft", Status: models.CourseStatusDraft}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/courses/draft", nil))
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestCourseHandler_GetProgress_RequiresEnrollment(t *testing.T) {
+	router, courses := setupCourseRouter("Student")
+	courses.courses["course-1"] = &models.Course{ID: "course-1", Status: models.CourseStatusPublished}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/courses/course-1/progress", nil))
+	assert.Equal(t, 403, w.Code)
+
+	courses.enrollments["course-1/user-1"] = true
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/courses/course-1/progress", nil))
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DailyChallengeHandler struct {
+	dailyChallengeService *service.DailyChallengeService
+}
+
+func NewDailyChallengeHandler(dailyChallengeService *service.DailyChallengeService) *DailyChallengeHandler {
+	return &DailyChallengeHandler{dailyChallengeService: dailyChallengeService}
+}
+
+func dailyChallengeError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDailyChallenge), errors.Is(err, service.ErrInvalidSchoolClass):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrDailyChallengeNotFound), errors.Is(err, service.ErrNoDailyChallengeTasks):
+		response.Error(c, http.StatusNotFound, err.Error())
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// staffRole reports whether the role may see answers of a running challenge.
+func staffRole(role string) bool {
+	return role == string(models.UserRoleTeacher) || role == string(models.UserRoleAdmin)
+}
+
+// GetToday godoc
+// @Summary Get today's daily challenge
+// @Tags daily-challenge
+// @Description Returns today's (UTC) challenge task of a school class, picking one automatically if no admin did. Solve it through the regular submit endpoint; the answer is only shown to teachers and admins
+// @Produce json
+// @Param schoolClass query string true "School class code"
+// @Success 200 {object} response.SuccessWrapper{data=dto.DailyChallengeResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/daily [get]
+func (h *DailyChallengeHandler) GetToday(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	challenge, err := h.dailyChallengeService.GetToday(ctx, c.Query("schoolClass"))
+	if err != nil {
+		dailyChallengeError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToDailyChallengeResponse(challenge, staffRole(c.GetString("role"))))
+}
+
+// Set godoc
+// @Summary Set daily challenge
+// @Tags daily-challenge
+// @Description Picks the challenge task of a school class for today (date omitted) or a future day, replacing an automatic pick
+// @Accept json
+// @Produce json
+// @Param request body dto.SetDailyChallengeRequest true "Daily challenge payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.DailyChallengeResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/daily [put]
+func (h *DailyChallengeHandler) Set(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.SetDailyChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	challenge, err := h.dailyChallengeService.SetChallenge(ctx, req.SchoolClass, req.Date, req.TaskID, c.GetString("userId"))
+	if err != nil {
+		dailyChallengeError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToDailyChallengeResponse(challenge, true))
+}
+
+// GetLeaderboard godoc
+// @Summary Get daily challenge leaderboard
+// @Tags daily-challenge
+// @Description Ranks the users who solved a day's challenge by the time of their first correct answer that day, counted from midnight UTC, and returns the caller's own entry (null if not solved). The answer is revealed once the day is over
+// @Produce json
+// @Param schoolClass query string true "School class code"
+// @Param date query string false "Day as YYYY-MM-DD (today by default)"
+// @Param limit query int false "Number of top entries (default 10, max 100)"
+// @Success 200 {object} response.SuccessWrapper{data=dto.DailyChallengeLeaderboardResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/daily/leaderboard [get]
+func (h *DailyChallengeHandler) GetLeaderboard(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := service.DefaultLeaderboardSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > service.MaxLeaderboardSize {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	board, err := h.dailyChallengeService.GetLeaderboard(ctx, c.Query("schoolClass"), c.Query("date"), c.GetString("userId"), limit)
+	if err != nil {
+		dailyChallengeError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	reveal := staffRole(c.GetString("role")) || board.Challenge.IsOver(time.Now())
+	response.Success(c, mapper.ToDailyChallengeLeaderboardResponse(board, reveal))
+}
+
+// GetHistory godoc
+// @Summary Get daily challenge history
+// @Tags daily-challenge
+// @Description Returns past challenges of a school class with their answers, newest first
+// @Produce json
+// @Param schoolClass query string true "School class code"
+// @Param limit query int false "Number of challenges (default 30, max 100)"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.DailyChallengeResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/daily/history [get]
+func (h *DailyChallengeHandler) GetHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := service.DefaultDailyChallengeHistory
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > service.MaxDailyChallengeHistory {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	challenges, err := h.dailyChallengeService.GetHistory(ctx, c.Query("schoolClass"), limit)
+	if err != nil {
+		dailyChallengeError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToDailyChallengeList(challenges, true))
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+type fakeDailyChallengeRepo struct {
+	tasks      *fakeTaskRepo
+	challenges []models.DailyChallenge
+	solves     []models.DailyChallengeSolve
+}
+
+func (f *fakeDailyChallengeRepo) FindByDay(ctx context.Context, schoolClass string, day time.Time) (*models.DailyChallenge, error) {
+	for _, c := range f.challenges {
+		if c.SchoolClass == schoolClass && c.Day.Equal(day) {
+			c.Task, _ = f.tasks.GetByID(ctx, c.TaskID)
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeDailyChallengeRepo) Create(ctx context.Context, challenge *models.DailyChallenge) (bool, error) {
+	challenge.ID = uuid.NewString()
+	f.challenges = append(f.challenges, *challenge)
+	return true, nil
+}
+
+func (f *fakeDailyChallengeRepo) Upsert(ctx context.Context, challenge *models.DailyChallenge) error {
+	for i := range f.challenges {
+		if f.challenges[i].SchoolClass == challenge.SchoolClass && f.challenges[i].Day.Equal(challenge.Day) {
+			f.challenges[i].TaskID = challenge.TaskID
+			f.challenges[i].SelectedBy = challenge.SelectedBy
+			return nil
+		}
+	}
+	_, err := f.Create(ctx, challenge)
+	return err
+}
+
+func (f *fakeDailyChallengeRepo) FindHistory(ctx context.Context, schoolClass string, before time.Time, limit int) ([]models.DailyChallenge, error) {
+	var out []models.DailyChallenge
+	for _, c := range f.challenges {
+		if c.SchoolClass == schoolClass && c.Day.Before(before) && len(out) < limit {
+			c.Task, _ = f.tasks.GetByID(ctx, c.TaskID)
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDailyChallengeRepo) GetSolves(ctx context.Context, taskID string, from, to time.Time) ([]models.DailyChallengeSolve, error) {
+	return f.solves, nil
+}
+
+func setupDailyChallengeRouter(userID, role string) (*gin.Engine, *fakeDailyChallengeRepo, *fakeUserRepoForHandler) {
+	gin.SetMode(gin.TestMode)
+
+	topic := &models.Topic{ID: "topic-1", SchoolClass: "SEVEN"}
+	tasks := &fakeTaskRepo{tasks: []models.Task{
+		{ID: "task-1", Title: "Fractions", TopicID: topic.ID, Topic: topic, Status: models.TaskStatusPublished, CorrectAnswer: "3/4"},
+		{ID: "task-2", Title: "Draft", TopicID: topic.ID, Topic: topic, Status: models.TaskStatusDraft, CorrectAnswer: "1"},
+	}}
+	challenges := &fakeDailyChallengeRepo{tasks: tasks}
+	users := newFakeUserRepoForHandler()
+
+	svc := service.NewDailyChallengeService(challenges, tasks, newFakeSchoolClassRepoForHandler("SEVEN"), users)
+	h := NewDailyChallengeHandler(svc)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", role)
+		c.Next()
+	})
+	r.GET("/tasks/daily", h.GetToday)
+	r.PUT("/tasks/daily", h.Set)
+	r.GET("/tasks/daily/leaderboard", h.GetLeaderboard)
+	r.GET("/tasks/daily/history", h.GetHistory)
+
+	return r, challenges, users
+}
+
+func TestDailyChallengeHandler_GetToday(t *testing.T) {
+	router, _, _ := setupDailyChallengeRouter(uuid.NewString(), "Student")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily?schoolClass=SEVEN", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Fractions"`)
+	assert.Contains(t, w.Body.String(), `"automatic":true`)
+	assert.NotContains(t, w.Body.String(), `3/4`, "students must not see the answer of a running challenge")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily?schoolClass=TWELVE", nil))
+	assert.Equal(t, 400, w.Code)
+
+	teacherRouter, _, _ := setupDailyChallengeRouter(uuid.NewString(), "Teacher")
+	w = httptest.NewRecorder()
+	teacherRouter.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily?schoolClass=SEVEN", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"correctAnswer":"3/4"`)
+}
+
+func TestDailyChallengeHandler_Set(t *testing.T) {
+	router, challenges, _ := setupDailyChallengeRouter(uuid.NewString(), "Admin")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/daily", strings.NewReader(
+		`{"schoolClass":"SEVEN","taskId":"`+uuid.NewString()+`"}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/daily", strings.NewReader(`{"schoolClass":"SEVEN"}`)))
+	assert.Equal(t, 400, w.Code)
+	assert.Empty(t, challenges.challenges)
+}
+
+func TestDailyChallengeHandler_LeaderboardAndHistory(t *testing.T) {
+	ctx := context.Background()
+	me := uuid.New()
+	router, challenges, users := setupDailyChallengeRouter(me.String(), "Student")
+
+	other := uuid.New()
+	require.NoError(t, users.Create(ctx, &models.User{ID: other, DisplayName: "Quick"}))
+	require.NoError(t, users.Create(ctx, &models.User{ID: me, DisplayName: "Me"}))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	challenges.solves = []models.DailyChallengeSolve{
+		{UserID: other.String(), SolvedAt: today.Add(5 * time.Minute), Attempts: 1},
+		{UserID: me.String(), SolvedAt: today.Add(time.Hour), Attempts: 2},
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily/leaderboard?schoolClass=SEVEN&limit=1", nil))
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"displayName":"Quick"`)
+	assert.Contains(t, body, `"solveTimeSeconds":300`)
+	assert.Contains(t, body, `"me":{"rank":2`)
+	assert.NotContains(t, body, `3/4`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily/leaderboard?schoolClass=SEVEN&limit=0", nil))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily/leaderboard?schoolClass=SEVEN&date=2020-01-01", nil))
+	assert.Equal(t, 404, w.Code)
+
+	// вчерашний челлендж попадает в историю вместе с ответом
+	challenges.challenges[0].Day = today.AddDate(0, 0, -1)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily/history?schoolClass=SEVEN", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"correctAnswer":"3/4"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/daily/leaderboard?schoolClass=SEVEN&date="+today.AddDate(0, 0, -1).Format("2006-01-02"), nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"correctAnswer":"3/4"`, "answers are revealed once the day is over")
+}
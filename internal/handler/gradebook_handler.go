@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GradebookHandler struct {
+	gradebookService *service.GradebookService
+}
+
+func NewGradebookHandler(gradebookService *service.GradebookService) *GradebookHandler {
+	return &GradebookHandler{gradebookService: gradebookService}
+}
+
+func gradebookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrClassroomForbidden):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidWeights):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Not found")
+	default:
+		response.Error(c, http.StatusInternalServerError, "Failed to build gradebook")
+	}
+}
+
+// Get godoc
+// @Summary Get classroom gradebook
+// @Tags gradebook
+// @Description Returns per-student scores for opened assignments and a weighted total over assignments past their due date
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.GradebookResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/gradebook [get]
+func (h *GradebookHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	gradebook, err := h.gradebookService.GetGradebook(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		gradebookError(c, err)
+		return
+	}
+
+	response.Success(c, mapper.ToGradebookResponse(gradebook))
+}
+
+// Export godoc
+// @Summary Export classroom gradebook
+// @Tags gradebook
+// @Description Streams the gradebook as a CSV or XLSX file
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "Classroom ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/gradebook/export [get]
+func (h *GradebookHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		response.Error(c, http.StatusBadRequest, "Unsupported format")
+		return
+	}
+
+	gradebook, err := h.gradebookService.GetGradebook(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		gradebookError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("gradebook-%s.%s", time.Now().Format("2006-01-02"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "xlsx" {
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Status(http.StatusOK)
+		err = service.WriteGradebookXLSX(c.Writer, gradebook)
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		err = service.WriteGradebookCSV(c.Writer, gradebook)
+	}
+
+	if err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// UpdateWeights godoc
+// @Summary Update assignment weights
+// @Tags gradebook
+// @Description Sets the weight of each listed assignment in the weighted total
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.GradebookWeightsRequest true "Weights"
+// @Success 200 {object} response.SuccessWrapper{data=dto.GradebookResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/gradebook/weights [put]
+func (h *GradebookHandler) UpdateWeights(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GradebookWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	weights := make(map[string]float64, len(req.Weights))
+	for _, w := range req.Weights {
+		weights[w.AssignmentID] = w.Weight
+	}
+
+	userID, role, classroomID := c.GetString("userId"), c.GetString("role"), c.Param("id")
+
+	if err := h.gradebookService.UpdateWeights(ctx, userID, role, classroomID, weights); err != nil {
+		gradebookError(c, err)
+		return
+	}
+
+	gradebook, err := h.gradebookService.GetGradebook(ctx, userID, role, classroomID)
+	if err != nil {
+		gradebookError(c, err)
+		return
+	}
+
+	response.Success(c, mapper.ToGradebookResponse(gradebook))
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+func setupGradebookRouter(userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	assignments := &fakeAssignmentRepo{assignments: map[string]*models.Assignment{
+		"a1": {
+			ID:          "a1",
+			ClassroomID: "c1",
+			Title:       "HW 1",
+			OpensAt:     time.Now().Add(-48 * time.Hour),
+			DueAt:       time.Now().Add(-24 * time.Hour),
+			Weight:      1,
+			Tasks:       []models.AssignmentTask{{TaskID: "t1"}},
+		},
+	}}
+	classrooms := &fakeClassroomRepo{members: []models.ClassroomMember{
+		{ClassroomID: "c1", UserID: "teacher-1", Role: models.ClassroomRoleOwner},
+		{ClassroomID: "c1", UserID: "student-1", Role: models.ClassroomRoleStudent,
+			User: &models.User{DisplayName: "Alice", Email: "alice@test.com"}},
+	}}
+
+	h := NewGradebookHandler(service.NewGradebookService(assignments, classrooms, &fakeSubmissionRepo{}))
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", "Teacher")
+		c.Next()
+	}
+	r.GET("/classrooms/:id/gradebook/export", withUser, h.Export)
+
+	return r
+}
+
+func TestGradebookHandler_ExportCSV(t *testing.T) {
+	router := setupGradebookRouter("teacher-1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/classrooms/c1/gradebook/export?format=csv", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, []string{"Student,Email,HW 1,Weighted total", "Alice,alice@test.com,0,0"}, lines)
+}
+
+func TestGradebookHandler_Export_Forbidden(t *testing.T) {
+	router := setupGradebookRouter("teacher-2")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/classrooms/c1/gradebook/export", nil))
+	assert.Equal(t, 403, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/classrooms/c1/gradebook/export?format=pdf", nil))
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GradingHandler struct {
+	gradingService *service.GradingService
+}
+
+func NewGradingHandler(gradingService *service.GradingService) *GradingHandler {
+	return &GradingHandler{gradingService: gradingService}
+}
+
+func gradingError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrInvalidGrade):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrGradingForbidden):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrSubmissionAlreadyGraded):
+		response.Error(c, http.StatusConflict, err.Error())
+	case err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Submission not found")
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// GetQueue godoc
+// @Summary Get grading queue
+// @Tags grading
+// @Description Returns answers to MANUAL tasks waiting for a grade, oldest first. Teachers see answers to their own tasks and to assignments of classrooms they teach; admins see all
+// @Produce json
+// @Param taskId query string false "Only answers to this task"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.GradingQueueItemResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /grading/queue [get]
+func (h *GradingHandler) GetQueue(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	submissions, err := h.gradingService.GetQueue(ctx, c.GetString("userId"), c.GetString("role"), c.Query("taskId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch grading queue")
+		return
+	}
+
+	response.Success(c, mapper.ToGradingQueue(submissions))
+}
+
+// Grade godoc
+// @Summary Grade submission
+// @Tags grading
+// @Description Scores an answer pending review from 0 to 100 with written feedback. A score of 60 or more counts as a correct solve for progress, streaks and leaderboards
+// @Accept json
+// @Produce json
+// @Param id path string true "Submission ID"
+// @Param request body dto.GradeSubmissionRequest true "Grade payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.SubmissionResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /grading/submissions/{id} [post]
+func (h *GradingHandler) Grade(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GradeSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	submission, err := h.gradingService.Grade(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"), *req.Score, req.Feedback)
+	if err != nil {
+		gradingError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToSubmissionResponse(submission))
+}
+
+// Override godoc
+// @Summary Override grade
+// @Tags grading
+// @Description Replaces the grade of an answer to a manually graded task, whether it is pending, graded by peers or graded by another teacher
+// @Accept json
+// @Produce json
+// @Param id path string true "Submission ID"
+// @Param request body dto.GradeSubmissionRequest true "Grade payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.SubmissionResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /grading/submissions/{id}/override [post]
+func (h *GradingHandler) Override(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GradeSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	submission, err := h.gradingService.Override(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"), *req.Score, req.Feedback)
+	if err != nil {
+		gradingError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToSubmissionResponse(submission))
+}
+
+// GetMySubmissions godoc
+// @Summary Get my submissions for a task
+// @Tags tasks
+// @Description Returns the current user's answers to a task, newest first, with the teacher's score and feedback once graded
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.SubmissionResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/{id}/submissions [get]
+func (h *GradingHandler) GetMySubmissions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	submissions, err := h.gradingService.GetMySubmissions(ctx, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch submissions")
+		return
+	}
+
+	response.Success(c, mapper.ToSubmissionList(submissions))
+}
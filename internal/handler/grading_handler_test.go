@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+func setupGradingRouter(t *testing.T, userID, role string) (*gin.Engine, *fakeSubmissionRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	tasks := &fakeTaskRepo{tasks: []models.Task{
+		{ID: "essay", AuthorID: "author", AnswerType: models.AnswerTypeText, GradingMode: models.GradingModeManual},
+	}}
+	submissions := &fakeSubmissionRepo{submissions: []models.Submission{
+		{ID: "sub-1", TaskID: "essay", UserID: "student", Answer: "essay text", Status: models.SubmissionStatusPendingReview},
+	}, tasks: tasks}
+	classrooms := &fakeClassroomRepo{}
+	assignments := &fakeAssignmentRepo{assignments: map[string]*models.Assignment{}}
+
+	taskService := service.NewTaskService(tasks, submissions, rdb)
+	h := NewGradingHandler(service.NewGradingService(submissions, assignments, classrooms, taskService))
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", role)
+		c.Next()
+	})
+	r.GET("/grading/queue", h.GetQueue)
+	r.POST("/grading/submissions/:id", h.Grade)
+	r.POST("/grading/submissions/:id/override", h.Override)
+	r.GET("/tasks/:id/submissions", h.GetMySubmissions)
+
+	return r, submissions
+}
+
+func TestGradingHandler_Grade(t *testing.T) {
+	router, submissions := setupGradingRouter(t, "admin", "Admin")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/grading/queue", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"answer":"essay text"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"score":150}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"feedback":"no score"}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/missing", strings.NewReader(`{"score":80}`)))
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"score":80,"feedback":"Nice"}`)))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"GRADED"`)
+	assert.Contains(t, w.Body.String(), `"score":80`)
+	require.Len(t, submissions.submissions, 1)
+	assert.True(t, submissions.submissions[0].IsCorrect)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"score":10}`)))
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestGradingHandler_Override(t *testing.T) {
+	router, submissions := setupGradingRouter(t, "author", "Teacher")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"score":30}`)))
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1/override", strings.NewReader(`{"score":90,"feedback":"Regraded"}`)))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"score":90`)
+	assert.True(t, submissions.submissions[0].IsCorrect)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/missing/override", strings.NewReader(`{"score":90}`)))
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestGradingHandler_Forbidden(t *testing.T) {
+	router, _ := setupGradingRouter(t, "stranger", "Teacher")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/grading/submissions/sub-1", strings.NewReader(`{"score":80}`)))
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestGradingHandler_GetMySubmissions(t *testing.T) {
+	router, _ := setupGradingRouter(t, "student", "Student")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/essay/submissions", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"PENDING_REVIEW"`)
+	// пока ответ не проверен, правильность не показывается
+	assert.Contains(t, w.Body.String(), `"correct":null`)
+}
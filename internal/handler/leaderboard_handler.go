@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *service.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *service.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardService: leaderboardService}
+}
+
+func leaderboardError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrLeaderboardForbidden):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case err.Error() == "record not found":
+		response.Error(c, http.StatusNotFound, "Not found")
+	case errors.Is(err, service.ErrInvalidLeaderboard):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// Get godoc
+// @Summary Get leaderboard
+// @Tags leaderboards
+// @Description Returns the top of a leaderboard and the caller's own rank (null if the caller has not scored yet). Points are awarded for the first correct solve of each task, weighted by difficulty. The classroom scope is available to classroom members only
+// @Produce json
+// @Param scope path string true "Leaderboard scope" Enums(global, topic, classroom, weekly)
+// @Param id query string false "Topic or classroom ID; ISO week like 2025-W07 for the weekly scope (current week by default)"
+// @Param limit query int false "Number of top entries (default 10, max 100)"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LeaderboardResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /leaderboards/{scope} [get]
+func (h *LeaderboardHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := service.DefaultLeaderboardSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > service.MaxLeaderboardSize {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	board, err := h.leaderboardService.GetLeaderboard(ctx,
+		models.LeaderboardScope(c.Param("scope")),
+		c.Query("id"),
+		c.GetString("userId"),
+		c.GetString("role"),
+		limit,
+	)
+	if err != nil {
+		leaderboardError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToLeaderboardResponse(board))
+}
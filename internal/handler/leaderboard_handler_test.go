@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+func setupLeaderboardRouter(t *testing.T, userID string) (*gin.Engine, *redis.Client, *fakeUserRepoForHandler, *fakeClassroomRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	users := newFakeUserRepoForHandler()
+	classrooms := &fakeClassroomRepo{}
+	svc := service.NewLeaderboardService(&fakeSubmissionRepo{}, classrooms, users, rdb)
+	h := NewLeaderboardHandler(svc)
+
+	r := gin.Default()
+	r.GET("/leaderboards/:scope", func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", "Student")
+		c.Next()
+	}, h.Get)
+
+	return r, rdb, users, classrooms
+}
+
+func TestLeaderboardHandler_Get(t *testing.T) {
+	ctx := context.Background()
+
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	router, rdb, users, classrooms := setupLeaderboardRouter(t, carol.String())
+
+	require.NoError(t, users.Create(ctx, &models.User{ID: alice, DisplayName: "Alice"}))
+	require.NoError(t, users.Create(ctx, &models.User{ID: bob, DisplayName: "Bob"}))
+	require.NoError(t, users.Create(ctx, &models.User{ID: carol, DisplayName: "Carol"}))
+
+	require.NoError(t, rdb.ZAdd(ctx, "leaderboard:global",
+		redis.Z{Member: alice.String(), Score: 50},
+		redis.Z{Member: bob.String(), Score: 30},
+		redis.Z{Member: carol.String(), Score: 10},
+	).Err())
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+
+	// Carol не попадает в топ-2, но получает свою позицию
+	w := get("/leaderboards/global?limit=2")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `{"rank":1,"userId":"`+alice.String()+`","displayName":"Alice","score":50}`)
+	assert.Contains(t, w.Body.String(), `"me":{"rank":3,"userId":"`+carol.String()+`","displayName":"Carol","score":10}`)
+
+	assert.Equal(t, 400, get("/leaderboards/global?limit=0").Code)
+	assert.Equal(t, 400, get("/leaderboards/monthly").Code)
+	assert.Equal(t, 400, get("/leaderboards/topic").Code)
+	assert.Equal(t, 400, get("/leaderboards/weekly?id=last").Code)
+	assert.Equal(t, 403, get("/leaderboards/classroom?id=c1").Code)
+
+	classrooms.members = []models.ClassroomMember{{ClassroomID: "c1", UserID: carol.String(), Role: models.ClassroomRoleStudent}}
+	w = get("/leaderboards/classroom?id=c1")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"entries":[]`)
+	assert.Contains(t, w.Body.String(), `"me":null`)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcBindingCookie keeps the value that ties a login state to the browser
+// that started it. It lives as long as the state does.
+const (
+	oidcBindingCookie       = "oidc_binding"
+	oidcBindingCookieMaxAge = 10 * 60
+)
+
+type OIDCHandler struct {
+	oidcService *service.OIDCService
+}
+
+func NewOIDCHandler(oidcService *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+func oidcError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCProviderNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrOIDCInvalidState), errors.Is(err, service.ErrOIDCEmailNotVerified):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("[OIDC] sign in failed: %v", err)
+		response.Error(c, http.StatusUnauthorized, "Sign in with the identity provider failed")
+	}
+}
+
+// GetProviders godoc
+// @Summary List identity providers
+// @Tags auth
+// @Description Returns the names of the configured OpenID Connect providers users can sign in with
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=dto.OIDCProvidersResponse}
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) GetProviders(c *gin.Context) {
+	response.Success(c, dto.OIDCProvidersResponse{
+		Providers: h.oidcService.Providers(),
+	})
+}
+
+// Authorize godoc
+// @Summary Start identity provider sign in
+// @Tags auth
+// @Description Returns the provider URL to send the user to and sets the oidc_binding cookie. The provider redirects back to the configured redirect URL with code and state, which are passed on to the callback endpoint within 10 minutes from the same browser
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} response.SuccessWrapper{data=dto.OIDCAuthorizeResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /auth/oidc/{provider}/authorize [get]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	authURL, binding, err := h.oidcService.AuthorizationURL(ctx, c.Param("provider"))
+	if err != nil {
+		oidcError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, binding, oidcBindingCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+
+	response.Success(c, dto.OIDCAuthorizeResponse{
+		AuthorizationURL: authURL,
+	})
+}
+
+// Callback godoc
+// @Summary Finish identity provider sign in
+// @Tags auth
+// @Description Exchanges the code from the provider for access + refresh tokens. Requires the oidc_binding cookie set by the authorize endpoint. The provider account is linked to the user with the same verified email, or a new user is created. With two-factor authentication enabled a challenge token is returned as with /auth/login
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the authorization URL"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LoginResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if providerError := c.Query("error"); providerError != "" {
+		response.Error(c, http.StatusBadRequest, "identity provider: "+providerError+" "+c.Query("error_description"))
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		response.Error(c, http.StatusBadRequest, "code and state are required")
+		return
+	}
+
+	// a missing cookie fails the state check
+	binding, _ := c.Cookie(oidcBindingCookie)
+	c.SetCookie(oidcBindingCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+
+	result, err := h.oidcService.Callback(ctx, c.Param("provider"), code, state, binding, clientInfo(c, ""))
+	if err != nil {
+		oidcError(c, err)
+		return
+	}
+
+	response.Success(c, toLoginResponse(result))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/service"
+)
+
+func newTestRouterWithOIDCHandler() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewOIDCService([]service.OIDCProviderConfig{{
+		Name:        "school",
+		Issuer:      "http://127.0.0.1:0",
+		ClientID:    "client",
+		RedirectURL: "http://localhost/callback",
+	}}, nil, nil, nil, nil)
+	h := NewOIDCHandler(svc)
+
+	r := gin.Default()
+	r.GET("/auth/oidc/providers", h.GetProviders)
+	r.GET("/auth/oidc/:provider/authorize", h.Authorize)
+	r.GET("/auth/oidc/:provider/callback", h.Callback)
+	return r
+}
+
+func TestOIDCHandler_GetProviders(t *testing.T) {
+	router := newTestRouterWithOIDCHandler()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/providers", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"providers":["school"]`)
+}
+
+func TestOIDCHandler_UnknownProvider(t *testing.T) {
+	router := newTestRouterWithOIDCHandler()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/other/authorize", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/other/callback?code=c&state=s", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOIDCHandler_Callback_InvalidQuery(t *testing.T) {
+	router := newTestRouterWithOIDCHandler()
+
+	// пользователь отменил вход у провайдера
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/school/callback?error=access_denied&state=s", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "access_denied")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/school/callback?state=s", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
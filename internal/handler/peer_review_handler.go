@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PeerReviewHandler struct {
+	peerReviewService *service.PeerReviewService
+}
+
+func NewPeerReviewHandler(peerReviewService *service.PeerReviewService) *PeerReviewHandler {
+	return &PeerReviewHandler{peerReviewService: peerReviewService}
+}
+
+func peerReviewError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRubric),
+		errors.Is(err, service.ErrInvalidPeerReview),
+		errors.Is(err, service.ErrPeerReviewDisabled),
+		errors.Is(err, service.ErrNotEnoughPeers):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrPeerReviewForbidden):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrRubricNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRubricLocked),
+		errors.Is(err, service.ErrPeerReviewSubmitted):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.Error(c, http.StatusNotFound, "Not found")
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+// GetRubric godoc
+// @Summary Get task rubric
+// @Tags peer-review
+// @Description Returns the criteria peers score answers to the task by and how many peers review each answer
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.RubricResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/{id}/rubric [get]
+func (h *PeerReviewHandler) GetRubric(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	rubric, err := h.peerReviewService.GetRubric(ctx, c.Param("id"))
+	if err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToRubricResponse(rubric))
+}
+
+// SaveRubric godoc
+// @Summary Save task rubric
+// @Tags peer-review
+// @Description Replaces the rubric of a manually graded task. peerReviews is the number of peers reviewing each answer; 0 turns peer review off. The rubric is locked once reviews are assigned. Only the task author or an admin may change it
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body dto.SaveRubricRequest true "Rubric"
+// @Success 200 {object} response.SuccessWrapper{data=dto.RubricResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/{id}/rubric [put]
+func (h *PeerReviewHandler) SaveRubric(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.SaveRubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rubric := &models.Rubric{
+		TaskID:      c.Param("id"),
+		PeerReviews: *req.PeerReviews,
+		Criteria:    mapper.ToRubricCriteria(req.Criteria),
+	}
+
+	if err := h.peerReviewService.SaveRubric(ctx, c.GetString("userId"), c.GetString("role"), rubric); err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToRubricResponse(rubric))
+}
+
+// AssignReviewers godoc
+// @Summary Assign peer reviewers
+// @Tags peer-review
+// @Description Gives every answer to the task that is pending review the rubric's number of reviewers, picked among the other students who answered it. Safe to run again as more answers come in
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.AssignPeerReviewsResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/{id}/peer-reviews/assign [post]
+func (h *PeerReviewHandler) AssignReviewers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	assigned, err := h.peerReviewService.AssignReviewers(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"))
+	if err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, dto.AssignPeerReviewsResponse{Assigned: assigned})
+}
+
+// GetMy godoc
+// @Summary Get my peer reviews
+// @Tags peer-review
+// @Description Returns the reviews assigned to the current user, oldest first
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.PeerReviewResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /peer-reviews/my [get]
+func (h *PeerReviewHandler) GetMy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	reviews, err := h.peerReviewService.GetMyReviews(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch peer reviews")
+		return
+	}
+
+	response.Success(c, mapper.ToPeerReviewList(reviews))
+}
+
+// GetByID godoc
+// @Summary Get peer review
+// @Tags peer-review
+// @Description Returns a review assigned to the current user with the task, the anonymous answer and the rubric
+// @Produce json
+// @Param id path string true "Peer review ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.PeerReviewTaskResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /peer-reviews/{id} [get]
+func (h *PeerReviewHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	review, rubric, err := h.peerReviewService.GetReview(ctx, c.Param("id"), c.GetString("userId"))
+	if err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToPeerReviewTaskResponse(review, rubric))
+}
+
+// Submit godoc
+// @Summary Submit peer review
+// @Tags peer-review
+// @Description Scores every criterion of the rubric for an assigned review. When the last review of an answer is in, the average becomes its peer score and grades it unless a teacher already did
+// @Accept json
+// @Produce json
+// @Param id path string true "Peer review ID"
+// @Param request body dto.SubmitPeerReviewRequest true "Scores and comment"
+// @Success 200 {object} response.SuccessWrapper{data=dto.PeerReviewResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /peer-reviews/{id} [post]
+func (h *PeerReviewHandler) Submit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.SubmitPeerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	points := make(map[string]int, len(req.Scores))
+	for _, s := range req.Scores {
+		if _, dup := points[s.CriterionID]; dup {
+			response.Error(c, http.StatusBadRequest, "Each criterion can only be scored once")
+			return
+		}
+		points[s.CriterionID] = *s.Points
+	}
+
+	review, err := h.peerReviewService.SubmitReview(ctx, c.Param("id"), c.GetString("userId"), points, req.Comment)
+	if err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := mapper.ToPeerReviewResponse(review)
+	resp.ReviewerID = ""
+	response.Success(c, resp)
+}
+
+// GetSubmissionReviews godoc
+// @Summary Get reviews of a submission
+// @Tags peer-review
+// @Description Returns the peer reviews of an answer. Its author sees submitted reviews without reviewer names; teachers who may grade it see all of them
+// @Produce json
+// @Param id path string true "Submission ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.PeerReviewResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /peer-reviews/submissions/{id} [get]
+func (h *PeerReviewHandler) GetSubmissionReviews(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	reviews, err := h.peerReviewService.GetSubmissionReviews(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role"))
+	if err != nil {
+		peerReviewError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToReceivedPeerReviews(reviews))
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakePeerReviewRepo реализует только методы, нужные хендлеру в тестах
+type fakePeerReviewRepo struct {
+	repository.IPeerReviewRepository
+	submissions *fakeSubmissionRepo
+	rubric      *models.Rubric
+	reviews     []models.PeerReview
+}
+
+func (r *fakePeerReviewRepo) FindRubric(ctx context.Context, taskID string) (*models.Rubric, error) {
+	if r.rubric == nil || r.rubric.TaskID != taskID {
+		return nil, nil
+	}
+	return r.rubric, nil
+}
+
+func (r *fakePeerReviewRepo) SaveRubric(ctx context.Context, rubric *models.Rubric) error {
+	for i := range rubric.Criteria {
+		rubric.Criteria[i].ID = "criterion-" + rubric.Criteria[i].Title
+	}
+	r.rubric = rubric
+	return nil
+}
+
+func (r *fakePeerReviewRepo) CountByTask(ctx context.Context, taskID string) (int64, error) {
+	return int64(len(r.reviews)), nil
+}
+
+func (r *fakePeerReviewRepo) FindByID(ctx context.Context, id string) (*models.PeerReview, error) {
+	for _, review := range r.reviews {
+		if review.ID == id {
+			review.Submission, _ = r.submissions.FindByID(ctx, review.SubmissionID)
+			return &review, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePeerReviewRepo) FindByReviewer(ctx context.Context, reviewerID string) ([]models.PeerReview, error) {
+	var out []models.PeerReview
+	for _, review := range r.reviews {
+		if review.ReviewerID == reviewerID {
+			out = append(out, review)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePeerReviewRepo) FindBySubmission(ctx context.Context, submissionID string) ([]models.PeerReview, error) {
+	var out []models.PeerReview
+	for _, review := range r.reviews {
+		if review.SubmissionID == submissionID {
+			review.Reviewer = &models.User{DisplayName: "Peer"}
+			out = append(out, review)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePeerReviewRepo) Submit(ctx context.Context, review *models.PeerReview) (bool, error) {
+	for i := range r.reviews {
+		if r.reviews[i].ID == review.ID && r.reviews[i].Status == models.PeerReviewAssigned {
+			r.reviews[i].Status = review.Status
+			r.reviews[i].Scores = review.Scores
+			r.reviews[i].Comment = review.Comment
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePeerReviewRepo) SavePeerScore(ctx context.Context, submissionID string, score int) error {
+	for i := range r.submissions.submissions {
+		if r.submissions.submissions[i].ID == submissionID {
+			r.submissions.submissions[i].PeerScore = &score
+		}
+	}
+	return nil
+}
+
+func setupPeerReviewRouter(t *testing.T, userID, role string) (*gin.Engine, *fakePeerReviewRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	tasks := &fakeTaskRepo{tasks: []models.Task{
+		{ID: "proof", Title: "Prove it", BodyMD: "Prove the theorem", AuthorID: "author",
+			AnswerType: models.AnswerTypeText, GradingMode: models.GradingModeManual},
+	}}
+	submissions := &fakeSubmissionRepo{submissions: []models.Submission{
+		{ID: "sub-1", TaskID: "proof", UserID: "alice", Answer: "alice's proof", Status: models.SubmissionStatusPendingReview},
+	}, tasks: tasks}
+	repo := &fakePeerReviewRepo{submissions: submissions}
+
+	taskService := service.NewTaskService(tasks, submissions, rdb)
+	grading := service.NewGradingService(submissions, &fakeAssignmentRepo{assignments: map[string]*models.Assignment{}}, &fakeClassroomRepo{}, taskService)
+	h := NewPeerReviewHandler(service.NewPeerReviewService(repo, submissions, tasks, grading))
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", role)
+		c.Next()
+	})
+	r.GET("/tasks/:id/rubric", h.GetRubric)
+	r.PUT("/tasks/:id/rubric", h.SaveRubric)
+	r.GET("/peer-reviews/my", h.GetMy)
+	r.GET("/peer-reviews/submissions/:id", h.GetSubmissionReviews)
+	r.GET("/peer-reviews/:id", h.GetByID)
+	r.POST("/peer-reviews/:id", h.Submit)
+
+	return r, repo
+}
+
+func TestPeerReviewHandler_Rubric(t *testing.T) {
+	router, repo := setupPeerReviewRouter(t, "author", "Teacher")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/proof/rubric", nil))
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/proof/rubric", strings.NewReader(`{"peerReviews":2,"criteria":[]}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/missing/rubric", strings.NewReader(
+		`{"peerReviews":2,"criteria":[{"title":"Logic","maxPoints":5}]}`)))
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/proof/rubric", strings.NewReader(
+		`{"peerReviews":2,"criteria":[{"title":"Logic","maxPoints":5},{"title":"Style","maxPoints":3}]}`)))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"maxPoints":8`)
+	require.NotNil(t, repo.rubric)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/proof/rubric", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"peerReviews":2`)
+
+	other, _ := setupPeerReviewRouter(t, "other", "Teacher")
+	w = httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest("PUT", "/tasks/proof/rubric", strings.NewReader(
+		`{"peerReviews":2,"criteria":[{"title":"Logic","maxPoints":5}]}`)))
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestPeerReviewHandler_ReviewFlow(t *testing.T) {
+	router, repo := setupPeerReviewRouter(t, "bob", "Student")
+	repo.rubric = &models.Rubric{TaskID: "proof", PeerReviews: 1, Criteria: []models.RubricCriterion{
+		{ID: "c1", Title: "Logic", MaxPoints: 5},
+	}}
+	repo.reviews = []models.PeerReview{
+		{ID: "review-1", SubmissionID: "sub-1", ReviewerID: "bob", Status: models.PeerReviewAssigned, AssignedAt: time.Now()},
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/peer-reviews/my", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"review-1"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/peer-reviews/review-1", nil))
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"answer":"alice's proof"`)
+	assert.Contains(t, body, `"taskBodyMd":"Prove the theorem"`)
+	assert.NotContains(t, body, "alice\"", "the author of the answer stays anonymous")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/peer-reviews/review-1", strings.NewReader(
+		`{"scores":[{"criterionId":"c1","points":9}]}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/peer-reviews/review-1", strings.NewReader(
+		`{"scores":[{"criterionId":"c1","points":4},{"criterionId":"c1","points":4}]}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/peer-reviews/review-1", strings.NewReader(
+		`{"scores":[{"criterionId":"c1","points":4}],"comment":"Solid"}`)))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"points":4`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/peer-reviews/review-1", strings.NewReader(
+		`{"scores":[{"criterionId":"c1","points":4}]}`)))
+	assert.Equal(t, 409, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/peer-reviews/missing", nil))
+	assert.Equal(t, 404, w.Code)
+
+	// единственная рецензия сдана — ответ оценён
+	assert.Equal(t, 80, *repo.submissions.submissions[0].PeerScore)
+	assert.Equal(t, models.SubmissionStatusGraded, repo.submissions.submissions[0].Status)
+
+	author, authorRepo := setupPeerReviewRouter(t, "alice", "Student")
+	authorRepo.reviews = repo.reviews
+	w = httptest.NewRecorder()
+	author.ServeHTTP(w, httptest.NewRequest("GET", "/peer-reviews/submissions/sub-1", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"comment":"Solid"`)
+	assert.NotContains(t, w.Body.String(), "bob")
+	assert.NotContains(t, w.Body.String(), "reviewerName")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/peer-reviews/submissions/sub-1", nil))
+	assert.Equal(t, 403, w.Code)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenHandler struct {
+	tokens *service.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenHandler(tokens *service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{tokens: tokens}
+}
+
+func accessTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAccessTokenNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidAccessTokenScope), errors.Is(err, service.ErrInvalidAccessTokenTTL):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrTooManyAccessTokens):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, "Failed to update access tokens")
+	}
+}
+
+// GetTokens godoc
+// @Summary Get personal access tokens
+// @Tags auth
+// @Description Returns the current user's active personal access tokens, newest first. The tokens themselves are not shown, only their prefix
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.AccessTokenResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/tokens [get]
+func (h *PersonalAccessTokenHandler) GetTokens(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tokens, err := h.tokens.List(ctx, c.GetString("userId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch access tokens")
+		return
+	}
+
+	response.Success(c, mapper.ToAccessTokenList(tokens))
+}
+
+// CreateToken godoc
+// @Summary Create personal access token
+// @Tags auth
+// @Description Creates a token for scripts and integrations, used as "Authorization: Bearer pat_...". The read scope allows GET requests, the write scope all others. Tokens never reach admin routes or the account security routes. The token is only shown in this response
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAccessTokenRequest true "Token name, scopes and lifetime"
+// @Success 201 {object} response.SuccessWrapper{data=dto.CreatedAccessTokenResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/tokens [post]
+func (h *PersonalAccessTokenHandler) CreateToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, raw, err := h.tokens.Create(ctx, c.GetString("userId"), req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, dto.CreatedAccessTokenResponse{
+		AccessTokenResponse: mapper.ToAccessTokenResponse(token),
+		Token:               raw,
+	})
+}
+
+// RevokeToken godoc
+// @Summary Revoke personal access token
+// @Tags auth
+// @Description Revokes one of the current user's personal access tokens
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.LogoutResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) RevokeToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.tokens.Revoke(ctx, c.GetString("userId"), c.Param("id")); err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	response.Success(c, dto.LogoutResponse{Message: "Access token revoked"})
+}
+
+// RevokeAllTokens godoc
+// @Summary Revoke all personal access tokens
+// @Tags auth
+// @Description Revokes every personal access token of the current user
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=dto.LogoutResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/tokens [delete]
+func (h *PersonalAccessTokenHandler) RevokeAllTokens(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.tokens.RevokeAll(ctx, c.GetString("userId")); err != nil {
+		accessTokenError(c, err)
+		return
+	}
+
+	response.Success(c, dto.LogoutResponse{Message: "All access tokens revoked"})
+}
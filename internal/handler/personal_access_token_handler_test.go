@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/middleware"
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+type fakeAccessTokenRepoForHandler struct {
+	repository.IPersonalAccessTokenRepository
+	byID map[uuid.UUID]*models.PersonalAccessToken
+}
+
+func (f *fakeAccessTokenRepoForHandler) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	token.ID = uuid.New()
+	f.byID[token.ID] = token
+	return nil
+}
+func (f *fakeAccessTokenRepoForHandler) FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	for _, t := range f.byID {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeAccessTokenRepoForHandler) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+func (f *fakeAccessTokenRepoForHandler) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	t, ok := f.byID[id]
+	if !ok || t.UserID != userID || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	return true, nil
+}
+func (f *fakeAccessTokenRepoForHandler) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	f.byID[id].LastUsedAt = &at
+	return nil
+}
+
+// newTestRouterWithAccessTokens собирает маршруты как в routes.go: управление
+// токенами только из сессии, остальное API доступно и по токену.
+func newTestRouterWithAccessTokens(t *testing.T, role models.UserRole) (*gin.Engine, *service.PersonalAccessTokenService, *service.KeySet, *models.User) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	users := newFakeUserRepoForHandler()
+	user := &models.User{ID: uuid.New(), Email: "teacher@example.com", Role: role}
+	require.NoError(t, users.Create(context.Background(), user))
+
+	svc := service.NewPersonalAccessTokenService(&fakeAccessTokenRepoForHandler{byID: make(map[uuid.UUID]*models.PersonalAccessToken)}, users)
+	keys := newTestKeySet(t)
+	h := NewPersonalAccessTokenHandler(svc)
+
+	r := gin.New()
+	sessions := r.Group("/auth", middleware.AuthMiddleware(keys, svc), middleware.SessionOnlyMiddleware())
+	sessions.POST("/tokens", h.CreateToken)
+	sessions.DELETE("/tokens/:id", h.RevokeToken)
+
+	api := r.Group("/api", middleware.AuthMiddleware(keys, svc))
+	api.GET("/topics", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"userId": c.GetString("userId")}) })
+	api.POST("/topics", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	api.POST("/admin", middleware.RoleMiddleware("Admin"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return r, svc, keys, user
+}
+
+func doWithToken(r *gin.Engine, method, path, token string, body []byte) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPersonalAccessTokenHandler_CreateToken(t *testing.T) {
+	router, _, keys, user := newTestRouterWithAccessTokens(t, models.UserRoleTeacher)
+	access, err := keys.Sign(map[string]interface{}{"userId": user.ID.String(), "role": "Teacher", "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "gradebook sync", "scopes": []string{"read"}, "expiresInDays": 30})
+	w := doWithToken(router, http.MethodPost, "/auth/tokens", access, body)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp struct {
+		Data struct {
+			ID          string   `json:"id"`
+			Token       string   `json:"token"`
+			TokenPrefix string   `json:"tokenPrefix"`
+			Scopes      []string `json:"scopes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Data.Token, resp.Data.TokenPrefix)
+	assert.Equal(t, []string{"read"}, resp.Data.Scopes)
+
+	// неизвестный scope отклоняется при валидации
+	body, _ = json.Marshal(map[string]interface{}{"name": "ci", "scopes": []string{"admin"}, "expiresInDays": 30})
+	w = doWithToken(router, http.MethodPost, "/auth/tokens", access, body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPersonalAccessTokenHandler_Middleware(t *testing.T) {
+	router, svc, _, user := newTestRouterWithAccessTokens(t, models.UserRoleTeacher)
+
+	_, readToken, err := svc.Create(context.Background(), user.ID.String(), "read", []string{"read"}, 30)
+	require.NoError(t, err)
+	_, writeToken, err := svc.Create(context.Background(), user.ID.String(), "write", []string{"write"}, 30)
+	require.NoError(t, err)
+
+	w := doWithToken(router, http.MethodGet, "/api/topics", readToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), user.ID.String())
+
+	// read не даёт менять данные, write не даёт читать
+	w = doWithToken(router, http.MethodPost, "/api/topics", readToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doWithToken(router, http.MethodGet, "/api/topics", writeToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doWithToken(router, http.MethodPost, "/api/topics", writeToken, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// токеном нельзя выпускать новые токены
+	body, _ := json.Marshal(map[string]interface{}{"name": "more", "scopes": []string{"write"}, "expiresInDays": 30})
+	w = doWithToken(router, http.MethodPost, "/auth/tokens", writeToken, body)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = doWithToken(router, http.MethodGet, "/api/topics", "pat_unknown", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPersonalAccessTokenHandler_AdminRoutes(t *testing.T) {
+	router, svc, _, user := newTestRouterWithAccessTokens(t, models.UserRoleAdmin)
+
+	_, token, err := svc.Create(context.Background(), user.ID.String(), "admin", []string{"write"}, 30)
+	require.NoError(t, err)
+
+	// токен не считается сессией с двухфакторной аутентификацией
+	w := doWithToken(router, http.MethodPost, "/api/admin", token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPersonalAccessTokenHandler_RevokeToken(t *testing.T) {
+	router, svc, keys, user := newTestRouterWithAccessTokens(t, models.UserRoleStudent)
+	access, err := keys.Sign(map[string]interface{}{"userId": user.ID.String(), "role": "Student", "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	token, raw, err := svc.Create(context.Background(), user.ID.String(), "script", []string{"read"}, 30)
+	require.NoError(t, err)
+
+	w := doWithToken(router, http.MethodDelete, "/auth/tokens/"+token.ID.String(), access, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doWithToken(router, http.MethodDelete, "/auth/tokens/"+token.ID.String(), access, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = doWithToken(router, http.MethodGet, "/api/topics", raw, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProgressHandler struct {
+	progressService *service.ProgressService
+}
+
+func NewProgressHandler(progressService *service.ProgressService) *ProgressHandler {
+	return &ProgressHandler{progressService: progressService}
+}
+
+// GetTopicProgress godoc
+// @Summary Get topic progress
+// @Tags progress
+// @Description Returns solved/attempted/total counts and mastery for the current user, rolled up through subtopics
+// @Produce json
+// @Param id path string true "Topic ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.TopicProgressResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /topics/{id}/progress [get]
+func (h *ProgressHandler) GetTopicProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetString("userId")
+	topicID := c.Param("id")
+
+	progress, err := h.progressService.GetTopicProgress(ctx, userID, topicID)
+	if err != nil {
+		if err.Error() == "topic not found" {
+			response.Error(c, http.StatusNotFound, "Topic not found")
+		} else {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch progress")
+		}
+		return
+	}
+
+	response.Success(c, mapper.ToTopicProgressResponse(progress))
+}
+
+// GetUserProgress godoc
+// @Summary Get current user progress
+// @Tags progress
+// @Description Returns progress and mastery for every topic for the current user
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.TopicProgressResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/progress [get]
+func (h *ProgressHandler) GetUserProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetString("userId")
+
+	progress, err := h.progressService.GetUserProgress(ctx, userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch progress")
+		return
+	}
+
+	response.Success(c, mapper.ToTopicProgressList(progress))
+}
+
+// GetNextTask godoc
+// @Summary Recommend next task
+// @Tags progress
+// @Description Recommends the next unsolved task based on recent results, task difficulty, topic prerequisites and topic progress, with an explanation of the choice
+// @Produce json
+// @Param topicId query string false "Limit the recommendation to a topic and its subtopics"
+// @Success 200 {object} response.SuccessWrapper{data=dto.TaskRecommendationResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/next-task [get]
+func (h *ProgressHandler) GetNextTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	recommendation, err := h.progressService.RecommendNextTask(ctx, c.GetString("userId"), c.Query("topicId"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to recommend a task")
+		return
+	}
+
+	if recommendation == nil {
+		response.Error(c, http.StatusNotFound, "No task to recommend")
+		return
+	}
+
+	response.Success(c, mapper.ToTaskRecommendationResponse(recommendation))
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+func setupProgressRouter() (*gin.Engine, *fakeTopicRepo, *fakeTaskRepo) {
+	gin.SetMode(gin.TestMode)
+
+	topics := &fakeTopicRepo{}
+	tasks := &fakeTaskRepo{}
+	h := NewProgressHandler(service.NewProgressService(topics, tasks, &fakeSubmissionRepo{}))
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", "user-1")
+		c.Next()
+	}
+	r.GET("/topics/:id/progress", withUser, h.GetTopicProgress)
+	r.GET("/user/progress", withUser, h.GetUserProgress)
+	r.GET("/user/next-task", withUser, h.GetNextTask)
+
+	return r, topics, tasks
+}
+
+func TestProgressHandler_GetTopicProgress(t *testing.T) {
+	router, topics, tasks := setupProgressRouter()
+
+	topics.topics = []models.Topic{{ID: "topic-1", Title: "Algebra"}}
+	tasks.tasks = []models.Task{
+		{ID: "t1", TopicID: "topic-1", Status: models.TaskStatusPublished},
+		{ID: "t2", TopicID: "topic-1", Status: models.TaskStatusPublished},
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/topics/topic-1/progress", nil))
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["total"])
+	assert.Equal(t, float64(0), data["solved"])
+}
+
+func TestProgressHandler_GetTopicProgress_NotFound(t *testing.T) {
+	router, _, _ := setupProgressRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/topics/missing/progress", nil))
+
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestProgressHandler_GetNextTask(t *testing.T) {
+	router, topics, tasks := setupProgressRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/next-task", nil))
+	assert.Equal(t, 404, w.Code)
+
+	topics.topics = []models.Topic{{ID: "topic-1", Title: "Algebra"}}
+	tasks.tasks = []models.Task{
+		{ID: "t1", TopicID: "topic-1", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished, CorrectAnswer: "42"},
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/next-task", nil))
+	assert.Equal(t, 200, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "t1", data["task"].(map[string]interface{})["id"])
+	assert.NotEmpty(t, data["explanation"])
+	assert.NotContains(t, w.Body.String(), "42")
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuizHandler struct {
+	quizService *service.QuizService
+}
+
+func NewQuizHandler(quizService *service.QuizService) *QuizHandler {
+	return &QuizHandler{quizService: quizService}
+}
+
+func quizError(c *gin.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrQuizForbidden),
+		errors.Is(err, service.ErrQuizNotOpen):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrQuizSessionExpired),
+		errors.Is(err, service.ErrQuizSessionClosed):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrQuizTaskNotFound),
+		errors.Is(err, service.ErrQuizNotFound),
+		errors.Is(err, service.ErrQuizSessionNotFound):
+		response.Error(c, http.StatusNotFound, "Not found")
+	case errors.Is(err, service.ErrInvalidQuiz):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(c, fallback, err.Error())
+	}
+}
+
+func quizFromRequest(req *dto.QuizRequest) *models.Quiz {
+	quiz := &models.Quiz{
+		Title:            req.Title,
+		Description:      req.Description,
+		Mode:             req.Mode,
+		DrawCount:        req.DrawCount,
+		TimeLimitSeconds: req.TimeLimitSeconds,
+		ClosesAt:         req.ClosesAt,
+	}
+	if req.OpensAt != nil {
+		quiz.OpensAt = *req.OpensAt
+	}
+	return quiz
+}
+
+// Create godoc
+// @Summary Create quiz
+// @Tags quizzes
+// @Description Creates a timed quiz from a fixed task list or a random draw from a task pool
+// @Accept json
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Param request body dto.QuizRequest true "Quiz payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.QuizResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/quizzes [post]
+func (h *QuizHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quiz := quizFromRequest(&req)
+	quiz.ClassroomID = c.Param("id")
+
+	if err := h.quizService.CreateQuiz(ctx, c.GetString("userId"), c.GetString("role"), quiz, req.TaskIDs); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToQuizResponse(quiz))
+}
+
+// GetByClassroom godoc
+// @Summary Get classroom quizzes
+// @Tags quizzes
+// @Description Returns quizzes of the classroom; students only see opened ones, without tasks
+// @Produce json
+// @Param id path string true "Classroom ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.QuizResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /classrooms/{id}/quizzes [get]
+func (h *QuizHandler) GetByClassroom(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	quizzes, err := h.quizService.GetClassroomQuizzes(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizList(quizzes))
+}
+
+// GetByID godoc
+// @Summary Get quiz by ID
+// @Tags quizzes
+// @Description Returns the quiz; the task list is only shown to classroom teachers
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id} [get]
+func (h *QuizHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	quiz, err := h.quizService.GetQuiz(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizResponse(quiz))
+}
+
+// Update godoc
+// @Summary Update quiz
+// @Tags quizzes
+// @Description Updates quiz settings and tasks; sessions already started keep their drawn tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Param request body dto.QuizRequest true "Quiz payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id} [put]
+func (h *QuizHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quiz := quizFromRequest(&req)
+	quiz.ID = c.Param("id")
+
+	if err := h.quizService.UpdateQuiz(ctx, c.GetString("userId"), c.GetString("role"), quiz, req.TaskIDs); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := h.quizService.GetQuiz(ctx, c.GetString("userId"), c.GetString("role"), quiz.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to fetch updated quiz")
+		return
+	}
+
+	response.Success(c, mapper.ToQuizResponse(updated))
+}
+
+// Delete godoc
+// @Summary Delete quiz
+// @Tags quizzes
+// @Description Deletes the quiz together with all sessions
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id} [delete]
+func (h *QuizHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.quizService.DeleteQuiz(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id")); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
+
+// Release godoc
+// @Summary Release quiz results
+// @Tags quizzes
+// @Description Lets students see scores and correct answers of their finished sessions
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/release [post]
+func (h *QuizHandler) Release(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.quizService.ReleaseResults(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id")); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Results released")
+}
+
+// GetResults godoc
+// @Summary Get quiz results
+// @Tags quizzes
+// @Description Returns all sessions of the quiz with scores; classroom teachers only
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.QuizResultResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/results [get]
+func (h *QuizHandler) GetResults(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sessions, err := h.quizService.GetResults(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizResultList(sessions))
+}
+
+// GetStudentSession godoc
+// @Summary Get student's quiz session
+// @Tags quizzes
+// @Description Returns a student's answers with correctness; classroom teachers only
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Param userId path string true "Student ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizSessionResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/sessions/{userId} [get]
+func (h *QuizHandler) GetStudentSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	session, err := h.quizService.GetStudentSession(ctx, c.GetString("userId"), c.GetString("role"), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizSessionResponse(session, session.IsFinished()))
+}
+
+// Start godoc
+// @Summary Start quiz session
+// @Tags quizzes
+// @Description Starts the caller's timed attempt or returns the existing one
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizSessionResponse}
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/start [post]
+func (h *QuizHandler) Start(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if _, err := h.quizService.StartSession(ctx, c.Param("id"), c.GetString("userId"), c.GetString("role")); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	session, showResults, err := h.quizService.GetMySession(ctx, c.Param("id"), c.GetString("userId"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizSessionResponse(session, showResults))
+}
+
+// GetMySession godoc
+// @Summary Get my quiz session
+// @Tags quizzes
+// @Description Returns the caller's session with saved answers and remaining time; results appear once released
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizSessionResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/session [get]
+func (h *QuizHandler) GetMySession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	session, showResults, err := h.quizService.GetMySession(ctx, c.Param("id"), c.GetString("userId"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizSessionResponse(session, showResults))
+}
+
+// SaveAnswer godoc
+// @Summary Save quiz answer
+// @Tags quizzes
+// @Description Autosaves the answer for a task of the running session
+// @Accept json
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Param taskId path string true "Task ID"
+// @Param request body dto.QuizAnswerRequest true "Answer"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/session/answers/{taskId} [put]
+func (h *QuizHandler) SaveAnswer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.QuizAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.quizService.SaveAnswer(ctx, c.Param("id"), c.GetString("userId"), c.Param("taskId"), req.Answer); err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, "Saved")
+}
+
+// Submit godoc
+// @Summary Submit quiz session
+// @Tags quizzes
+// @Description Finishes the caller's session before the time limit
+// @Produce json
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} response.SuccessWrapper{data=dto.QuizSessionResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /quizzes/{id}/session/submit [post]
+func (h *QuizHandler) Submit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	session, showResults, err := h.quizService.Submit(ctx, c.Param("id"), c.GetString("userId"))
+	if err != nil {
+		quizError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(c, mapper.ToQuizSessionResponse(session, showResults))
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+	"learning-platform/internal/service"
+)
+
+// fakeQuizRepo реализует только методы, нужные хендлеру в тестах
+type fakeQuizRepo struct {
+	repository.IQuizRepository
+	quizzes  map[string]*models.Quiz
+	sessions []*models.QuizSession
+}
+
+func (r *fakeQuizRepo) FindByID(ctx context.Context, id string) (*models.Quiz, error) {
+	q, ok := r.quizzes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return q, nil
+}
+
+func (r *fakeQuizRepo) FindSession(ctx context.Context, quizID, userID string) (*models.QuizSession, error) {
+	for _, s := range r.sessions {
+		if s.QuizID == quizID && s.UserID == userID {
+			cp := *s
+			cp.Quiz = r.quizzes[quizID]
+			cp.Answers = make([]models.QuizAnswer, len(s.Answers))
+			for i, a := range s.Answers {
+				task := *a.Task
+				a.Task = &task
+				cp.Answers[i] = a
+			}
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeQuizRepo) SaveAnswer(ctx context.Context, sessionID, taskID, answer string) error {
+	return nil
+}
+
+func setupQuizRouter(t *testing.T, userID, role string) (*gin.Engine, *fakeQuizRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	quizzes := &fakeQuizRepo{quizzes: map[string]*models.Quiz{
+		"q1": {ID: "q1", ClassroomID: "c1", TimeLimitSeconds: 60, OpensAt: time.Now().Add(-time.Hour)},
+	}}
+	classrooms := &fakeClassroomRepo{members: []models.ClassroomMember{
+		{ClassroomID: "c1", UserID: "teacher-1", Role: models.ClassroomRoleOwner},
+		{ClassroomID: "c1", UserID: "student-1", Role: models.ClassroomRoleStudent},
+	}}
+	submissions := &fakeSubmissionRepo{}
+
+	svc := service.NewQuizService(quizzes, classrooms, service.NewTaskService(&fakeTaskRepo{}, submissions, rdb), rdb)
+	h := NewQuizHandler(svc)
+
+	r := gin.Default()
+	withUser := func(c *gin.Context) {
+		c.Set("userId", userID)
+		c.Set("role", role)
+		c.Next()
+	}
+	r.POST("/quizzes/:id/start", withUser, h.Start)
+	r.GET("/quizzes/:id/session", withUser, h.GetMySession)
+	r.PUT("/quizzes/:id/session/answers/:taskId", withUser, h.SaveAnswer)
+
+	return r, quizzes
+}
+
+func TestQuizHandler_Start_TeacherForbidden(t *testing.T) {
+	router, _ := setupQuizRouter(t, "teacher-1", "Teacher")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/quizzes/q1/start", nil))
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestQuizHandler_SessionHidesResults(t *testing.T) {
+	router, quizzes := setupQuizRouter(t, "student-1", "Student")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/quizzes/q1/session", nil))
+	assert.Equal(t, 404, w.Code)
+
+	submitted := time.Now()
+	quizzes.sessions = append(quizzes.sessions, &models.QuizSession{
+		ID: "s1", QuizID: "q1", UserID: "student-1",
+		Status:    models.QuizSessionSubmitted,
+		ExpiresAt: submitted, SubmittedAt: &submitted,
+		Score: 100, CorrectCount: 1,
+		Answers: []models.QuizAnswer{{TaskID: "t1", Answer: "42", IsCorrect: true,
+			Task: &models.Task{ID: "t1", CorrectAnswer: "42"}}},
+	})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/quizzes/q1/session", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), "correctAnswer")
+	assert.NotContains(t, w.Body.String(), `"score"`)
+
+	// после завершения автосохранение невозможно
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/quizzes/q1/session/answers/t1", bytes.NewBufferString(`{"answer":"1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+
+	quizzes.quizzes["q1"].ResultsReleased = true
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/quizzes/q1/session", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"correctAnswer":"42"`)
+	assert.Contains(t, w.Body.String(), `"score":100`)
+}
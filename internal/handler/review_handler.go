@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// GetQueue godoc
+// @Summary Get review queue
+// @Tags review
+// @Description Returns previously solved tasks scheduled for review by the end of today, most overdue first. Submitting a task through the regular submit endpoint updates its schedule
+// @Produce json
+// @Param limit query int false "Maximum number of tasks (default 20, max 100)"
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.ReviewItemResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/review-queue [get]
+func (h *ReviewHandler) GetQueue(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := service.DefaultReviewQueueSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > service.MaxReviewQueueSize {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	queue, err := h.reviewService.GetQueue(ctx, c.GetString("userId"), limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch review queue")
+		return
+	}
+
+	response.Success(c, mapper.ToReviewQueue(queue))
+}
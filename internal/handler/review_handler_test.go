@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+)
+
+type fakeReviewRepo struct {
+	due       []models.ReviewSchedule
+	lastLimit int
+}
+
+func (r *fakeReviewRepo) Find(ctx context.Context, userID, taskID string) (*models.ReviewSchedule, error) {
+	return nil, nil
+}
+
+func (r *fakeReviewRepo) Save(ctx context.Context, schedule *models.ReviewSchedule) error {
+	return nil
+}
+
+func (r *fakeReviewRepo) FindDue(ctx context.Context, userID string, before time.Time, limit int) ([]models.ReviewSchedule, error) {
+	r.lastLimit = limit
+	return r.due, nil
+}
+
+func TestReviewHandler_GetQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeReviewRepo{due: []models.ReviewSchedule{{
+		UserID: "user-1", TaskID: "t1", IntervalDays: 6, DueAt: time.Now(),
+		Task: &models.Task{ID: "t1", Title: "Fractions"},
+	}}}
+	h := NewReviewHandler(service.NewReviewService(repo))
+
+	r := gin.Default()
+	r.GET("/user/review-queue", func(c *gin.Context) {
+		c.Set("userId", "user-1")
+		c.Next()
+	}, h.GetQueue)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user/review-queue?limit=5", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Fractions"`)
+	assert.Equal(t, 5, repo.lastLimit)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user/review-queue?limit=abc", nil))
+	assert.Equal(t, 400, w.Code)
+}
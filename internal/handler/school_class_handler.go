@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+
+	"learning-platform/internal/dto"
+	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
+	"learning-platform/internal/response"
+	"learning-platform/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchoolClassHandler struct {
+	schoolClassService *service.SchoolClassService
+}
+
+func NewSchoolClassHandler(schoolClassService *service.SchoolClassService) *SchoolClassHandler {
+	return &SchoolClassHandler{schoolClassService: schoolClassService}
+}
+
+// GetAll godoc
+// @Summary Get all school classes
+// @Tags school-classes
+// @Description Returns all configured school classes ordered by education system and sort order
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=[]dto.SchoolClassResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /school-classes [get]
+func (h *SchoolClassHandler) GetAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	classes, err := h.schoolClassService.GetAll(ctx)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch school classes")
+		return
+	}
+
+	response.Success(c, mapper.ToSchoolClassList(classes))
+}
+
+// Create godoc
+// @Summary Create school class
+// @Tags school-classes
+// @Description Creates a new school class (grade level)
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSchoolClassRequest true "School class payload"
+// @Success 201 {object} response.SuccessWrapper{data=dto.SchoolClassResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /school-classes [post]
+func (h *SchoolClassHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.CreateSchoolClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	class := &models.SchoolClass{
+		Code:            req.Code,
+		Title:           req.Title,
+		EducationSystem: req.EducationSystem,
+		SortOrder:       req.SortOrder,
+		IsActive:        true,
+	}
+	if class.EducationSystem == "" {
+		class.EducationSystem = "SCHOOL"
+	}
+	if req.IsActive != nil {
+		class.IsActive = *req.IsActive
+	}
+
+	if err := h.schoolClassService.Create(ctx, class); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToSchoolClassResponse(class))
+}
+
+// Update godoc
+// @Summary Update school class
+// @Tags school-classes
+// @Description Updates school class by ID; renaming the code cascades to topics
+// @Accept json
+// @Produce json
+// @Param id path string true "School class ID"
+// @Param request body dto.UpdateSchoolClassRequest true "Update payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.SchoolClassResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /school-classes/{id} [put]
+func (h *SchoolClassHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	var req dto.UpdateSchoolClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	existing, err := h.schoolClassService.GetByID(ctx, id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "School class not found")
+		return
+	}
+
+	existing.Code = req.Code
+	existing.Title = req.Title
+	existing.SortOrder = req.SortOrder
+	if req.EducationSystem != "" {
+		existing.EducationSystem = req.EducationSystem
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	if err := h.schoolClassService.Update(ctx, existing); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, mapper.ToSchoolClassResponse(existing))
+}
+
+// Delete godoc
+// @Summary Delete school class
+// @Tags school-classes
+// @Description Deletes school class by ID; fails while topics still reference it
+// @Produce json
+// @Param id path string true "School class ID"
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /school-classes/{id} [delete]
+func (h *SchoolClassHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	if _, err := h.schoolClassService.GetByID(ctx, id); err != nil {
+		response.Error(c, http.StatusNotFound, "School class not found")
+		return
+	}
+
+	if err := h.schoolClassService.Delete(ctx, id); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, "Deleted")
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeSchoolClassRepo struct {
+	classes []models.SchoolClass
+}
+
+func newFakeSchoolClassRepoForHandler(codes ...string) *fakeSchoolClassRepo {
+	repo := &fakeSchoolClassRepo{}
+	for _, code := range codes {
+		repo.classes = append(repo.classes, models.SchoolClass{ID: "class-" + code, Code: code, Title: code, IsActive: true})
+	}
+	return repo
+}
+
+func (r *fakeSchoolClassRepo) Create(ctx context.Context, class *models.SchoolClass) error {
+	class.ID = "class-" + class.Code
+	r.classes = append(r.classes, *class)
+	return nil
+}
+
+func (r *fakeSchoolClassRepo) FindAll(ctx context.Context) ([]models.SchoolClass, error) {
+	return r.classes, nil
+}
+
+func (r *fakeSchoolClassRepo) FindByID(ctx context.Context, id string) (*models.SchoolClass, error) {
+	for _, c := range r.classes {
+		if c.ID == id {
+			cp := c
+			return &cp, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func (r *fakeSchoolClassRepo) FindByCode(ctx context.Context, code string) (*models.SchoolClass, error) {
+	for _, c := range r.classes {
+		if c.Code == code {
+			cp := c
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSchoolClassRepo) Update(ctx context.Context, class *models.SchoolClass) error {
+	for i, c := range r.classes {
+		if c.ID == class.ID {
+			r.classes[i] = *class
+		}
+	}
+	return nil
+}
+
+func (r *fakeSchoolClassRepo) Delete(ctx context.Context, id string) error {
+	for i, c := range r.classes {
+		if c.ID == id {
+			r.classes = append(r.classes[:i], r.classes[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func setupSchoolClassRouter(t *testing.T) (*gin.Engine, *fakeSchoolClassRepo) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	repo := newFakeSchoolClassRepoForHandler("SEVEN")
+	h := NewSchoolClassHandler(service.NewSchoolClassService(repo, rdb))
+
+	r := gin.Default()
+	r.GET("/school-classes", h.GetAll)
+	r.POST("/school-classes", h.Create)
+	r.PUT("/school-classes/:id", h.Update)
+
+	return r, repo
+}
+
+func TestSchoolClassHandler_Create(t *testing.T) {
+	router, repo := setupSchoolClassRouter(t)
+
+	body := `{"code": "grade_1", "title": "1st grade", "sortOrder": 1}`
+	req := httptest.NewRequest("POST", "/school-classes", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 201, w.Code)
+	require.Len(t, repo.classes, 2)
+	assert.Equal(t, "GRADE_1", repo.classes[1].Code)
+	assert.Equal(t, "SCHOOL", repo.classes[1].EducationSystem)
+	assert.True(t, repo.classes[1].IsActive)
+}
+
+func TestSchoolClassHandler_GetAll(t *testing.T) {
+	router, _ := setupSchoolClassRouter(t)
+
+	req := httptest.NewRequest("GET", "/school-classes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	data, ok := resp["data"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, data, 1)
+}
+
+func TestSchoolClassHandler_Update_Deactivate(t *testing.T) {
+	router, repo := setupSchoolClassRouter(t)
+
+	body := `{"code": "SEVEN", "title": "Grade 7", "sortOrder": 7, "isActive": false}`
+	req := httptest.NewRequest("PUT", "/school-classes/class-SEVEN", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.False(t, repo.classes[0].IsActive)
+	assert.Equal(t, "Grade 7", repo.classes[0].Title)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"learning-platform/internal/dto"
@@ -25,27 +26,35 @@ func NewTaskHandler(taskService *service.TaskService, s3 *service.S3Service) *Ta
 // GetAllTasks godoc
 // @Summary Get all published tasks
 // @Tags tasks
-// @Description Returns list of all published tasks
+// @Description Returns list of all published tasks, optionally filtered by school class. correctAnswer and officialSolution are only shown to the author and admins
 // @Produce json
+// @Param schoolClass query string false "School class code"
 // @Success 200 {object} response.SuccessWrapper{data=[]dto.TaskResponse}
 // @Failure 500 {object} response.ErrorResponse
 // @Router /tasks [get]
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	tasks, err := h.taskService.GetAllTasks(ctx)
+	var tasks []models.Task
+	var err error
+
+	if schoolClass := c.Query("schoolClass"); schoolClass != "" {
+		tasks, err = h.taskService.GetTasksBySchoolClass(ctx, schoolClass)
+	} else {
+		tasks, err = h.taskService.GetAllTasks(ctx)
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "failed to fetch tasks")
 		return
 	}
 
-	response.Success(c, mapper.ToTaskList(tasks))
+	response.Success(c, mapper.ToTaskListFor(tasks, c.GetString("userId"), c.GetString("role")))
 }
 
 // GetDraftTasks godoc
 // @Summary Get all draft tasks
 // @Tags tasks
-// @Description Returns tasks with status DRAFT
+// @Description Returns tasks with status DRAFT. correctAnswer and officialSolution are only shown to the author and admins
 // @Produce json
 // @Success 200 {object} response.SuccessWrapper{data=[]dto.TaskResponse}
 // @Failure 500 {object} response.ErrorResponse
@@ -60,7 +69,7 @@ func (h *TaskHandler) GetDraftTasks(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, mapper.ToTaskList(tasks))
+	response.Success(c, mapper.ToTaskListFor(tasks, c.GetString("userId"), c.GetString("role")))
 }
 
 // PublishTask godoc
@@ -84,7 +93,7 @@ func (h *TaskHandler) PublishTask(c *gin.Context) {
         return
     }
 
-    response.Success(c, mapper.ToTaskResponse(t))
+    response.Success(c, mapper.ToTaskResponseFor(t, c.GetString("userId"), c.GetString("role")))
 }
 
 
@@ -102,6 +111,7 @@ func (h *TaskHandler) PublishTask(c *gin.Context) {
 // @Param officialSolution formData string true "Solution"
 // @Param correctAnswer formData string true "Correct answer"
 // @Param answerType formData string true "Answer type"
+// @Param gradingMode formData string false "AUTO (default) or MANUAL; MANUAL answers are graded by a teacher and require the TEXT answer type"
 // @Param imageUrl formData file false "Task image"
 // @Success 201 {object} response.SuccessWrapper{data=dto.TaskResponse}
 // @Failure 400 {object} response.ErrorResponse
@@ -141,21 +151,26 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		OfficialSolution: req.OfficialSolution,
 		CorrectAnswer:    req.CorrectAnswer,
 		AnswerType:       req.AnswerType,
+		GradingMode:      req.GradingMode,
 		ImageURL:         imageURL,
 	}
 
 	if err := h.taskService.CreateTask(ctx, task); err != nil {
+		if errors.Is(err, service.ErrInvalidGradingMode) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to create task")
 		return
 	}
 
-	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToTaskResponse(task))
+	response.SuccessWithStatus(c, http.StatusCreated, mapper.ToTaskResponseFor(task, c.GetString("userId"), c.GetString("role")))
 }
 
 // GetTask godoc
 // @Summary Get task by ID
 // @Tags tasks
-// @Description Returns a single task
+// @Description Returns a single task. correctAnswer and officialSolution are only shown to the author and admins
 // @Produce json
 // @Param id path string true "Task ID"
 // @Success 200 {object} response.SuccessWrapper{data=dto.TaskResponse}
@@ -172,13 +187,13 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, mapper.ToTaskResponse(task))
+	response.Success(c, mapper.ToTaskResponseFor(task, c.GetString("userId"), c.GetString("role")))
 }
 
 // GetTasksByTopic godoc
 // @Summary Get tasks by topic
 // @Tags tasks
-// @Description Returns all tasks belonging to topic
+// @Description Returns all tasks belonging to topic. correctAnswer and officialSolution are only shown to the author and admins
 // @Produce json
 // @Param topicId path string true "Topic ID"
 // @Success 200 {object} response.SuccessWrapper{data=[]dto.TaskResponse}
@@ -195,7 +210,7 @@ func (h *TaskHandler) GetTasksByTopic(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, mapper.ToTaskList(tasks))
+	response.Success(c, mapper.ToTaskListFor(tasks, c.GetString("userId"), c.GetString("role")))
 }
 
 // UpdateTask godoc
@@ -213,6 +228,7 @@ func (h *TaskHandler) GetTasksByTopic(c *gin.Context) {
 // @Param officialSolution formData string true "Solution"
 // @Param correctAnswer formData string true "Correct answer"
 // @Param answerType formData string true "Answer type"
+// @Param gradingMode formData string false "AUTO (default) or MANUAL; MANUAL answers are graded by a teacher and require the TEXT answer type"
 // @Param imageUrl formData file false "Task image"
 // @Success 200 {object} response.SuccessWrapper{data=dto.TaskResponse}
 // @Failure 400 {object} response.ErrorResponse
@@ -241,6 +257,11 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	
 	imageURL := existing.ImageURL
 
+	gradingMode := req.GradingMode
+	if gradingMode == "" {
+		gradingMode = existing.GradingMode
+	}
+
     file, err := c.FormFile("imageUrl")
     if err == nil && file != nil {
         url, uploadErr := h.s3.UploadFile(ctx, file)
@@ -262,10 +283,15 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
         OfficialSolution: req.OfficialSolution,
         CorrectAnswer:    req.CorrectAnswer,
         AnswerType:       req.AnswerType,
+        GradingMode:      gradingMode,
         ImageURL:         imageURL,
     }
 
     if err := h.taskService.UpdateTask(ctx, updated); err != nil {
+        if errors.Is(err, service.ErrInvalidGradingMode) {
+            response.Error(c, http.StatusBadRequest, err.Error())
+            return
+        }
         response.Error(c, http.StatusInternalServerError, "Failed to update task")
         return
     }
@@ -277,7 +303,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 	
-    response.Success(c, mapper.ToTaskResponse(finalTask))
+    response.Success(c, mapper.ToTaskResponseFor(finalTask, c.GetString("userId"), c.GetString("role")))
 }
 
 
@@ -331,13 +357,13 @@ func (h *TaskHandler) GetMyTasks(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, mapper.ToTaskList(tasks))
+	response.Success(c, mapper.ToTaskListFor(tasks, c.GetString("userId"), c.GetString("role")))
 }
 
 // SubmitTaskAnswer godoc
 // @Summary Submit answer for a task
 // @Tags tasks
-// @Description Check if user's answer is correct
+// @Description Check if user's answer is correct. Answers to MANUAL tasks are queued for a teacher with status PENDING_REVIEW; the grade shows up in the task's submissions
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
@@ -359,7 +385,13 @@ func (h *TaskHandler) SubmitTaskAnswer(c *gin.Context) {
         return
     }
 
-    isCorrect, err := h.taskService.SubmitAnswer(ctx, id, req.Answer)
+    submission := &models.Submission{
+        TaskID: id,
+        UserID: c.GetString("userId"),
+        Answer: req.Answer,
+    }
+
+    err := h.taskService.Submit(ctx, submission)
     if err != nil {
         if err.Error() == "record not found" {
             response.Error(c, http.StatusNotFound, "Task not found")
@@ -370,6 +402,8 @@ func (h *TaskHandler) SubmitTaskAnswer(c *gin.Context) {
     }
 
     response.Success(c, dto.TaskSubmitResponse{
-        Correct: isCorrect,
+        SubmissionID: submission.ID,
+        Correct:      submission.IsCorrect,
+        Status:       string(submission.Status),
     })
 }
\ No newline at end of file
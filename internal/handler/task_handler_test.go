@@ -9,9 +9,12 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
+	"learning-platform/internal/dto"
 	"learning-platform/internal/models"
 	"learning-platform/internal/service"
 
@@ -38,7 +41,12 @@ func (r *fakeTaskRepo) Create(ctx context.Context, t *models.Task) error {
 }
 
 func (r *fakeTaskRepo) GetByID(ctx context.Context, id string) (*models.Task, error) {
-	return nil, nil
+	for i := range r.tasks {
+		if r.tasks[i].ID == id {
+			return &r.tasks[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
 }
 
 func (r *fakeTaskRepo) UpdateStatus(ctx context.Context, id string, s models.TaskStatus) error {
@@ -52,10 +60,107 @@ func (r *fakeTaskRepo) GetByTopic(ctx context.Context, topicID string) ([]models
 	return nil, nil
 }
 
+func (r *fakeTaskRepo) GetBySchoolClass(ctx context.Context, schoolClass string) ([]models.Task, error) {
+	var out []models.Task
+	for _, t := range r.tasks {
+		if t.Topic != nil && t.Topic.SchoolClass == schoolClass && t.Status == models.TaskStatusPublished {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
 func (r *fakeTaskRepo) GetByAuthor(ctx context.Context, authorID string) ([]models.Task, error) {
 	return nil, nil
 }
 
+type fakeSubmissionRepo struct {
+	submissions []models.Submission
+	// tasks, when set, is used to load Task in FindByID
+	tasks *fakeTaskRepo
+}
+
+func (r *fakeSubmissionRepo) Create(ctx context.Context, s *models.Submission) error {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	r.submissions = append(r.submissions, *s)
+	return nil
+}
+
+func (r *fakeSubmissionRepo) GetStatsByUser(ctx context.Context, userID string) ([]models.TaskAttemptStats, error) {
+	return nil, nil
+}
+
+func (r *fakeSubmissionRepo) GetAssignmentStats(ctx context.Context, assignmentIDs []string, userID string) ([]models.AssignmentTaskStats, error) {
+	return nil, nil
+}
+
+func (r *fakeSubmissionRepo) GetRecentByUser(ctx context.Context, userID string, limit int) ([]models.Submission, error) {
+	return nil, nil
+}
+
+func (r *fakeSubmissionRepo) CountCorrect(ctx context.Context, userID, taskID string) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeSubmissionRepo) GetFirstSolves(ctx context.Context, userID string) ([]models.SolvedTask, error) {
+	return nil, nil
+}
+
+func (r *fakeSubmissionRepo) FindByID(ctx context.Context, id string) (*models.Submission, error) {
+	for i := range r.submissions {
+		if r.submissions[i].ID == id {
+			s := r.submissions[i]
+			if r.tasks != nil {
+				s.Task, _ = r.tasks.GetByID(ctx, s.TaskID)
+			}
+			return &s, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSubmissionRepo) FindByUserAndTask(ctx context.Context, userID, taskID string) ([]models.Submission, error) {
+	var out []models.Submission
+	for i := len(r.submissions) - 1; i >= 0; i-- {
+		if r.submissions[i].UserID == userID && r.submissions[i].TaskID == taskID {
+			out = append(out, r.submissions[i])
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeSubmissionRepo) FindPendingReview(ctx context.Context, graderID, taskID string) ([]models.Submission, error) {
+	var out []models.Submission
+	for _, s := range r.submissions {
+		if s.Status == models.SubmissionStatusPendingReview && (taskID == "" || s.TaskID == taskID) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeSubmissionRepo) Grade(ctx context.Context, s *models.Submission) (bool, error) {
+	for i := range r.submissions {
+		if r.submissions[i].ID == s.ID && r.submissions[i].Status == models.SubmissionStatusPendingReview {
+			r.submissions[i] = *s
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeSubmissionRepo) Regrade(ctx context.Context, s *models.Submission) error {
+	for i := range r.submissions {
+		if r.submissions[i].ID == s.ID {
+			r.submissions[i] = *s
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
 func setupTaskRouter(t *testing.T) (*gin.Engine, *fakeTaskRepo) {
 	gin.SetMode(gin.TestMode)
 
@@ -63,7 +168,7 @@ func setupTaskRouter(t *testing.T) (*gin.Engine, *fakeTaskRepo) {
 	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
 
 	repo := &fakeTaskRepo{}
-	taskService := service.NewTaskService(repo, rdb)
+	taskService := service.NewTaskService(repo, &fakeSubmissionRepo{}, rdb)
 
 	s3 := &service.S3Service{}
 
@@ -126,3 +231,53 @@ func TestTaskHandler_GetAllTasks(t *testing.T) {
 	require.True(t, ok)
 	assert.Len(t, data, 2)
 }
+
+func TestTaskHandler_GetTask_HidesAnswers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	repo := &fakeTaskRepo{tasks: []models.Task{{
+		ID:               "task-1",
+		Title:            "Quiz question",
+		AuthorID:         "teacher-1",
+		CorrectAnswer:    "42",
+		OfficialSolution: "six times seven",
+	}}}
+	h := NewTaskHandler(service.NewTaskService(repo, &fakeSubmissionRepo{}, redis.NewClient(&redis.Options{Addr: mr.Addr()})), &service.S3Service{})
+
+	get := func(userID, role string) dto.TaskResponse {
+		router := gin.New()
+		router.GET("/tasks/:id", func(c *gin.Context) {
+			c.Set("userId", userID)
+			c.Set("role", role)
+			c.Next()
+		}, h.GetTask)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/task-1", nil))
+		require.Equal(t, 200, w.Code)
+
+		var resp struct {
+			Data dto.TaskResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	// ученик не видит ни ответ, ни решение
+	task := get("student-1", "Student")
+	assert.Equal(t, "Quiz question", task.Title)
+	assert.Empty(t, task.CorrectAnswer)
+	assert.Empty(t, task.OfficialSolution)
+
+	// как и учитель, который задачу не писал
+	task = get("teacher-2", "Teacher")
+	assert.Empty(t, task.CorrectAnswer)
+
+	task = get("teacher-1", "Teacher")
+	assert.Equal(t, "42", task.CorrectAnswer)
+	assert.Equal(t, "six times seven", task.OfficialSolution)
+
+	task = get("admin-1", "Admin")
+	assert.Equal(t, "42", task.CorrectAnswer)
+}
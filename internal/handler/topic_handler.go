@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"learning-platform/internal/models"
@@ -20,6 +21,14 @@ func NewTopicHandler(topicService *service.TopicService) *TopicHandler {
 	return &TopicHandler{topicService: topicService}
 }
 
+func topicPrerequisites(ids []string) []models.TopicPrerequisite {
+	prerequisites := make([]models.TopicPrerequisite, 0, len(ids))
+	for _, id := range ids {
+		prerequisites = append(prerequisites, models.TopicPrerequisite{PrerequisiteID: id})
+	}
+	return prerequisites
+}
+
 // Create godoc
 // @Summary Create topic
 // @Tags topics
@@ -48,8 +57,13 @@ func (h *TopicHandler) Create(c *gin.Context) {
 		ParentID:    req.ParentID,
 		SchoolClass: req.SchoolClass,
 	}
+	topic.Prerequisites = topicPrerequisites(req.PrerequisiteIDs)
 
 	if err := h.topicService.CreateTopic(ctx, topic); err != nil {
+		if errors.Is(err, service.ErrInvalidSchoolClass) || errors.Is(err, service.ErrInvalidPrerequisites) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -60,15 +74,23 @@ func (h *TopicHandler) Create(c *gin.Context) {
 // GetAll godoc
 // @Summary Get all topics
 // @Tags topics
-// @Description Returns all topics
+// @Description Returns all topics, optionally filtered by school class
 // @Produce json
+// @Param schoolClass query string false "School class code"
 // @Success 200 {object} response.SuccessWrapper{data=[]dto.TopicResponse}
 // @Failure 500 {object} response.ErrorResponse
 // @Router /topics [get]
 func (h *TopicHandler) GetAll(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	topics, err := h.topicService.GetAllTopics(ctx)
+	var topics []models.Topic
+	var err error
+
+	if schoolClass := c.Query("schoolClass"); schoolClass != "" {
+		topics, err = h.topicService.GetTopicsBySchoolClass(ctx, schoolClass)
+	} else {
+		topics, err = h.topicService.GetAllTopics(ctx)
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -131,8 +153,15 @@ func (h *TopicHandler) Update(c *gin.Context) {
 		ParentID:    req.ParentID,
 		SchoolClass: req.SchoolClass,
 	}
+	if req.PrerequisiteIDs != nil {
+		topic.Prerequisites = topicPrerequisites(req.PrerequisiteIDs)
+	}
 
 	if err := h.topicService.UpdateTopic(ctx, &topic); err != nil {
+		if errors.Is(err, service.ErrInvalidSchoolClass) || errors.Is(err, service.ErrInvalidPrerequisites) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
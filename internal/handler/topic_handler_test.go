@@ -34,6 +34,16 @@ func (r *fakeTopicRepo) FindAll(ctx context.Context) ([]models.Topic, error) {
 	return r.topics, nil
 }
 
+func (r *fakeTopicRepo) FindBySchoolClass(ctx context.Context, schoolClass string) ([]models.Topic, error) {
+	var out []models.Topic
+	for _, t := range r.topics {
+		if t.SchoolClass == schoolClass {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
 func (r *fakeTopicRepo) FindByID(ctx context.Context, id string) (*models.Topic, error) {
 	for _, t := range r.topics {
 		if t.ID == id {
@@ -73,7 +83,8 @@ func setupTopicRouter(t *testing.T) (*gin.Engine, *fakeTopicRepo) {
 	})
 
 	repo := &fakeTopicRepo{}
-	svc := service.NewTopicService(repo, rdb)
+	classes := newFakeSchoolClassRepoForHandler("GRADE_5", "GRADE_6", "GRADE_7", "GRADE_8")
+	svc := service.NewTopicService(repo, classes, rdb)
 	h := NewTopicHandler(svc)
 
 	r := gin.Default()
@@ -130,6 +141,48 @@ func TestTopicHandler_GetAll(t *testing.T) {
 	assert.Len(t, data, 2)
 }
 
+func TestTopicHandler_Create_InvalidSchoolClass(t *testing.T) {
+	router, repo := setupTopicRouter(t)
+
+	body := `{
+		"title": "Algebra",
+		"slug": "algebra",
+		"schoolClass": "GRADE_42"
+	}`
+
+	req := httptest.NewRequest("POST", "/topics", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	assert.Len(t, repo.topics, 0)
+}
+
+func TestTopicHandler_GetAll_FilterBySchoolClass(t *testing.T) {
+	router, repo := setupTopicRouter(t)
+
+	repo.topics = []models.Topic{
+		{ID: "1", Title: "T1", Slug: "t1", SchoolClass: "GRADE_5"},
+		{ID: "2", Title: "T2", Slug: "t2", SchoolClass: "GRADE_6"},
+	}
+
+	req := httptest.NewRequest("GET", "/topics?schoolClass=GRADE_6", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	data, ok := resp["data"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, data, 1)
+}
+
 func TestTopicHandler_GetByID(t *testing.T) {
 	router, repo := setupTopicRouter(t)
 
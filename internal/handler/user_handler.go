@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,23 +9,33 @@ import (
 
 	"learning-platform/internal/dto"
 	"learning-platform/internal/mapper"
+	"learning-platform/internal/models"
 	"learning-platform/internal/response"
 	"learning-platform/internal/service"
 )
 
 type UserHandler struct {
-	userService *service.UserService
-	s3          *service.S3Service
+	userService         *service.UserService
+	classroomService    *service.ClassroomService
+	achievementService  *service.AchievementService
+	gamificationService *service.GamificationService
+	s3                  *service.S3Service
 }
 
-func NewUserHandler(userService *service.UserService, s3 *service.S3Service) *UserHandler {
-	return &UserHandler{userService: userService, s3: s3}
+func NewUserHandler(userService *service.UserService, classroomService *service.ClassroomService, achievementService *service.AchievementService, gamificationService *service.GamificationService, s3 *service.S3Service) *UserHandler {
+	return &UserHandler{
+		userService:         userService,
+		classroomService:    classroomService,
+		achievementService:  achievementService,
+		gamificationService: gamificationService,
+		s3:                  s3,
+	}
 }
 
 // GetAllUsers godoc
 // @Summary Get all users
 // @Tags users
-// @Description Returns list of all users
+// @Description Returns list of all users; teachers only get students of their classrooms
 // @Produce json
 // @Success 200 {object} response.SuccessWrapper{data=[]dto.UserResponse}
 // @Failure 500 {object} response.ErrorResponse
@@ -33,7 +44,13 @@ func NewUserHandler(userService *service.UserService, s3 *service.S3Service) *Us
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	users, err := h.userService.GetAllUsers(ctx)
+	var users []models.User
+	var err error
+	if c.GetString("role") == string(models.UserRoleTeacher) {
+		users, err = h.classroomService.GetVisibleStudents(ctx, c.GetString("userId"))
+	} else {
+		users, err = h.userService.GetAllUsers(ctx)
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to fetch users")
 		return
@@ -46,10 +63,11 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // GetProfile godoc
 // @Summary Get current user profile
 // @Tags users
-// @Description Returns profile of the currently authenticated user
+// @Description Returns profile of the currently authenticated user with XP, daily streak and earned badges
 // @Produce json
-// @Success 200 {object} response.SuccessWrapper{data=dto.UserResponse}
+// @Success 200 {object} response.SuccessWrapper{data=dto.ProfileResponse}
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
 // @Router /user/profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
@@ -62,16 +80,88 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	stats, err := h.gamificationService.GetStats(ctx, user)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch stats")
+		return
+	}
+
+	badges, err := h.achievementService.GetUserAchievements(ctx, userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch achievements")
+		return
+	}
+
+	response.Success(c, mapper.ToProfileResponse(user, stats, badges))
+}
+
+// UpdateTimezone godoc
+// @Summary Update timezone
+// @Tags users
+// @Description Sets the IANA timezone that decides when the user's day starts for the daily streak
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateTimezoneRequest true "Timezone payload"
+// @Success 200 {object} response.SuccessWrapper{data=dto.UserResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/timezone [put]
+func (h *UserHandler) UpdateTimezone(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.UpdateTimezoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.SetTimezone(ctx, c.GetString("userId"), req.Timezone)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimezone) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	response.Success(c, mapper.ToUserResponse(user))
 }
 
+// GetStreakCalendar godoc
+// @Summary Get streak calendar
+// @Tags users
+// @Description Returns the days of a month that count towards the streak, in the user's timezone. FROZEN days were covered by a streak freeze
+// @Produce json
+// @Param month query string false "Month as YYYY-MM (current month by default)"
+// @Success 200 {object} response.SuccessWrapper{data=dto.StreakCalendarResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/streak/calendar [get]
+func (h *UserHandler) GetStreakCalendar(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	calendar, err := h.gamificationService.GetCalendar(ctx, c.GetString("userId"), c.Query("month"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCalendarMonth) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch streak calendar")
+		return
+	}
+
+	response.Success(c, mapper.ToStreakCalendarResponse(calendar))
+}
+
 // UpdateProfile godoc
 // @Summary Update user profile
 // @Tags users
-// @Description Updates profile fields and optionally uploads avatar
+// @Description Updates profile fields and optionally uploads avatar. The email is changed with POST /user/email
 // @Accept multipart/form-data
 // @Produce json
-// @Param email formData string false "Updated email"
 // @Param displayName formData string false "Updated display name"
 // @Param avatar formData file false "Avatar image"
 // @Success 200 {object} response.SuccessWrapper{data=dto.UserResponse}
@@ -101,7 +191,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		avatarURL = &url
 	}
 
-	updatedProfile, err := h.userService.Update(ctx, userID, req.Email, req.DisplayName, avatarURL)
+	updatedProfile, err := h.userService.Update(ctx, userID, req.DisplayName, avatarURL)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -110,6 +200,130 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	response.Success(c, mapper.ToUserResponse(updatedProfile))
 }
 
+func emailChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmailUnchanged), errors.Is(err, service.ErrInvalidEmailChangeCode):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNoPendingEmailChange):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrEmailTaken):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		authError(c, err, http.StatusInternalServerError, "Failed to change email")
+	}
+}
+
+// RequestEmailChange godoc
+// @Summary Request email change
+// @Tags users
+// @Description Emails a 6 digit code to the new address and a notice to the current one. The email only changes once the code is confirmed within 15 minutes; a new request replaces the pending one. Codes can be requested at most once a minute per address
+// @Accept json
+// @Produce json
+// @Param request body dto.RequestEmailChangeRequest true "New email"
+// @Success 202 {object} response.SuccessWrapper{data=dto.EmailChangeResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/email [post]
+func (h *UserHandler) RequestEmailChange(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.RequestEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	change, err := h.userService.RequestEmailChange(ctx, c.GetString("userId"), req.NewEmail)
+	if err != nil {
+		emailChangeError(c, err)
+		return
+	}
+
+	response.SuccessWithStatus(c, http.StatusAccepted, mapper.ToEmailChangeResponse(change))
+}
+
+// GetEmailChange godoc
+// @Summary Get pending email change
+// @Tags users
+// @Description Returns the email change that waits for confirmation
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=dto.EmailChangeResponse}
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/email [get]
+func (h *UserHandler) GetEmailChange(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	change, err := h.userService.GetPendingEmailChange(ctx, c.GetString("userId"))
+	if err != nil {
+		emailChangeError(c, err)
+		return
+	}
+	if change == nil {
+		emailChangeError(c, service.ErrNoPendingEmailChange)
+		return
+	}
+
+	response.Success(c, mapper.ToEmailChangeResponse(change))
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Tags users
+// @Description Switches to the new email with the code sent there. After 5 wrong codes the change has to be requested again
+// @Accept json
+// @Produce json
+// @Param request body dto.ConfirmEmailChangeRequest true "Code from the new address"
+// @Success 200 {object} response.SuccessWrapper{data=dto.UserResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 429 {object} response.RateLimitErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/email/confirm [post]
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.ConfirmEmailChange(ctx, c.GetString("userId"), req.Code)
+	if err != nil {
+		emailChangeError(c, err)
+		return
+	}
+
+	response.Success(c, mapper.ToUserResponse(user))
+}
+
+// CancelEmailChange godoc
+// @Summary Cancel email change
+// @Tags users
+// @Description Drops the pending email change, the code sent to the new address stops working
+// @Produce json
+// @Success 200 {object} response.SuccessWrapper{data=string}
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /user/email [delete]
+func (h *UserHandler) CancelEmailChange(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.userService.CancelEmailChange(ctx, c.GetString("userId")); err != nil {
+		emailChangeError(c, err)
+		return
+	}
+
+	response.Success(c, "Cancelled")
+}
+
 // BanUser godoc
 // @Summary Ban user
 // @Tags admin-users
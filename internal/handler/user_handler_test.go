@@ -2,28 +2,52 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"mime/multipart"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"learning-platform/internal/kafka"
 	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
 	"learning-platform/internal/service"
 )
 
+type fakeUserStatsRepoForHandler struct {
+	repository.IUserStatsRepository
+	stats map[string]*models.UserStats
+	days  []models.StreakDay
+}
+
+func (f *fakeUserStatsRepoForHandler) Find(ctx context.Context, userID string) (*models.UserStats, error) {
+	return f.stats[userID], nil
+}
+
+func (f *fakeUserStatsRepoForHandler) FindDays(ctx context.Context, userID string, from, to time.Time) ([]models.StreakDay, error) {
+	var out []models.StreakDay
+	for _, d := range f.days {
+		if d.UserID == userID && !d.Day.Before(from) && !d.Day.After(to) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
 
 func setupUserRouter() (*gin.Engine, *fakeUserRepoForHandler, *service.UserService) {
 	gin.SetMode(gin.TestMode)
 
 	repo := newFakeUserRepoForHandler()
-	userSvc := service.NewUserService(repo)
+	userSvc := service.NewUserService(repo, nil, nil, nil)
 	s3 := &service.S3Service{} 
 
-	h := NewUserHandler(userSvc, s3)
+	h := NewUserHandler(userSvc, nil, nil, nil, s3)
 
 	r := gin.Default()
 	r.GET("/user/all", h.GetAllUsers)
@@ -59,10 +83,18 @@ func TestUserHandler_GetProfile(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	repo := newFakeUserRepoForHandler()
-	userSvc := service.NewUserService(repo)
+	userSvc := service.NewUserService(repo, nil, nil, nil)
 	s3 := &service.S3Service{}
 
-	h := NewUserHandler(userSvc, s3)
+	achievements := &fakeAchievementRepo{achievements: map[string]*models.Achievement{}}
+	stats := &fakeUserStatsRepoForHandler{stats: map[string]*models.UserStats{}}
+	h := NewUserHandler(
+		userSvc,
+		nil,
+		service.NewAchievementService(achievements, nil, stats, repo, nil),
+		service.NewGamificationService(stats, nil, repo),
+		s3,
+	)
 
 	r := gin.Default()
 
@@ -84,16 +116,42 @@ func TestUserHandler_GetProfile(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"badges":[]`)
+	assert.Contains(t, w.Body.String(), `"xp":0`)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stats.stats[user.ID.String()] = &models.UserStats{
+		UserID:         user.ID.String(),
+		XP:             50,
+		CurrentStreak:  4,
+		LongestStreak:  9,
+		LastActiveDate: &today,
+	}
+
+	achievements.unlocked = []models.UserAchievement{{
+		UserID:        user.ID.String(),
+		AchievementID: "a1",
+		Achievement:   &models.Achievement{ID: "a1", Code: "first-extreme", Title: "Fearless"},
+	}}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user/profile", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"email":"test@example.com"`)
+	assert.Contains(t, w.Body.String(), `"title":"Fearless"`)
+	assert.Contains(t, w.Body.String(), `"xp":50`)
+	assert.Contains(t, w.Body.String(), `"current":4`)
 }
 
 func TestUserHandler_UpdateProfile(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	repo := newFakeUserRepoForHandler()
-	userSvc := service.NewUserService(repo)
+	userSvc := service.NewUserService(repo, nil, nil, nil)
 	s3 := &service.S3Service{}
 
-	h := NewUserHandler(userSvc, s3)
+	h := NewUserHandler(userSvc, nil, nil, nil, s3)
 
 	r := gin.Default()
 
@@ -126,6 +184,158 @@ func TestUserHandler_UpdateProfile(t *testing.T) {
 	updated, err := repo.FindByID(nil, user.ID.String())
 	require.NoError(t, err)
 	require.NotNil(t, updated)
-	assert.Equal(t, "new@example.com", updated.Email)
+	// email меняется только через подтверждение кодом
+	assert.Equal(t, "old@example.com", updated.Email)
 	assert.Equal(t, "New Name", updated.DisplayName)
 }
+
+func TestUserHandler_UpdateTimezone(t *testing.T) {
+	router, repo, _ := setupUserRouter()
+
+	user := &models.User{ID: uuid.New(), Email: "tz@example.com"}
+	require.NoError(t, repo.Create(nil, user))
+
+	h := NewUserHandler(service.NewUserService(repo, nil, nil, nil), nil, nil, nil, &service.S3Service{})
+	router.PUT("/user/timezone", func(c *gin.Context) {
+		c.Set("userId", user.ID.String())
+		h.UpdateTimezone(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/timezone", strings.NewReader(`{"timezone":"Mars/Olympus"}`)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/timezone", strings.NewReader(`{"timezone":"Europe/Berlin"}`)))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"timezone":"Europe/Berlin"`)
+
+	updated, err := repo.FindByID(nil, user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", updated.Timezone)
+}
+
+type fakeEmailChangeRepoForHandler struct {
+	repository.IEmailChangeRepository
+	users  *fakeUserRepoForHandler
+	byUser map[uuid.UUID]*models.EmailChange
+}
+
+func (f *fakeEmailChangeRepoForHandler) Replace(ctx context.Context, change *models.EmailChange) error {
+	f.byUser[change.UserID] = change
+	return nil
+}
+func (f *fakeEmailChangeRepoForHandler) FindByUser(ctx context.Context, userID uuid.UUID) (*models.EmailChange, error) {
+	return f.byUser[userID], nil
+}
+func (f *fakeEmailChangeRepoForHandler) Delete(ctx context.Context, userID uuid.UUID) error {
+	delete(f.byUser, userID)
+	return nil
+}
+func (f *fakeEmailChangeRepoForHandler) Apply(ctx context.Context, change *models.EmailChange) error {
+	delete(f.byUser, change.UserID)
+	return f.users.Update(ctx, change.UserID.String(), map[string]interface{}{"email": change.NewEmail})
+}
+
+type fakeEmailSenderForHandler struct {
+	sent []kafka.EmailMessage
+}
+
+func (f *fakeEmailSenderForHandler) SendAsync(msg kafka.EmailMessage) {
+	f.sent = append(f.sent, msg)
+}
+
+func TestUserHandler_EmailChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newFakeUserRepoForHandler()
+	user := &models.User{ID: uuid.New(), Email: "old@example.com", DisplayName: "Anna"}
+	require.NoError(t, repo.Create(nil, user))
+	other := &models.User{ID: uuid.New(), Email: "taken@example.com"}
+	require.NoError(t, repo.Create(nil, other))
+
+	changes := &fakeEmailChangeRepoForHandler{users: repo, byUser: make(map[uuid.UUID]*models.EmailChange)}
+	emails := &fakeEmailSenderForHandler{}
+	h := NewUserHandler(service.NewUserService(repo, changes, nil, emails), nil, nil, nil, &service.S3Service{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("userId", user.ID.String()) })
+	r.GET("/user/email", h.GetEmailChange)
+	r.POST("/user/email", h.RequestEmailChange)
+	r.POST("/user/email/confirm", h.ConfirmEmailChange)
+	r.DELETE("/user/email", h.CancelEmailChange)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("GET", "/user/email", "")
+	assert.Equal(t, 404, w.Code)
+
+	w = send("POST", "/user/email", `{"newEmail":"taken@example.com"}`)
+	assert.Equal(t, 409, w.Code)
+
+	w = send("POST", "/user/email", `{"newEmail":"new@example.com"}`)
+	require.Equal(t, 202, w.Code)
+	assert.Contains(t, w.Body.String(), `"newEmail":"new@example.com"`)
+	require.Len(t, emails.sent, 2)
+	code := emails.sent[0].Code
+
+	w = send("GET", "/user/email", "")
+	assert.Equal(t, 200, w.Code)
+
+	// пока код не подтверждён, email прежний
+	assert.Equal(t, "old@example.com", user.Email)
+
+	w = send("POST", "/user/email/confirm", `{"code":"12"}`)
+	assert.Equal(t, 400, w.Code)
+
+	w = send("POST", "/user/email/confirm", `{"code":"`+code+`"}`)
+	require.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"email":"new@example.com"`)
+
+	w = send("POST", "/user/email", `{"newEmail":"third@example.com"}`)
+	require.Equal(t, 202, w.Code)
+	w = send("DELETE", "/user/email", "")
+	assert.Equal(t, 200, w.Code)
+	w = send("POST", "/user/email/confirm", `{"code":"`+emails.sent[3].Code+`"}`)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestUserHandler_GetStreakCalendar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newFakeUserRepoForHandler()
+	user := &models.User{ID: uuid.New(), Email: "cal@example.com"}
+	require.NoError(t, repo.Create(nil, user))
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	stats := &fakeUserStatsRepoForHandler{
+		stats: map[string]*models.UserStats{},
+		days: []models.StreakDay{
+			{UserID: user.ID.String(), Day: day, Kind: models.StreakDayActive},
+			{UserID: user.ID.String(), Day: day.AddDate(0, 0, 1), Kind: models.StreakDayFrozen},
+		},
+	}
+	h := NewUserHandler(nil, nil, nil, service.NewGamificationService(stats, nil, repo), &service.S3Service{})
+
+	r := gin.Default()
+	r.GET("/user/streak/calendar", func(c *gin.Context) {
+		c.Set("userId", user.ID.String())
+		h.GetStreakCalendar(c)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user/streak/calendar?month=2025-03", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"date":"2025-03-14"`)
+	assert.Contains(t, w.Body.String(), `"status":"FROZEN"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user/streak/calendar?month=03.2025", nil))
+	assert.Equal(t, 400, w.Code)
+}
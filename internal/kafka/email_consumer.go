@@ -52,7 +52,10 @@ func (c *EmailConsumer) Start() {
 		}
 
 		_, emailSpan := otel.Tracer("email").Start(ctx, "SMTP.SendEmail")
-		body := fmt.Sprintf("Your code: %s", em.Code)
+		body := em.Body
+		if body == "" {
+			body = fmt.Sprintf("Your code: %s", em.Code)
+		}
 		if err := c.sender.SendEmail(em.Email, em.Subject, body); err != nil {
 			log.Println("Email error:", err)
 			continue
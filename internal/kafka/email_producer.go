@@ -11,10 +11,13 @@ import (
 	"go.opentelemetry.io/otel"
 )
 
+// EmailMessage is either a verification code or, when Body is set, a
+// plain-text notification.
 type EmailMessage struct {
 	Email   string `json:"email"`
 	Subject string `json:"subject"`
 	Code    string `json:"code"`
+	Body    string `json:"body,omitempty"`
 }
 
 type queuedMessage struct {
@@ -0,0 +1,55 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToAchievementResponse(a *models.Achievement) dto.AchievementResponse {
+    resp := dto.AchievementResponse{
+        ID:          a.ID,
+        Code:        a.Code,
+        Title:       a.Title,
+        Description: a.Description,
+        IconURL:     a.IconURL,
+        Rule:        string(a.Rule),
+        Threshold:   a.Threshold,
+        TopicID:     a.TopicID,
+        IsActive:    a.IsActive,
+    }
+    if a.Difficulty != nil {
+        d := string(*a.Difficulty)
+        resp.Difficulty = &d
+    }
+    return resp
+}
+
+func ToAchievementList(achievements []models.Achievement) []dto.AchievementResponse {
+    result := make([]dto.AchievementResponse, 0, len(achievements))
+    for _, a := range achievements {
+        result = append(result, ToAchievementResponse(&a))
+    }
+    return result
+}
+
+func ToBadgeResponse(u *models.UserAchievement) dto.BadgeResponse {
+    badge := dto.BadgeResponse{
+        AchievementID: u.AchievementID,
+        UnlockedAt:    u.UnlockedAt,
+    }
+    if u.Achievement != nil {
+        badge.Code = u.Achievement.Code
+        badge.Title = u.Achievement.Title
+        badge.Description = u.Achievement.Description
+        badge.IconURL = u.Achievement.IconURL
+    }
+    return badge
+}
+
+func ToBadgeList(unlocked []models.UserAchievement) []dto.BadgeResponse {
+    result := make([]dto.BadgeResponse, 0, len(unlocked))
+    for _, u := range unlocked {
+        result = append(result, ToBadgeResponse(&u))
+    }
+    return result
+}
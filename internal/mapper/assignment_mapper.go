@@ -0,0 +1,86 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToAssignmentResponse(a *models.Assignment) dto.AssignmentResponse {
+    tasks := make([]dto.AssignmentTaskResponse, 0, len(a.Tasks))
+    for _, t := range a.Tasks {
+        item := dto.AssignmentTaskResponse{
+            TaskID:   t.TaskID,
+            Position: t.Position,
+        }
+        if t.Task != nil {
+            item.Title = t.Task.Title
+            item.Difficulty = string(t.Task.Difficulty)
+        }
+        tasks = append(tasks, item)
+    }
+
+    return dto.AssignmentResponse{
+        ID:          a.ID,
+        ClassroomID: a.ClassroomID,
+        Title:       a.Title,
+        Description: a.Description,
+        OpensAt:     a.OpensAt,
+        DueAt:       a.DueAt,
+        ClosesAt:    a.ClosesAt,
+        LatePolicy:  string(a.LatePolicy),
+        LatePenalty: a.LatePenalty,
+        Weight:      a.Weight,
+        AuthorID:    a.AuthorID,
+        Tasks:       tasks,
+    }
+}
+
+func ToAssignmentList(assignments []models.Assignment) []dto.AssignmentResponse {
+    result := make([]dto.AssignmentResponse, 0, len(assignments))
+    for _, a := range assignments {
+        result = append(result, ToAssignmentResponse(&a))
+    }
+    return result
+}
+
+func ToAssignmentProgressResponse(p *models.AssignmentProgress) dto.AssignmentProgressResponse {
+    return dto.AssignmentProgressResponse{
+        TotalTasks:   p.TotalTasks,
+        Attempted:    p.Attempted,
+        SolvedOnTime: p.SolvedOnTime,
+        SolvedLate:   p.SolvedLate,
+        Score:        p.Score,
+        Status:       string(p.Status),
+    }
+}
+
+func ToStudentAssignmentList(items []models.StudentAssignment) []dto.StudentAssignmentResponse {
+    result := make([]dto.StudentAssignmentResponse, 0, len(items))
+    for _, item := range items {
+        result = append(result, dto.StudentAssignmentResponse{
+            AssignmentResponse: ToAssignmentResponse(&item.Assignment),
+            Progress:           ToAssignmentProgressResponse(&item.Progress),
+        })
+    }
+    return result
+}
+
+func ToAssignmentReportResponse(r *models.AssignmentReport) dto.AssignmentReportResponse {
+    students := make([]dto.AssignmentStudentReportResponse, 0, len(r.Students))
+    for _, s := range r.Students {
+        item := dto.AssignmentStudentReportResponse{
+            UserID:   s.Progress.UserID,
+            Progress: ToAssignmentProgressResponse(&s.Progress),
+        }
+        if s.Student != nil {
+            item.DisplayName = s.Student.DisplayName
+            item.Email = s.Student.Email
+        }
+        students = append(students, item)
+    }
+
+    return dto.AssignmentReportResponse{
+        Assignment: ToAssignmentResponse(r.Assignment),
+        Students:   students,
+    }
+}
@@ -13,4 +13,71 @@ func ToMeResponse(u *models.User) dto.MeResponse {
         Role:        string(u.Role),
         AvatarURL:   u.AvatarURL,
     }
+}
+
+func ToSessionResponse(t *models.RefreshToken, currentSessionID string) dto.SessionResponse {
+    return dto.SessionResponse{
+        ID:         t.SessionID.String(),
+        DeviceName: t.DeviceName,
+        IPAddress:  t.IPAddress,
+        UserAgent:  t.UserAgent,
+        LastUsedAt: t.LastUsedAt,
+        ExpiresAt:  t.ExpiresAt,
+        Current:    t.SessionID.String() == currentSessionID,
+    }
+}
+
+func ToSessionList(tokens []models.RefreshToken, currentSessionID string) []dto.SessionResponse {
+    result := make([]dto.SessionResponse, 0, len(tokens))
+    for _, t := range tokens {
+        result = append(result, ToSessionResponse(&t, currentSessionID))
+    }
+    return result
+}
+
+func ToSecurityEventResponse(e *models.SecurityEvent) dto.SecurityEventResponse {
+    resp := dto.SecurityEventResponse{
+        ID:        e.ID.String(),
+        Kind:      string(e.Kind),
+        IPAddress: e.IPAddress,
+        UserAgent: e.UserAgent,
+        CreatedAt: e.CreatedAt,
+    }
+    if e.SessionID != nil {
+        id := e.SessionID.String()
+        resp.SessionID = &id
+    }
+    return resp
+}
+
+func ToSecurityEventList(events []models.SecurityEvent) []dto.SecurityEventResponse {
+    result := make([]dto.SecurityEventResponse, 0, len(events))
+    for _, e := range events {
+        result = append(result, ToSecurityEventResponse(&e))
+    }
+    return result
+}
+
+func ToAccessTokenResponse(t *models.PersonalAccessToken) dto.AccessTokenResponse {
+    scopes := make([]string, 0, 2)
+    for _, s := range t.ScopeList() {
+        scopes = append(scopes, string(s))
+    }
+    return dto.AccessTokenResponse{
+        ID:          t.ID.String(),
+        Name:        t.Name,
+        TokenPrefix: t.TokenPrefix,
+        Scopes:      scopes,
+        ExpiresAt:   t.ExpiresAt,
+        LastUsedAt:  t.LastUsedAt,
+        CreatedAt:   t.CreatedAt,
+    }
+}
+
+func ToAccessTokenList(tokens []models.PersonalAccessToken) []dto.AccessTokenResponse {
+    result := make([]dto.AccessTokenResponse, 0, len(tokens))
+    for _, t := range tokens {
+        result = append(result, ToAccessTokenResponse(&t))
+    }
+    return result
 }
\ No newline at end of file
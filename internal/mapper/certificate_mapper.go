@@ -0,0 +1,30 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToCertificateResponse(c *models.Certificate, verifyURL string) dto.CertificateResponse {
+    return dto.CertificateResponse{
+        ID:        c.ID,
+        Code:      c.Code,
+        Kind:      string(c.Kind),
+        SubjectID: c.SubjectID,
+        Title:     c.Title,
+        PDFURL:    c.PDFURL,
+        VerifyURL: verifyURL,
+        IssuedAt:  c.IssuedAt,
+    }
+}
+
+func ToCertificateVerificationResponse(c *models.Certificate) dto.CertificateVerificationResponse {
+    return dto.CertificateVerificationResponse{
+        Valid:         true,
+        Code:          c.Code,
+        Kind:          string(c.Kind),
+        Title:         c.Title,
+        RecipientName: c.RecipientName,
+        IssuedAt:      c.IssuedAt,
+    }
+}
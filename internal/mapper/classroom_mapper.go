@@ -0,0 +1,49 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToClassroomResponse(c *models.Classroom) dto.ClassroomResponse {
+    return dto.ClassroomResponse{
+        ID:          c.ID,
+        Name:        c.Name,
+        Description: c.Description,
+        SchoolClass: c.SchoolClass,
+        JoinCode:    c.JoinCode,
+        OwnerID:     c.OwnerID,
+        CreatedAt:   c.CreatedAt,
+    }
+}
+
+func ToClassroomList(classrooms []models.Classroom) []dto.ClassroomResponse {
+    result := make([]dto.ClassroomResponse, 0, len(classrooms))
+    for _, c := range classrooms {
+        result = append(result, ToClassroomResponse(&c))
+    }
+    return result
+}
+
+func ToClassroomMemberResponse(m *models.ClassroomMember) dto.ClassroomMemberResponse {
+    resp := dto.ClassroomMemberResponse{
+        UserID:   m.UserID,
+        Role:     string(m.Role),
+        JoinedAt: m.JoinedAt,
+    }
+
+    if m.User != nil {
+        resp.DisplayName = m.User.DisplayName
+        resp.Email = m.User.Email
+    }
+
+    return resp
+}
+
+func ToClassroomMemberList(members []models.ClassroomMember) []dto.ClassroomMemberResponse {
+    result := make([]dto.ClassroomMemberResponse, 0, len(members))
+    for _, m := range members {
+        result = append(result, ToClassroomMemberResponse(&m))
+    }
+    return result
+}
@@ -0,0 +1,95 @@
+package mapper
+
+import (
+    "learning-platform/internal/models"
+    "learning-platform/internal/dto"
+)
+
+func ToCourseItemResponse(i *models.CourseItem) dto.CourseItemResponse {
+    return dto.CourseItemResponse{
+        ID:       i.ID,
+        ItemType: string(i.ItemType),
+        Title:    i.Title,
+        BodyMD:   i.BodyMD,
+        TopicID:  i.TopicID,
+        TaskID:   i.TaskID,
+        Position: i.Position,
+    }
+}
+
+func ToCourseModuleResponse(m *models.CourseModule) dto.CourseModuleResponse {
+    items := make([]dto.CourseItemResponse, len(m.Items))
+    for i, item := range m.Items {
+        items[i] = ToCourseItemResponse(&item)
+    }
+
+    return dto.CourseModuleResponse{
+        ID:               m.ID,
+        Title:            m.Title,
+        Position:         m.Position,
+        RequiresPrevious: m.RequiresPrevious,
+        UnlockAt:         m.UnlockAt,
+        Items:            items,
+    }
+}
+
+func ToCourseResponse(c *models.Course) dto.CourseResponse {
+    var modules []dto.CourseModuleResponse
+    for _, m := range c.Modules {
+        modules = append(modules, ToCourseModuleResponse(&m))
+    }
+
+    return dto.CourseResponse{
+        ID:            c.ID,
+        Title:         c.Title,
+        DescriptionMD: c.DescriptionMD,
+        Status:        string(c.Status),
+        SchoolClass:   c.SchoolClass,
+        AuthorID:      c.AuthorID,
+        Modules:       modules,
+        CreatedAt:     c.CreatedAt.Format("2006-01-02T15:04:05Z"),
+        UpdatedAt:     c.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+    }
+}
+
+func ToCourseList(courses []models.Course) []dto.CourseResponse {
+    res := make([]dto.CourseResponse, len(courses))
+    for i, c := range courses {
+        res[i] = ToCourseResponse(&c)
+    }
+    return res
+}
+
+func ToCourseProgressResponse(p *models.CourseProgress) dto.CourseProgressResponse {
+    modules := make([]dto.CourseModuleProgressResponse, len(p.Modules))
+    for i, m := range p.Modules {
+        items := make([]dto.CourseItemProgressResponse, len(m.Items))
+        for j, item := range m.Items {
+            items[j] = dto.CourseItemProgressResponse{
+                ItemID:    item.ItemID,
+                ItemType:  string(item.ItemType),
+                Completed: item.Completed,
+            }
+        }
+
+        modules[i] = dto.CourseModuleProgressResponse{
+            ModuleID:       m.ModuleID,
+            Title:          m.Title,
+            Position:       m.Position,
+            Unlocked:       m.Unlocked,
+            Completed:      m.Completed,
+            UnlockAt:       m.UnlockAt,
+            TotalItems:     m.TotalItems,
+            CompletedItems: m.CompletedItems,
+            Items:          items,
+        }
+    }
+
+    return dto.CourseProgressResponse{
+        CourseID:       p.CourseID,
+        TotalItems:     p.TotalItems,
+        CompletedItems: p.CompletedItems,
+        Completed:      p.Completed,
+        Modules:        modules,
+    }
+}
@@ -0,0 +1,59 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+// ToDailyChallengeResponse maps a challenge; the task's answer and official
+// solution are only included when reveal is set.
+func ToDailyChallengeResponse(c *models.DailyChallenge, reveal bool) dto.DailyChallengeResponse {
+    resp := dto.DailyChallengeResponse{
+        ID:          c.ID,
+        SchoolClass: c.SchoolClass,
+        Date:        c.Day.Format(dateLayout),
+        Automatic:   c.SelectedBy == nil,
+    }
+    if c.Task != nil {
+        resp.Task = ToTaskResponse(c.Task)
+        if !reveal {
+            resp.Task.CorrectAnswer = ""
+            resp.Task.OfficialSolution = ""
+        }
+    }
+    return resp
+}
+
+func ToDailyChallengeList(challenges []models.DailyChallenge, reveal bool) []dto.DailyChallengeResponse {
+    res := make([]dto.DailyChallengeResponse, 0, len(challenges))
+    for i := range challenges {
+        res = append(res, ToDailyChallengeResponse(&challenges[i], reveal))
+    }
+    return res
+}
+
+func ToDailyChallengeEntryResponse(e *models.DailyChallengeEntry) dto.DailyChallengeEntryResponse {
+    return dto.DailyChallengeEntryResponse{
+        Rank:             e.Rank,
+        UserID:           e.UserID,
+        DisplayName:      e.DisplayName,
+        SolvedAt:         e.SolvedAt,
+        SolveTimeSeconds: int64(e.SolveTime.Seconds()),
+        Attempts:         e.Attempts,
+    }
+}
+
+func ToDailyChallengeLeaderboardResponse(b *models.DailyChallengeLeaderboard, reveal bool) dto.DailyChallengeLeaderboardResponse {
+    resp := dto.DailyChallengeLeaderboardResponse{
+        Challenge: ToDailyChallengeResponse(b.Challenge, reveal),
+        Entries:   make([]dto.DailyChallengeEntryResponse, 0, len(b.Entries)),
+    }
+    for i := range b.Entries {
+        resp.Entries = append(resp.Entries, ToDailyChallengeEntryResponse(&b.Entries[i]))
+    }
+    if b.Me != nil {
+        me := ToDailyChallengeEntryResponse(b.Me)
+        resp.Me = &me
+    }
+    return resp
+}
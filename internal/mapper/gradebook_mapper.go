@@ -0,0 +1,37 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToGradebookResponse(gb *models.Gradebook) dto.GradebookResponse {
+    rows := make([]dto.GradebookRowResponse, 0, len(gb.Rows))
+    for _, r := range gb.Rows {
+        scores := make([]dto.GradebookCellResponse, 0, len(r.Cells))
+        for _, cell := range r.Cells {
+            scores = append(scores, dto.GradebookCellResponse{
+                AssignmentID: cell.AssignmentID,
+                Score:        cell.Score,
+                Status:       string(cell.Status),
+            })
+        }
+
+        row := dto.GradebookRowResponse{
+            UserID:   r.UserID,
+            Scores:   scores,
+            Weighted: r.Weighted,
+        }
+        if r.Student != nil {
+            row.DisplayName = r.Student.DisplayName
+            row.Email = r.Student.Email
+        }
+        rows = append(rows, row)
+    }
+
+    return dto.GradebookResponse{
+        ClassroomID: gb.ClassroomID,
+        Assignments: ToAssignmentList(gb.Assignments),
+        Students:    rows,
+    }
+}
@@ -0,0 +1,31 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToLeaderboardEntryResponse(e *models.LeaderboardEntry) dto.LeaderboardEntryResponse {
+    return dto.LeaderboardEntryResponse{
+        Rank:        e.Rank,
+        UserID:      e.UserID,
+        DisplayName: e.DisplayName,
+        Score:       e.Score,
+    }
+}
+
+func ToLeaderboardResponse(b *models.Leaderboard) dto.LeaderboardResponse {
+    resp := dto.LeaderboardResponse{
+        Scope:   string(b.Scope),
+        ScopeID: b.ScopeID,
+        Entries: make([]dto.LeaderboardEntryResponse, 0, len(b.Entries)),
+    }
+    for _, e := range b.Entries {
+        resp.Entries = append(resp.Entries, ToLeaderboardEntryResponse(&e))
+    }
+    if b.Me != nil {
+        me := ToLeaderboardEntryResponse(b.Me)
+        resp.Me = &me
+    }
+    return resp
+}
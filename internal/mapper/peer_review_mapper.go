@@ -0,0 +1,101 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToRubricResponse(r *models.Rubric) dto.RubricResponse {
+    criteria := make([]dto.RubricCriterionResponse, 0, len(r.Criteria))
+    for _, c := range r.Criteria {
+        criteria = append(criteria, dto.RubricCriterionResponse{
+            ID:          c.ID,
+            Title:       c.Title,
+            Description: c.Description,
+            MaxPoints:   c.MaxPoints,
+        })
+    }
+    return dto.RubricResponse{
+        TaskID:      r.TaskID,
+        PeerReviews: r.PeerReviews,
+        MaxPoints:   r.MaxPoints(),
+        Criteria:    criteria,
+    }
+}
+
+func ToRubricCriteria(req []dto.RubricCriterionRequest) []models.RubricCriterion {
+    criteria := make([]models.RubricCriterion, 0, len(req))
+    for _, c := range req {
+        criteria = append(criteria, models.RubricCriterion{
+            Title:       c.Title,
+            Description: c.Description,
+            MaxPoints:   c.MaxPoints,
+        })
+    }
+    return criteria
+}
+
+func ToPeerReviewResponse(r *models.PeerReview) dto.PeerReviewResponse {
+    scores := make([]dto.PeerReviewScoreResponse, 0, len(r.Scores))
+    for _, s := range r.Scores {
+        scores = append(scores, dto.PeerReviewScoreResponse{CriterionID: s.CriterionID, Points: s.Points})
+    }
+    resp := dto.PeerReviewResponse{
+        ID:           r.ID,
+        SubmissionID: r.SubmissionID,
+        ReviewerID:   r.ReviewerID,
+        Status:       string(r.Status),
+        Points:       r.Points(),
+        Scores:       scores,
+        Comment:      r.Comment,
+        AssignedAt:   r.AssignedAt,
+        SubmittedAt:  r.SubmittedAt,
+    }
+    if r.Submission != nil {
+        resp.TaskID = r.Submission.TaskID
+        if r.Submission.Task != nil {
+            resp.TaskTitle = r.Submission.Task.Title
+        }
+    }
+    if r.Reviewer != nil {
+        resp.ReviewerName = r.Reviewer.DisplayName
+    }
+    return resp
+}
+
+// ToPeerReviewList maps reviews for their reviewer, who is left out since it
+// is the caller.
+func ToPeerReviewList(reviews []models.PeerReview) []dto.PeerReviewResponse {
+    res := make([]dto.PeerReviewResponse, 0, len(reviews))
+    for i := range reviews {
+        resp := ToPeerReviewResponse(&reviews[i])
+        resp.ReviewerID = ""
+        res = append(res, resp)
+    }
+    return res
+}
+
+func ToReceivedPeerReviews(reviews []models.PeerReview) []dto.PeerReviewResponse {
+    res := make([]dto.PeerReviewResponse, 0, len(reviews))
+    for i := range reviews {
+        res = append(res, ToPeerReviewResponse(&reviews[i]))
+    }
+    return res
+}
+
+// ToPeerReviewTaskResponse maps a review with the answer it is about; the
+// answer's author is never included.
+func ToPeerReviewTaskResponse(r *models.PeerReview, rubric *models.Rubric) dto.PeerReviewTaskResponse {
+    resp := dto.PeerReviewTaskResponse{
+        PeerReviewResponse: ToPeerReviewResponse(r),
+        Rubric:             ToRubricResponse(rubric),
+    }
+    resp.ReviewerID = ""
+    if r.Submission != nil {
+        resp.Answer = r.Submission.Answer
+        if r.Submission.Task != nil {
+            resp.TaskBodyMD = r.Submission.Task.BodyMD
+        }
+    }
+    return resp
+}
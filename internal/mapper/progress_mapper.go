@@ -0,0 +1,50 @@
+package mapper
+
+import (
+    "strings"
+
+    "learning-platform/internal/models"
+    "learning-platform/internal/dto"
+)
+
+func ToTopicProgressResponse(p *models.TopicProgress) dto.TopicProgressResponse {
+    return dto.TopicProgressResponse{
+        TopicID:   p.TopicID,
+        Title:     p.Title,
+        ParentID:  p.ParentID,
+        Total:     p.Total,
+        Attempted: p.Attempted,
+        Solved:    p.Solved,
+        Mastery:   p.Mastery,
+    }
+}
+
+func ToTopicProgressList(progress []models.TopicProgress) []dto.TopicProgressResponse {
+    res := make([]dto.TopicProgressResponse, len(progress))
+    for i, p := range progress {
+        res[i] = ToTopicProgressResponse(&p)
+    }
+    return res
+}
+
+func ToTaskRecommendationResponse(r *models.TaskRecommendation) dto.TaskRecommendationResponse {
+    explanation := strings.Join(r.Reasons, "; ")
+    if explanation != "" {
+        explanation = strings.ToUpper(explanation[:1]) + explanation[1:] + "."
+    }
+
+    return dto.TaskRecommendationResponse{
+        Task: dto.RecommendedTaskResponse{
+            ID:         r.Task.ID,
+            Title:      r.Task.Title,
+            Difficulty: string(r.Task.Difficulty),
+            TopicID:    r.Task.TopicID,
+            AnswerType: string(r.Task.AnswerType),
+        },
+        TopicTitle:       r.TopicTitle,
+        TargetDifficulty: string(r.TargetDifficulty),
+        TopicMastery:     r.TopicMastery,
+        Explanation:      explanation,
+        Reasons:          r.Reasons,
+    }
+}
@@ -0,0 +1,121 @@
+package mapper
+
+import (
+    "time"
+
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToQuizResponse(q *models.Quiz) dto.QuizResponse {
+    tasks := make([]dto.QuizTaskResponse, 0, len(q.Tasks))
+    for _, t := range q.Tasks {
+        item := dto.QuizTaskResponse{
+            TaskID:   t.TaskID,
+            Position: t.Position,
+        }
+        if t.Task != nil {
+            item.Title = t.Task.Title
+            item.Difficulty = string(t.Task.Difficulty)
+        }
+        tasks = append(tasks, item)
+    }
+
+    return dto.QuizResponse{
+        ID:               q.ID,
+        ClassroomID:      q.ClassroomID,
+        Title:            q.Title,
+        Description:      q.Description,
+        Mode:             string(q.Mode),
+        DrawCount:        q.DrawCount,
+        TimeLimitSeconds: q.TimeLimitSeconds,
+        OpensAt:          q.OpensAt,
+        ClosesAt:         q.ClosesAt,
+        ResultsReleased:  q.ResultsReleased,
+        AuthorID:         q.AuthorID,
+        Tasks:            tasks,
+    }
+}
+
+func ToQuizList(quizzes []models.Quiz) []dto.QuizResponse {
+    result := make([]dto.QuizResponse, 0, len(quizzes))
+    for _, q := range quizzes {
+        result = append(result, ToQuizResponse(&q))
+    }
+    return result
+}
+
+// ToQuizSessionResponse maps a session; correctness, score and correct answers
+// are only included when showResults is set.
+func ToQuizSessionResponse(s *models.QuizSession, showResults bool) dto.QuizSessionResponse {
+    answers := make([]dto.QuizAnswerResponse, 0, len(s.Answers))
+    for _, a := range s.Answers {
+        item := dto.QuizAnswerResponse{
+            TaskID:   a.TaskID,
+            Position: a.Position,
+            Answer:   a.Answer,
+        }
+        if a.Task != nil {
+            item.Title = a.Task.Title
+            item.BodyMD = a.Task.BodyMD
+            item.AnswerType = string(a.Task.AnswerType)
+            item.ImageURL = a.Task.ImageURL
+        }
+        if showResults {
+            correct := a.IsCorrect
+            item.IsCorrect = &correct
+            if a.Task != nil {
+                item.CorrectAnswer = a.Task.CorrectAnswer
+                item.OfficialSolution = a.Task.OfficialSolution
+            }
+        }
+        answers = append(answers, item)
+    }
+
+    resp := dto.QuizSessionResponse{
+        ID:               s.ID,
+        QuizID:           s.QuizID,
+        UserID:           s.UserID,
+        Status:           string(s.Status),
+        StartedAt:        s.StartedAt,
+        ExpiresAt:        s.ExpiresAt,
+        SubmittedAt:      s.SubmittedAt,
+        ResultsAvailable: showResults,
+        Answers:          answers,
+    }
+
+    if !s.IsFinished() {
+        if remaining := time.Until(s.ExpiresAt); remaining > 0 {
+            resp.RemainingSeconds = int(remaining.Seconds())
+        }
+    }
+
+    if showResults {
+        correctCount, score := s.CorrectCount, s.Score
+        resp.CorrectCount = &correctCount
+        resp.Score = &score
+    }
+
+    return resp
+}
+
+func ToQuizResultList(sessions []models.QuizSession) []dto.QuizResultResponse {
+    result := make([]dto.QuizResultResponse, 0, len(sessions))
+    for _, s := range sessions {
+        item := dto.QuizResultResponse{
+            SessionID:    s.ID,
+            UserID:       s.UserID,
+            Status:       string(s.Status),
+            StartedAt:    s.StartedAt,
+            SubmittedAt:  s.SubmittedAt,
+            CorrectCount: s.CorrectCount,
+            Score:        s.Score,
+        }
+        if s.User != nil {
+            item.DisplayName = s.User.DisplayName
+            item.Email = s.User.Email
+        }
+        result = append(result, item)
+    }
+    return result
+}
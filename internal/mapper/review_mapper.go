@@ -0,0 +1,31 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+func ToReviewItemResponse(s *models.ReviewSchedule) dto.ReviewItemResponse {
+    item := dto.ReviewItemResponse{
+        TaskID:         s.TaskID,
+        DueAt:          s.DueAt,
+        LastReviewedAt: s.LastReviewedAt,
+        IntervalDays:   s.IntervalDays,
+        Repetitions:    s.Repetitions,
+        Lapses:         s.Lapses,
+    }
+    if s.Task != nil {
+        item.Title = s.Task.Title
+        item.Difficulty = string(s.Task.Difficulty)
+        item.TopicID = s.Task.TopicID
+    }
+    return item
+}
+
+func ToReviewQueue(schedules []models.ReviewSchedule) []dto.ReviewItemResponse {
+    result := make([]dto.ReviewItemResponse, 0, len(schedules))
+    for _, s := range schedules {
+        result = append(result, ToReviewItemResponse(&s))
+    }
+    return result
+}
@@ -0,0 +1,25 @@
+package mapper
+
+import (
+    "learning-platform/internal/models"
+    "learning-platform/internal/dto"
+)
+
+func ToSchoolClassResponse(s *models.SchoolClass) dto.SchoolClassResponse {
+    return dto.SchoolClassResponse{
+        ID:              s.ID,
+        Code:            s.Code,
+        Title:           s.Title,
+        EducationSystem: s.EducationSystem,
+        SortOrder:       s.SortOrder,
+        IsActive:        s.IsActive,
+    }
+}
+
+func ToSchoolClassList(classes []models.SchoolClass) []dto.SchoolClassResponse {
+    res := make([]dto.SchoolClassResponse, len(classes))
+    for i, s := range classes {
+        res[i] = ToSchoolClassResponse(&s)
+    }
+    return res
+}
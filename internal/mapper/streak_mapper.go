@@ -0,0 +1,38 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+func ToStreakResponse(s *models.UserStats) dto.StreakResponse {
+    resp := dto.StreakResponse{
+        Current: s.CurrentStreak,
+        Longest: s.LongestStreak,
+        Freezes: s.StreakFreezes,
+    }
+    if s.LastActiveDate != nil {
+        d := s.LastActiveDate.Format(dateLayout)
+        resp.LastActiveDate = &d
+    }
+    return resp
+}
+
+func ToStreakCalendarResponse(c *models.StreakCalendar) dto.StreakCalendarResponse {
+    resp := dto.StreakCalendarResponse{
+        Month:    c.Month,
+        Timezone: c.Timezone,
+        Days:     make([]dto.StreakDayResponse, 0, len(c.Days)),
+        XP:       c.Stats.XP,
+        Streak:   ToStreakResponse(c.Stats),
+    }
+    for _, d := range c.Days {
+        resp.Days = append(resp.Days, dto.StreakDayResponse{
+            Date:   d.Day.Format(dateLayout),
+            Status: string(d.Kind),
+        })
+    }
+    return resp
+}
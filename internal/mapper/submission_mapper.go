@@ -0,0 +1,61 @@
+package mapper
+
+import (
+    "learning-platform/internal/dto"
+    "learning-platform/internal/models"
+)
+
+// ToSubmissionResponse maps a submission; correctness is left out while it
+// is pending review.
+func ToSubmissionResponse(s *models.Submission) dto.SubmissionResponse {
+    resp := dto.SubmissionResponse{
+        ID:           s.ID,
+        TaskID:       s.TaskID,
+        AssignmentID: s.AssignmentID,
+        Answer:       s.Answer,
+        Status:       string(s.Status),
+        Late:         s.IsLate,
+        Score:        s.Score,
+        PeerScore:    s.PeerScore,
+        Feedback:     s.Feedback,
+        GradedAt:     s.GradedAt,
+        CreatedAt:    s.CreatedAt,
+    }
+    if s.Status != models.SubmissionStatusPendingReview {
+        correct := s.IsCorrect
+        resp.Correct = &correct
+    }
+    return resp
+}
+
+func ToSubmissionList(submissions []models.Submission) []dto.SubmissionResponse {
+    res := make([]dto.SubmissionResponse, 0, len(submissions))
+    for i := range submissions {
+        res = append(res, ToSubmissionResponse(&submissions[i]))
+    }
+    return res
+}
+
+func ToGradingQueue(submissions []models.Submission) []dto.GradingQueueItemResponse {
+    res := make([]dto.GradingQueueItemResponse, 0, len(submissions))
+    for _, s := range submissions {
+        item := dto.GradingQueueItemResponse{
+            ID:           s.ID,
+            TaskID:       s.TaskID,
+            StudentID:    s.UserID,
+            AssignmentID: s.AssignmentID,
+            Answer:       s.Answer,
+            Late:         s.IsLate,
+            PeerScore:    s.PeerScore,
+            CreatedAt:    s.CreatedAt,
+        }
+        if s.Task != nil {
+            item.TaskTitle = s.Task.Title
+        }
+        if s.User != nil {
+            item.StudentName = s.User.DisplayName
+        }
+        res = append(res, item)
+    }
+    return res
+}
@@ -17,16 +17,28 @@ func ToTaskResponse(t *models.Task) dto.TaskResponse {
         OfficialSolution: t.OfficialSolution,
         CorrectAnswer:    t.CorrectAnswer,
         AnswerType:       string(t.AnswerType),
+        GradingMode:      string(t.GradingMode),
         ImageURL:         t.ImageURL,
         CreatedAt:        t.CreatedAt.Format("2006-01-02T15:04:05Z"),
         UpdatedAt:        t.UpdatedAt.Format("2006-01-02T15:04:05Z"),
     }
 }
 
-func ToTaskList(tasks []models.Task) []dto.TaskResponse {
+// ToTaskResponseFor leaves out the correct answer and the official solution
+// unless the viewer wrote the task or is an admin.
+func ToTaskResponseFor(t *models.Task, viewerID, role string) dto.TaskResponse {
+    resp := ToTaskResponse(t)
+    if t.AuthorID != viewerID && role != string(models.UserRoleAdmin) {
+        resp.CorrectAnswer = ""
+        resp.OfficialSolution = ""
+    }
+    return resp
+}
+
+func ToTaskListFor(tasks []models.Task, viewerID, role string) []dto.TaskResponse {
     res := make([]dto.TaskResponse, len(tasks))
-    for i, t := range tasks {
-        res[i] = ToTaskResponse(&t)
+    for i := range tasks {
+        res[i] = ToTaskResponseFor(&tasks[i], viewerID, role)
     }
     return res
 }
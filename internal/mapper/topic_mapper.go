@@ -7,11 +7,12 @@ import (
 
 func ToTopicResponse(t *models.Topic) dto.TopicResponse {
     return dto.TopicResponse{
-        ID:          t.ID,
-        Title:       t.Title,
-        Slug:        t.Slug,
-        ParentID:    t.ParentID,
-        SchoolClass: t.SchoolClass,
+        ID:              t.ID,
+        Title:           t.Title,
+        Slug:            t.Slug,
+        ParentID:        t.ParentID,
+        SchoolClass:     t.SchoolClass,
+        PrerequisiteIDs: t.PrerequisiteIDs(),
     }
 }
 
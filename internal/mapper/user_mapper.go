@@ -16,6 +16,16 @@ func ToUserResponse(u *models.User) dto.UserResponse {
 		Role:        role,
 		AvatarURL:   u.AvatarURL,
 		IsBanned:    isBanned,
+		Timezone:    u.Timezone,
+	}
+}
+
+func ToProfileResponse(u *models.User, stats *models.UserStats, badges []models.UserAchievement) dto.ProfileResponse {
+	return dto.ProfileResponse{
+		UserResponse: ToUserResponse(u),
+		XP:           stats.XP,
+		Streak:       ToStreakResponse(stats),
+		Badges:       ToBadgeList(badges),
 	}
 }
 
@@ -36,3 +46,10 @@ func ToBanProfileResponse(u *models.User) dto.BanProfileResponse {
 		BannedReason: u.BanReason,
 	}
 }
+
+func ToEmailChangeResponse(c *models.EmailChange) dto.EmailChangeResponse {
+	return dto.EmailChangeResponse{
+		NewEmail:  c.NewEmail,
+		ExpiresAt: c.ExpiresAt,
+	}
+}
@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/service"
 )
 
-func AuthMiddleware(secret string) gin.HandlerFunc {
+// AuthMiddleware accepts access tokens signed by any key of the key set and
+// personal access tokens (pat_...).
+func AuthMiddleware(keys *service.KeySet, pats *service.PersonalAccessTokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,19 +30,22 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 		}
 
 		tokenStr := parts[1]
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+		if strings.HasPrefix(tokenStr, service.PersonalAccessTokenPrefix) {
+			personalAccessToken(c, pats, tokenStr)
+			return
+		}
 
-		if err != nil || !token.Valid {
+		claims, err := keys.Parse(tokenStr)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+		// two-factor challenge tokens are signed with the same keys but are
+		// no access tokens
+		if _, ok := claims["typ"]; ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token type"})
 			c.Abort()
 			return
 		}
@@ -57,7 +65,72 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 		}
 
 		c.Set("userId", userID)
-		c.Set("role", role)
+		// tokens issued before sessions were tracked carry no session id
+		if sessionID, ok := claims["sid"].(string); ok {
+			c.Set("sessionId", sessionID)
+		}
+		twoFactor, _ := claims["mfa"].(bool)
+		setRole(c, role, twoFactor)
+		c.Next()
+	}
+}
+
+func personalAccessToken(c *gin.Context, pats *service.PersonalAccessTokenService, tokenStr string) {
+	if pats == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return
+	}
+
+	identity, err := pats.Authenticate(c.Request.Context(), tokenStr)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAccessToken) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	// read tokens may only look, write tokens may only change things
+	scope := models.TokenScopeWrite
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		scope = models.TokenScopeRead
+	}
+	if !identity.Token.HasScope(scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token lacks the " + string(scope) + " scope"})
+		return
+	}
+
+	c.Set("userId", identity.UserID.String())
+	c.Set("accessTokenId", identity.TokenID.String())
+	// personal access tokens never count as two-factor sessions, so they
+	// never carry admin privileges
+	setRole(c, string(identity.Role), false)
+	c.Next()
+}
+
+// setRole stores the role handlers and services check. Admins only get their
+// privileges in sessions started with two-factor authentication; otherwise
+// they act as students everywhere, not just behind RoleMiddleware.
+func setRole(c *gin.Context, role string, twoFactor bool) {
+	if role == string(models.UserRoleAdmin) && !twoFactor {
+		role = string(models.UserRoleStudent)
+		c.Set("twoFactorRequired", true)
+	}
+	c.Set("role", role)
+	c.Set("twoFactor", twoFactor)
+}
+
+// SessionOnlyMiddleware rejects personal access tokens. It guards the account
+// security routes, so a leaked token cannot create more tokens or change the
+// sign in methods.
+func SessionOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("accessTokenId") != "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "not allowed with a personal access token"})
+			return
+		}
 		c.Next()
 	}
 }
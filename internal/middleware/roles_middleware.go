@@ -22,6 +22,17 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 			}
 		}
 
+		// AuthMiddleware demoted an admin whose session skipped two-factor
+		// authentication
+		if c.GetBool("twoFactorRequired") {
+			for _, r := range allowedRoles {
+				if r == "Admin" {
+					c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "two-factor authentication required"})
+					return
+				}
+			}
+		}
+
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Don't have permissions"})
 	}
 }
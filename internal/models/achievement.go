@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type AchievementRule string
+
+const (
+    // AchievementRuleSolvedTasks counts solved tasks, optionally limited to a
+    // difficulty and a topic.
+    AchievementRuleSolvedTasks AchievementRule = "SOLVED_TASKS"
+    // AchievementRuleTopicSolvedTasks counts solved tasks within a single
+    // topic: the given one, or any topic when none is set.
+    AchievementRuleTopicSolvedTasks AchievementRule = "TOPIC_SOLVED_TASKS"
+    // AchievementRuleStreakDays counts consecutive days with a correct answer.
+    AchievementRuleStreakDays AchievementRule = "STREAK_DAYS"
+)
+
+type Achievement struct {
+    ID          string          `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    Code        string          `gorm:"type:varchar(64);uniqueIndex;not null"`
+    Title       string          `gorm:"not null"`
+    Description string          `gorm:"not null"`
+    IconURL     *string
+    Rule        AchievementRule `gorm:"type:achievement_rule;not null"`
+    Threshold   int             `gorm:"not null"`
+    Difficulty  *Difficulty     `gorm:"type:difficulty"`
+    TopicID     *string         `gorm:"type:uuid"`
+    IsActive    bool            `gorm:"not null"`
+    CreatedAt   time.Time       `gorm:"autoCreateTime"`
+    UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+}
+
+type UserAchievement struct {
+    UserID        string    `gorm:"type:uuid;primaryKey"`
+    AchievementID string    `gorm:"type:uuid;primaryKey"`
+    UnlockedAt    time.Time `gorm:"not null"`
+
+    Achievement *Achievement `gorm:"foreignKey:AchievementID"`
+}
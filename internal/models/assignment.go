@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type LatePolicy string
+type AssignmentStatus string
+
+const (
+    LatePolicyAccept  LatePolicy = "ACCEPT"
+    LatePolicyPenalty LatePolicy = "PENALTY"
+    LatePolicyReject  LatePolicy = "REJECT"
+)
+
+const (
+    AssignmentStatusPending       AssignmentStatus = "PENDING"
+    AssignmentStatusOverdue       AssignmentStatus = "OVERDUE"
+    AssignmentStatusMissed        AssignmentStatus = "MISSED"
+    AssignmentStatusCompleted     AssignmentStatus = "COMPLETED"
+    AssignmentStatusCompletedLate AssignmentStatus = "COMPLETED_LATE"
+)
+
+type Assignment struct {
+    ID          string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    ClassroomID string     `gorm:"type:uuid;not null"`
+    Title       string     `gorm:"not null"`
+    Description string     `gorm:"not null"`
+    OpensAt     time.Time  `gorm:"not null"`
+    DueAt       time.Time  `gorm:"not null"`
+    ClosesAt    *time.Time
+    LatePolicy  LatePolicy `gorm:"type:assignment_late_policy;not null"`
+    LatePenalty int        `gorm:"not null"`
+    Weight      float64    `gorm:"not null"`
+    AuthorID    string     `gorm:"type:uuid;not null"`
+    CreatedAt   time.Time  `gorm:"autoCreateTime"`
+    UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+
+    Tasks []AssignmentTask `gorm:"foreignKey:AssignmentID"`
+}
+
+type AssignmentTask struct {
+    AssignmentID string `gorm:"type:uuid;primaryKey"`
+    TaskID       string `gorm:"type:uuid;primaryKey"`
+    Position     int    `gorm:"not null"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type CertificateKind string
+
+const (
+    // CertificateKindCourse is issued when every item of a course is done.
+    CertificateKindCourse CertificateKind = "COURSE"
+    // CertificateKindTopic is issued when the mastery of a topic reaches the
+    // certificate threshold.
+    CertificateKindTopic CertificateKind = "TOPIC"
+)
+
+// Certificate keeps a snapshot of the recipient name and the course or topic
+// title, so it can still be verified after either of them changes.
+type Certificate struct {
+    ID            string          `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    Code          string          `gorm:"type:varchar(32);uniqueIndex;not null"`
+    UserID        string          `gorm:"type:uuid;not null"`
+    Kind          CertificateKind `gorm:"type:certificate_kind;not null"`
+    SubjectID     string          `gorm:"type:uuid;not null"`
+    Title         string          `gorm:"not null"`
+    RecipientName string          `gorm:"not null"`
+    PDFURL        string          `gorm:"column:pdf_url;not null"`
+    IssuedAt      time.Time       `gorm:"not null"`
+}
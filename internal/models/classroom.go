@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type ClassroomRole string
+
+const (
+    ClassroomRoleOwner   ClassroomRole = "OWNER"
+    ClassroomRoleTeacher ClassroomRole = "TEACHER"
+    ClassroomRoleStudent ClassroomRole = "STUDENT"
+)
+
+type Classroom struct {
+    ID          string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    Name        string    `gorm:"not null"`
+    Description string    `gorm:"not null"`
+    SchoolClass *string
+    JoinCode    string    `gorm:"type:varchar(16);uniqueIndex;not null"`
+    OwnerID     string    `gorm:"type:uuid;not null"`
+    CreatedAt   time.Time `gorm:"autoCreateTime"`
+    UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+type ClassroomMember struct {
+    ClassroomID string        `gorm:"type:uuid;primaryKey"`
+    UserID      string        `gorm:"type:uuid;primaryKey"`
+    Role        ClassroomRole `gorm:"type:classroom_member_role;not null"`
+    JoinedAt    time.Time     `gorm:"autoCreateTime"`
+
+    User *User `gorm:"foreignKey:UserID"`
+}
+
+func (m ClassroomMember) IsTeacher() bool {
+    return m.Role == ClassroomRoleOwner || m.Role == ClassroomRoleTeacher
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+type CourseStatus string
+type CourseItemType string
+
+const (
+    CourseStatusDraft     CourseStatus = "DRAFT"
+    CourseStatusPublished CourseStatus = "PUBLISHED"
+    CourseStatusArchived  CourseStatus = "ARCHIVED"
+)
+
+const (
+    CourseItemTopic   CourseItemType = "TOPIC"
+    CourseItemTask    CourseItemType = "TASK"
+    CourseItemReading CourseItemType = "READING"
+)
+
+type Course struct {
+    ID            string       `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    Title         string       `gorm:"not null"`
+    DescriptionMD string       `gorm:"not null"`
+    Status        CourseStatus `gorm:"type:course_status;not null"`
+    SchoolClass   *string
+    AuthorID      string       `gorm:"type:uuid;not null"`
+    CreatedAt     time.Time    `gorm:"autoCreateTime"`
+    UpdatedAt     time.Time    `gorm:"autoUpdateTime"`
+
+    Modules []CourseModule `gorm:"foreignKey:CourseID"`
+}
+
+type CourseModule struct {
+    ID               string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    CourseID         string     `gorm:"type:uuid;not null"`
+    Title            string     `gorm:"not null"`
+    Position         int        `gorm:"not null"`
+    RequiresPrevious bool       `gorm:"not null"`
+    UnlockAt         *time.Time
+    CreatedAt        time.Time  `gorm:"autoCreateTime"`
+    UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+
+    Items []CourseItem `gorm:"foreignKey:ModuleID"`
+}
+
+type CourseItem struct {
+    ID        string         `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    ModuleID  string         `gorm:"type:uuid;not null"`
+    Position  int            `gorm:"not null"`
+    ItemType  CourseItemType `gorm:"type:course_item_type;not null"`
+    Title     string         `gorm:"not null"`
+    BodyMD    string         `gorm:"not null"`
+    TopicID   *string        `gorm:"type:uuid"`
+    TaskID    *string        `gorm:"type:uuid"`
+    CreatedAt time.Time      `gorm:"autoCreateTime"`
+}
+
+type CourseEnrollment struct {
+    ID        string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    CourseID  string    `gorm:"type:uuid;not null"`
+    UserID    string    `gorm:"type:uuid;not null"`
+    CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+type CourseItemCompletion struct {
+    ItemID      string    `gorm:"type:uuid;primaryKey"`
+    UserID      string    `gorm:"type:uuid;primaryKey"`
+    CompletedAt time.Time `gorm:"autoCreateTime"`
+}
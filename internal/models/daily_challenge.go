@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// DailyChallenge is the task of the day for a school class. Days are UTC
+// calendar days stored as midnight UTC; SelectedBy is nil when the task was
+// picked automatically.
+type DailyChallenge struct {
+    ID          string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    SchoolClass string    `gorm:"not null"`
+    Day         time.Time `gorm:"type:date;not null"`
+    TaskID      string    `gorm:"type:uuid;not null"`
+    SelectedBy  *string   `gorm:"type:uuid"`
+    CreatedAt   time.Time `gorm:"autoCreateTime"`
+    UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+}
+
+// IsOver reports whether the challenge's day has ended at now.
+func (c *DailyChallenge) IsOver(now time.Time) bool {
+    return !now.Before(c.Day.AddDate(0, 0, 1))
+}
+
+// DailyChallengeSolve is a user's first correct answer to a daily challenge
+// on its day, with the number of attempts it took.
+type DailyChallengeSolve struct {
+    UserID   string
+    SolvedAt time.Time
+    Attempts int
+}
+
+type DailyChallengeEntry struct {
+    Rank        int
+    UserID      string
+    DisplayName string
+    SolvedAt    time.Time
+    SolveTime   time.Duration
+    Attempts    int
+}
+
+// DailyChallengeLeaderboard ranks the users who solved a challenge by how
+// soon after the start of its day they solved it.
+type DailyChallengeLeaderboard struct {
+    Challenge *DailyChallenge
+    Entries   []DailyChallengeEntry
+    Me        *DailyChallengeEntry
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChange is a requested email change that waits for the code sent to the
+// new address.
+type EmailChange struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	NewEmail  string    `gorm:"type:varchar(255);not null"`
+	Code      string    `gorm:"type:varchar(6);not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -6,10 +6,24 @@ import (
 	"github.com/google/uuid"
 )
 
+type VerificationPurpose string
+
+const (
+	VerificationPurposeEmail         VerificationPurpose = "VERIFY_EMAIL"
+	VerificationPurposePasswordReset VerificationPurpose = "PASSWORD_RESET"
+	// VerificationPurposeEmailChange codes are kept in EmailChange, the
+	// purpose only separates their rate limits.
+	VerificationPurposeEmailChange VerificationPurpose = "CHANGE_EMAIL"
+	// VerificationPurposeTwoFactor counts wrong authenticator codes per user
+	// when two-factor authentication is turned on or off. Nothing is emailed.
+	VerificationPurposeTwoFactor VerificationPurpose = "TWO_FACTOR"
+)
+
 type EmailVerification struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid"`
 	Code      string
+	Purpose   VerificationPurpose `gorm:"type:varchar(32);not null;default:VERIFY_EMAIL"`
 	ExpiresAt time.Time
 	Used      bool
 	CreatedAt time.Time
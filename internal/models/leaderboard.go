@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type LeaderboardScope string
+
+const (
+    LeaderboardScopeGlobal    LeaderboardScope = "global"
+    LeaderboardScopeTopic     LeaderboardScope = "topic"
+    LeaderboardScopeClassroom LeaderboardScope = "classroom"
+    LeaderboardScopeWeekly    LeaderboardScope = "weekly"
+)
+
+type LeaderboardEntry struct {
+    Rank        int64
+    UserID      string
+    DisplayName string
+    Score       int64
+}
+
+type Leaderboard struct {
+    Scope   LeaderboardScope
+    ScopeID string
+    Entries []LeaderboardEntry
+    Me      *LeaderboardEntry
+}
+
+// SolvedTask is the first correct submission of a task by a user.
+type SolvedTask struct {
+    UserID     string
+    TaskID     string
+    TopicID    string
+    Difficulty Difficulty
+    SolvedAt   time.Time
+}
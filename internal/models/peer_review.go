@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type PeerReviewStatus string
+
+const (
+    PeerReviewAssigned  PeerReviewStatus = "ASSIGNED"
+    PeerReviewSubmitted PeerReviewStatus = "SUBMITTED"
+)
+
+// Rubric holds the criteria peers score answers to a MANUAL task by and how
+// many peers review each answer; PeerReviews of 0 turns peer review off.
+type Rubric struct {
+    TaskID      string    `gorm:"type:uuid;primaryKey"`
+    PeerReviews int       `gorm:"not null"`
+    CreatedAt   time.Time `gorm:"autoCreateTime"`
+    UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+    Criteria []RubricCriterion `gorm:"foreignKey:TaskID;references:TaskID"`
+}
+
+func (Rubric) TableName() string {
+    return "task_rubrics"
+}
+
+func (r *Rubric) MaxPoints() int {
+    total := 0
+    for _, c := range r.Criteria {
+        total += c.MaxPoints
+    }
+    return total
+}
+
+type RubricCriterion struct {
+    ID          string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    TaskID      string `gorm:"type:uuid;not null"`
+    Title       string `gorm:"not null"`
+    Description string `gorm:"not null"`
+    MaxPoints   int    `gorm:"not null"`
+    Position    int    `gorm:"not null"`
+}
+
+func (RubricCriterion) TableName() string {
+    return "rubric_criteria"
+}
+
+// PeerReview is one student's review of another student's submission.
+// Reviewers never see whose answer they review, and authors only see their
+// reviewers' scores and comments.
+type PeerReview struct {
+    ID           string           `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    SubmissionID string           `gorm:"type:uuid;not null"`
+    ReviewerID   string           `gorm:"type:uuid;not null"`
+    Status       PeerReviewStatus `gorm:"type:peer_review_status;not null"`
+    Comment      string           `gorm:"not null"`
+    AssignedAt   time.Time        `gorm:"not null"`
+    SubmittedAt  *time.Time
+
+    Submission *Submission       `gorm:"foreignKey:SubmissionID"`
+    Reviewer   *User             `gorm:"foreignKey:ReviewerID"`
+    Scores     []PeerReviewScore `gorm:"foreignKey:ReviewID"`
+}
+
+// Points returns the total of the review's scores.
+func (r *PeerReview) Points() int {
+    total := 0
+    for _, s := range r.Scores {
+        total += s.Points
+    }
+    return total
+}
+
+type PeerReviewScore struct {
+    ReviewID    string `gorm:"type:uuid;primaryKey"`
+    CriterionID string `gorm:"type:uuid;primaryKey"`
+    Points      int    `gorm:"not null"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TokenScope string
+
+const (
+	// TokenScopeRead allows GET requests.
+	TokenScopeRead TokenScope = "read"
+	// TokenScopeWrite allows requests that change data.
+	TokenScopeWrite TokenScope = "write"
+)
+
+// PersonalAccessToken lets scripts and integrations call the API on behalf of
+// a user without a session. Only the hash of the token is stored.
+type PersonalAccessToken struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	TokenHash   string    `gorm:"type:text;uniqueIndex;not null"`
+	TokenPrefix string    `gorm:"type:varchar(16);not null"`
+	Scopes      string    `gorm:"type:text;not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (t *PersonalAccessToken) ScopeList() []TokenScope {
+	var scopes []TokenScope
+	for _, s := range strings.Fields(t.Scopes) {
+		scopes = append(scopes, TokenScope(s))
+	}
+	return scopes
+}
+
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+type TaskAttemptStats struct {
+    TaskID        string
+    Attempts      int
+    Solved        bool
+    LastAttemptAt time.Time
+    LastSolvedAt  *time.Time
+}
+
+type TopicProgress struct {
+    TopicID   string
+    Title     string
+    ParentID  *string
+    Total     int
+    Attempted int
+    Solved    int
+    Mastery   float64
+}
+
+type CourseItemProgress struct {
+    ItemID    string
+    ItemType  CourseItemType
+    Completed bool
+}
+
+type CourseModuleProgress struct {
+    ModuleID       string
+    Title          string
+    Position       int
+    Unlocked       bool
+    Completed      bool
+    UnlockAt       *time.Time
+    TotalItems     int
+    CompletedItems int
+    Items          []CourseItemProgress
+}
+
+type CourseProgress struct {
+    CourseID       string
+    TotalItems     int
+    CompletedItems int
+    Completed      bool
+    Modules        []CourseModuleProgress
+}
+
+type AssignmentTaskStats struct {
+    AssignmentID string
+    UserID       string
+    TaskID       string
+    Attempts     int
+    Solved       bool
+    SolvedOnTime bool
+}
+
+type AssignmentProgress struct {
+    AssignmentID string
+    UserID       string
+    TotalTasks   int
+    Attempted    int
+    SolvedOnTime int
+    SolvedLate   int
+    Score        float64
+    Status       AssignmentStatus
+}
+
+type StudentAssignment struct {
+    Assignment Assignment
+    Progress   AssignmentProgress
+}
+
+type AssignmentStudentReport struct {
+    Student  *User
+    Progress AssignmentProgress
+}
+
+type AssignmentReport struct {
+    Assignment *Assignment
+    Students   []AssignmentStudentReport
+}
+
+type GradebookCell struct {
+    AssignmentID string
+    Score        float64
+    Status       AssignmentStatus
+}
+
+type GradebookRow struct {
+    Student  *User
+    UserID   string
+    Cells    []GradebookCell
+    Weighted float64
+}
+
+type Gradebook struct {
+    ClassroomID string
+    Assignments []Assignment
+    Rows        []GradebookRow
+}
+
+type TaskRecommendation struct {
+    Task             *Task
+    TopicTitle       string
+    TargetDifficulty Difficulty
+    TopicMastery     float64
+    Reasons          []string
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+type QuizMode string
+type QuizSessionStatus string
+
+const (
+    QuizModeFixed  QuizMode = "FIXED"
+    QuizModeRandom QuizMode = "RANDOM"
+)
+
+const (
+    QuizSessionInProgress QuizSessionStatus = "IN_PROGRESS"
+    QuizSessionSubmitted  QuizSessionStatus = "SUBMITTED"
+    QuizSessionExpired    QuizSessionStatus = "EXPIRED"
+)
+
+type Quiz struct {
+    ID               string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    ClassroomID      string     `gorm:"type:uuid;not null"`
+    Title            string     `gorm:"not null"`
+    Description      string     `gorm:"not null"`
+    Mode             QuizMode   `gorm:"type:quiz_mode;not null"`
+    DrawCount        int        `gorm:"not null"`
+    TimeLimitSeconds int        `gorm:"not null"`
+    OpensAt          time.Time  `gorm:"not null"`
+    ClosesAt         *time.Time
+    ResultsReleased  bool       `gorm:"not null"`
+    AuthorID         string     `gorm:"type:uuid;not null"`
+    CreatedAt        time.Time  `gorm:"autoCreateTime"`
+    UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+
+    Tasks []QuizTask `gorm:"foreignKey:QuizID"`
+}
+
+// QuizTask is a task of a fixed quiz or a task in the pool of a random one.
+type QuizTask struct {
+    QuizID   string `gorm:"type:uuid;primaryKey"`
+    TaskID   string `gorm:"type:uuid;primaryKey"`
+    Position int    `gorm:"not null"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+}
+
+type QuizSession struct {
+    ID           string            `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    QuizID       string            `gorm:"type:uuid;not null"`
+    UserID       string            `gorm:"type:uuid;not null"`
+    Status       QuizSessionStatus `gorm:"type:quiz_session_status;not null"`
+    StartedAt    time.Time         `gorm:"not null"`
+    ExpiresAt    time.Time         `gorm:"not null"`
+    SubmittedAt  *time.Time
+    CorrectCount int               `gorm:"not null"`
+    Score        float64           `gorm:"not null"`
+
+    Quiz    *Quiz        `gorm:"foreignKey:QuizID"`
+    User    *User        `gorm:"foreignKey:UserID"`
+    Answers []QuizAnswer `gorm:"foreignKey:SessionID"`
+}
+
+func (s *QuizSession) IsFinished() bool {
+    return s.Status != QuizSessionInProgress
+}
+
+// QuizAnswer holds the task drawn for the session and the latest saved answer.
+type QuizAnswer struct {
+    SessionID string    `gorm:"type:uuid;primaryKey"`
+    TaskID    string    `gorm:"type:uuid;primaryKey"`
+    Position  int       `gorm:"not null"`
+    Answer    string    `gorm:"not null"`
+    IsCorrect bool      `gorm:"not null"`
+    UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+}
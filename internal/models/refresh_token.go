@@ -7,18 +7,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// RefreshToken is one link of a session: every refresh revokes the presented
+// token and issues a new one with the same SessionID whose ParentID points
+// back to it. The tokens of a session form its family.
 type RefreshToken struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserID     uuid.UUID `gorm:"not null;index"`
-	TokenHash  string    `gorm:"type:text;uniqueIndex;not null"`
-	Revoked    bool      `gorm:"default:false"`
-	ExpiresAt  time.Time `gorm:"not null"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID     uuid.UUID  `gorm:"not null;index"`
+	SessionID  uuid.UUID  `gorm:"type:uuid;not null"`
+	ParentID   *uuid.UUID `gorm:"type:uuid"`
+	TokenHash  string     `gorm:"type:text;uniqueIndex;not null"`
+	Revoked    bool       `gorm:"default:false"`
+	DeviceName string     `gorm:"not null"`
+	IPAddress  string     `gorm:"type:varchar(64);not null"`
+	UserAgent  string     `gorm:"not null"`
+	LastUsedAt time.Time  `gorm:"not null"`
+	TwoFactor  bool       `gorm:"not null;default:false"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
 }
 
 func (r *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
 	if r.ExpiresAt.IsZero() {
 		r.ExpiresAt = time.Now().Add(30 * 24 * time.Hour)
 	}
+	if r.SessionID == uuid.Nil {
+		r.SessionID = uuid.New()
+	}
+	if r.LastUsedAt.IsZero() {
+		r.LastUsedAt = time.Now()
+	}
 	return
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ReviewSchedule is the SM-2 repetition state of a solved task for a user.
+type ReviewSchedule struct {
+    UserID         string    `gorm:"type:uuid;primaryKey"`
+    TaskID         string    `gorm:"type:uuid;primaryKey"`
+    Repetitions    int       `gorm:"not null"`
+    IntervalDays   int       `gorm:"not null"`
+    EaseFactor     float64   `gorm:"not null"`
+    DueAt          time.Time `gorm:"not null"`
+    LastReviewedAt time.Time `gorm:"not null"`
+    Lapses         int       `gorm:"not null"`
+    CreatedAt      time.Time `gorm:"autoCreateTime"`
+    UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type SchoolClass struct {
+    ID              string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    Code            string    `gorm:"uniqueIndex;not null"`
+    Title           string    `gorm:"not null"`
+    EducationSystem string    `gorm:"not null;default:'SCHOOL'"`
+    SortOrder       int       `gorm:"not null;default:0"`
+    IsActive        bool      `gorm:"not null"`
+    CreatedAt       time.Time `gorm:"autoCreateTime"`
+    UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
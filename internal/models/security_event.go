@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SecurityEventKind string
+
+const (
+	// SecurityEventRefreshTokenReuse is recorded when a refresh token that was
+	// already rotated is presented again, which means it was copied.
+	SecurityEventRefreshTokenReuse SecurityEventKind = "REFRESH_TOKEN_REUSE"
+)
+
+type SecurityEvent struct {
+	ID        uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    uuid.UUID         `gorm:"type:uuid;not null"`
+	Kind      SecurityEventKind `gorm:"type:security_event_kind;not null"`
+	SessionID *uuid.UUID        `gorm:"type:uuid"`
+	IPAddress string            `gorm:"type:varchar(64);not null"`
+	UserAgent string            `gorm:"not null"`
+	CreatedAt time.Time         `gorm:"autoCreateTime"`
+}
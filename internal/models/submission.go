@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type SubmissionStatus string
+
+const (
+    SubmissionStatusGraded        SubmissionStatus = "GRADED"
+    SubmissionStatusPendingReview SubmissionStatus = "PENDING_REVIEW"
+)
+
+// ManualPassingScore is the lowest manual grade that counts as a correct
+// answer.
+const ManualPassingScore = 60
+
+type Submission struct {
+    ID           string           `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+    TaskID       string           `gorm:"type:uuid;not null"`
+    UserID       string           `gorm:"type:uuid;not null"`
+    AssignmentID *string          `gorm:"type:uuid"`
+    Answer       string           `gorm:"not null"`
+    IsCorrect    bool             `gorm:"not null"`
+    IsLate       bool             `gorm:"not null"`
+    Status       SubmissionStatus `gorm:"type:submission_status;not null;default:'GRADED'"`
+    Score        *int
+    PeerScore    *int
+    Feedback     *string
+    GradedBy     *string          `gorm:"type:uuid"`
+    GradedAt     *time.Time
+    CreatedAt    time.Time        `gorm:"autoCreateTime"`
+
+    Task *Task `gorm:"foreignKey:TaskID"`
+    User *User `gorm:"foreignKey:UserID"`
+}
+
+func (Submission) TableName() string {
+    return "task_submissions"
+}
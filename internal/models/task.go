@@ -7,6 +7,7 @@ import (
 type Difficulty string
 type TaskStatus string
 type AnswerType string
+type GradingMode string
 
 const (
     DifficultyEasy    Difficulty = "EASY"
@@ -27,6 +28,13 @@ const (
     AnswerTypeFormula AnswerType  = "FORMULA"
 )
 
+// GradingModeManual tasks are graded by a teacher instead of comparing the
+// answer with CorrectAnswer.
+const (
+    GradingModeAuto   GradingMode = "AUTO"
+    GradingModeManual GradingMode = "MANUAL"
+)
+
 type Task struct {
     ID              string       `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
     Title           string       `gorm:"not null"`
@@ -42,6 +50,7 @@ type Task struct {
     OfficialSolution string      
     CorrectAnswer     string      
     AnswerType        AnswerType  `gorm:"type:answer_type;not null"`
+    GradingMode       GradingMode `gorm:"type:grading_mode;not null;default:'AUTO'"`
     ImageURL          string      
 
     Topic  *Topic `gorm:"foreignKey:TopicID"`
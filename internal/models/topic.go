@@ -7,7 +7,23 @@ type Topic struct {
     Title       string     `gorm:"not null"`
     Slug        string     `gorm:"unique;not null"`
     ParentID    *string    `gorm:"type:uuid"`
-    SchoolClass string     `gorm:"type:school_class;not null"`
+    SchoolClass string     `gorm:"not null"`
     CreatedAt   time.Time  `gorm:"autoCreateTime"`
     UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+
+    Prerequisites []TopicPrerequisite `gorm:"foreignKey:TopicID"`
+}
+
+// TopicPrerequisite marks a topic that should be mastered before TopicID.
+type TopicPrerequisite struct {
+    TopicID        string `gorm:"type:uuid;primaryKey"`
+    PrerequisiteID string `gorm:"type:uuid;primaryKey"`
+}
+
+func (t *Topic) PrerequisiteIDs() []string {
+    ids := make([]string, 0, len(t.Prerequisites))
+    for _, p := range t.Prerequisites {
+        ids = append(ids, p.PrerequisiteID)
+    }
+    return ids
 }
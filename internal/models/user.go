@@ -35,6 +35,7 @@ type User struct {
 	AvatarURL    *string        `gorm:"type:text" json:"avatar,omitempty"`
 	Role         UserRole       `gorm:"type:userRole;default:'Student';not null"`
 	Status       UserStatus     `gorm:"type:userStatus;default:'PENDING';not null"`
+	Timezone     string         `gorm:"type:varchar(64);default:'UTC';not null"`
 	CreatedAt    time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt    gorm.DeletedAt `gorm:"index"`	
@@ -42,4 +43,14 @@ type User struct {
 	BannedAt     *time.Time
 	BannedUntil  *time.Time
 	BanReason    *string
+	// TOTPSecret is set while enrolling; two-factor login is only required
+	// once TOTPEnabledAt is set as well.
+	TOTPSecret    *string    `gorm:"column:totp_secret;type:text"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;not null;default:0"`
+}
+
+// TwoFactorEnabled reports whether signing in requires a TOTP code.
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPSecret != nil && u.TOTPEnabledAt != nil
 }
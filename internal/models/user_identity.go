@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an OpenID Connect provider.
+type UserIdentity struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider    string    `gorm:"type:varchar(64);not null"`
+	Subject     string    `gorm:"type:varchar(255);not null"`
+	Email       string    `gorm:"type:varchar(255);not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	LastLoginAt time.Time `gorm:"not null"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type StreakDayKind string
+
+const (
+    StreakDayActive StreakDayKind = "ACTIVE"
+    StreakDayFrozen StreakDayKind = "FROZEN"
+)
+
+// UserStats holds a user's XP and daily streak. Dates are calendar days in
+// the user's timezone, stored as midnight UTC.
+type UserStats struct {
+    UserID         string     `gorm:"type:uuid;primaryKey"`
+    XP             int64      `gorm:"column:xp;not null"`
+    CurrentStreak  int        `gorm:"not null"`
+    LongestStreak  int        `gorm:"not null"`
+    LastActiveDate *time.Time `gorm:"type:date"`
+    StreakFreezes  int        `gorm:"not null"`
+    CreatedAt      time.Time  `gorm:"autoCreateTime"`
+    UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+}
+
+// StreakDay is a day that counts towards the streak, either because the user
+// was active or because a streak freeze covered it.
+type StreakDay struct {
+    UserID string        `gorm:"type:uuid;primaryKey"`
+    Day    time.Time     `gorm:"type:date;primaryKey"`
+    Kind   StreakDayKind `gorm:"type:streak_day_kind;not null"`
+}
+
+// StreakCalendar lists the streak days of one month in the user's timezone.
+type StreakCalendar struct {
+    Month    string
+    Timezone string
+    Days     []StreakDay
+    Stats    *UserStats
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IAchievementRepository interface {
+	Create(ctx context.Context, achievement *models.Achievement) error
+	FindByID(ctx context.Context, id string) (*models.Achievement, error)
+	FindAll(ctx context.Context, activeOnly bool) ([]models.Achievement, error)
+	Update(ctx context.Context, achievement *models.Achievement) error
+	Delete(ctx context.Context, id string) error
+
+	FindLocked(ctx context.Context, userID string) ([]models.Achievement, error)
+	Unlock(ctx context.Context, unlocked *models.UserAchievement) (bool, error)
+	FindByUser(ctx context.Context, userID string) ([]models.UserAchievement, error)
+}
+
+type AchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+func (r *AchievementRepository) Create(ctx context.Context, achievement *models.Achievement) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(achievement).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AchievementRepository) FindByID(ctx context.Context, id string) (*models.Achievement, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.FindByID")
+	defer span.End()
+
+	var achievement models.Achievement
+	err := r.db.WithContext(ctx).First(&achievement, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &achievement, nil
+}
+
+func (r *AchievementRepository) FindAll(ctx context.Context, activeOnly bool) ([]models.Achievement, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.FindAll")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if activeOnly {
+		query = query.Where("is_active")
+	}
+
+	var achievements []models.Achievement
+	if err := query.Find(&achievements).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *AchievementRepository) Update(ctx context.Context, achievement *models.Achievement) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Save(achievement).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AchievementRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.Achievement{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindLocked returns active achievements the user has not unlocked yet.
+func (r *AchievementRepository) FindLocked(ctx context.Context, userID string) ([]models.Achievement, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.FindLocked")
+	defer span.End()
+
+	var achievements []models.Achievement
+	err := r.db.WithContext(ctx).
+		Where("is_active").
+		Where("NOT EXISTS (SELECT 1 FROM user_achievements ua WHERE ua.achievement_id = achievements.id AND ua.user_id = ?)", userID).
+		Find(&achievements).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// Unlock records an unlocked achievement and reports whether it is new.
+func (r *AchievementRepository) Unlock(ctx context.Context, unlocked *models.UserAchievement) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.Unlock")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Omit("Achievement").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(unlocked)
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *AchievementRepository) FindByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AchievementRepository.FindByUser")
+	defer span.End()
+
+	var unlocked []models.UserAchievement
+	err := r.db.WithContext(ctx).
+		Preload("Achievement").
+		Where("user_id = ?", userID).
+		Order("unlocked_at DESC").
+		Find(&unlocked).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return unlocked, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IAssignmentRepository interface {
+	Create(ctx context.Context, assignment *models.Assignment) error
+	FindByID(ctx context.Context, id string) (*models.Assignment, error)
+	FindByClassroom(ctx context.Context, classroomID string) ([]models.Assignment, error)
+	FindForStudent(ctx context.Context, userID string) ([]models.Assignment, error)
+	Update(ctx context.Context, assignment *models.Assignment) error
+	Delete(ctx context.Context, id string) error
+	UpdateWeights(ctx context.Context, classroomID string, weights map[string]float64) error
+}
+
+type AssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAssignmentRepository(db *gorm.DB) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+func preloadAssignmentTasks(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (r *AssignmentRepository) Create(ctx context.Context, assignment *models.Assignment) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Tasks.Task").Create(assignment).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AssignmentRepository) FindByID(ctx context.Context, id string) (*models.Assignment, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.FindByID")
+	defer span.End()
+
+	var assignment models.Assignment
+	err := r.db.WithContext(ctx).
+		Preload("Tasks", preloadAssignmentTasks).
+		Preload("Tasks.Task").
+		First(&assignment, "id = ?", id).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+func (r *AssignmentRepository) FindByClassroom(ctx context.Context, classroomID string) ([]models.Assignment, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.FindByClassroom")
+	defer span.End()
+
+	var assignments []models.Assignment
+	err := r.db.WithContext(ctx).
+		Preload("Tasks", preloadAssignmentTasks).
+		Where("classroom_id = ?", classroomID).
+		Order("due_at ASC").
+		Find(&assignments).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+func (r *AssignmentRepository) FindForStudent(ctx context.Context, userID string) ([]models.Assignment, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.FindForStudent")
+	defer span.End()
+
+	var assignments []models.Assignment
+	err := r.db.WithContext(ctx).
+		Preload("Tasks", preloadAssignmentTasks).
+		Joins("JOIN classroom_members m ON m.classroom_id = assignments.classroom_id").
+		Where("m.user_id = ? AND m.role = ?", userID, models.ClassroomRoleStudent).
+		Order("assignments.due_at ASC").
+		Find(&assignments).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// Update saves assignment fields and replaces its task list.
+func (r *AssignmentRepository) Update(ctx context.Context, assignment *models.Assignment) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tasks").Save(assignment).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("assignment_id = ?", assignment.ID).Delete(&models.AssignmentTask{}).Error; err != nil {
+			return err
+		}
+		if len(assignment.Tasks) == 0 {
+			return nil
+		}
+		return tx.Omit("Task").Create(&assignment.Tasks).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AssignmentRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.Assignment{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AssignmentRepository) UpdateWeights(ctx context.Context, classroomID string, weights map[string]float64) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "AssignmentRepository.UpdateWeights")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, weight := range weights {
+			res := tx.Model(&models.Assignment{}).
+				Where("id = ? AND classroom_id = ?", id, classroomID).
+				Update("weight", weight)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
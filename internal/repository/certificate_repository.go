@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICertificateRepository interface {
+	Create(ctx context.Context, certificate *models.Certificate) (bool, error)
+	FindByCode(ctx context.Context, code string) (*models.Certificate, error)
+	FindByUser(ctx context.Context, userID string) ([]models.Certificate, error)
+}
+
+type CertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+// Create stores a certificate and reports whether it is new: a user gets at
+// most one certificate per course or topic.
+func (r *CertificateRepository) Create(ctx context.Context, certificate *models.Certificate) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CertificateRepository.Create")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}, {Name: "subject_id"}},
+			DoNothing: true,
+		}).
+		Create(certificate)
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *CertificateRepository) FindByCode(ctx context.Context, code string) (*models.Certificate, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CertificateRepository.FindByCode")
+	defer span.End()
+
+	var certificate models.Certificate
+	err := r.db.WithContext(ctx).First(&certificate, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+func (r *CertificateRepository) FindByUser(ctx context.Context, userID string) ([]models.Certificate, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CertificateRepository.FindByUser")
+	defer span.End()
+
+	var certificates []models.Certificate
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("issued_at DESC").
+		Find(&certificates).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return certificates, nil
+}
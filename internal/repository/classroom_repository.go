@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IClassroomRepository interface {
+	Create(ctx context.Context, classroom *models.Classroom) error
+	FindByID(ctx context.Context, id string) (*models.Classroom, error)
+	FindByJoinCode(ctx context.Context, code string) (*models.Classroom, error)
+	FindByMember(ctx context.Context, userID string) ([]models.Classroom, error)
+	Update(ctx context.Context, classroom *models.Classroom) error
+	Delete(ctx context.Context, id string) error
+
+	AddMember(ctx context.Context, member *models.ClassroomMember) error
+	FindMember(ctx context.Context, classroomID, userID string) (*models.ClassroomMember, error)
+	FindMembers(ctx context.Context, classroomID string) ([]models.ClassroomMember, error)
+	UpdateMemberRole(ctx context.Context, classroomID, userID string, role models.ClassroomRole) error
+	RemoveMember(ctx context.Context, classroomID, userID string) error
+	TransferOwnership(ctx context.Context, classroomID, fromUserID, toUserID string) error
+
+	FindStudentsForTeacher(ctx context.Context, teacherID string) ([]models.User, error)
+	IsStudentOfTeacher(ctx context.Context, teacherID, studentID string) (bool, error)
+	FindStudentMemberships(ctx context.Context, userID string) ([]models.ClassroomMember, error)
+}
+
+type ClassroomRepository struct {
+	db *gorm.DB
+}
+
+func NewClassroomRepository(db *gorm.DB) *ClassroomRepository {
+	return &ClassroomRepository{db: db}
+}
+
+// Create inserts the classroom together with its owner membership.
+func (r *ClassroomRepository) Create(ctx context.Context, classroom *models.Classroom) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(classroom).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ClassroomMember{
+			ClassroomID: classroom.ID,
+			UserID:      classroom.OwnerID,
+			Role:        models.ClassroomRoleOwner,
+		}).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) FindByID(ctx context.Context, id string) (*models.Classroom, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindByID")
+	defer span.End()
+
+	var classroom models.Classroom
+	err := r.db.WithContext(ctx).First(&classroom, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &classroom, nil
+}
+
+func (r *ClassroomRepository) FindByJoinCode(ctx context.Context, code string) (*models.Classroom, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindByJoinCode")
+	defer span.End()
+
+	var classroom models.Classroom
+	err := r.db.WithContext(ctx).First(&classroom, "join_code = ?", code).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &classroom, nil
+}
+
+func (r *ClassroomRepository) FindByMember(ctx context.Context, userID string) ([]models.Classroom, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindByMember")
+	defer span.End()
+
+	var classrooms []models.Classroom
+	err := r.db.WithContext(ctx).
+		Joins("JOIN classroom_members m ON m.classroom_id = classrooms.id").
+		Where("m.user_id = ?", userID).
+		Order("classrooms.created_at DESC").
+		Find(&classrooms).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return classrooms, nil
+}
+
+func (r *ClassroomRepository) Update(ctx context.Context, classroom *models.Classroom) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Save(classroom).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.Classroom{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) AddMember(ctx context.Context, member *models.ClassroomMember) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.AddMember")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("User").Create(member).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) FindMember(ctx context.Context, classroomID, userID string) (*models.ClassroomMember, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindMember")
+	defer span.End()
+
+	var member models.ClassroomMember
+	err := r.db.WithContext(ctx).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		First(&member).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *ClassroomRepository) FindMembers(ctx context.Context, classroomID string) ([]models.ClassroomMember, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindMembers")
+	defer span.End()
+
+	var members []models.ClassroomMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("classroom_id = ?", classroomID).
+		Order("role ASC, joined_at ASC").
+		Find(&members).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *ClassroomRepository) UpdateMemberRole(ctx context.Context, classroomID, userID string, role models.ClassroomRole) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.UpdateMemberRole")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ClassroomMember{}).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		Update("role", role).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) RemoveMember(ctx context.Context, classroomID, userID string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.RemoveMember")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		Delete(&models.ClassroomMember{}).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// TransferOwnership demotes the current owner to co-teacher and promotes the
+// target member in a single transaction.
+func (r *ClassroomRepository) TransferOwnership(ctx context.Context, classroomID, fromUserID, toUserID string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.TransferOwnership")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ClassroomMember{}).
+			Where("classroom_id = ? AND user_id = ?", classroomID, fromUserID).
+			Update("role", models.ClassroomRoleTeacher).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClassroomMember{}).
+			Where("classroom_id = ? AND user_id = ?", classroomID, toUserID).
+			Update("role", models.ClassroomRoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Classroom{}).
+			Where("id = ?", classroomID).
+			Update("owner_id", toUserID).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ClassroomRepository) FindStudentsForTeacher(ctx context.Context, teacherID string) ([]models.User, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindStudentsForTeacher")
+	defer span.End()
+
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Where(`id IN (
+			SELECT s.user_id FROM classroom_members s
+			JOIN classroom_members t ON t.classroom_id = s.classroom_id
+			WHERE t.user_id = ? AND t.role IN ('OWNER', 'TEACHER') AND s.role = 'STUDENT'
+		)`, teacherID).
+		Order("display_name ASC").
+		Find(&users).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *ClassroomRepository) IsStudentOfTeacher(ctx context.Context, teacherID, studentID string) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.IsStudentOfTeacher")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ClassroomMember{}).
+		Joins("JOIN classroom_members s ON s.classroom_id = classroom_members.classroom_id").
+		Where("classroom_members.user_id = ? AND classroom_members.role IN ?", teacherID,
+			[]models.ClassroomRole{models.ClassroomRoleOwner, models.ClassroomRoleTeacher}).
+		Where("s.user_id = ? AND s.role = ?", studentID, models.ClassroomRoleStudent).
+		Count(&count).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindStudentMemberships returns the classrooms the user studies in. An empty
+// userID returns the student memberships of every classroom.
+func (r *ClassroomRepository) FindStudentMemberships(ctx context.Context, userID string) ([]models.ClassroomMember, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ClassroomRepository.FindStudentMemberships")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Where("role = ?", models.ClassroomRoleStudent)
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var members []models.ClassroomMember
+	if err := query.Find(&members).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return members, nil
+}
@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICourseRepository interface {
+	Create(ctx context.Context, course *models.Course) error
+	FindAll(ctx context.Context, status *models.CourseStatus) ([]models.Course, error)
+	FindByID(ctx context.Context, id string) (*models.Course, error)
+	Update(ctx context.Context, course *models.Course) error
+	Delete(ctx context.Context, id string) error
+
+	CreateModule(ctx context.Context, module *models.CourseModule) error
+	FindModule(ctx context.Context, courseID, moduleID string) (*models.CourseModule, error)
+	UpdateModule(ctx context.Context, module *models.CourseModule) error
+	DeleteModule(ctx context.Context, moduleID string) error
+	MaxModulePosition(ctx context.Context, courseID string) (int, error)
+
+	CreateItem(ctx context.Context, item *models.CourseItem) error
+	FindItem(ctx context.Context, moduleID, itemID string) (*models.CourseItem, error)
+	DeleteItem(ctx context.Context, itemID string) error
+	MaxItemPosition(ctx context.Context, moduleID string) (int, error)
+
+	Enroll(ctx context.Context, enrollment *models.CourseEnrollment) error
+	Unenroll(ctx context.Context, courseID, userID string) error
+	FindEnrollment(ctx context.Context, courseID, userID string) (*models.CourseEnrollment, error)
+	FindEnrolledCourses(ctx context.Context, userID string) ([]models.Course, error)
+
+	CompleteItem(ctx context.Context, completion *models.CourseItemCompletion) error
+	GetCompletedItemIDs(ctx context.Context, courseID, userID string) ([]string, error)
+}
+
+type CourseRepository struct {
+	db *gorm.DB
+}
+
+func NewCourseRepository(db *gorm.DB) *CourseRepository {
+	return &CourseRepository{db: db}
+}
+
+func (r *CourseRepository) Create(ctx context.Context, course *models.Course) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Modules").Create(course).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) FindAll(ctx context.Context, status *models.CourseStatus) ([]models.Course, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindAll")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var courses []models.Course
+	err := query.Find(&courses).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (r *CourseRepository) FindByID(ctx context.Context, id string) (*models.Course, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindByID")
+	defer span.End()
+
+	var course models.Course
+	err := r.db.WithContext(ctx).
+		Preload("Modules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Modules.Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&course, "id = ?", id).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &course, nil
+}
+
+func (r *CourseRepository) Update(ctx context.Context, course *models.Course) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Modules").Save(course).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.Course{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) CreateModule(ctx context.Context, module *models.CourseModule) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.CreateModule")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Items").Create(module).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) FindModule(ctx context.Context, courseID, moduleID string) (*models.CourseModule, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindModule")
+	defer span.End()
+
+	var module models.CourseModule
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND course_id = ?", moduleID, courseID).
+		First(&module).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &module, nil
+}
+
+func (r *CourseRepository) UpdateModule(ctx context.Context, module *models.CourseModule) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.UpdateModule")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Items").Save(module).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) DeleteModule(ctx context.Context, moduleID string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.DeleteModule")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.CourseModule{}, "id = ?", moduleID).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) MaxModulePosition(ctx context.Context, courseID string) (int, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.MaxModulePosition")
+	defer span.End()
+
+	var max int
+	err := r.db.WithContext(ctx).
+		Model(&models.CourseModule{}).
+		Where("course_id = ?", courseID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&max).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return max, nil
+}
+
+func (r *CourseRepository) CreateItem(ctx context.Context, item *models.CourseItem) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.CreateItem")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(item).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) FindItem(ctx context.Context, moduleID, itemID string) (*models.CourseItem, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindItem")
+	defer span.End()
+
+	var item models.CourseItem
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND module_id = ?", itemID, moduleID).
+		First(&item).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *CourseRepository) DeleteItem(ctx context.Context, itemID string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.DeleteItem")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.CourseItem{}, "id = ?", itemID).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) MaxItemPosition(ctx context.Context, moduleID string) (int, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.MaxItemPosition")
+	defer span.End()
+
+	var max int
+	err := r.db.WithContext(ctx).
+		Model(&models.CourseItem{}).
+		Where("module_id = ?", moduleID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&max).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return max, nil
+}
+
+func (r *CourseRepository) Enroll(ctx context.Context, enrollment *models.CourseEnrollment) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.Enroll")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(enrollment).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) Unenroll(ctx context.Context, courseID, userID string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.Unenroll")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Delete(&models.CourseEnrollment{}).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) FindEnrollment(ctx context.Context, courseID, userID string) (*models.CourseEnrollment, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindEnrollment")
+	defer span.End()
+
+	var enrollment models.CourseEnrollment
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		First(&enrollment).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
+
+func (r *CourseRepository) FindEnrolledCourses(ctx context.Context, userID string) ([]models.Course, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.FindEnrolledCourses")
+	defer span.End()
+
+	var courses []models.Course
+	err := r.db.WithContext(ctx).
+		Joins("JOIN course_enrollments e ON e.course_id = courses.id").
+		Where("e.user_id = ?", userID).
+		Order("e.created_at DESC").
+		Find(&courses).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (r *CourseRepository) CompleteItem(ctx context.Context, completion *models.CourseItemCompletion) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.CompleteItem")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(completion).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) GetCompletedItemIDs(ctx context.Context, courseID, userID string) ([]string, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "CourseRepository.GetCompletedItemIDs")
+	defer span.End()
+
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.CourseItemCompletion{}).
+		Joins("JOIN course_items i ON i.id = course_item_completions.item_id").
+		Joins("JOIN course_modules m ON m.id = i.module_id").
+		Where("m.course_id = ? AND course_item_completions.user_id = ?", courseID, userID).
+		Pluck("course_item_completions.item_id", &ids).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IDailyChallengeRepository interface {
+	FindByDay(ctx context.Context, schoolClass string, day time.Time) (*models.DailyChallenge, error)
+	Create(ctx context.Context, challenge *models.DailyChallenge) (bool, error)
+	Upsert(ctx context.Context, challenge *models.DailyChallenge) error
+	FindHistory(ctx context.Context, schoolClass string, before time.Time, limit int) ([]models.DailyChallenge, error)
+	GetSolves(ctx context.Context, taskID string, from, to time.Time) ([]models.DailyChallengeSolve, error)
+}
+
+type DailyChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewDailyChallengeRepository(db *gorm.DB) *DailyChallengeRepository {
+	return &DailyChallengeRepository{db: db}
+}
+
+func (r *DailyChallengeRepository) FindByDay(ctx context.Context, schoolClass string, day time.Time) (*models.DailyChallenge, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "DailyChallengeRepository.FindByDay")
+	defer span.End()
+
+	var challenge models.DailyChallenge
+	err := r.db.WithContext(ctx).
+		Preload("Task").
+		Where("school_class = ? AND day = ?", schoolClass, day).
+		First(&challenge).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// Create stores the challenge unless the class already has one for that day
+// and reports whether it was stored.
+func (r *DailyChallengeRepository) Create(ctx context.Context, challenge *models.DailyChallenge) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "DailyChallengeRepository.Create")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "school_class"}, {Name: "day"}},
+			DoNothing: true,
+		}).
+		Create(challenge)
+
+	if result.Error != nil {
+		span.RecordError(result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Upsert stores the challenge, replacing the task of an existing challenge
+// for the same class and day.
+func (r *DailyChallengeRepository) Upsert(ctx context.Context, challenge *models.DailyChallenge) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "DailyChallengeRepository.Upsert")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "school_class"}, {Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"task_id", "selected_by", "updated_at"}),
+		}).
+		Create(challenge).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindHistory returns the class's challenges before the given day, newest
+// first.
+func (r *DailyChallengeRepository) FindHistory(ctx context.Context, schoolClass string, before time.Time, limit int) ([]models.DailyChallenge, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "DailyChallengeRepository.FindHistory")
+	defer span.End()
+
+	var challenges []models.DailyChallenge
+	err := r.db.WithContext(ctx).
+		Preload("Task").
+		Where("school_class = ? AND day < ?", schoolClass, before).
+		Order("day DESC").
+		Limit(limit).
+		Find(&challenges).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return challenges, nil
+}
+
+// GetSolves returns every user's first correct answer to the task within
+// [from, to), fastest first, counting the attempts made in that window up
+// to and including it.
+func (r *DailyChallengeRepository) GetSolves(ctx context.Context, taskID string, from, to time.Time) ([]models.DailyChallengeSolve, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "DailyChallengeRepository.GetSolves")
+	defer span.End()
+
+	var solves []models.DailyChallengeSolve
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT s.user_id, s.solved_at, COUNT(a.id) AS attempts
+			FROM (
+				SELECT user_id, MIN(created_at) AS solved_at
+				FROM task_submissions
+				WHERE task_id = ? AND is_correct AND created_at >= ? AND created_at < ?
+				GROUP BY user_id
+			) s
+			JOIN task_submissions a
+				ON a.user_id = s.user_id AND a.task_id = ? AND a.created_at >= ? AND a.created_at <= s.solved_at
+			GROUP BY s.user_id, s.solved_at
+			ORDER BY s.solved_at ASC, attempts ASC`,
+			taskID, from, to, taskID, from).
+		Scan(&solves).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return solves, nil
+}
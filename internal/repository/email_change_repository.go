@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IEmailChangeRepository interface {
+	Replace(ctx context.Context, change *models.EmailChange) error
+	FindByUser(ctx context.Context, userID uuid.UUID) (*models.EmailChange, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+	Apply(ctx context.Context, change *models.EmailChange) error
+}
+
+type EmailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// Replace drops the pending change of the user and stores the new one.
+func (r *EmailChangeRepository) Replace(ctx context.Context, change *models.EmailChange) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "EmailChangeRepository.Replace")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", change.UserID).Delete(&models.EmailChange{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *EmailChangeRepository) FindByUser(ctx context.Context, userID uuid.UUID) (*models.EmailChange, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "EmailChangeRepository.FindByUser")
+	defer span.End()
+
+	var change models.EmailChange
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&change).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+func (r *EmailChangeRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "EmailChangeRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.EmailChange{}).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Apply switches the user to the new email and drops the pending change. It
+// fails with a unique violation if the address was taken in the meantime.
+func (r *EmailChangeRepository) Apply(ctx context.Context, change *models.EmailChange) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "EmailChangeRepository.Apply")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", change.UserID).Update("email", change.NewEmail).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", change.ID).Delete(&models.EmailChange{}).Error
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
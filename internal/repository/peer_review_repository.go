@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IPeerReviewRepository interface {
+	FindRubric(ctx context.Context, taskID string) (*models.Rubric, error)
+	SaveRubric(ctx context.Context, rubric *models.Rubric) error
+	CountByTask(ctx context.Context, taskID string) (int64, error)
+	FindLatestSubmissions(ctx context.Context, taskID string) ([]models.Submission, error)
+	FindByTask(ctx context.Context, taskID string) ([]models.PeerReview, error)
+	CreateReviews(ctx context.Context, reviews []models.PeerReview) error
+	FindByID(ctx context.Context, id string) (*models.PeerReview, error)
+	FindByReviewer(ctx context.Context, reviewerID string) ([]models.PeerReview, error)
+	FindBySubmission(ctx context.Context, submissionID string) ([]models.PeerReview, error)
+	Submit(ctx context.Context, review *models.PeerReview) (bool, error)
+	SavePeerScore(ctx context.Context, submissionID string, score int) error
+}
+
+type PeerReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewPeerReviewRepository(db *gorm.DB) *PeerReviewRepository {
+	return &PeerReviewRepository{db: db}
+}
+
+func (r *PeerReviewRepository) FindRubric(ctx context.Context, taskID string) (*models.Rubric, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindRubric")
+	defer span.End()
+
+	var rubric models.Rubric
+	err := r.db.WithContext(ctx).
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&rubric, "task_id = ?", taskID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &rubric, nil
+}
+
+// SaveRubric creates or replaces the rubric of a task together with its
+// criteria.
+func (r *PeerReviewRepository) SaveRubric(ctx context.Context, rubric *models.Rubric) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.SaveRubric")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Criteria").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "task_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"peer_reviews", "updated_at"}),
+		}).Create(rubric).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", rubric.TaskID).Delete(&models.RubricCriterion{}).Error; err != nil {
+			return err
+		}
+		if len(rubric.Criteria) == 0 {
+			return nil
+		}
+		return tx.Create(&rubric.Criteria).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// CountByTask counts the peer reviews assigned for answers to the task.
+func (r *PeerReviewRepository) CountByTask(ctx context.Context, taskID string) (int64, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.CountByTask")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.PeerReview{}).
+		Joins("JOIN task_submissions s ON s.id = peer_reviews.submission_id").
+		Where("s.task_id = ?", taskID).
+		Count(&count).Error
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindLatestSubmissions returns the newest submission of every user who
+// answered the task.
+func (r *PeerReviewRepository) FindLatestSubmissions(ctx context.Context, taskID string) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindLatestSubmissions")
+	defer span.End()
+
+	var submissions []models.Submission
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (user_id) * FROM task_submissions
+			WHERE task_id = ?
+			ORDER BY user_id, created_at DESC`, taskID).
+		Scan(&submissions).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+func (r *PeerReviewRepository) FindByTask(ctx context.Context, taskID string) ([]models.PeerReview, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindByTask")
+	defer span.End()
+
+	var reviews []models.PeerReview
+	err := r.db.WithContext(ctx).
+		Joins("JOIN task_submissions s ON s.id = peer_reviews.submission_id").
+		Where("s.task_id = ?", taskID).
+		Find(&reviews).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// CreateReviews stores new review assignments, skipping reviewers that are
+// already assigned to the submission.
+func (r *PeerReviewRepository) CreateReviews(ctx context.Context, reviews []models.PeerReview) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.CreateReviews")
+	defer span.End()
+
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Omit("Submission", "Reviewer", "Scores").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "submission_id"}, {Name: "reviewer_id"}},
+			DoNothing: true,
+		}).
+		Create(&reviews).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PeerReviewRepository) FindByID(ctx context.Context, id string) (*models.PeerReview, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindByID")
+	defer span.End()
+
+	var review models.PeerReview
+	err := r.db.WithContext(ctx).
+		Preload("Submission.Task").
+		Preload("Scores").
+		First(&review, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *PeerReviewRepository) FindByReviewer(ctx context.Context, reviewerID string) ([]models.PeerReview, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindByReviewer")
+	defer span.End()
+
+	var reviews []models.PeerReview
+	err := r.db.WithContext(ctx).
+		Preload("Submission.Task").
+		Preload("Scores").
+		Where("reviewer_id = ?", reviewerID).
+		Order("assigned_at ASC").
+		Find(&reviews).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *PeerReviewRepository) FindBySubmission(ctx context.Context, submissionID string) ([]models.PeerReview, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.FindBySubmission")
+	defer span.End()
+
+	var reviews []models.PeerReview
+	err := r.db.WithContext(ctx).
+		Preload("Reviewer").
+		Preload("Scores").
+		Where("submission_id = ?", submissionID).
+		Order("assigned_at ASC, id ASC").
+		Find(&reviews).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Submit stores the scores and comment of a review that has not been
+// submitted yet and reports whether it had not.
+func (r *PeerReviewRepository) Submit(ctx context.Context, review *models.PeerReview) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.Submit")
+	defer span.End()
+
+	submitted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.PeerReview{}).
+			Where("id = ? AND status = ?", review.ID, models.PeerReviewAssigned).
+			Updates(map[string]any{
+				"status":       models.PeerReviewSubmitted,
+				"comment":      review.Comment,
+				"submitted_at": review.SubmittedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		submitted = true
+		if len(review.Scores) == 0 {
+			return nil
+		}
+		return tx.Create(&review.Scores).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return submitted, nil
+}
+
+func (r *PeerReviewRepository) SavePeerScore(ctx context.Context, submissionID string, score int) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PeerReviewRepository.SavePeerScore")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Where("id = ?", submissionID).
+		Update("peer_score", score).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IPersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error)
+	FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error)
+	CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type PersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{db: db}
+}
+
+func (r *PersonalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindByHash returns the token whether or not it is still valid.
+func (r *PersonalAccessTokenRepository) FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.FindByHash")
+	defer span.End()
+
+	var token models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&token).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// FindActiveByUser returns the tokens that are neither revoked nor expired,
+// newest first.
+func (r *PersonalAccessTokenRepository) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.FindActiveByUser")
+	defer span.End()
+
+	var tokens []models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *PersonalAccessTokenRepository) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.CountActiveByUser")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Revoke reports false if the user has no such active token.
+func (r *PersonalAccessTokenRepository) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.Revoke")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *PersonalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.RevokeAllForUser")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "PersonalAccessTokenRepository.TouchLastUsed")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
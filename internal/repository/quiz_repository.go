@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IQuizRepository interface {
+	Create(ctx context.Context, quiz *models.Quiz) error
+	FindByID(ctx context.Context, id string) (*models.Quiz, error)
+	FindByClassroom(ctx context.Context, classroomID string) ([]models.Quiz, error)
+	Update(ctx context.Context, quiz *models.Quiz) error
+	Delete(ctx context.Context, id string) error
+	ReleaseResults(ctx context.Context, id string) error
+
+	CreateSession(ctx context.Context, session *models.QuizSession) error
+	FindSession(ctx context.Context, quizID, userID string) (*models.QuizSession, error)
+	FindSessionByID(ctx context.Context, id string) (*models.QuizSession, error)
+	FindSessionsByQuiz(ctx context.Context, quizID string) ([]models.QuizSession, error)
+	FindInProgressSessions(ctx context.Context) ([]models.QuizSession, error)
+	SaveAnswer(ctx context.Context, sessionID, taskID, answer string) error
+	FinishSession(ctx context.Context, session *models.QuizSession) (bool, error)
+}
+
+type QuizRepository struct {
+	db *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{db: db}
+}
+
+func preloadQuizTasks(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (r *QuizRepository) Create(ctx context.Context, quiz *models.Quiz) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Tasks.Task").Create(quiz).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) FindByID(ctx context.Context, id string) (*models.Quiz, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindByID")
+	defer span.End()
+
+	var quiz models.Quiz
+	err := r.db.WithContext(ctx).
+		Preload("Tasks", preloadQuizTasks).
+		Preload("Tasks.Task").
+		First(&quiz, "id = ?", id).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+func (r *QuizRepository) FindByClassroom(ctx context.Context, classroomID string) ([]models.Quiz, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindByClassroom")
+	defer span.End()
+
+	var quizzes []models.Quiz
+	err := r.db.WithContext(ctx).
+		Preload("Tasks", preloadQuizTasks).
+		Where("classroom_id = ?", classroomID).
+		Order("opens_at ASC").
+		Find(&quizzes).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return quizzes, nil
+}
+
+// Update saves quiz fields and replaces its task list. Tasks already drawn
+// into sessions are kept in quiz_answers and are not affected.
+func (r *QuizRepository) Update(ctx context.Context, quiz *models.Quiz) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tasks").Save(quiz).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&models.QuizTask{}).Error; err != nil {
+			return err
+		}
+		if len(quiz.Tasks) == 0 {
+			return nil
+		}
+		return tx.Omit("Task").Create(&quiz.Tasks).Error
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.Quiz{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) ReleaseResults(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.ReleaseResults")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Quiz{}).
+		Where("id = ?", id).
+		Update("results_released", true).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) CreateSession(ctx context.Context, session *models.QuizSession) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.CreateSession")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Answers.Task", "Quiz", "User").Create(session).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) findSession(ctx context.Context, query string, args ...any) (*models.QuizSession, error) {
+	var session models.QuizSession
+	err := r.db.WithContext(ctx).
+		Preload("Quiz").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Answers.Task").
+		Where(query, args...).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *QuizRepository) FindSession(ctx context.Context, quizID, userID string) (*models.QuizSession, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindSession")
+	defer span.End()
+
+	session, err := r.findSession(ctx, "quiz_id = ? AND user_id = ?", quizID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *QuizRepository) FindSessionByID(ctx context.Context, id string) (*models.QuizSession, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindSessionByID")
+	defer span.End()
+
+	session, err := r.findSession(ctx, "id = ?", id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *QuizRepository) FindSessionsByQuiz(ctx context.Context, quizID string) ([]models.QuizSession, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindSessionsByQuiz")
+	defer span.End()
+
+	var sessions []models.QuizSession
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("quiz_id = ?", quizID).
+		Order("started_at ASC").
+		Find(&sessions).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *QuizRepository) FindInProgressSessions(ctx context.Context) ([]models.QuizSession, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FindInProgressSessions")
+	defer span.End()
+
+	var sessions []models.QuizSession
+	err := r.db.WithContext(ctx).
+		Where("status = ?", models.QuizSessionInProgress).
+		Find(&sessions).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// SaveAnswer overwrites the answer for a task drawn into an in-progress session.
+func (r *QuizRepository) SaveAnswer(ctx context.Context, sessionID, taskID, answer string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.SaveAnswer")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Model(&models.QuizAnswer{}).
+		Where("session_id = ? AND task_id = ?", sessionID, taskID).
+		Where("EXISTS (SELECT 1 FROM quiz_sessions s WHERE s.id = quiz_answers.session_id AND s.status = ?)", models.QuizSessionInProgress).
+		Updates(map[string]any{"answer": answer, "updated_at": time.Now()})
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FinishSession stores grading results and closes the session. It reports
+// false if the session had already been finished by a concurrent call.
+func (r *QuizRepository) FinishSession(ctx context.Context, session *models.QuizSession) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "QuizRepository.FinishSession")
+	defer span.End()
+
+	finished := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.QuizSession{}).
+			Where("id = ? AND status = ?", session.ID, models.QuizSessionInProgress).
+			Updates(map[string]any{
+				"status":        session.Status,
+				"submitted_at":  session.SubmittedAt,
+				"correct_count": session.CorrectCount,
+				"score":         session.Score,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, a := range session.Answers {
+			err := tx.Model(&models.QuizAnswer{}).
+				Where("session_id = ? AND task_id = ?", session.ID, a.TaskID).
+				Update("is_correct", a.IsCorrect).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		finished = true
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return finished, nil
+}
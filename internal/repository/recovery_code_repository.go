@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IRecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID uuid.UUID, hashes []string) error
+	Use(ctx context.Context, userID uuid.UUID, hash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// Replace drops all recovery codes of the user and stores the new set.
+func (r *RecoveryCodeRepository) Replace(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "RecoveryCodeRepository.Replace")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]models.RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Use marks an unused code as used and reports whether there was one.
+func (r *RecoveryCodeRepository) Use(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "RecoveryCodeRepository.Use")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "RecoveryCodeRepository.DeleteForUser")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type IReviewRepository interface {
+	Find(ctx context.Context, userID, taskID string) (*models.ReviewSchedule, error)
+	Save(ctx context.Context, schedule *models.ReviewSchedule) error
+	FindDue(ctx context.Context, userID string, before time.Time, limit int) ([]models.ReviewSchedule, error)
+}
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) Find(ctx context.Context, userID, taskID string) (*models.ReviewSchedule, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ReviewRepository.Find")
+	defer span.End()
+
+	var schedule models.ReviewSchedule
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND task_id = ?", userID, taskID).
+		First(&schedule).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (r *ReviewRepository) Save(ctx context.Context, schedule *models.ReviewSchedule) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "ReviewRepository.Save")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Omit("Task").Save(schedule).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindDue returns schedules due before the given time, most overdue first,
+// skipping tasks that are no longer published.
+func (r *ReviewRepository) FindDue(ctx context.Context, userID string, before time.Time, limit int) ([]models.ReviewSchedule, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "ReviewRepository.FindDue")
+	defer span.End()
+
+	var schedules []models.ReviewSchedule
+	err := r.db.WithContext(ctx).
+		Preload("Task").
+		Joins("JOIN tasks t ON t.id = review_schedules.task_id").
+		Where("review_schedules.user_id = ? AND review_schedules.due_at < ?", userID, before).
+		Where("t.status = ?", models.TaskStatusPublished).
+		Order("review_schedules.due_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type ISchoolClassRepository interface {
+	Create(ctx context.Context, class *models.SchoolClass) error
+	FindAll(ctx context.Context) ([]models.SchoolClass, error)
+	FindByID(ctx context.Context, id string) (*models.SchoolClass, error)
+	FindByCode(ctx context.Context, code string) (*models.SchoolClass, error)
+	Update(ctx context.Context, class *models.SchoolClass) error
+	Delete(ctx context.Context, id string) error
+}
+
+type SchoolClassRepository struct {
+	db *gorm.DB
+}
+
+func NewSchoolClassRepository(db *gorm.DB) *SchoolClassRepository {
+	return &SchoolClassRepository{db: db}
+}
+
+func (r *SchoolClassRepository) Create(ctx context.Context, class *models.SchoolClass) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(class).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SchoolClassRepository) FindAll(ctx context.Context) ([]models.SchoolClass, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.FindAll")
+	defer span.End()
+
+	var classes []models.SchoolClass
+	err := r.db.WithContext(ctx).
+		Order("education_system ASC, sort_order ASC").
+		Find(&classes).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+func (r *SchoolClassRepository) FindByID(ctx context.Context, id string) (*models.SchoolClass, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.FindByID")
+	defer span.End()
+
+	var class models.SchoolClass
+	err := r.db.WithContext(ctx).First(&class, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &class, nil
+}
+
+func (r *SchoolClassRepository) FindByCode(ctx context.Context, code string) (*models.SchoolClass, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.FindByCode")
+	defer span.End()
+
+	var class models.SchoolClass
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&class).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &class, nil
+}
+
+func (r *SchoolClassRepository) Update(ctx context.Context, class *models.SchoolClass) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.Update")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Save(class).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SchoolClassRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SchoolClassRepository.Delete")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Delete(&models.SchoolClass{}, "id = ?", id).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
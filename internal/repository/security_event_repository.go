@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"learning-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type ISecurityEventRepository interface {
+	Create(ctx context.Context, event *models.SecurityEvent) error
+	FindByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.SecurityEvent, error)
+}
+
+type SecurityEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+func (r *SecurityEventRepository) Create(ctx context.Context, event *models.SecurityEvent) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SecurityEventRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(event).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SecurityEventRepository) FindByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.SecurityEvent, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SecurityEventRepository.FindByUser")
+	defer span.End()
+
+	var events []models.SecurityEvent
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&events).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+type ISubmissionRepository interface {
+	Create(ctx context.Context, submission *models.Submission) error
+	GetStatsByUser(ctx context.Context, userID string) ([]models.TaskAttemptStats, error)
+	GetAssignmentStats(ctx context.Context, assignmentIDs []string, userID string) ([]models.AssignmentTaskStats, error)
+	GetRecentByUser(ctx context.Context, userID string, limit int) ([]models.Submission, error)
+	CountCorrect(ctx context.Context, userID, taskID string) (int64, error)
+	GetFirstSolves(ctx context.Context, userID string) ([]models.SolvedTask, error)
+	FindByID(ctx context.Context, id string) (*models.Submission, error)
+	FindByUserAndTask(ctx context.Context, userID, taskID string) ([]models.Submission, error)
+	FindPendingReview(ctx context.Context, graderID, taskID string) ([]models.Submission, error)
+	Grade(ctx context.Context, submission *models.Submission) (bool, error)
+	Regrade(ctx context.Context, submission *models.Submission) error
+}
+
+type SubmissionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubmissionRepository(db *gorm.DB) *SubmissionRepository {
+	return &SubmissionRepository{db: db}
+}
+
+func (r *SubmissionRepository) Create(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.Create")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Create(submission).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SubmissionRepository) GetStatsByUser(ctx context.Context, userID string) ([]models.TaskAttemptStats, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.GetStatsByUser")
+	defer span.End()
+
+	var stats []models.TaskAttemptStats
+	err := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Select(`task_id,
+			COUNT(*) AS attempts,
+			BOOL_OR(is_correct) AS solved,
+			MAX(created_at) AS last_attempt_at,
+			MAX(created_at) FILTER (WHERE is_correct) AS last_solved_at`).
+		// answers waiting for a teacher are neither right nor wrong yet
+		Where("user_id = ? AND status = ?", userID, models.SubmissionStatusGraded).
+		Group("task_id").
+		Scan(&stats).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetAssignmentStats aggregates submissions made against the given assignments.
+// An empty userID returns stats for every student.
+func (r *SubmissionRepository) GetAssignmentStats(ctx context.Context, assignmentIDs []string, userID string) ([]models.AssignmentTaskStats, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.GetAssignmentStats")
+	defer span.End()
+
+	var stats []models.AssignmentTaskStats
+	if len(assignmentIDs) == 0 {
+		return stats, nil
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Select(`assignment_id, user_id, task_id,
+			COUNT(*) AS attempts,
+			BOOL_OR(is_correct) AS solved,
+			BOOL_OR(is_correct AND NOT is_late) AS solved_on_time`).
+		Where("assignment_id IN ?", assignmentIDs)
+
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	err := query.Group("assignment_id, user_id, task_id").Scan(&stats).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetRecentByUser returns the user's latest graded submissions, newest first.
+func (r *SubmissionRepository) GetRecentByUser(ctx context.Context, userID string, limit int) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.GetRecentByUser")
+	defer span.End()
+
+	var submissions []models.Submission
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, models.SubmissionStatusGraded).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&submissions).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+func (r *SubmissionRepository) CountCorrect(ctx context.Context, userID, taskID string) (int64, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.CountCorrect")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Where("user_id = ? AND task_id = ? AND is_correct", userID, taskID).
+		Count(&count).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetFirstSolves returns, for every task the user solved, when it was first
+// solved along with the task's topic and difficulty. An empty userID returns
+// the solves of every user.
+func (r *SubmissionRepository) GetFirstSolves(ctx context.Context, userID string) ([]models.SolvedTask, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.GetFirstSolves")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Select(`task_submissions.user_id, task_submissions.task_id,
+			tasks.topic_id, tasks.difficulty,
+			MIN(task_submissions.created_at) AS solved_at`).
+		Joins("JOIN tasks ON tasks.id = task_submissions.task_id").
+		Where("task_submissions.is_correct")
+
+	if userID != "" {
+		query = query.Where("task_submissions.user_id = ?", userID)
+	}
+
+	var solves []models.SolvedTask
+	err := query.
+		Group("task_submissions.user_id, task_submissions.task_id, tasks.topic_id, tasks.difficulty").
+		Scan(&solves).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return solves, nil
+}
+
+func (r *SubmissionRepository) FindByID(ctx context.Context, id string) (*models.Submission, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.FindByID")
+	defer span.End()
+
+	var submission models.Submission
+	err := r.db.WithContext(ctx).
+		Preload("Task").
+		First(&submission, "id = ?", id).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+// FindByUserAndTask returns the user's submissions for the task, newest first.
+func (r *SubmissionRepository) FindByUserAndTask(ctx context.Context, userID, taskID string) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.FindByUserAndTask")
+	defer span.End()
+
+	var submissions []models.Submission
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND task_id = ?", userID, taskID).
+		Order("created_at DESC").
+		Find(&submissions).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+// FindPendingReview returns submissions waiting for manual grading, oldest
+// first. A non-empty graderID limits them to tasks the grader authored and
+// assignments of classrooms the grader teaches; taskID optionally narrows
+// them to one task.
+func (r *SubmissionRepository) FindPendingReview(ctx context.Context, graderID, taskID string) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.FindPendingReview")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).
+		Preload("Task").
+		Preload("User").
+		Where("task_submissions.status = ?", models.SubmissionStatusPendingReview)
+
+	if graderID != "" {
+		query = query.
+			Joins("JOIN tasks ON tasks.id = task_submissions.task_id").
+			Where(`tasks.author_id = ? OR task_submissions.assignment_id IN (
+				SELECT a.id FROM assignments a
+				JOIN classroom_members m ON m.classroom_id = a.classroom_id
+				WHERE m.user_id = ? AND m.role IN ?)`,
+				graderID, graderID, []models.ClassroomRole{models.ClassroomRoleOwner, models.ClassroomRoleTeacher})
+	}
+
+	if taskID != "" {
+		query = query.Where("task_submissions.task_id = ?", taskID)
+	}
+
+	var submissions []models.Submission
+	err := query.Order("task_submissions.created_at ASC").Find(&submissions).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+// Grade stores the grade of a submission that is still pending review and
+// reports whether it was; a concurrent grade wins otherwise.
+func (r *SubmissionRepository) Grade(ctx context.Context, submission *models.Submission) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.Grade")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Where("id = ? AND status = ?", submission.ID, models.SubmissionStatusPendingReview).
+		Updates(map[string]any{
+			"status":     submission.Status,
+			"is_correct": submission.IsCorrect,
+			"score":      submission.Score,
+			"feedback":   submission.Feedback,
+			"graded_by":  submission.GradedBy,
+			"graded_at":  submission.GradedAt,
+		})
+
+	if result.Error != nil {
+		span.RecordError(result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Regrade overwrites the grade of a submission whatever its status.
+func (r *SubmissionRepository) Regrade(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "SubmissionRepository.Regrade")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Submission{}).
+		Where("id = ?", submission.ID).
+		Updates(map[string]any{
+			"status":     submission.Status,
+			"is_correct": submission.IsCorrect,
+			"score":      submission.Score,
+			"feedback":   submission.Feedback,
+			"graded_by":  submission.GradedBy,
+			"graded_at":  submission.GradedAt,
+		}).Error
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
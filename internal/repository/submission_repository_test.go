@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"learning-platform/internal/models"
+)
+
+// newDryRunDB собирает SQL без подключения к базе и возвращает его текст
+// вместе с аргументами.
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]string, *[]interface{}) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Discard})
+	require.NoError(t, err)
+
+	var queries []string
+	var args []interface{}
+	capture := func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+		args = append(args, tx.Statement.Vars...)
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", capture))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:capture", capture))
+	return db, &queries, &args
+}
+
+func TestSubmissionRepository_SkipsPendingReview(t *testing.T) {
+	db, queries, args := newDryRunDB(t)
+	repo := NewSubmissionRepository(db)
+
+	_, _ = repo.GetStatsByUser(context.Background(), "user-1")
+	_, _ = repo.GetRecentByUser(context.Background(), "user-1", 10)
+
+	// ответ, ждущий проверки учителем, не считается неудачной попыткой
+	require.Len(t, *queries, 2)
+	for _, q := range *queries {
+		assert.Contains(t, q, "user_id = $1 AND status = $2")
+	}
+	assert.Equal(t, []interface{}{"user-1", models.SubmissionStatusGraded, "user-1", models.SubmissionStatusGraded, 10}, *args)
+}
@@ -16,6 +16,7 @@ type ITaskRepository interface {
 	Create(ctx context.Context, task *models.Task) error
 	GetByID(ctx context.Context, id string) (*models.Task, error)
 	GetByTopic(ctx context.Context, topicID string) ([]models.Task, error)
+	GetBySchoolClass(ctx context.Context, schoolClass string) ([]models.Task, error)
 	Update(ctx context.Context, task *models.Task) error
 	Delete(ctx context.Context, id string) error
 	GetByAuthor(ctx context.Context, authorID string) ([]models.Task, error)
@@ -126,6 +127,25 @@ func (r *TaskRepository) GetByTopic(ctx context.Context, topicID string) ([]mode
 	return tasks, nil
 }
 
+func (r *TaskRepository) GetBySchoolClass(ctx context.Context, schoolClass string) ([]models.Task, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TaskRepository.GetBySchoolClass")
+	defer span.End()
+
+	var tasks []models.Task
+	err := r.db.WithContext(ctx).
+		Joins("JOIN topics t ON t.id = tasks.topic_id").
+		Where("t.school_class = ? AND tasks.status = ?", schoolClass, models.TaskStatusPublished).
+		Order("tasks.created_at DESC").
+		Find(&tasks).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
 func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
 	ctx, span := otel.Tracer("db").Start(ctx, "TaskRepository.Update")
 	defer span.End()
@@ -15,8 +15,13 @@ import (
 type ITokenRepository interface {
 	Save(ctx context.Context, token *models.RefreshToken) error
 	FindValid(ctx context.Context, hash string) (*models.RefreshToken, error)
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, old, next *models.RefreshToken) (bool, error)
+	HasSuccessor(ctx context.Context, id uuid.UUID) (bool, error)
 	Revoke(ctx context.Context, hash string) error
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error)
 	DeleteExpired(ctx context.Context) error
 }
 
@@ -63,6 +68,83 @@ func (r *TokenRepository) FindValid(ctx context.Context, hash string) (*models.R
 	return &token, nil
 }
 
+// FindByHash returns the token whether or not it is still valid.
+func (r *TokenRepository) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.FindByHash")
+	defer span.End()
+
+	var token models.RefreshToken
+
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&token).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate revokes old and saves next as its successor. It reports false without
+// saving next when old was revoked in the meantime.
+func (r *TokenRepository) Rotate(ctx context.Context, old, next *models.RefreshToken) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.Rotate")
+	defer span.End()
+
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked = ?", old.ID, false).
+			Update("revoked", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		next.ParentID = &old.ID
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return rotated, nil
+}
+
+// HasSuccessor reports whether the token was rotated rather than revoked by a
+// logout.
+func (r *TokenRepository) HasSuccessor(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.HasSuccessor")
+	defer span.End()
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("parent_id = ?", id).
+		Count(&count).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (r *TokenRepository) Revoke(ctx context.Context, hash string) error {
 	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.Revoke")
 	defer span.End()
@@ -97,6 +179,45 @@ func (r *TokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID
 	return nil
 }
 
+// FindActiveByUser returns the current token of every session that is still
+// signed in, most recently used first.
+func (r *TokenRepository) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.FindActiveByUser")
+	defer span.End()
+
+	var tokens []models.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("last_used_at DESC").
+		Find(&tokens).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// RevokeSession revokes the tokens of one session and reports whether the
+// session was still signed in.
+func (r *TokenRepository) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.RevokeSession")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ? AND session_id = ? AND revoked = ?", userID, sessionID, false).
+		Update("revoked", true)
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
 func (r *TokenRepository) DeleteExpired(ctx context.Context) error {
 	ctx, span := otel.Tracer("db").Start(ctx, "TokenRepository.DeleteExpired")
 	defer span.End()
@@ -12,6 +12,7 @@ import (
 type ITopicRepository interface {
 	Create(ctx context.Context, topic *models.Topic) error
 	FindAll(ctx context.Context) ([]models.Topic, error)
+	FindBySchoolClass(ctx context.Context, schoolClass string) ([]models.Topic, error)
 	FindByID(ctx context.Context, id string) (*models.Topic, error)
 	Update(ctx context.Context, topic *models.Topic) error
 	Delete(ctx context.Context, id string) error
@@ -43,7 +44,25 @@ func (r *TopicRepository) FindAll(ctx context.Context) ([]models.Topic, error) {
 	defer span.End()
 
 	var topics []models.Topic
-	err := r.db.WithContext(ctx).Find(&topics).Error
+	err := r.db.WithContext(ctx).Preload("Prerequisites").Find(&topics).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func (r *TopicRepository) FindBySchoolClass(ctx context.Context, schoolClass string) ([]models.Topic, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "TopicRepository.FindBySchoolClass")
+	defer span.End()
+
+	var topics []models.Topic
+	err := r.db.WithContext(ctx).
+		Preload("Prerequisites").
+		Where("school_class = ?", schoolClass).
+		Find(&topics).Error
+
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
@@ -57,7 +76,7 @@ func (r *TopicRepository) FindByID(ctx context.Context, id string) (*models.Topi
 	defer span.End()
 
 	var topic models.Topic
-	err := r.db.WithContext(ctx).First(&topic, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("Prerequisites").First(&topic, "id = ?", id).Error
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
@@ -66,11 +85,24 @@ func (r *TopicRepository) FindByID(ctx context.Context, id string) (*models.Topi
 	return &topic, nil
 }
 
+// Update saves topic fields and replaces its prerequisites.
 func (r *TopicRepository) Update(ctx context.Context, topic *models.Topic) error {
 	ctx, span := otel.Tracer("db").Start(ctx, "TopicRepository.Update")
 	defer span.End()
 
-	err := r.db.WithContext(ctx).Save(topic).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Prerequisites").Save(topic).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("topic_id = ?", topic.ID).Delete(&models.TopicPrerequisite{}).Error; err != nil {
+			return err
+		}
+		if len(topic.Prerequisites) == 0 {
+			return nil
+		}
+		return tx.Create(&topic.Prerequisites).Error
+	})
+
 	if err != nil {
 		span.RecordError(err)
 		return err
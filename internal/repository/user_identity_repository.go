@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IUserIdentityRepository interface {
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	Create(ctx context.Context, identity *models.UserIdentity) (bool, error)
+	TouchLogin(ctx context.Context, id uuid.UUID) error
+}
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserIdentityRepository.FindByProviderSubject")
+	defer span.End()
+
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// Create links the identity and reports false if the provider account is
+// already linked, e.g. by a concurrent first login.
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserIdentityRepository.Create")
+	defer span.End()
+
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "subject"}},
+			DoNothing: true,
+		}).
+		Create(identity)
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *UserIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserIdentityRepository.TouchLogin")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_at", time.Now()).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
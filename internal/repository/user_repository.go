@@ -16,7 +16,9 @@ type IUserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id string) (*models.User, error)
 	Update(ctx context.Context, id string, updates map[string]interface{}) error
+	UseTOTPStep(ctx context.Context, id string, step int64, updates map[string]interface{}) (bool, error)
 	GetAll(ctx context.Context) ([]models.User, error)
+	FindByIDs(ctx context.Context, ids []string) ([]models.User, error)
 }
 
 type UserRepository struct {
@@ -113,3 +115,45 @@ func (r *UserRepository) Update(ctx context.Context, id string, updates map[stri
 
 	return nil
 }
+
+// UseTOTPStep records step as the last accepted TOTP step, together with
+// updates, unless a step as recent was accepted already. It reports false
+// for a replayed code, including one used by a concurrent request.
+func (r *UserRepository) UseTOTPStep(ctx context.Context, id string, step int64, updates map[string]interface{}) (bool, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserRepository.UseTOTPStep")
+	defer span.End()
+
+	values := map[string]interface{}{"totp_last_step": step}
+	for k, v := range updates {
+		values[k] = v
+	}
+
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Updates(values)
+
+	if res.Error != nil {
+		span.RecordError(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserRepository.FindByIDs")
+	defer span.End()
+
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IUserStatsRepository interface {
+	Find(ctx context.Context, userID string) (*models.UserStats, error)
+	Apply(ctx context.Context, userID string, apply func(stats *models.UserStats) []models.StreakDay) (*models.UserStats, error)
+	FindDays(ctx context.Context, userID string, from, to time.Time) ([]models.StreakDay, error)
+}
+
+type UserStatsRepository struct {
+	db *gorm.DB
+}
+
+func NewUserStatsRepository(db *gorm.DB) *UserStatsRepository {
+	return &UserStatsRepository{db: db}
+}
+
+func (r *UserStatsRepository) Find(ctx context.Context, userID string) (*models.UserStats, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserStatsRepository.Find")
+	defer span.End()
+
+	var stats models.UserStats
+	err := r.db.WithContext(ctx).First(&stats, "user_id = ?", userID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// Apply locks the user's stats row, creating it if needed, lets apply change
+// it and saves the result together with the streak days apply returns.
+func (r *UserStatsRepository) Apply(ctx context.Context, userID string, apply func(stats *models.UserStats) []models.StreakDay) (*models.UserStats, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserStatsRepository.Apply")
+	defer span.End()
+
+	var stats models.UserStats
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.UserStats{UserID: userID}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&stats, "user_id = ?", userID).Error
+		if err != nil {
+			return err
+		}
+
+		days := apply(&stats)
+
+		if err := tx.Save(&stats).Error; err != nil {
+			return err
+		}
+
+		if len(days) > 0 {
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&days).Error
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// FindDays returns streak days between from and to inclusive, oldest first.
+func (r *UserStatsRepository) FindDays(ctx context.Context, userID string, from, to time.Time) ([]models.StreakDay, error) {
+	ctx, span := otel.Tracer("db").Start(ctx, "UserStatsRepository.FindDays")
+	defer span.End()
+
+	var days []models.StreakDay
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND day BETWEEN ? AND ?", userID, from, to).
+		Order("day ASC").
+		Find(&days).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return days, nil
+}
@@ -13,8 +13,9 @@ import (
 
 type IVerificationRepository interface {
 	Create(ctx context.Context, model *models.EmailVerification) error
-	FindValid(ctx context.Context, email, code string) (*models.EmailVerification, error)
+	FindValid(ctx context.Context, email, code string, purpose models.VerificationPurpose) (*models.EmailVerification, error)
 	MarkUsed(ctx context.Context, id uuid.UUID) error
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose models.VerificationPurpose) error
 }
 
 type VerificationRepository struct {
@@ -38,7 +39,7 @@ func (r *VerificationRepository) Create(ctx context.Context, model *models.Email
 	return nil
 }
 
-func (r *VerificationRepository) FindValid(ctx context.Context, email, code string) (*models.EmailVerification, error) {
+func (r *VerificationRepository) FindValid(ctx context.Context, email, code string, purpose models.VerificationPurpose) (*models.EmailVerification, error) {
 	ctx, span := otel.Tracer("db").Start(ctx, "VerificationRepository.FindValid")
 	defer span.End()
 
@@ -46,8 +47,8 @@ func (r *VerificationRepository) FindValid(ctx context.Context, email, code stri
 	err := r.db.WithContext(ctx).
 		Joins("JOIN users u ON u.id = email_verifications.user_id").
 		Where(
-			"u.email = ? AND email_verifications.code = ? AND email_verifications.used = FALSE AND email_verifications.expires_at > ?",
-			email, code, time.Now(),
+			"u.email = ? AND email_verifications.code = ? AND email_verifications.purpose = ? AND email_verifications.used = FALSE AND email_verifications.expires_at > ?",
+			email, code, purpose, time.Now(),
 		).
 		First(&v).Error
 
@@ -75,3 +76,22 @@ func (r *VerificationRepository) MarkUsed(ctx context.Context, id uuid.UUID) err
 
 	return nil
 }
+
+// InvalidateForUser marks every unused code of the given purpose as used, so
+// only the most recently sent one stays valid.
+func (r *VerificationRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose models.VerificationPurpose) error {
+	ctx, span := otel.Tracer("db").Start(ctx, "VerificationRepository.InvalidateForUser")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).
+		Model(&models.EmailVerification{}).
+		Where("user_id = ? AND purpose = ? AND used = FALSE", userID, purpose).
+		Update("used", true).Error
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
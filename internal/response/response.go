@@ -1,6 +1,11 @@
 package response
 
 import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,4 +30,22 @@ func Error(c *gin.Context, status int, message string) {
 			"message": message,
 		},
 	})
+}
+
+// TooManyRequests writes a 429 with a machine readable code. A positive
+// retryAfter is also sent as the Retry-After header.
+func TooManyRequests(c *gin.Context, code, message string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds > 0 {
+		c.Header("Retry-After", strconv.Itoa(seconds))
+	}
+
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"success": false,
+		"error": gin.H{
+			"message":           message,
+			"code":              code,
+			"retryAfterSeconds": seconds,
+		},
+	})
 }
\ No newline at end of file
@@ -9,6 +9,17 @@ type ErrorMessage struct {
 	Message string `json:"message"`
 }
 
+type RateLimitErrorResponse struct {
+	Success bool                  `json:"success" example:"false"`
+	Error   RateLimitErrorMessage `json:"error"`
+}
+
+type RateLimitErrorMessage struct {
+	Message           string `json:"message"`
+	Code              string `json:"code" example:"LOGIN_LOCKED"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds" example:"60"`
+}
+
 type SuccessWrapper struct {
     Success bool        `json:"success" example:"true"`
     Data    interface{} `json:"data"`
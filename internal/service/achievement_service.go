@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"learning-platform/internal/kafka"
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrInvalidAchievement   = errors.New("invalid achievement")
+	ErrAchievementCodeTaken = errors.New("achievement code already exists")
+)
+
+var achievementCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// AchievementService evaluates achievement rules on submissions. Streak
+// rules read the streak kept by GamificationService, so it must be
+// registered as a submission listener after it.
+type AchievementService struct {
+	achievements repository.IAchievementRepository
+	submissions  repository.ISubmissionRepository
+	stats        repository.IUserStatsRepository
+	users        repository.IUserRepository
+	email        EmailSender
+}
+
+func NewAchievementService(achievements repository.IAchievementRepository, submissions repository.ISubmissionRepository, stats repository.IUserStatsRepository, users repository.IUserRepository, email EmailSender) *AchievementService {
+	return &AchievementService{
+		achievements: achievements,
+		submissions:  submissions,
+		stats:        stats,
+		users:        users,
+		email:        email,
+	}
+}
+
+func validateAchievement(a *models.Achievement) error {
+	a.Code = strings.TrimSpace(a.Code)
+	a.Title = strings.TrimSpace(a.Title)
+
+	if !achievementCodePattern.MatchString(a.Code) {
+		return errors.New("code must be lowercase letters, digits and dashes")
+	}
+	if a.Title == "" {
+		return errors.New("title is required")
+	}
+	if a.Threshold < 1 {
+		return errors.New("threshold must be positive")
+	}
+
+	switch a.Rule {
+	case models.AchievementRuleSolvedTasks, models.AchievementRuleTopicSolvedTasks:
+		if a.Difficulty != nil {
+			if _, ok := difficultyWeights[*a.Difficulty]; !ok {
+				return errors.New("unknown difficulty")
+			}
+		}
+	case models.AchievementRuleStreakDays:
+		if a.Difficulty != nil || a.TopicID != nil {
+			return errors.New("streak achievements cannot be limited to a difficulty or topic")
+		}
+	default:
+		return errors.New("unknown rule")
+	}
+
+	return nil
+}
+
+func achievementSaveError(err error) error {
+	if strings.Contains(err.Error(), "duplicate key value") {
+		return ErrAchievementCodeTaken
+	}
+	if strings.Contains(err.Error(), "violates foreign key constraint") {
+		return fmt.Errorf("%w: topic not found", ErrInvalidAchievement)
+	}
+	return err
+}
+
+// GetCatalog returns the achievements catalog; inactive achievements are
+// only listed for admins.
+func (s *AchievementService) GetCatalog(ctx context.Context, role string) ([]models.Achievement, error) {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.GetCatalog")
+	defer span.End()
+
+	achievements, err := s.achievements.FindAll(ctx, !isAdmin(role))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (s *AchievementService) CreateAchievement(ctx context.Context, a *models.Achievement) error {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.CreateAchievement")
+	defer span.End()
+
+	if err := validateAchievement(a); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAchievement, err)
+	}
+
+	if err := s.achievements.Create(ctx, a); err != nil {
+		span.RecordError(err)
+		return achievementSaveError(err)
+	}
+
+	return nil
+}
+
+// UpdateAchievement replaces the editable fields of an achievement. Users
+// who already unlocked it keep the badge.
+func (s *AchievementService) UpdateAchievement(ctx context.Context, id string, changes *models.Achievement) (*models.Achievement, error) {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.UpdateAchievement")
+	defer span.End()
+
+	a, err := s.achievements.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	a.Code = changes.Code
+	a.Title = changes.Title
+	a.Description = changes.Description
+	a.IconURL = changes.IconURL
+	a.Rule = changes.Rule
+	a.Threshold = changes.Threshold
+	a.Difficulty = changes.Difficulty
+	a.TopicID = changes.TopicID
+	a.IsActive = changes.IsActive
+
+	if err := validateAchievement(a); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAchievement, err)
+	}
+
+	if err := s.achievements.Update(ctx, a); err != nil {
+		span.RecordError(err)
+		return nil, achievementSaveError(err)
+	}
+
+	return a, nil
+}
+
+func (s *AchievementService) DeleteAchievement(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.DeleteAchievement")
+	defer span.End()
+
+	if _, err := s.achievements.FindByID(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.achievements.Delete(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUserAchievements returns the user's unlocked badges, newest first.
+func (s *AchievementService) GetUserAchievements(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.GetUserAchievements")
+	defer span.End()
+
+	unlocked, err := s.achievements.FindByUser(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return unlocked, nil
+}
+
+// achievementProgress returns how far the user is towards the achievement's
+// threshold. streak is only consulted for streak achievements.
+func achievementProgress(a *models.Achievement, solves []models.SolvedTask, streak func() int) int {
+	switch a.Rule {
+	case models.AchievementRuleSolvedTasks, models.AchievementRuleTopicSolvedTasks:
+		perTopic := make(map[string]int)
+		total := 0
+		for _, solve := range solves {
+			if a.Difficulty != nil && solve.Difficulty != *a.Difficulty {
+				continue
+			}
+			if a.TopicID != nil && solve.TopicID != *a.TopicID {
+				continue
+			}
+			perTopic[solve.TopicID]++
+			total++
+		}
+		if a.Rule == models.AchievementRuleSolvedTasks {
+			return total
+		}
+		best := 0
+		for _, n := range perTopic {
+			best = max(best, n)
+		}
+		return best
+	case models.AchievementRuleStreakDays:
+		return streak()
+	}
+	return 0
+}
+
+// OnSubmission unlocks every active achievement whose rule the user now
+// satisfies and notifies the user about each new badge.
+func (s *AchievementService) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("achievement").Start(ctx, "AchievementService.OnSubmission")
+	defer span.End()
+
+	if !submission.IsCorrect {
+		return nil
+	}
+
+	locked, err := s.achievements.FindLocked(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if len(locked) == 0 {
+		return nil
+	}
+
+	solves, err := s.submissions.GetFirstSolves(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	now := time.Now()
+	streak := -1
+	var streakErr error
+	streakFn := func() int {
+		if streak < 0 {
+			var stats *models.UserStats
+			stats, streakErr = s.stats.Find(ctx, submission.UserID)
+			streak = 0
+			if stats != nil {
+				streak = stats.CurrentStreak
+			}
+		}
+		return streak
+	}
+
+	for i := range locked {
+		a := &locked[i]
+		progress := achievementProgress(a, solves, streakFn)
+		if streakErr != nil {
+			span.RecordError(streakErr)
+			return streakErr
+		}
+		if progress < a.Threshold {
+			continue
+		}
+
+		unlocked, err := s.achievements.Unlock(ctx, &models.UserAchievement{
+			UserID:        submission.UserID,
+			AchievementID: a.ID,
+			UnlockedAt:    now,
+		})
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		if unlocked {
+			s.notify(ctx, submission.UserID, a)
+		}
+	}
+
+	return nil
+}
+
+func (s *AchievementService) notify(ctx context.Context, userID string, a *models.Achievement) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		return
+	}
+
+	body := fmt.Sprintf("Congratulations, %s! You earned the %q badge.", user.DisplayName, a.Title)
+	if a.Description != "" {
+		body += "\n\n" + a.Description
+	}
+
+	s.email.SendAsync(kafka.EmailMessage{
+		Email:   user.Email,
+		Subject: "New badge: " + a.Title,
+		Body:    body,
+	})
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/kafka"
+	"learning-platform/internal/models"
+)
+
+type fakeAchievementRepo struct {
+	achievements map[string]*models.Achievement
+	unlocked     []models.UserAchievement
+}
+
+func newFakeAchievementRepo(achievements ...models.Achievement) *fakeAchievementRepo {
+	f := &fakeAchievementRepo{achievements: make(map[string]*models.Achievement)}
+	for i := range achievements {
+		f.achievements[achievements[i].ID] = &achievements[i]
+	}
+	return f
+}
+
+func (f *fakeAchievementRepo) Create(ctx context.Context, a *models.Achievement) error {
+	if a.ID == "" {
+		a.ID = uuid.NewString()
+	}
+	f.achievements[a.ID] = a
+	return nil
+}
+
+func (f *fakeAchievementRepo) FindByID(ctx context.Context, id string) (*models.Achievement, error) {
+	a, ok := f.achievements[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (f *fakeAchievementRepo) FindAll(ctx context.Context, activeOnly bool) ([]models.Achievement, error) {
+	var out []models.Achievement
+	for _, a := range f.achievements {
+		if !activeOnly || a.IsActive {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAchievementRepo) Update(ctx context.Context, a *models.Achievement) error {
+	cp := *a
+	f.achievements[a.ID] = &cp
+	return nil
+}
+
+func (f *fakeAchievementRepo) Delete(ctx context.Context, id string) error {
+	delete(f.achievements, id)
+	return nil
+}
+
+func (f *fakeAchievementRepo) FindLocked(ctx context.Context, userID string) ([]models.Achievement, error) {
+	var out []models.Achievement
+	for _, a := range f.achievements {
+		if !a.IsActive {
+			continue
+		}
+		locked := true
+		for _, u := range f.unlocked {
+			if u.UserID == userID && u.AchievementID == a.ID {
+				locked = false
+			}
+		}
+		if locked {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAchievementRepo) Unlock(ctx context.Context, unlocked *models.UserAchievement) (bool, error) {
+	for _, u := range f.unlocked {
+		if u.UserID == unlocked.UserID && u.AchievementID == unlocked.AchievementID {
+			return false, nil
+		}
+	}
+	f.unlocked = append(f.unlocked, *unlocked)
+	return true, nil
+}
+
+func (f *fakeAchievementRepo) FindByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	var out []models.UserAchievement
+	for _, u := range f.unlocked {
+		if u.UserID == userID {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+type fakeEmailSender struct {
+	sent []kafka.EmailMessage
+}
+
+func (f *fakeEmailSender) SendAsync(msg kafka.EmailMessage) {
+	f.sent = append(f.sent, msg)
+}
+
+func TestAchievementProgress(t *testing.T) {
+	extreme := models.DifficultyExtreme
+	algebra := "algebra"
+	solves := []models.SolvedTask{
+		{TaskID: "t1", TopicID: "algebra", Difficulty: models.DifficultyEasy},
+		{TaskID: "t2", TopicID: "algebra", Difficulty: models.DifficultyExtreme},
+		{TaskID: "t3", TopicID: "algebra", Difficulty: models.DifficultyMedium},
+		{TaskID: "t4", TopicID: "geometry", Difficulty: models.DifficultyEasy},
+	}
+	noStreak := func() int { return 0 }
+
+	assert.Equal(t, 4, achievementProgress(&models.Achievement{Rule: models.AchievementRuleSolvedTasks}, solves, noStreak))
+	assert.Equal(t, 1, achievementProgress(&models.Achievement{Rule: models.AchievementRuleSolvedTasks, Difficulty: &extreme}, solves, noStreak))
+	assert.Equal(t, 3, achievementProgress(&models.Achievement{Rule: models.AchievementRuleTopicSolvedTasks}, solves, noStreak))
+	assert.Equal(t, 3, achievementProgress(&models.Achievement{Rule: models.AchievementRuleSolvedTasks, TopicID: &algebra}, solves, noStreak))
+	assert.Equal(t, 5, achievementProgress(&models.Achievement{Rule: models.AchievementRuleStreakDays}, solves, func() int { return 5 }))
+}
+
+func TestAchievementService_OnSubmission(t *testing.T) {
+	ctx := context.Background()
+	extreme := models.DifficultyExtreme
+
+	users := newFakeUserRepo()
+	user := &models.User{Email: "kid@example.com", DisplayName: "Kid"}
+	require.NoError(t, users.Create(ctx, user))
+	userID := user.ID.String()
+
+	repo := newFakeAchievementRepo(
+		models.Achievement{ID: "first-extreme", Title: "Fearless", Rule: models.AchievementRuleSolvedTasks, Threshold: 1, Difficulty: &extreme, IsActive: true},
+		models.Achievement{ID: "topic-2", Title: "Explorer", Rule: models.AchievementRuleTopicSolvedTasks, Threshold: 2, IsActive: true},
+		models.Achievement{ID: "streak-3", Title: "On fire", Rule: models.AchievementRuleStreakDays, Threshold: 3, IsActive: true},
+		models.Achievement{ID: "retired", Title: "Retired", Rule: models.AchievementRuleSolvedTasks, Threshold: 1, IsActive: false},
+	)
+
+	submissions := &fakeSolvesRepo{
+		fakeSubmissionRepo: newFakeSubmissionRepo(),
+		solves: []models.SolvedTask{
+			{UserID: userID, TaskID: "t1", TopicID: "algebra", Difficulty: models.DifficultyExtreme},
+		},
+	}
+	stats := newFakeUserStatsRepo()
+	email := &fakeEmailSender{}
+	svc := NewAchievementService(repo, submissions, stats, users, email)
+
+	// неверный ответ правила не проверяет
+	require.NoError(t, svc.OnSubmission(ctx, &models.Submission{UserID: userID, TaskID: "t1"}))
+	assert.Empty(t, repo.unlocked)
+
+	require.NoError(t, svc.OnSubmission(ctx, &models.Submission{UserID: userID, TaskID: "t1", IsCorrect: true}))
+	require.Len(t, repo.unlocked, 1)
+	assert.Equal(t, "first-extreme", repo.unlocked[0].AchievementID)
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "kid@example.com", email.sent[0].Email)
+	assert.Contains(t, email.sent[0].Subject, "Fearless")
+	assert.NotEmpty(t, email.sent[0].Body)
+
+	submissions.solves = append(submissions.solves, models.SolvedTask{UserID: userID, TaskID: "t2", TopicID: "algebra", Difficulty: models.DifficultyEasy})
+	stats.stats[userID] = &models.UserStats{UserID: userID, CurrentStreak: 3}
+
+	require.NoError(t, svc.OnSubmission(ctx, &models.Submission{UserID: userID, TaskID: "t2", IsCorrect: true}))
+	assert.Len(t, repo.unlocked, 3)
+	assert.Len(t, email.sent, 3, "each badge is announced once")
+
+	badges, err := svc.GetUserAchievements(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, badges, 3)
+}
+
+func TestAchievementService_UpdateAchievement(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeAchievementRepo(models.Achievement{ID: "a1", Code: "streak-7", Title: "On fire", Rule: models.AchievementRuleStreakDays, Threshold: 7, IsActive: true})
+	svc := NewAchievementService(repo, nil, nil, nil, nil)
+
+	topic := "algebra"
+	_, err := svc.UpdateAchievement(ctx, "a1", &models.Achievement{Code: "streak-7", Title: "On fire", Rule: models.AchievementRuleStreakDays, Threshold: 7, TopicID: &topic})
+	assert.ErrorIs(t, err, ErrInvalidAchievement)
+
+	updated, err := svc.UpdateAchievement(ctx, "a1", &models.Achievement{Code: "streak-14", Title: " Blazing ", Rule: models.AchievementRuleStreakDays, Threshold: 14})
+	require.NoError(t, err)
+	assert.Equal(t, "Blazing", updated.Title)
+	assert.False(t, updated.IsActive)
+
+	_, err = svc.UpdateAchievement(ctx, "missing", &models.Achievement{})
+	assert.Error(t, err)
+}
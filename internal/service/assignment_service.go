@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrAssignmentForbidden    = errors.New("you don't have access to this assignment")
+	ErrAssignmentNotOpen      = errors.New("assignment is not open yet")
+	ErrAssignmentClosed       = errors.New("assignment no longer accepts submissions")
+	ErrAssignmentTaskNotFound = errors.New("task is not part of this assignment")
+	ErrInvalidAssignment      = errors.New("invalid assignment")
+)
+
+type AssignmentService struct {
+	assignments repository.IAssignmentRepository
+	classrooms  repository.IClassroomRepository
+	submissions repository.ISubmissionRepository
+	tasks       *TaskService
+}
+
+func NewAssignmentService(assignments repository.IAssignmentRepository, classrooms repository.IClassroomRepository, submissions repository.ISubmissionRepository, tasks *TaskService) *AssignmentService {
+	return &AssignmentService{
+		assignments: assignments,
+		classrooms:  classrooms,
+		submissions: submissions,
+		tasks:       tasks,
+	}
+}
+
+type AssignmentSubmitResult struct {
+	Correct bool
+	Late    bool
+	// Pending is set for answers waiting for a teacher's grade.
+	Pending bool
+}
+
+func validateAssignment(a *models.Assignment, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return errors.New("assignment must contain at least one task")
+	}
+	if a.DueAt.Before(a.OpensAt) {
+		return errors.New("due date must be after open date")
+	}
+	if a.ClosesAt != nil && a.ClosesAt.Before(a.DueAt) {
+		return errors.New("close date must be after due date")
+	}
+	if a.LatePenalty < 0 || a.LatePenalty > 100 {
+		return errors.New("late penalty must be between 0 and 100")
+	}
+	if a.Weight < 0 {
+		return errors.New("weight must not be negative")
+	}
+	return nil
+}
+
+func (s *AssignmentService) requireClassroomTeacher(ctx context.Context, classroomID, userID, role string) error {
+	if isAdmin(role) {
+		_, err := s.classrooms.FindByID(ctx, classroomID)
+		return err
+	}
+
+	member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member == nil || !member.IsTeacher() {
+		return ErrAssignmentForbidden
+	}
+
+	return nil
+}
+
+func (s *AssignmentService) buildTasks(ctx context.Context, assignmentID string, taskIDs []string) ([]models.AssignmentTask, error) {
+	published, err := s.tasks.publishedTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.AssignmentTask, 0, len(published))
+	for _, task := range published {
+		tasks = append(tasks, models.AssignmentTask{
+			AssignmentID: assignmentID,
+			TaskID:       task.ID,
+			Position:     len(tasks) + 1,
+		})
+	}
+
+	return tasks, nil
+}
+
+func (s *AssignmentService) CreateAssignment(ctx context.Context, userID, role string, assignment *models.Assignment, taskIDs []string) error {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.CreateAssignment")
+	defer span.End()
+
+	if err := s.requireClassroomTeacher(ctx, assignment.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if assignment.OpensAt.IsZero() {
+		assignment.OpensAt = time.Now()
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = models.LatePolicyAccept
+	}
+
+	if err := validateAssignment(assignment, taskIDs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+
+	tasks, err := s.buildTasks(ctx, "", taskIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+
+	assignment.AuthorID = userID
+	assignment.Tasks = tasks
+
+	if err := s.assignments.Create(ctx, assignment); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AssignmentService) UpdateAssignment(ctx context.Context, userID, role string, assignment *models.Assignment, taskIDs []string) error {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.UpdateAssignment")
+	defer span.End()
+
+	existing, err := s.assignments.FindByID(ctx, assignment.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, existing.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if assignment.OpensAt.IsZero() {
+		assignment.OpensAt = existing.OpensAt
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = existing.LatePolicy
+	}
+
+	if err := validateAssignment(assignment, taskIDs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+
+	tasks, err := s.buildTasks(ctx, existing.ID, taskIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+
+	assignment.ClassroomID = existing.ClassroomID
+	assignment.AuthorID = existing.AuthorID
+	assignment.Weight = existing.Weight
+	assignment.CreatedAt = existing.CreatedAt
+	assignment.Tasks = tasks
+
+	if err := s.assignments.Update(ctx, assignment); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AssignmentService) DeleteAssignment(ctx context.Context, userID, role, id string) error {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.DeleteAssignment")
+	defer span.End()
+
+	existing, err := s.assignments.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, existing.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.assignments.Delete(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAssignment returns the assignment for classroom teachers, and for
+// students once it has opened.
+func (s *AssignmentService) GetAssignment(ctx context.Context, userID, role, id string) (*models.Assignment, error) {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.GetAssignment")
+	defer span.End()
+
+	assignment, err := s.assignments.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if isAdmin(role) {
+		return assignment, nil
+	}
+
+	member, err := s.classrooms.FindMember(ctx, assignment.ClassroomID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if member == nil {
+		return nil, ErrAssignmentForbidden
+	}
+
+	if !member.IsTeacher() && time.Now().Before(assignment.OpensAt) {
+		return nil, ErrAssignmentNotOpen
+	}
+
+	return assignment, nil
+}
+
+func (s *AssignmentService) GetClassroomAssignments(ctx context.Context, userID, role, classroomID string) ([]models.Assignment, error) {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.GetClassroomAssignments")
+	defer span.End()
+
+	teacher := isAdmin(role)
+	if !teacher {
+		member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		if member == nil {
+			return nil, ErrAssignmentForbidden
+		}
+		teacher = member.IsTeacher()
+	}
+
+	assignments, err := s.assignments.FindByClassroom(ctx, classroomID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if teacher {
+		return assignments, nil
+	}
+
+	now := time.Now()
+	visible := make([]models.Assignment, 0, len(assignments))
+	for _, a := range assignments {
+		if !now.Before(a.OpensAt) {
+			visible = append(visible, a)
+		}
+	}
+
+	return visible, nil
+}
+
+// GetStudentAssignments returns opened assignments from all of the student's
+// classrooms with their progress, optionally filtered by status.
+func (s *AssignmentService) GetStudentAssignments(ctx context.Context, userID string, status models.AssignmentStatus) ([]models.StudentAssignment, error) {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.GetStudentAssignments")
+	defer span.End()
+
+	assignments, err := s.assignments.FindForStudent(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	opened := make([]models.Assignment, 0, len(assignments))
+	ids := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		if now.Before(a.OpensAt) {
+			continue
+		}
+		opened = append(opened, a)
+		ids = append(ids, a.ID)
+	}
+
+	_, statsSpan := otel.Tracer("assignment").Start(ctx, "Submissions.GetAssignmentStats")
+	stats, err := s.submissions.GetAssignmentStats(ctx, ids, userID)
+	statsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	byAssignment := make(map[string][]models.AssignmentTaskStats)
+	for _, st := range stats {
+		byAssignment[st.AssignmentID] = append(byAssignment[st.AssignmentID], st)
+	}
+
+	result := make([]models.StudentAssignment, 0, len(opened))
+	for i := range opened {
+		progress := computeAssignmentProgress(&opened[i], userID, byAssignment[opened[i].ID], now)
+		if status != "" && progress.Status != status {
+			continue
+		}
+		result = append(result, models.StudentAssignment{Assignment: opened[i], Progress: progress})
+	}
+
+	return result, nil
+}
+
+// Submit checks the answer for a task of the assignment and records whether
+// it was made on time, enforcing the open date and late policy.
+func (s *AssignmentService) Submit(ctx context.Context, assignmentID, taskID, userID, answer string) (*AssignmentSubmitResult, error) {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.Submit")
+	defer span.End()
+
+	assignment, err := s.assignments.FindByID(ctx, assignmentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	member, err := s.classrooms.FindMember(ctx, assignment.ClassroomID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if member == nil || member.Role != models.ClassroomRoleStudent {
+		return nil, ErrAssignmentForbidden
+	}
+
+	if !assignmentHasTask(assignment, taskID) {
+		return nil, ErrAssignmentTaskNotFound
+	}
+
+	late, err := submissionTiming(assignment, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	submission := &models.Submission{
+		TaskID:       taskID,
+		UserID:       userID,
+		AssignmentID: &assignment.ID,
+		Answer:       answer,
+		IsLate:       late,
+	}
+
+	if err := s.tasks.Submit(ctx, submission); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &AssignmentSubmitResult{
+		Correct: submission.IsCorrect,
+		Late:    late,
+		Pending: submission.Status == models.SubmissionStatusPendingReview,
+	}, nil
+}
+
+// GetReport returns completion of the assignment for every student in the classroom.
+func (s *AssignmentService) GetReport(ctx context.Context, userID, role, id string) (*models.AssignmentReport, error) {
+	ctx, span := otel.Tracer("assignment").Start(ctx, "AssignmentService.GetReport")
+	defer span.End()
+
+	assignment, err := s.assignments.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, assignment.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, membersSpan := otel.Tracer("assignment").Start(ctx, "Classrooms.FindMembers")
+	members, err := s.classrooms.FindMembers(ctx, assignment.ClassroomID)
+	membersSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, statsSpan := otel.Tracer("assignment").Start(ctx, "Submissions.GetAssignmentStats")
+	stats, err := s.submissions.GetAssignmentStats(ctx, []string{assignment.ID}, "")
+	statsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	byUser := make(map[string][]models.AssignmentTaskStats)
+	for _, st := range stats {
+		byUser[st.UserID] = append(byUser[st.UserID], st)
+	}
+
+	now := time.Now()
+	report := &models.AssignmentReport{Assignment: assignment}
+	for _, m := range members {
+		if m.Role != models.ClassroomRoleStudent {
+			continue
+		}
+		report.Students = append(report.Students, models.AssignmentStudentReport{
+			Student:  m.User,
+			Progress: computeAssignmentProgress(assignment, m.UserID, byUser[m.UserID], now),
+		})
+	}
+
+	return report, nil
+}
+
+func assignmentHasTask(a *models.Assignment, taskID string) bool {
+	for _, t := range a.Tasks {
+		if t.TaskID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// submissionTiming reports whether a submission made at now is late, or an
+// error when the assignment doesn't accept submissions at that moment.
+func submissionTiming(a *models.Assignment, now time.Time) (bool, error) {
+	if now.Before(a.OpensAt) {
+		return false, ErrAssignmentNotOpen
+	}
+
+	if !now.After(a.DueAt) {
+		return false, nil
+	}
+
+	if a.LatePolicy == models.LatePolicyReject {
+		return false, ErrAssignmentClosed
+	}
+
+	if a.ClosesAt != nil && now.After(*a.ClosesAt) {
+		return false, ErrAssignmentClosed
+	}
+
+	return true, nil
+}
+
+func acceptsSubmissions(a *models.Assignment, now time.Time) bool {
+	_, err := submissionTiming(a, now)
+	return err == nil
+}
+
+func computeAssignmentProgress(a *models.Assignment, userID string, stats []models.AssignmentTaskStats, now time.Time) models.AssignmentProgress {
+	byTask := make(map[string]models.AssignmentTaskStats, len(stats))
+	for _, st := range stats {
+		byTask[st.TaskID] = st
+	}
+
+	lateCredit := 1.0
+	if a.LatePolicy == models.LatePolicyPenalty {
+		lateCredit = float64(100-a.LatePenalty) / 100
+	}
+
+	progress := models.AssignmentProgress{
+		AssignmentID: a.ID,
+		UserID:       userID,
+		TotalTasks:   len(a.Tasks),
+	}
+
+	earned := 0.0
+	for _, t := range a.Tasks {
+		st, ok := byTask[t.TaskID]
+		if !ok {
+			continue
+		}
+		progress.Attempted++
+		switch {
+		case st.SolvedOnTime:
+			progress.SolvedOnTime++
+			earned++
+		case st.Solved:
+			progress.SolvedLate++
+			earned += lateCredit
+		}
+	}
+
+	if progress.TotalTasks > 0 {
+		progress.Score = math.Round(earned/float64(progress.TotalTasks)*1000) / 10
+	}
+
+	solved := progress.SolvedOnTime + progress.SolvedLate
+	switch {
+	case progress.TotalTasks > 0 && progress.SolvedOnTime == progress.TotalTasks:
+		progress.Status = models.AssignmentStatusCompleted
+	case progress.TotalTasks > 0 && solved == progress.TotalTasks:
+		progress.Status = models.AssignmentStatusCompletedLate
+	case !now.After(a.DueAt):
+		progress.Status = models.AssignmentStatusPending
+	case acceptsSubmissions(a, now):
+		progress.Status = models.AssignmentStatusOverdue
+	default:
+		progress.Status = models.AssignmentStatusMissed
+	}
+
+	return progress
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeAssignmentRepo struct {
+	assignments map[string]*models.Assignment
+}
+
+func newFakeAssignmentRepo() *fakeAssignmentRepo {
+	return &fakeAssignmentRepo{assignments: make(map[string]*models.Assignment)}
+}
+
+func (f *fakeAssignmentRepo) Create(ctx context.Context, assignment *models.Assignment) error {
+	if assignment.ID == "" {
+		assignment.ID = uuid.NewString()
+	}
+	for i := range assignment.Tasks {
+		assignment.Tasks[i].AssignmentID = assignment.ID
+	}
+	f.assignments[assignment.ID] = assignment
+	return nil
+}
+
+func (f *fakeAssignmentRepo) FindByID(ctx context.Context, id string) (*models.Assignment, error) {
+	a, ok := f.assignments[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (f *fakeAssignmentRepo) FindByClassroom(ctx context.Context, classroomID string) ([]models.Assignment, error) {
+	var out []models.Assignment
+	for _, a := range f.assignments {
+		if a.ClassroomID == classroomID {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAssignmentRepo) FindForStudent(ctx context.Context, userID string) ([]models.Assignment, error) {
+	return nil, nil
+}
+
+func (f *fakeAssignmentRepo) Update(ctx context.Context, assignment *models.Assignment) error {
+	cp := *assignment
+	f.assignments[assignment.ID] = &cp
+	return nil
+}
+
+func (f *fakeAssignmentRepo) Delete(ctx context.Context, id string) error {
+	delete(f.assignments, id)
+	return nil
+}
+
+func (f *fakeAssignmentRepo) UpdateWeights(ctx context.Context, classroomID string, weights map[string]float64) error {
+	for id, w := range weights {
+		a, ok := f.assignments[id]
+		if !ok || a.ClassroomID != classroomID {
+			return errFakeNotFound
+		}
+		a.Weight = w
+	}
+	return nil
+}
+
+func TestSubmissionTiming_LatePolicies(t *testing.T) {
+	due := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	closes := due.Add(48 * time.Hour)
+
+	a := &models.Assignment{
+		OpensAt:    due.Add(-7 * 24 * time.Hour),
+		DueAt:      due,
+		LatePolicy: models.LatePolicyAccept,
+	}
+
+	_, err := submissionTiming(a, a.OpensAt.Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrAssignmentNotOpen)
+
+	late, err := submissionTiming(a, due)
+	require.NoError(t, err)
+	assert.False(t, late, "submission exactly at the deadline is on time")
+
+	late, err = submissionTiming(a, due.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, late)
+
+	a.ClosesAt = &closes
+	_, err = submissionTiming(a, closes.Add(time.Second))
+	assert.ErrorIs(t, err, ErrAssignmentClosed)
+
+	a.LatePolicy = models.LatePolicyReject
+	_, err = submissionTiming(a, due.Add(time.Second))
+	assert.ErrorIs(t, err, ErrAssignmentClosed)
+}
+
+func TestComputeAssignmentProgress_StatusAndScore(t *testing.T) {
+	due := time.Now().Add(-time.Hour)
+	a := &models.Assignment{
+		ID:          "a1",
+		OpensAt:     due.Add(-24 * time.Hour),
+		DueAt:       due,
+		LatePolicy:  models.LatePolicyPenalty,
+		LatePenalty: 50,
+		Tasks: []models.AssignmentTask{
+			{TaskID: "t1"}, {TaskID: "t2"},
+		},
+	}
+
+	p := computeAssignmentProgress(a, "s1", nil, time.Now())
+	assert.Equal(t, models.AssignmentStatusOverdue, p.Status)
+	assert.Equal(t, 0.0, p.Score)
+
+	stats := []models.AssignmentTaskStats{
+		{TaskID: "t1", Attempts: 1, Solved: true, SolvedOnTime: true},
+		{TaskID: "t2", Attempts: 3, Solved: true},
+	}
+	p = computeAssignmentProgress(a, "s1", stats, time.Now())
+	assert.Equal(t, models.AssignmentStatusCompletedLate, p.Status)
+	assert.Equal(t, 1, p.SolvedOnTime)
+	assert.Equal(t, 1, p.SolvedLate)
+	assert.Equal(t, 75.0, p.Score)
+
+	a.LatePolicy = models.LatePolicyReject
+	p = computeAssignmentProgress(a, "s1", stats[:1], time.Now())
+	assert.Equal(t, models.AssignmentStatusMissed, p.Status)
+
+	p = computeAssignmentProgress(a, "s1", stats[:1], due.Add(-time.Minute))
+	assert.Equal(t, models.AssignmentStatusPending, p.Status)
+}
+
+func TestAssignmentService_SubmitAndReport(t *testing.T) {
+	ctx := context.Background()
+
+	classrooms := newFakeClassroomRepo()
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	classrooms.members[classroom.ID]["student"] = &models.ClassroomMember{
+		ClassroomID: classroom.ID, UserID: "student", Role: models.ClassroomRoleStudent,
+		User: &models.User{DisplayName: "Student"},
+	}
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["t1"] = &models.Task{ID: "t1", Status: models.TaskStatusPublished, CorrectAnswer: "42"}
+	tasks.byID["t2"] = &models.Task{ID: "t2", Status: models.TaskStatusPublished, CorrectAnswer: "7"}
+	tasks.byID["draft"] = &models.Task{ID: "draft", Status: models.TaskStatusDraft}
+
+	submissions := newFakeSubmissionRepo()
+	taskService := NewTaskService(tasks, submissions, newTestRedis(t))
+	svc := NewAssignmentService(newFakeAssignmentRepo(), classrooms, submissions, taskService)
+
+	assignment := &models.Assignment{
+		ClassroomID: classroom.ID,
+		Title:       "Homework 1",
+		DueAt:       time.Now().Add(24 * time.Hour),
+	}
+
+	err := svc.CreateAssignment(ctx, "student", "Student", assignment, []string{"t1"})
+	assert.ErrorIs(t, err, ErrAssignmentForbidden)
+
+	err = svc.CreateAssignment(ctx, "teacher", "Teacher", assignment, []string{"t1", "draft"})
+	assert.ErrorIs(t, err, ErrInvalidAssignment)
+
+	require.NoError(t, svc.CreateAssignment(ctx, "teacher", "Teacher", assignment, []string{"t1", "t2", "t1"}))
+	assert.Len(t, assignment.Tasks, 2, "duplicate task ids are collapsed")
+	assert.Equal(t, models.LatePolicyAccept, assignment.LatePolicy)
+
+	_, err = svc.Submit(ctx, assignment.ID, "t1", "teacher", "42")
+	assert.ErrorIs(t, err, ErrAssignmentForbidden)
+
+	_, err = svc.Submit(ctx, assignment.ID, "other-task", "student", "42")
+	assert.ErrorIs(t, err, ErrAssignmentTaskNotFound)
+
+	result, err := svc.Submit(ctx, assignment.ID, "t1", "student", "42")
+	require.NoError(t, err)
+	assert.True(t, result.Correct)
+	assert.False(t, result.Late)
+
+	require.Len(t, submissions.submissions, 1)
+	require.NotNil(t, submissions.submissions[0].AssignmentID)
+	assert.Equal(t, assignment.ID, *submissions.submissions[0].AssignmentID)
+
+	report, err := svc.GetReport(ctx, "teacher", "Teacher", assignment.ID)
+	require.NoError(t, err)
+	require.Len(t, report.Students, 1)
+	assert.Equal(t, "Student", report.Students[0].Student.DisplayName)
+	assert.Equal(t, 1, report.Students[0].Progress.SolvedOnTime)
+	assert.Equal(t, 50.0, report.Students[0].Progress.Score)
+	assert.Equal(t, models.AssignmentStatusPending, report.Students[0].Progress.Status)
+
+	_, err = svc.GetReport(ctx, "student", "Student", assignment.ID)
+	assert.ErrorIs(t, err, ErrAssignmentForbidden)
+}
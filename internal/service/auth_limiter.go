@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	authLimiterKeyPrefix = "auth:"
+
+	// loginEmailMaxFailures failed logins for one email within
+	// loginFailureWindow lock the email out.
+	loginEmailMaxFailures = 5
+	// loginIPMaxFailures is higher than the per email limit because a whole
+	// school often signs in from a single address.
+	loginIPMaxFailures = 50
+	loginFailureWindow = 15 * time.Minute
+
+	// Every lockout within loginLockoutMemory doubles the next one, from
+	// loginBaseLockout up to loginMaxLockout.
+	loginBaseLockout   = time.Minute
+	loginMaxLockout    = time.Hour
+	loginLockoutMemory = 24 * time.Hour
+
+	// MaxVerificationAttempts wrong guesses invalidate the code that was sent.
+	MaxVerificationAttempts = 5
+	verificationWindow      = 15 * time.Minute
+
+	// CodeResendInterval is how often a new code can be emailed to an address.
+	CodeResendInterval = time.Minute
+)
+
+const (
+	RateLimitLoginLocked          = "LOGIN_LOCKED"
+	RateLimitCodeAttemptsExceeded = "CODE_ATTEMPTS_EXCEEDED"
+	RateLimitCodeResendThrottled  = "CODE_RESEND_THROTTLED"
+)
+
+// RateLimitError is returned when a client has to slow down. RetryAfter is
+// zero when waiting does not help, e.g. a code that has to be requested again.
+type RateLimitError struct {
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// AuthLimiter keeps brute-force counters for login and code verification in
+// Redis. Redis errors let the request through: an outage must not lock
+// everybody out. A nil *AuthLimiter allows everything.
+type AuthLimiter struct {
+	redis *redis.Client
+}
+
+func NewAuthLimiter(rdb *redis.Client) *AuthLimiter {
+	return &AuthLimiter{redis: rdb}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginKeys(kind, email, ip string) []string {
+	keys := []string{authLimiterKeyPrefix + "login:" + kind + ":email:" + normalizeEmail(email)}
+	if ip != "" {
+		keys = append(keys, authLimiterKeyPrefix+"login:"+kind+":ip:"+ip)
+	}
+	return keys
+}
+
+func verificationKey(purpose models.VerificationPurpose, email string) string {
+	return authLimiterKeyPrefix + "verify:" + string(purpose) + ":" + normalizeEmail(email)
+}
+
+func verificationLockKey(purpose models.VerificationPurpose, email string) string {
+	return authLimiterKeyPrefix + "verify-lock:" + string(purpose) + ":" + normalizeEmail(email)
+}
+
+func resendKey(purpose models.VerificationPurpose, email string) string {
+	return authLimiterKeyPrefix + "resend:" + string(purpose) + ":" + normalizeEmail(email)
+}
+
+// lockoutDuration is the length of the n-th lockout (counting from 1).
+func lockoutDuration(n int64) time.Duration {
+	d := loginBaseLockout
+	for i := int64(1); i < n && d < loginMaxLockout; i++ {
+		d *= 2
+	}
+	if d > loginMaxLockout {
+		d = loginMaxLockout
+	}
+	return d
+}
+
+// CheckLogin fails while the email or the client address is locked out.
+func (l *AuthLimiter) CheckLogin(ctx context.Context, email, ip string) error {
+	if l == nil {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.CheckLogin")
+	defer span.End()
+
+	for _, key := range loginKeys("lock", email, ip) {
+		ttl, err := l.redis.PTTL(ctx, key).Result()
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		if ttl > 0 {
+			return &RateLimitError{
+				Code:       RateLimitLoginLocked,
+				Message:    fmt.Sprintf("too many failed login attempts, try again in %s", ttl.Round(time.Second)),
+				RetryAfter: ttl,
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoginFailed counts a failed login and starts a lockout once the email or
+// the address reaches its limit.
+func (l *AuthLimiter) LoginFailed(ctx context.Context, email, ip string) {
+	if l == nil {
+		return
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.LoginFailed")
+	defer span.End()
+
+	failKeys := loginKeys("fail", email, ip)
+	lockKeys := loginKeys("lock", email, ip)
+	levelKeys := loginKeys("level", email, ip)
+	limits := []int64{loginEmailMaxFailures, loginIPMaxFailures}
+
+	for i, key := range failKeys {
+		failures, err := l.redis.Incr(ctx, key).Result()
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		if failures == 1 {
+			l.redis.Expire(ctx, key, loginFailureWindow)
+		}
+		if failures < limits[i] {
+			continue
+		}
+
+		level, err := l.redis.Incr(ctx, levelKeys[i]).Result()
+		if err != nil {
+			span.RecordError(err)
+			level = 1
+		}
+		l.redis.Expire(ctx, levelKeys[i], loginLockoutMemory)
+
+		if err := l.redis.Set(ctx, lockKeys[i], level, lockoutDuration(level)).Err(); err != nil {
+			span.RecordError(err)
+		}
+		l.redis.Del(ctx, key)
+	}
+}
+
+// LoginSucceeded clears the failures of the email. Failures of the address are
+// kept, since other accounts may be guessed from it.
+func (l *AuthLimiter) LoginSucceeded(ctx context.Context, email string) {
+	if l == nil {
+		return
+	}
+
+	normalized := normalizeEmail(email)
+	l.redis.Del(ctx,
+		authLimiterKeyPrefix+"login:fail:email:"+normalized,
+		authLimiterKeyPrefix+"login:level:email:"+normalized,
+	)
+}
+
+// VerificationFailed counts a wrong code and reports whether the attempts
+// are used up, in which case the caller invalidates the code.
+func (l *AuthLimiter) VerificationFailed(ctx context.Context, purpose models.VerificationPurpose, email string) bool {
+	if l == nil {
+		return false
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.VerificationFailed")
+	defer span.End()
+
+	key := verificationKey(purpose, email)
+	attempts, err := l.redis.Incr(ctx, key).Result()
+	if err != nil {
+		span.RecordError(err)
+		return false
+	}
+	if attempts == 1 {
+		l.redis.Expire(ctx, key, verificationWindow)
+	}
+
+	if attempts >= MaxVerificationAttempts {
+		l.redis.Del(ctx, key)
+		return true
+	}
+	return false
+}
+
+// CheckVerification fails while LockVerification keeps the purpose locked
+// for the email. It is for codes that cannot be invalidated and sent again,
+// like those of an authenticator app.
+func (l *AuthLimiter) CheckVerification(ctx context.Context, purpose models.VerificationPurpose, email string) error {
+	if l == nil {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.CheckVerification")
+	defer span.End()
+
+	ttl, err := l.redis.PTTL(ctx, verificationLockKey(purpose, email)).Result()
+	if err != nil {
+		span.RecordError(err)
+		return nil
+	}
+	if ttl > 0 {
+		return &RateLimitError{
+			Code:       RateLimitCodeAttemptsExceeded,
+			Message:    fmt.Sprintf("too many wrong codes, try again in %s", ttl.Round(time.Second)),
+			RetryAfter: ttl,
+		}
+	}
+	return nil
+}
+
+// LockVerification locks the purpose for the email for verificationWindow
+// once VerificationFailed reports the attempts used up, and returns the error
+// to hand to the client.
+func (l *AuthLimiter) LockVerification(ctx context.Context, purpose models.VerificationPurpose, email string) error {
+	err := &RateLimitError{
+		Code:       RateLimitCodeAttemptsExceeded,
+		Message:    fmt.Sprintf("too many wrong codes, try again in %s", verificationWindow),
+		RetryAfter: verificationWindow,
+	}
+	if l == nil {
+		return err
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.LockVerification")
+	defer span.End()
+
+	if setErr := l.redis.Set(ctx, verificationLockKey(purpose, email), 1, verificationWindow).Err(); setErr != nil {
+		span.RecordError(setErr)
+	}
+	return err
+}
+
+// CodeSent throttles emailing codes to an address and starts a fresh attempt
+// count for the new code.
+func (l *AuthLimiter) CodeSent(ctx context.Context, purpose models.VerificationPurpose, email string) error {
+	if l == nil {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthLimiter.CodeSent")
+	defer span.End()
+
+	key := resendKey(purpose, email)
+	ok, err := l.redis.SetNX(ctx, key, 1, CodeResendInterval).Result()
+	if err != nil {
+		span.RecordError(err)
+		return nil
+	}
+	if !ok {
+		ttl, _ := l.redis.PTTL(ctx, key).Result()
+		if ttl <= 0 {
+			ttl = CodeResendInterval
+		}
+		return &RateLimitError{
+			Code:       RateLimitCodeResendThrottled,
+			Message:    fmt.Sprintf("a code was sent recently, try again in %s", ttl.Round(time.Second)),
+			RetryAfter: ttl,
+		}
+	}
+
+	l.redis.Del(ctx, verificationKey(purpose, email))
+	return nil
+}
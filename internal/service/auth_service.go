@@ -29,21 +29,42 @@ func generateVerificationCode() (string, error) {
 	return fmt.Sprintf("%06d", n.Int64()), nil
 }
 
+// securityEventsLimit is how many recent security events a user can see.
+const securityEventsLimit = 50
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token was already used, the session has been signed out")
+)
+
+// ClientInfo describes the device a session is signed in from.
+type ClientInfo struct {
+	DeviceName string
+	IPAddress  string
+	UserAgent  string
+}
+
 type AuthService struct {
 	users         repository.IUserRepository
 	verifications repository.IVerificationRepository
 	tokens        repository.ITokenRepository
-	emailProducer *kafka.EmailProducer
-	secret        string
+	events        repository.ISecurityEventRepository
+	recoveryCodes repository.IRecoveryCodeRepository
+	limiter       *AuthLimiter
+	emailProducer EmailSender
+	keys          *KeySet
 }
 
-func NewAuthService(u repository.IUserRepository, v repository.IVerificationRepository, t repository.ITokenRepository, p *kafka.EmailProducer, secret string) *AuthService {
+func NewAuthService(u repository.IUserRepository, v repository.IVerificationRepository, t repository.ITokenRepository, e repository.ISecurityEventRepository, r repository.IRecoveryCodeRepository, l *AuthLimiter, p EmailSender, keys *KeySet) *AuthService {
 	return &AuthService{
 		users:         u,
 		verifications: v,
 		tokens:        t,
+		events:        e,
+		recoveryCodes: r,
+		limiter:       l,
 		emailProducer: p,
-		secret:        secret,
+		keys:          keys,
 	}
 }
 
@@ -52,13 +73,34 @@ func (s *AuthService) generateHash(value string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-func (s *AuthService) createJWT(userID uuid.UUID, role models.UserRole) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// createJWT issues an access token. twoFactor tells whether the session was
+// started with a second factor.
+func (s *AuthService) createJWT(userID uuid.UUID, role models.UserRole, sessionID uuid.UUID, twoFactor bool) (string, error) {
+	return s.keys.Sign(jwt.MapClaims{
 		"userId": userID.String(),
 		"role":   string(role),
+		"sid":    sessionID.String(),
+		"mfa":    twoFactor,
 		"exp":    time.Now().Add(15 * time.Minute).Unix(),
 	})
-	return token.SignedString([]byte(s.secret))
+}
+
+// codeRejected counts a wrong verification code. Once the attempts are used
+// up the outstanding codes of the user are invalidated, so guessing has to
+// start over with a freshly emailed code.
+func (s *AuthService) codeRejected(ctx context.Context, purpose models.VerificationPurpose, email string) error {
+	if !s.limiter.VerificationFailed(ctx, purpose, email) {
+		return errors.New("invalid or expired code")
+	}
+
+	if user, err := s.users.FindByEmail(ctx, email); err == nil && user != nil {
+		s.verifications.InvalidateForUser(ctx, user.ID, purpose)
+	}
+
+	return &RateLimitError{
+		Code:    RateLimitCodeAttemptsExceeded,
+		Message: "too many wrong codes, request a new one",
+	}
 }
 
 func (s *AuthService) Register(ctx context.Context, email, password, name string) error {
@@ -75,6 +117,11 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		return err
 	}
 
+	if err := s.limiter.CodeSent(ctx, models.VerificationPurposeEmail, email); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
 	if existing != nil && existing.Status != "ACTIVE" {
 		_, codeSpan := otel.Tracer("auth").Start(ctx, "GenerateVerificationCode (resend)")
 		code, err := generateVerificationCode()
@@ -85,11 +132,22 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 			return err
 		}
 
+		// only the newest code works, as with password reset
+		_, invalidateSpan := otel.Tracer("auth").Start(ctx, "Verification.InvalidatePrevious")
+		err = s.verifications.InvalidateForUser(ctx, existing.ID, models.VerificationPurposeEmail)
+		invalidateSpan.End()
+
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+
 		_, verSpan := otel.Tracer("auth").Start(ctx, "DB.SaveVerification (resend)")
 		model := &models.EmailVerification{
 			ID:        uuid.New(),
 			UserID:    existing.ID,
 			Code:      code,
+			Purpose:   models.VerificationPurposeEmail,
 			ExpiresAt: time.Now().Add(15 * time.Minute),
 		}
 		err = s.verifications.Create(ctx, model)
@@ -140,6 +198,7 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		ID:        uuid.New(),
 		UserID:    user.ID,
 		Code:      code,
+		Purpose:   models.VerificationPurposeEmail,
 		ExpiresAt: time.Now().Add(15 * time.Minute),
 	}
 	err = s.verifications.Create(ctx, model);
@@ -166,12 +225,13 @@ func (s *AuthService) VerifyEmail(ctx context.Context, email, code string) error
 	defer span.End()
 
 	_, findSpan := otel.Tracer("auth").Start(ctx, "Verification.FindValid")
-	rec, err := s.verifications.FindValid(ctx, email, code)
+	rec, err := s.verifications.FindValid(ctx, email, code, models.VerificationPurposeEmail)
 	findSpan.End()
 
-	if err != nil {
+	if err != nil || rec == nil {
+		err = s.codeRejected(ctx, models.VerificationPurposeEmail, email)
 		span.RecordError(err)
-		return errors.New("invalid or expired code")
+		return err
 	}
 
 	_, markSpan := otel.Tracer("auth").Start(ctx, "Verification.MarkUsed")
@@ -205,18 +265,147 @@ func (s *AuthService) VerifyEmail(ctx context.Context, email, code string) error
 	return nil
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, string, error) {
+// ForgotPassword emails a password reset code. Unknown and unverified
+// addresses are ignored without an error, so the endpoint cannot be used to
+// find out who has an account. The resend throttle is applied before the
+// lookup for the same reason.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.ForgotPassword")
+	defer span.End()
+
+	if err := s.limiter.CodeSent(ctx, models.VerificationPurposePasswordReset, email); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, findSpan := otel.Tracer("auth").Start(ctx, "User.FindByEmail")
+	user, err := s.users.FindByEmail(ctx, email)
+	findSpan.End()
+
+	if err != nil || user == nil || user.Status != "ACTIVE" {
+		return nil
+	}
+
+	_, codeSpan := otel.Tracer("auth").Start(ctx, "GenerateVerificationCode")
+	code, err := generateVerificationCode()
+	codeSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, invalidateSpan := otel.Tracer("auth").Start(ctx, "Verification.InvalidatePrevious")
+	err = s.verifications.InvalidateForUser(ctx, user.ID, models.VerificationPurposePasswordReset)
+	invalidateSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, verSpan := otel.Tracer("auth").Start(ctx, "DB.SaveVerification")
+	err = s.verifications.Create(ctx, &models.EmailVerification{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Code:      code,
+		Purpose:   models.VerificationPurposePasswordReset,
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	})
+	verSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, kSpan := otel.Tracer("kafka").Start(ctx, "Kafka.SendEmailCode")
+	s.emailProducer.SendAsync(kafka.EmailMessage{
+		Email:   user.Email,
+		Subject: "Reset your password",
+		Code:    code,
+	})
+	kSpan.End()
+
+	return nil
+}
+
+// ResetPassword sets a new password using a code from ForgotPassword and
+// signs the user out of every session.
+func (s *AuthService) ResetPassword(ctx context.Context, email, code, password string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.ResetPassword")
+	defer span.End()
+
+	_, findSpan := otel.Tracer("auth").Start(ctx, "Verification.FindValid")
+	rec, err := s.verifications.FindValid(ctx, email, code, models.VerificationPurposePasswordReset)
+	findSpan.End()
+
+	if err != nil || rec == nil {
+		err = s.codeRejected(ctx, models.VerificationPurposePasswordReset, email)
+		span.RecordError(err)
+		return err
+	}
+
+	_, markSpan := otel.Tracer("auth").Start(ctx, "Verification.MarkUsed")
+	err = s.verifications.MarkUsed(ctx, rec.ID)
+	markSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, hashSpan := otel.Tracer("auth").Start(ctx, "Password.Hash")
+	pass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, updateSpan := otel.Tracer("auth").Start(ctx, "User.UpdatePassword")
+	err = s.users.Update(ctx, rec.UserID.String(), map[string]interface{}{"password_hash": string(pass)})
+	updateSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, revokeSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.RevokeAll")
+	err = s.tokens.RevokeAllForUser(ctx, rec.UserID)
+	revokeSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Login starts a new session. Other sessions of the user stay signed in.
+// Repeated wrong passwords lock the email and the client address out. Users
+// with two-factor authentication get a challenge token instead of a session.
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error) {
 	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.Login")
 	defer span.End()
 
+	if err := s.limiter.CheckLogin(ctx, email, client.IPAddress); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	_, findSpan := otel.Tracer("auth").Start(ctx, "User.FindByEmail")
 	user, err := s.users.FindByEmail(ctx, email)
 	findSpan.End()
 
 	if err != nil || user == nil {
+		s.limiter.LoginFailed(ctx, email, client.IPAddress)
 		err := errors.New("invalid credentials")
 		span.RecordError(err)
-		return "", "", err
+		return nil, err
 	}
 
 	_, checkSpan := otel.Tracer("auth").Start(ctx, "Password.Verify")
@@ -224,41 +413,71 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 	checkSpan.End()
 
 	if err != nil {
+		s.limiter.LoginFailed(ctx, email, client.IPAddress)
 		err2 := errors.New("invalid credentials")
 		span.RecordError(err2)
-		return "", "", err2
+		return nil, err2
 	}
 
 	if user.Status != "ACTIVE" {
 		err := errors.New("email not verified")
 		span.RecordError(err)
-		return "", "", err
+		return nil, err
 	}
 
-	_, revokeSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.RevokeAll")
-	err = s.tokens.RevokeAllForUser(ctx, user.ID)
-	revokeSpan.End()
+	// the failure count is only reset once the second factor is checked too,
+	// otherwise a known password would allow unlimited code guesses
+	if !user.TwoFactorEnabled() {
+		s.limiter.LoginSucceeded(ctx, email)
+	}
+
+	return s.signIn(ctx, user, client)
+}
+
+// signIn starts a session for a user whose first factor was checked, or
+// returns a challenge token if a second factor is needed.
+func (s *AuthService) signIn(ctx context.Context, user *models.User, client ClientInfo) (*LoginResult, error) {
+	if !user.TwoFactorEnabled() {
+		return s.startSession(ctx, user, client, false)
+	}
+
+	_, challengeSpan := otel.Tracer("auth").Start(ctx, "JWT.CreateChallenge")
+	challenge, err := s.createChallenge(user.ID)
+	challengeSpan.End()
 
 	if err != nil {
-		span.RecordError(err)
-		return "", "", err
+		return nil, err
 	}
+	return &LoginResult{ChallengeToken: challenge}, nil
+}
+
+func (s *AuthService) startSession(ctx context.Context, user *models.User, client ClientInfo, twoFactor bool) (*LoginResult, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.startSession")
+	defer span.End()
+
+	sessionID := uuid.New()
 
 	_, jwtSpan := otel.Tracer("auth").Start(ctx, "JWT.CreateAccess")
-	access, err := s.createJWT(user.ID, user.Role)
+	access, err := s.createJWT(user.ID, user.Role, sessionID, twoFactor)
 	jwtSpan.End()
 
 	if err != nil {
 		span.RecordError(err)
-		return "", "", err
+		return nil, err
 	}
 
 	rawRefresh := uuid.New().String()
 
 	refreshModel := models.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: s.generateHash(rawRefresh),
-		ExpiresAt: time.Now().Add(30 * 24 * time.Hour),
+		UserID:     user.ID,
+		SessionID:  sessionID,
+		TokenHash:  s.generateHash(rawRefresh),
+		DeviceName: client.DeviceName,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		LastUsedAt: time.Now(),
+		TwoFactor:  twoFactor,
+		ExpiresAt:  time.Now().Add(30 * 24 * time.Hour),
 	}
 
 	_, saveSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.Save")
@@ -267,13 +486,17 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 
 	if err != nil {
 		span.RecordError(err)
-		return "", "", err
+		return nil, err
 	}
 
-	return access, rawRefresh, nil
+	return &LoginResult{AccessToken: access, RefreshToken: rawRefresh}, nil
 }
 
-func (s *AuthService) Refresh(ctx context.Context, oldRefresh string) (string, string, error) {
+// Refresh rotates the refresh token of a session. The device name is kept;
+// the address and user agent are updated to the client's current ones.
+// Presenting a token that was already rotated means it has been copied, so
+// the whole session is revoked and the user is warned.
+func (s *AuthService) Refresh(ctx context.Context, oldRefresh string, client ClientInfo) (string, string, error) {
 	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.Refresh")
 	defer span.End()
 
@@ -283,17 +506,17 @@ func (s *AuthService) Refresh(ctx context.Context, oldRefresh string) (string, s
 	token, err := s.tokens.FindValid(ctx, hash)
 	findSpan.End()
 
-	if err != nil || token == nil {
-		err = errors.New("invalid refresh token")
+	if err != nil {
 		span.RecordError(err)
-		return "", "", err
+		return "", "", errors.New("invalid refresh token")
 	}
 
-	_, revokeSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.Revoke")
-	err = s.tokens.Revoke(ctx, hash)
-	revokeSpan.End()
-
-	if err != nil {
+	if token == nil {
+		if reused := s.detectReuse(ctx, hash, client); reused {
+			span.RecordError(ErrRefreshTokenReused)
+			return "", "", ErrRefreshTokenReused
+		}
+		err = errors.New("invalid refresh token")
 		span.RecordError(err)
 		return "", "", err
 	}
@@ -309,7 +532,7 @@ func (s *AuthService) Refresh(ctx context.Context, oldRefresh string) (string, s
 	}
 
 	_, accessSpan := otel.Tracer("auth").Start(ctx, "JWT.CreateAccess")
-	newAccess, err := s.createJWT(token.UserID, user.Role)
+	newAccess, err := s.createJWT(token.UserID, user.Role, token.SessionID, token.TwoFactor)
 	accessSpan.End()
 
 	if err != nil {
@@ -319,23 +542,222 @@ func (s *AuthService) Refresh(ctx context.Context, oldRefresh string) (string, s
 
 	newRefreshRaw := uuid.NewString()
 	newModel := &models.RefreshToken{
-		UserID:    token.UserID,
-		TokenHash: s.generateHash(newRefreshRaw),
-		ExpiresAt: time.Now().Add(30 * 24 * time.Hour),
+		UserID:     token.UserID,
+		SessionID:  token.SessionID,
+		TokenHash:  s.generateHash(newRefreshRaw),
+		DeviceName: token.DeviceName,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		LastUsedAt: time.Now(),
+		TwoFactor:  token.TwoFactor,
+		ExpiresAt:  time.Now().Add(30 * 24 * time.Hour),
+	}
+	if newModel.IPAddress == "" {
+		newModel.IPAddress = token.IPAddress
+	}
+	if newModel.UserAgent == "" {
+		newModel.UserAgent = token.UserAgent
 	}
 
-	_, saveSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.SaveNew")
-	err = s.tokens.Save(ctx, newModel)
-	saveSpan.End()
+	_, rotateSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.Rotate")
+	rotated, err := s.tokens.Rotate(ctx, token, newModel)
+	rotateSpan.End()
 
 	if err != nil {
 		span.RecordError(err)
 		return "", "", err
 	}
 
+	// another request rotated the same token first
+	if !rotated {
+		s.detectReuse(ctx, hash, client)
+		span.RecordError(ErrRefreshTokenReused)
+		return "", "", ErrRefreshTokenReused
+	}
+
 	return newAccess, newRefreshRaw, nil
 }
 
+// detectReuse checks whether an invalid refresh token was rotated before. If
+// so, it revokes the token's session, records a security event and emails the
+// user. Tokens revoked by a logout are not reuse.
+func (s *AuthService) detectReuse(ctx context.Context, hash string, client ClientInfo) bool {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.detectReuse")
+	defer span.End()
+
+	token, err := s.tokens.FindByHash(ctx, hash)
+	if err != nil {
+		span.RecordError(err)
+		return false
+	}
+	if token == nil || !token.Revoked {
+		return false
+	}
+
+	rotated, err := s.tokens.HasSuccessor(ctx, token.ID)
+	if err != nil {
+		span.RecordError(err)
+		return false
+	}
+	if !rotated {
+		return false
+	}
+
+	if _, err := s.tokens.RevokeSession(ctx, token.UserID, token.SessionID); err != nil {
+		span.RecordError(err)
+	}
+
+	sessionID := token.SessionID
+	err = s.events.Create(ctx, &models.SecurityEvent{
+		UserID:    token.UserID,
+		Kind:      models.SecurityEventRefreshTokenReuse,
+		SessionID: &sessionID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID.String())
+	if err != nil || user == nil {
+		return true
+	}
+
+	device := token.DeviceName
+	if device == "" {
+		device = "an unnamed device"
+	}
+
+	_, kSpan := otel.Tracer("kafka").Start(ctx, "Kafka.SendSecurityAlert")
+	s.emailProducer.SendAsync(kafka.EmailMessage{
+		Email:   user.Email,
+		Subject: "Security alert: a session was signed out",
+		Body: fmt.Sprintf("Hi %s,\n\nAn old sign-in token of your session on %s was used again from %s. "+
+			"This can mean someone copied it, so we signed that session out.\n\n"+
+			"If this wasn't you, reset your password and sign out of all sessions.",
+			user.DisplayName, device, client.IPAddress),
+	})
+	kSpan.End()
+
+	return true
+}
+
+func (s *AuthService) GetSecurityEvents(ctx context.Context, userID string) ([]models.SecurityEvent, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.GetSecurityEvents")
+	defer span.End()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	events, err := s.events.FindByUser(ctx, id, securityEventsLimit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Logout ends the session the refresh token belongs to.
+func (s *AuthService) Logout(ctx context.Context, refresh string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	_, findSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.FindValid")
+	token, err := s.tokens.FindValid(ctx, s.generateHash(refresh))
+	findSpan.End()
+
+	if err != nil || token == nil {
+		err = errors.New("invalid refresh token")
+		span.RecordError(err)
+		return err
+	}
+
+	_, revokeSpan := otel.Tracer("auth").Start(ctx, "RefreshTokens.RevokeSession")
+	_, err = s.tokens.RevokeSession(ctx, token.UserID, token.SessionID)
+	revokeSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// LogoutAll ends every session of the user. Access tokens already issued stay
+// valid until they expire.
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.LogoutAll")
+	defer span.End()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.tokens.RevokeAllForUser(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSessions returns the signed in sessions of the user. Each one is
+// represented by its current refresh token.
+func (s *AuthService) GetSessions(ctx context.Context, userID string) ([]models.RefreshToken, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.GetSessions")
+	defer span.End()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	sessions, err := s.tokens.FindActiveByUser(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.RevokeSession")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+
+	revoked, err := s.tokens.RevokeSession(ctx, uid, sid)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
 func (s *AuthService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.GetUserByID")
 	defer span.End()
@@ -2,11 +2,19 @@ package service
 
 import (
 	"context"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	miniredis "github.com/alicebob/miniredis/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
@@ -84,9 +92,59 @@ func (f *fakeUserRepo) Update(ctx context.Context, id string, updates map[string
 		u.AvatarURL = &avatar
 	}
 
+	if tz, ok := updates["timezone"].(string); ok {
+		u.Timezone = tz
+	}
+
+	if hash, ok := updates["password_hash"].(string); ok {
+		u.PasswordHash = hash
+	}
+
+	if v, ok := updates["totp_secret"]; ok {
+		u.TOTPSecret = nil
+		if secret, ok := v.(string); ok {
+			u.TOTPSecret = &secret
+		}
+	}
+	if v, ok := updates["totp_enabled_at"]; ok {
+		u.TOTPEnabledAt = nil
+		if at, ok := v.(time.Time); ok {
+			u.TOTPEnabledAt = &at
+		}
+	}
+	switch step := updates["totp_last_step"].(type) {
+	case int64:
+		u.TOTPLastStep = step
+	case int:
+		u.TOTPLastStep = int64(step)
+	}
+
 	return nil
 }
 
+func (f *fakeUserRepo) UseTOTPStep(ctx context.Context, id string, step int64, updates map[string]interface{}) (bool, error) {
+	u, ok := f.byID[id]
+	if !ok || u == nil || u.TOTPLastStep >= step {
+		return false, nil
+	}
+
+	values := map[string]interface{}{"totp_last_step": step}
+	for k, v := range updates {
+		values[k] = v
+	}
+	return true, f.Update(ctx, id, values)
+}
+
+func (f *fakeUserRepo) FindByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	var res []models.User
+	for _, id := range ids {
+		if u, ok := f.byID[id]; ok && u != nil {
+			res = append(res, *u)
+		}
+	}
+	return res, nil
+}
+
 func (f *fakeUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 	res := make([]models.User, 0, len(f.byID))
 	for _, u := range f.byID {
@@ -101,6 +159,8 @@ func (f *fakeUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 
 type fakeTokenRepo struct {
 	byHash map[string]*models.RefreshToken
+	// revoked keeps rotated and signed out tokens for reuse detection
+	revoked map[string]*models.RefreshToken
 
 	saveErr          error
 	findErr          error
@@ -113,7 +173,8 @@ type fakeTokenRepo struct {
 
 func newFakeTokenRepo() *fakeTokenRepo {
 	return &fakeTokenRepo{
-		byHash: make(map[string]*models.RefreshToken),
+		byHash:  make(map[string]*models.RefreshToken),
+		revoked: make(map[string]*models.RefreshToken),
 	}
 }
 
@@ -158,6 +219,87 @@ func (f *fakeTokenRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID)
 	return nil
 }
 
+func (f *fakeTokenRepo) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error) {
+	var out []models.RefreshToken
+	for _, t := range f.byHash {
+		if t.UserID == userID && !t.Revoked && t.ExpiresAt.After(time.Now()) {
+			out = append(out, *t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTokenRepo) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error) {
+	revoked := false
+	for h, t := range f.byHash {
+		if t.UserID == userID && t.SessionID == sessionID {
+			t.Revoked = true
+			f.revoked[h] = t
+			delete(f.byHash, h)
+			revoked = true
+		}
+	}
+	return revoked, nil
+}
+
+func (f *fakeTokenRepo) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	if t, ok := f.byHash[hash]; ok {
+		return t, nil
+	}
+	return f.revoked[hash], nil
+}
+
+func (f *fakeTokenRepo) Rotate(ctx context.Context, old, next *models.RefreshToken) (bool, error) {
+	if _, ok := f.byHash[old.TokenHash]; !ok {
+		return false, nil
+	}
+	if old.ID == uuid.Nil {
+		old.ID = uuid.New()
+	}
+	old.Revoked = true
+	f.revoked[old.TokenHash] = old
+	delete(f.byHash, old.TokenHash)
+
+	next.ID = uuid.New()
+	next.ParentID = &old.ID
+	f.byHash[next.TokenHash] = next
+	return true, nil
+}
+
+func (f *fakeTokenRepo) HasSuccessor(ctx context.Context, id uuid.UUID) (bool, error) {
+	for _, t := range f.byHash {
+		if t.ParentID != nil && *t.ParentID == id {
+			return true, nil
+		}
+	}
+	for _, t := range f.revoked {
+		if t.ParentID != nil && *t.ParentID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeSecurityEventRepo struct {
+	events []models.SecurityEvent
+}
+
+func (f *fakeSecurityEventRepo) Create(ctx context.Context, event *models.SecurityEvent) error {
+	event.ID = uuid.New()
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func (f *fakeSecurityEventRepo) FindByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.SecurityEvent, error) {
+	var out []models.SecurityEvent
+	for i := len(f.events) - 1; i >= 0 && len(out) < limit; i-- {
+		if f.events[i].UserID == userID {
+			out = append(out, f.events[i])
+		}
+	}
+	return out, nil
+}
+
 func (f *fakeTokenRepo) DeleteExpired(ctx context.Context) error {
 	if f.deleteExpiredErr != nil {
 		return f.deleteExpiredErr
@@ -165,22 +307,70 @@ func (f *fakeTokenRepo) DeleteExpired(ctx context.Context) error {
 	return nil
 }
 
-type fakeVerificationRepo struct{}
+type fakeVerificationRepo struct {
+	users   *fakeUserRepo
+	records []*models.EmailVerification
+}
 
 func (f *fakeVerificationRepo) Create(ctx context.Context, v *models.EmailVerification) error {
+	f.records = append(f.records, v)
 	return nil
 }
-func (f *fakeVerificationRepo) FindValid(ctx context.Context, email, code string) (*models.EmailVerification, error) {
-	return nil, nil
+func (f *fakeVerificationRepo) FindValid(ctx context.Context, email, code string, purpose models.VerificationPurpose) (*models.EmailVerification, error) {
+	user := f.users.byEmail[email]
+	for _, v := range f.records {
+		if user != nil && v.UserID == user.ID && v.Code == code && v.Purpose == purpose && !v.Used && v.ExpiresAt.After(time.Now()) {
+			return v, nil
+		}
+	}
+	return nil, errors.New("record not found")
 }
 func (f *fakeVerificationRepo) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	for _, v := range f.records {
+		if v.ID == id {
+			v.Used = true
+		}
+	}
+	return nil
+}
+func (f *fakeVerificationRepo) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose models.VerificationPurpose) error {
+	for _, v := range f.records {
+		if v.UserID == userID && v.Purpose == purpose {
+			v.Used = true
+		}
+	}
 	return nil
 }
 
-func TestAuthService_generateHash(t *testing.T) {
-	svc := &AuthService{
-		secret: "test-secret",
+type fakeRecoveryCodeRepo struct {
+	unused map[uuid.UUID]map[string]bool
+}
+
+func newFakeRecoveryCodeRepo() *fakeRecoveryCodeRepo {
+	return &fakeRecoveryCodeRepo{unused: make(map[uuid.UUID]map[string]bool)}
+}
+
+func (f *fakeRecoveryCodeRepo) Replace(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	f.unused[userID] = make(map[string]bool)
+	for _, h := range hashes {
+		f.unused[userID][h] = true
 	}
+	return nil
+}
+func (f *fakeRecoveryCodeRepo) Use(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	if !f.unused[userID][hash] {
+		return false, nil
+	}
+	delete(f.unused[userID], hash)
+	return true, nil
+}
+func (f *fakeRecoveryCodeRepo) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	delete(f.unused, userID)
+	return nil
+}
+
+func TestAuthService_generateHash(t *testing.T) {
+	svc := &AuthService{}
 
 	h1 := svc.generateHash("value")
 	h2 := svc.generateHash("value")
@@ -194,35 +384,147 @@ func TestAuthService_generateHash(t *testing.T) {
 	assert.NotEqual(t, h1, h3)
 }
 
+func newTestKeySet(t *testing.T) *KeySet {
+	t.Helper()
+	key, err := GenerateSigningKey("test")
+	require.NoError(t, err)
+	keys, err := NewKeySet("test-issuer", "test-audience", []*SigningKey{key}, "")
+	require.NoError(t, err)
+	return keys
+}
+
 func TestAuthService_createJWT(t *testing.T) {
-	secret := "super-secret"
+	keys := newTestKeySet(t)
 
 	svc := &AuthService{
-		secret: secret,
+		keys: keys,
 	}
 
 	userID := uuid.New()
 	role := models.UserRole("STUDENT")
 
-	tokenStr, err := svc.createJWT(userID, role)
+	sessionID := uuid.New()
+
+	tokenStr, err := svc.createJWT(userID, role, sessionID, true)
 	require.NoError(t, err)
 	require.NotEmpty(t, tokenStr)
 
-	parsed, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+	claims, err := keys.Parse(tokenStr)
 	require.NoError(t, err)
-	require.True(t, parsed.Valid)
-
-	claims, ok := parsed.Claims.(jwt.MapClaims)
-	require.True(t, ok)
 
 	assert.Equal(t, userID.String(), claims["userId"])
 	assert.Equal(t, string(role), claims["role"])
+	assert.Equal(t, sessionID.String(), claims["sid"])
+	assert.Equal(t, true, claims["mfa"])
 
 	exp, ok := claims["exp"].(float64)
 	require.True(t, ok)
 	assert.Greater(t, int64(exp), time.Now().Unix())
+	assert.Equal(t, "test-issuer", claims["iss"])
+	assert.Equal(t, "test-audience", claims["aud"])
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(crand.Reader, 2048)
+	require.NoError(t, err)
+	oldKey, err := ParseSigningKey("2026-01", pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodRS256, oldKey.Method)
+
+	newKey, err := GenerateSigningKey("2026-02")
+	require.NoError(t, err)
+
+	before, err := NewKeySet("iss", "aud", []*SigningKey{oldKey}, "")
+	require.NoError(t, err)
+	oldToken, err := before.Sign(jwt.MapClaims{"userId": "u1", "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	// новый ключ подписывает, старый ещё принимается
+	during, err := NewKeySet("iss", "aud", []*SigningKey{oldKey, newKey}, "2026-02")
+	require.NoError(t, err)
+	newToken, err := during.Sign(jwt.MapClaims{"userId": "u1", "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+	_, err = during.Parse(oldToken)
+	assert.NoError(t, err)
+	_, err = during.Parse(newToken)
+	assert.NoError(t, err)
+
+	jwks := during.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+
+	// после удаления старого ключа его токены больше не принимаются
+	after, err := NewKeySet("iss", "aud", []*SigningKey{newKey}, "")
+	require.NoError(t, err)
+	_, err = after.Parse(oldToken)
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+
+	_, err = NewKeySet("iss", "aud", []*SigningKey{newKey}, "missing")
+	assert.Error(t, err)
+}
+
+func TestLoadKeySetFromEnv_DefaultIssuer(t *testing.T) {
+	t.Setenv("JWT_SIGNING_KEYS", "")
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("PUBLIC_URL", "")
+	t.Setenv("JWT_AUDIENCE", "")
+
+	keys, err := LoadKeySetFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, defaultJWTIssuer, keys.issuer)
+	assert.Equal(t, defaultJWTAudience, keys.audience)
+
+	// без издателя проверка iss при разборе токена бы отключилась
+	_, err = NewKeySet("", "aud", []*SigningKey{keys.active}, "")
+	assert.Error(t, err)
+}
+
+func TestKeySet_Parse_Rejects(t *testing.T) {
+	keys := newTestKeySet(t)
+	exp := time.Now().Add(time.Minute).Unix()
+
+	otherAudience, err := NewKeySet("test-issuer", "other-api", []*SigningKey{keys.active}, "")
+	require.NoError(t, err)
+	wrongAud, err := otherAudience.Sign(jwt.MapClaims{"exp": exp})
+	require.NoError(t, err)
+
+	otherKey, err := GenerateSigningKey("test")
+	require.NoError(t, err)
+	otherKeys, err := NewKeySet("test-issuer", "test-audience", []*SigningKey{otherKey}, "")
+	require.NoError(t, err)
+	wrongSignature, err := otherKeys.Sign(jwt.MapClaims{"exp": exp})
+	require.NoError(t, err)
+
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "test-issuer", "aud": "test-audience", "exp": exp})
+	hs.Header["kid"] = "test"
+	hmac, err := hs.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	noExp, err := keys.Sign(jwt.MapClaims{"userId": "u1"})
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"wrong audience":  wrongAud,
+		"wrong signature": wrongSignature,
+		"hmac":            hmac,
+		"no expiry":       noExp,
+		"garbage":         "not-a-token",
+	} {
+		_, err := keys.Parse(token)
+		assert.ErrorIs(t, err, ErrInvalidAccessToken, name)
+	}
 }
 
 func TestAuthService_Login(t *testing.T) {
@@ -327,28 +629,33 @@ func TestAuthService_Login(t *testing.T) {
 				users:         userRepo,
 				verifications: nil,
 				tokens:        tokenRepo,
-				secret:        "test-secret",
+				keys:          newTestKeySet(t),
 			}
 
-			access, refresh, err := svc.Login(ctx, tt.inputEmail, tt.inputPassword)
+			result, err := svc.Login(ctx, tt.inputEmail, tt.inputPassword, ClientInfo{DeviceName: "Laptop", IPAddress: "10.0.0.1", UserAgent: "Firefox"})
 
 			if tt.wantErr {
 				require.Error(t, err)
 				if tt.errContains != "" {
 					assert.Contains(t, err.Error(), tt.errContains)
 				}
-				assert.Empty(t, access)
-				assert.Empty(t, refresh)
+				assert.Nil(t, result)
 				return
 			}
 
 			require.NoError(t, err)
-			assert.NotEmpty(t, access)
-			assert.NotEmpty(t, refresh)
+			assert.NotEmpty(t, result.AccessToken)
+			assert.NotEmpty(t, result.RefreshToken)
+			assert.Empty(t, result.ChallengeToken)
 
 			require.Len(t, tokenRepo.byHash, 1)
-			require.Len(t, tokenRepo.revokeAllFor, 1)
-			assert.Equal(t, activeUserID, tokenRepo.revokeAllFor[0])
+			assert.Empty(t, tokenRepo.revokeAllFor, "login keeps other sessions signed in")
+			for _, tk := range tokenRepo.byHash {
+				assert.NotEqual(t, uuid.Nil, tk.SessionID)
+				assert.Equal(t, "Laptop", tk.DeviceName)
+				assert.Equal(t, "10.0.0.1", tk.IPAddress)
+				assert.Equal(t, "Firefox", tk.UserAgent)
+			}
 		})
 	}
 }
@@ -364,10 +671,10 @@ func TestAuthService_Refresh_InvalidToken(t *testing.T) {
 		users:         userRepo,
 		verifications: nil,
 		tokens:        tokenRepo,
-		secret:        "test-secret",
+		keys:          newTestKeySet(t),
 	}
 
-	_, _, err := svc.Refresh(ctx, "non-existent")
+	_, _, err := svc.Refresh(ctx, "non-existent", ClientInfo{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid refresh token")
 }
@@ -378,13 +685,11 @@ func TestAuthService_Refresh_Success(t *testing.T) {
 	userRepo := newFakeUserRepo()
 	tokenRepo := newFakeTokenRepo()
 
-	secret := "test-secret"
-
 	svc := &AuthService{
 		users:         userRepo,
 		verifications: nil,
 		tokens:        tokenRepo,
-		secret:        secret,
+		keys:          newTestKeySet(t),
 	}
 
 	userID := uuid.New()
@@ -398,13 +703,17 @@ func TestAuthService_Refresh_Success(t *testing.T) {
 
 	oldRaw := "old-refresh"
 	oldHash := svc.generateHash(oldRaw)
+	sessionID := uuid.New()
 	tokenRepo.byHash[oldHash] = &models.RefreshToken{
-		UserID:    userID,
-		TokenHash: oldHash,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
+		UserID:     userID,
+		SessionID:  sessionID,
+		TokenHash:  oldHash,
+		DeviceName: "Phone",
+		IPAddress:  "10.0.0.1",
+		ExpiresAt:  time.Now().Add(24 * time.Hour),
 	}
 
-	newAccess, newRefresh, err := svc.Refresh(ctx, oldRaw)
+	newAccess, newRefresh, err := svc.Refresh(ctx, oldRaw, ClientInfo{UserAgent: "Safari"})
 	require.NoError(t, err)
 
 	assert.NotEmpty(t, newAccess)
@@ -414,4 +723,488 @@ func TestAuthService_Refresh_Success(t *testing.T) {
 	assert.False(t, ok)
 
 	require.Equal(t, 1, len(tokenRepo.byHash))
+
+	// новый токен продолжает ту же сессию
+	rotated := tokenRepo.byHash[svc.generateHash(newRefresh)]
+	require.NotNil(t, rotated)
+	assert.Equal(t, sessionID, rotated.SessionID)
+	assert.Equal(t, "Phone", rotated.DeviceName)
+	assert.Equal(t, "10.0.0.1", rotated.IPAddress)
+	assert.Equal(t, "Safari", rotated.UserAgent)
+}
+
+// signIn входит без второго фактора и возвращает refresh-токен новой сессии
+func signIn(t *testing.T, svc *AuthService, email string, client ClientInfo) string {
+	t.Helper()
+
+	result, err := svc.Login(context.Background(), email, "old-password", client)
+	require.NoError(t, err)
+	require.Empty(t, result.ChallengeToken)
+	return result.RefreshToken
+}
+
+func newPasswordResetFixture(t *testing.T) (*AuthService, *fakeVerificationRepo, *fakeTokenRepo, *fakeEmailSender, *models.User) {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := &models.User{Email: "user@example.com", PasswordHash: string(hash), Status: "ACTIVE"}
+	require.NoError(t, users.Create(context.Background(), user))
+
+	verifications := &fakeVerificationRepo{users: users}
+	tokens := newFakeTokenRepo()
+	email := &fakeEmailSender{}
+
+	return NewAuthService(users, verifications, tokens, &fakeSecurityEventRepo{}, newFakeRecoveryCodeRepo(), nil, email, newTestKeySet(t)), verifications, tokens, email, user
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, verifications, _, email, user := newPasswordResetFixture(t)
+
+	require.NoError(t, svc.ForgotPassword(ctx, "unknown@example.com"))
+	assert.Empty(t, email.sent, "unknown emails get no code and no error")
+
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+
+	require.Len(t, email.sent, 2)
+	assert.Equal(t, "Reset your password", email.sent[1].Subject)
+	assert.Len(t, email.sent[1].Code, 6)
+
+	require.Len(t, verifications.records, 2)
+	assert.Equal(t, models.VerificationPurposePasswordReset, verifications.records[1].Purpose)
+	assert.True(t, verifications.records[0].Used, "a new code replaces the previous one")
+	assert.False(t, verifications.records[1].Used)
+}
+
+func TestAuthService_Register_ResendReplacesCode(t *testing.T) {
+	ctx := context.Background()
+	svc, verifications, _, email, _ := newPasswordResetFixture(t)
+
+	require.NoError(t, svc.Register(ctx, "new@example.com", "password", "New"))
+	require.NoError(t, svc.Register(ctx, "new@example.com", "password", "New"))
+	require.Len(t, email.sent, 2)
+
+	require.Len(t, verifications.records, 2)
+	assert.True(t, verifications.records[0].Used, "a new code replaces the previous one")
+	assert.False(t, verifications.records[1].Used)
+
+	assert.Error(t, svc.VerifyEmail(ctx, "new@example.com", email.sent[0].Code))
+	require.NoError(t, svc.VerifyEmail(ctx, "new@example.com", email.sent[1].Code))
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, verifications, tokens, email, user := newPasswordResetFixture(t)
+
+	_, err := svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	require.NoError(t, err)
+	require.Len(t, tokens.byHash, 1)
+
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+	code := email.sent[0].Code
+
+	err = svc.ResetPassword(ctx, user.Email, "000000", "new-password")
+	assert.EqualError(t, err, "invalid or expired code")
+
+	// код подтверждения почты не подходит для сброса пароля
+	verifications.records = append(verifications.records, &models.EmailVerification{
+		ID: uuid.New(), UserID: user.ID, Code: "111111", Purpose: models.VerificationPurposeEmail, ExpiresAt: time.Now().Add(time.Minute),
+	})
+	assert.Error(t, svc.ResetPassword(ctx, user.Email, "111111", "new-password"))
+
+	require.NoError(t, svc.ResetPassword(ctx, user.Email, code, "new-password"))
+	assert.Empty(t, tokens.byHash, "all sessions are signed out")
+	assert.Contains(t, tokens.revokeAllFor, user.ID)
+
+	_, err = svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	assert.Error(t, err)
+	_, err = svc.Login(ctx, user.Email, "new-password", ClientInfo{})
+	assert.NoError(t, err)
+
+	assert.Error(t, svc.ResetPassword(ctx, user.Email, code, "another-password"), "codes are single use")
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	ctx := context.Background()
+	svc, _, tokens, _, user := newPasswordResetFixture(t)
+	userID := user.ID.String()
+
+	laptop := signIn(t, svc, user.Email, ClientInfo{DeviceName: "Laptop"})
+	phone := signIn(t, svc, user.Email, ClientInfo{DeviceName: "Phone"})
+
+	sessions, err := svc.GetSessions(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2, "signing in on the phone keeps the laptop signed in")
+
+	// ротация не создаёт новую сессию
+	_, laptop, err = svc.Refresh(ctx, laptop, ClientInfo{})
+	require.NoError(t, err)
+	sessions, err = svc.GetSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	phoneSession := tokens.byHash[svc.generateHash(phone)].SessionID
+	assert.ErrorIs(t, svc.RevokeSession(ctx, uuid.NewString(), phoneSession.String()), ErrSessionNotFound, "only the owner can revoke a session")
+	assert.ErrorIs(t, svc.RevokeSession(ctx, userID, "not-a-uuid"), ErrSessionNotFound)
+
+	require.NoError(t, svc.RevokeSession(ctx, userID, phoneSession.String()))
+	_, _, err = svc.Refresh(ctx, phone, ClientInfo{})
+	assert.Error(t, err)
+	assert.ErrorIs(t, svc.RevokeSession(ctx, userID, phoneSession.String()), ErrSessionNotFound)
+
+	require.NoError(t, svc.Logout(ctx, laptop))
+	assert.Error(t, svc.Logout(ctx, laptop))
+	sessions, err = svc.GetSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	require.NoError(t, err)
+	_, err = svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	require.NoError(t, err)
+	require.NoError(t, svc.LogoutAll(ctx, userID))
+	assert.Empty(t, tokens.byHash)
+}
+
+func TestAuthService_Refresh_ReuseDetection(t *testing.T) {
+	ctx := context.Background()
+	svc, _, tokens, email, user := newPasswordResetFixture(t)
+	events := svc.events.(*fakeSecurityEventRepo)
+
+	stolen := signIn(t, svc, user.Email, ClientInfo{DeviceName: "Laptop"})
+	other := signIn(t, svc, user.Email, ClientInfo{DeviceName: "Phone"})
+
+	// владелец обновил токен, затем украденная копия старого токена предъявлена снова
+	_, current, err := svc.Refresh(ctx, stolen, ClientInfo{})
+	require.NoError(t, err)
+	rotated := tokens.byHash[svc.generateHash(current)]
+	require.NotNil(t, rotated.ParentID, "rotated tokens link to their predecessor")
+	assert.Equal(t, tokens.revoked[svc.generateHash(stolen)].ID, *rotated.ParentID)
+
+	_, _, err = svc.Refresh(ctx, stolen, ClientInfo{IPAddress: "203.0.113.7", UserAgent: "curl"})
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	_, _, err = svc.Refresh(ctx, current, ClientInfo{})
+	assert.Error(t, err, "the whole family is revoked")
+
+	_, _, err = svc.Refresh(ctx, other, ClientInfo{})
+	assert.NoError(t, err, "other sessions are not affected")
+
+	require.Len(t, events.events, 1)
+	assert.Equal(t, models.SecurityEventRefreshTokenReuse, events.events[0].Kind)
+	assert.Equal(t, user.ID, events.events[0].UserID)
+	assert.Equal(t, "203.0.113.7", events.events[0].IPAddress)
+	assert.Equal(t, rotated.SessionID, *events.events[0].SessionID)
+
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, user.Email, email.sent[0].Email)
+	assert.Contains(t, email.sent[0].Body, "Laptop")
+	assert.Contains(t, email.sent[0].Body, "203.0.113.7")
+
+	listed, err := svc.GetSecurityEvents(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
+
+func TestAuthService_Refresh_AfterLogoutIsNotReuse(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, email, user := newPasswordResetFixture(t)
+	events := svc.events.(*fakeSecurityEventRepo)
+
+	refresh := signIn(t, svc, user.Email, ClientInfo{})
+	require.NoError(t, svc.Logout(ctx, refresh))
+
+	_, _, err := svc.Refresh(ctx, refresh, ClientInfo{})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Empty(t, events.events)
+	assert.Empty(t, email.sent)
+}
+
+// withLimiter включает ограничение попыток на miniredis, чтобы тесты могли
+// проматывать время блокировок.
+func withLimiter(t *testing.T, svc *AuthService) *miniredis.Miniredis {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	svc.limiter = NewAuthLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	return mr
+}
+
+func TestAuthService_Login_Lockout(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, user := newPasswordResetFixture(t)
+	mr := withLimiter(t, svc)
+	client := ClientInfo{IPAddress: "203.0.113.7"}
+
+	for i := 0; i < loginEmailMaxFailures; i++ {
+		_, err := svc.Login(ctx, user.Email, "wrong", client)
+		require.EqualError(t, err, "invalid credentials")
+	}
+
+	// даже верный пароль не принимается во время блокировки
+	_, err := svc.Login(ctx, user.Email, "old-password", client)
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitLoginLocked, limited.Code)
+	assert.Equal(t, loginBaseLockout, limited.RetryAfter)
+
+	// другие адреса с того же IP не заблокированы
+	_, err = svc.Login(ctx, "other@example.com", "wrong", client)
+	assert.EqualError(t, err, "invalid credentials")
+
+	mr.FastForward(loginBaseLockout)
+	for i := 0; i < loginEmailMaxFailures; i++ {
+		_, err = svc.Login(ctx, user.Email, "wrong", client)
+		require.EqualError(t, err, "invalid credentials")
+	}
+	_, err = svc.Login(ctx, user.Email, "old-password", client)
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, 2*loginBaseLockout, limited.RetryAfter, "each lockout doubles the next one")
+
+	mr.FastForward(2 * loginBaseLockout)
+	_, err = svc.Login(ctx, user.Email, "old-password", client)
+	require.NoError(t, err)
+
+	// успешный вход сбрасывает счётчик неудач по почте
+	for i := 0; i < loginEmailMaxFailures-1; i++ {
+		_, err = svc.Login(ctx, user.Email, "wrong", client)
+		require.EqualError(t, err, "invalid credentials")
+	}
+	_, err = svc.Login(ctx, user.Email, "old-password", client)
+	assert.NoError(t, err)
+}
+
+func TestAuthService_Login_LockoutByIP(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, user := newPasswordResetFixture(t)
+	withLimiter(t, svc)
+
+	for i := 0; i < loginIPMaxFailures; i++ {
+		_, err := svc.Login(ctx, uuid.NewString()+"@example.com", "wrong", ClientInfo{IPAddress: "203.0.113.7"})
+		require.EqualError(t, err, "invalid credentials")
+	}
+
+	_, err := svc.Login(ctx, user.Email, "old-password", ClientInfo{IPAddress: "203.0.113.7"})
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+
+	_, err = svc.Login(ctx, user.Email, "old-password", ClientInfo{IPAddress: "198.51.100.1"})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_ResetPassword_AttemptsExhausted(t *testing.T) {
+	ctx := context.Background()
+	svc, verifications, _, email, user := newPasswordResetFixture(t)
+	withLimiter(t, svc)
+
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+	code := email.sent[0].Code
+
+	for i := 0; i < MaxVerificationAttempts-1; i++ {
+		err := svc.ResetPassword(ctx, user.Email, "wrong!", "new-password")
+		require.EqualError(t, err, "invalid or expired code")
+	}
+
+	err := svc.ResetPassword(ctx, user.Email, "wrong!", "new-password")
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitCodeAttemptsExceeded, limited.Code)
+	assert.True(t, verifications.records[0].Used, "the code is invalidated")
+
+	assert.Error(t, svc.ResetPassword(ctx, user.Email, code, "new-password"))
+}
+
+func TestAuthService_CodeResendThrottle(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, email, user := newPasswordResetFixture(t)
+	mr := withLimiter(t, svc)
+
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+
+	err := svc.ForgotPassword(ctx, user.Email)
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitCodeResendThrottled, limited.Code)
+	assert.Equal(t, CodeResendInterval, limited.RetryAfter)
+
+	// неизвестные адреса ограничиваются так же, чтобы не выдавать наличие аккаунта
+	require.NoError(t, svc.ForgotPassword(ctx, "unknown@example.com"))
+	assert.ErrorAs(t, svc.ForgotPassword(ctx, "unknown@example.com"), &limited)
+
+	require.NoError(t, svc.Register(ctx, "new@example.com", "password", "New"))
+	assert.ErrorAs(t, svc.Register(ctx, "new@example.com", "password", "New"), &limited)
+
+	mr.FastForward(CodeResendInterval)
+	require.NoError(t, svc.ForgotPassword(ctx, user.Email))
+	require.NoError(t, svc.Register(ctx, "new@example.com", "password", "New"))
+	assert.Len(t, email.sent, 4)
+}
+
+func TestTOTP_RFC6238Vector(t *testing.T) {
+	// тестовый вектор из RFC 6238 для SHA-1, последние 6 цифр
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	code, err := totpCode(secret, totpStep(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	code, err = totpCode(secret, totpStep(time.Unix(1111111109, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "081804", code)
+}
+
+func TestAuthService_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	svc, _, tokens, _, user := newPasswordResetFixture(t)
+	userID := user.ID.String()
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, userID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/")
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	require.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+
+	// до подтверждения вход работает без второго фактора
+	signIn(t, svc, user.Email, ClientInfo{})
+
+	assert.ErrorIs(t, svc.ConfirmTwoFactor(ctx, userID, "000000"), ErrInvalidTwoFactorCode)
+
+	now := totpStep(time.Now())
+	code, err := totpCode(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, svc.ConfirmTwoFactor(ctx, userID, code))
+
+	_, err = svc.EnrollTwoFactor(ctx, userID)
+	assert.ErrorIs(t, err, ErrTwoFactorAlreadyEnabled)
+
+	sessionsBefore := len(tokens.byHash)
+	result, err := svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	require.NoError(t, err)
+	assert.Empty(t, result.AccessToken)
+	assert.Empty(t, result.RefreshToken)
+	require.NotEmpty(t, result.ChallengeToken)
+	assert.Len(t, tokens.byHash, sessionsBefore, "no session before the second factor")
+
+	_, err = svc.LoginTwoFactor(ctx, "garbage", code, ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorChallenge)
+
+	_, err = svc.LoginTwoFactor(ctx, result.ChallengeToken, code, ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode, "a code is accepted only once")
+
+	next, err := totpCode(enrollment.Secret, now+1)
+	require.NoError(t, err)
+	session, err := svc.LoginTwoFactor(ctx, result.ChallengeToken, next, ClientInfo{DeviceName: "Laptop"})
+	require.NoError(t, err)
+	require.NotEmpty(t, session.AccessToken)
+
+	stored := tokens.byHash[svc.generateHash(session.RefreshToken)]
+	require.NotNil(t, stored)
+	assert.True(t, stored.TwoFactor)
+	assert.Equal(t, "Laptop", stored.DeviceName)
+
+	// второй фактор сохраняется при ротации
+	_, rotated, err := svc.Refresh(ctx, session.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+	assert.True(t, tokens.byHash[svc.generateHash(rotated)].TwoFactor)
+
+	// код восстановления одноразовый и не зависит от регистра
+	recovery := strings.ToLower(enrollment.RecoveryCodes[0])
+	_, err = svc.LoginTwoFactor(ctx, result.ChallengeToken, recovery, ClientInfo{})
+	require.NoError(t, err)
+	_, err = svc.LoginTwoFactor(ctx, result.ChallengeToken, recovery, ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	user.Role = models.UserRoleAdmin
+	assert.ErrorIs(t, svc.DisableTwoFactor(ctx, userID, enrollment.RecoveryCodes[1]), ErrTwoFactorRequired)
+
+	user.Role = models.UserRoleStudent
+	assert.ErrorIs(t, svc.DisableTwoFactor(ctx, userID, "wrong"), ErrInvalidTwoFactorCode)
+	require.NoError(t, svc.DisableTwoFactor(ctx, userID, enrollment.RecoveryCodes[1]))
+	signIn(t, svc, user.Email, ClientInfo{})
+}
+
+func TestAuthService_LoginTwoFactor_Lockout(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, user := newPasswordResetFixture(t)
+	withLimiter(t, svc)
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, user.ID.String())
+	require.NoError(t, err)
+	code, err := totpCode(enrollment.Secret, totpStep(time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, svc.ConfirmTwoFactor(ctx, user.ID.String(), code))
+
+	// верный пароль не сбрасывает счётчик неверных кодов
+	for i := 0; i < loginEmailMaxFailures; i++ {
+		result, err := svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+		require.NoError(t, err)
+		_, err = svc.LoginTwoFactor(ctx, result.ChallengeToken, "WRONG-CODE", ClientInfo{})
+		require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	}
+
+	_, err = svc.Login(ctx, user.Email, "old-password", ClientInfo{})
+	var limited *RateLimitError
+	assert.ErrorAs(t, err, &limited)
+}
+
+func TestAuthService_TwoFactorSettings_Lockout(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, user := newPasswordResetFixture(t)
+	mr := withLimiter(t, svc)
+	userID := user.ID.String()
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, userID)
+	require.NoError(t, err)
+
+	// подбор кода при включении упирается в лимит попыток
+	for i := 1; i < MaxVerificationAttempts; i++ {
+		require.ErrorIs(t, svc.ConfirmTwoFactor(ctx, userID, "000000"), ErrInvalidTwoFactorCode)
+	}
+	var limited *RateLimitError
+	require.ErrorAs(t, svc.ConfirmTwoFactor(ctx, userID, "000000"), &limited)
+	assert.Equal(t, RateLimitCodeAttemptsExceeded, limited.Code)
+
+	// пока блокировка действует, не проходит даже верный код
+	code, err := totpCode(enrollment.Secret, totpStep(time.Now()))
+	require.NoError(t, err)
+	assert.ErrorAs(t, svc.ConfirmTwoFactor(ctx, userID, code), &limited)
+
+	mr.FastForward(verificationWindow)
+	require.NoError(t, svc.ConfirmTwoFactor(ctx, userID, code))
+
+	// то же при выключении
+	for i := 1; i < MaxVerificationAttempts; i++ {
+		require.ErrorIs(t, svc.DisableTwoFactor(ctx, userID, "WRONG-CODE"), ErrInvalidTwoFactorCode)
+	}
+	require.ErrorAs(t, svc.DisableTwoFactor(ctx, userID, "WRONG-CODE"), &limited)
+	assert.ErrorAs(t, svc.DisableTwoFactor(ctx, userID, enrollment.RecoveryCodes[0]), &limited)
+	assert.True(t, user.TwoFactorEnabled())
+}
+
+func TestAuthService_CheckSecondFactor_ConcurrentReplay(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, user := newPasswordResetFixture(t)
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, user.ID.String())
+	require.NoError(t, err)
+	now := totpStep(time.Now())
+	code, err := totpCode(enrollment.Secret, now-1)
+	require.NoError(t, err)
+	require.NoError(t, svc.ConfirmTwoFactor(ctx, user.ID.String(), code))
+
+	// оба запроса прочитали пользователя до того, как кто-то из них принял код
+	first, second := *user, *user
+	code, err = totpCode(enrollment.Secret, now)
+	require.NoError(t, err)
+
+	ok, err := svc.checkSecondFactor(ctx, &first, code)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = svc.checkSecondFactor(ctx, &second, code)
+	require.NoError(t, err)
+	assert.False(t, ok, "the same code must not pass twice")
 }
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"learning-platform/internal/models"
+)
+
+const (
+	// twoFactorChallengeTTL is how long the user has to enter the code after
+	// the password was accepted.
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorChallengeType = "2fa_challenge"
+
+	recoveryCodeCount     = 10
+	recoveryCodeGroupSize = 5
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two-factor enrollment has not been started")
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired         = errors.New("two-factor authentication cannot be turned off for admins")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+)
+
+// LoginResult carries either the tokens of the new session or, when the user
+// has two-factor authentication enabled, a challenge token that LoginTwoFactor
+// exchanges for them.
+type LoginResult struct {
+	AccessToken    string
+	RefreshToken   string
+	ChallengeToken string
+}
+
+// TwoFactorEnrollment is shown to the user once. The recovery codes are only
+// stored hashed.
+type TwoFactorEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+func generateRecoveryCode() (string, error) {
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	code := make([]byte, 2*recoveryCodeGroupSize)
+	for i := range code {
+		n, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = joinCodeAlphabet[n.Int64()]
+	}
+	return string(code[:recoveryCodeGroupSize]) + "-" + string(code[recoveryCodeGroupSize:]), nil
+}
+
+// normalizeRecoveryCode makes recovery codes case and dash insensitive.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// createChallenge signs the challenge with the access token keys. The typ
+// claim keeps AuthMiddleware from accepting it as an access token.
+func (s *AuthService) createChallenge(userID uuid.UUID) (string, error) {
+	return s.keys.Sign(jwt.MapClaims{
+		"userId": userID.String(),
+		"typ":    twoFactorChallengeType,
+		"exp":    time.Now().Add(twoFactorChallengeTTL).Unix(),
+	})
+}
+
+func (s *AuthService) parseChallenge(challenge string) (string, error) {
+	claims, err := s.keys.Parse(challenge)
+	if err != nil || claims["typ"] != twoFactorChallengeType {
+		return "", ErrInvalidTwoFactorChallenge
+	}
+
+	userID, ok := claims["userId"].(string)
+	if !ok {
+		return "", ErrInvalidTwoFactorChallenge
+	}
+	return userID, nil
+}
+
+// checkSecondFactor accepts a current TOTP code or an unused recovery code.
+// Accepted TOTP steps are remembered so a code cannot be used twice.
+func (s *AuthService) checkSecondFactor(ctx context.Context, user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits && user.TOTPSecret != nil {
+		step := matchTOTP(*user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		if step == 0 {
+			return false, nil
+		}
+		// a concurrent request may have used the same code since user was read
+		return s.users.UseTOTPStep(ctx, user.ID.String(), step, nil)
+	}
+
+	return s.recoveryCodes.Use(ctx, user.ID, s.generateHash(normalizeRecoveryCode(code)))
+}
+
+// twoFactorCodeRejected counts a wrong code of a signed in user and locks
+// turning two-factor authentication on and off once the attempts are used up.
+func (s *AuthService) twoFactorCodeRejected(ctx context.Context, userID string) error {
+	if s.limiter.VerificationFailed(ctx, models.VerificationPurposeTwoFactor, userID) {
+		return s.limiter.LockVerification(ctx, models.VerificationPurposeTwoFactor, userID)
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// EnrollTwoFactor starts enrollment with a new secret and recovery codes.
+// Starting again before confirming replaces both.
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, userID string) (*TwoFactorEnrollment, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.EnrollTwoFactor")
+	defer span.End()
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		err = errors.New("user not found")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled() {
+		span.RecordError(ErrTwoFactorAlreadyEnabled)
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = s.generateHash(normalizeRecoveryCode(code))
+	}
+
+	err = s.users.Update(ctx, userID, map[string]interface{}{
+		"totp_secret":     secret,
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.recoveryCodes.Replace(ctx, user.ID, hashes); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:        secret,
+		URI:           totpURI(secret, user.Email),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// ConfirmTwoFactor turns two-factor authentication on once the user proves
+// the authenticator app produces valid codes. Existing sessions are kept but
+// do not count as two-factor sessions until the user signs in again.
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userID, code string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.ConfirmTwoFactor")
+	defer span.End()
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		err = errors.New("user not found")
+		span.RecordError(err)
+		return err
+	}
+
+	if user.TwoFactorEnabled() {
+		span.RecordError(ErrTwoFactorAlreadyEnabled)
+		return ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		span.RecordError(ErrTwoFactorNotEnrolled)
+		return ErrTwoFactorNotEnrolled
+	}
+
+	if err := s.limiter.CheckVerification(ctx, models.VerificationPurposeTwoFactor, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	step := matchTOTP(*user.TOTPSecret, strings.TrimSpace(code), time.Now(), user.TOTPLastStep)
+	if step == 0 {
+		err := s.twoFactorCodeRejected(ctx, userID)
+		span.RecordError(err)
+		return err
+	}
+
+	used, err := s.users.UseTOTPStep(ctx, userID, step, map[string]interface{}{"totp_enabled_at": time.Now()})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !used {
+		span.RecordError(ErrInvalidTwoFactorCode)
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// DisableTwoFactor turns two-factor authentication off after checking a code.
+// Admins always need it.
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID, code string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.DisableTwoFactor")
+	defer span.End()
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		err = errors.New("user not found")
+		span.RecordError(err)
+		return err
+	}
+
+	if user.Role == models.UserRoleAdmin {
+		span.RecordError(ErrTwoFactorRequired)
+		return ErrTwoFactorRequired
+	}
+	if !user.TwoFactorEnabled() {
+		span.RecordError(ErrTwoFactorNotEnabled)
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := s.limiter.CheckVerification(ctx, models.VerificationPurposeTwoFactor, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !ok {
+		err := s.twoFactorCodeRejected(ctx, userID)
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.users.Update(ctx, userID, map[string]interface{}{
+		"totp_secret":     nil,
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.recoveryCodes.DeleteForUser(ctx, user.ID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// LoginTwoFactor finishes a login that returned a challenge token. Wrong codes
+// count towards the same lockout as wrong passwords.
+func (s *AuthService) LoginTwoFactor(ctx context.Context, challenge, code string, client ClientInfo) (*LoginResult, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "AuthService.LoginTwoFactor")
+	defer span.End()
+
+	userID, err := s.parseChallenge(challenge)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil || user == nil || !user.TwoFactorEnabled() {
+		span.RecordError(ErrInvalidTwoFactorChallenge)
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	if err := s.limiter.CheckLogin(ctx, user.Email, client.IPAddress); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !ok {
+		s.limiter.LoginFailed(ctx, user.Email, client.IPAddress)
+		span.RecordError(ErrInvalidTwoFactorCode)
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	s.limiter.LoginSucceeded(ctx, user.Email)
+
+	return s.startSession(ctx, user, client, true)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"learning-platform/internal/models"
+)
+
+// Certificates are rendered as a single landscape A4 page using the standard
+// Helvetica fonts every PDF reader ships with, so no font files or PDF
+// library are needed. Those fonts only cover WinAnsi (Latin-1), so Cyrillic
+// names and titles are transliterated and other characters become '?'.
+
+const (
+	certificatePageWidth  = 842
+	certificatePageHeight = 595
+)
+
+// helveticaWidths and helveticaBoldWidths are the glyph widths of the
+// printable ASCII range (32..126) in 1/1000 of the font size, from the
+// Adobe font metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	// Kazakh letters, spelled as in Kazakh passports
+	'ә': "a", 'ғ': "g", 'қ': "k", 'ң': "n", 'ө': "o", 'ұ': "u", 'ү': "u",
+	'һ': "h", 'і': "i",
+}
+
+// pdfText converts s to WinAnsi bytes the standard fonts can show.
+func pdfText(s string) []byte {
+	var out []byte
+	for _, r := range s {
+		if latin, ok := cyrillicToLatin[unicode.ToLower(r)]; ok {
+			if unicode.IsUpper(r) && latin != "" {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+			out = append(out, latin...)
+			continue
+		}
+		switch {
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case unicode.IsSpace(r):
+			out = append(out, ' ')
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func pdfTextWidth(text []byte, widths *[95]int, size float64) float64 {
+	total := 0
+	for _, b := range text {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfString escapes text for a PDF literal string.
+func pdfString(text []byte) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range text {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// centeredLine writes one line of text centered on the page. Lines wider
+// than the page are shrunk to fit.
+func centeredLine(content *bytes.Buffer, s string, bold bool, size, y float64) {
+	text := pdfText(s)
+	font, widths := "F1", &helveticaWidths
+	if bold {
+		font, widths = "F2", &helveticaBoldWidths
+	}
+
+	const maxWidth = certificatePageWidth - 140
+	width := pdfTextWidth(text, widths, size)
+	if width > maxWidth {
+		size = size * maxWidth / width
+		width = maxWidth
+	}
+
+	x := (certificatePageWidth - width) / 2
+	fmt.Fprintf(content, "BT /%s %.2f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfString(text))
+}
+
+// renderCertificatePDF draws the certificate: recipient, course or topic,
+// issue date and the verification code with the link to check it.
+func renderCertificatePDF(c *models.Certificate, verifyURL string) []byte {
+	achievement := "has successfully completed the course"
+	if c.Kind == models.CertificateKindTopic {
+		achievement = "has mastered the topic"
+	}
+
+	var content bytes.Buffer
+	content.WriteString("q 0.16 0.29 0.55 RG 4 w 28 28 786 539 re S 1 w 40 40 762 515 re S Q\n")
+	content.WriteString("0.16 0.29 0.55 rg\n")
+	centeredLine(&content, "CERTIFICATE", true, 40, 460)
+	content.WriteString("0.2 0.2 0.2 rg\n")
+	centeredLine(&content, "OF ACHIEVEMENT", false, 18, 430)
+	centeredLine(&content, "This is to certify that", false, 16, 370)
+	content.WriteString("0 0 0 rg\n")
+	centeredLine(&content, c.RecipientName, true, 30, 325)
+	content.WriteString("0.2 0.2 0.2 rg\n")
+	centeredLine(&content, achievement, false, 16, 285)
+	content.WriteString("0 0 0 rg\n")
+	centeredLine(&content, c.Title, true, 24, 245)
+	content.WriteString("0.2 0.2 0.2 rg\n")
+	centeredLine(&content, "Issued on "+c.IssuedAt.UTC().Format("January 2, 2006"), false, 14, 180)
+	centeredLine(&content, "Verification code: "+c.Code, true, 12, 95)
+	centeredLine(&content, "Verify at "+verifyURL, false, 10, 75)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>",
+			certificatePageWidth, certificatePageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes()
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"learning-platform/internal/kafka"
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// TopicCertificateMastery is the topic mastery that earns a certificate.
+	TopicCertificateMastery = 0.8
+	// TopicCertificateMinSolved keeps topics with only a couple of tasks from
+	// handing out certificates.
+	TopicCertificateMinSolved = 3
+
+	certificateCodeGroups    = 4
+	certificateCodeGroupSize = 4
+)
+
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// CertificateService issues PDF certificates for finished courses and
+// mastered topics. It listens to graded submissions and to courses finished
+// by reading items; certificates are issued once per course or topic.
+type CertificateService struct {
+	certificates repository.ICertificateRepository
+	users        repository.IUserRepository
+	courses      *CourseService
+	progress     *ProgressService
+	storage      FileStorage
+	email        EmailSender
+	publicURL    string
+}
+
+func NewCertificateService(certificates repository.ICertificateRepository, users repository.IUserRepository, courses *CourseService, progress *ProgressService, storage FileStorage, email EmailSender, publicURL string) *CertificateService {
+	return &CertificateService{
+		certificates: certificates,
+		users:        users,
+		courses:      courses,
+		progress:     progress,
+		storage:      storage,
+		email:        email,
+		publicURL:    strings.TrimRight(publicURL, "/"),
+	}
+}
+
+func generateCertificateCode() (string, error) {
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	groups := make([]string, certificateCodeGroups)
+	for g := range groups {
+		group := make([]byte, certificateCodeGroupSize)
+		for i := range group {
+			n, err := crand.Int(crand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			group[i] = joinCodeAlphabet[n.Int64()]
+		}
+		groups[g] = string(group)
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// VerifyURL is the public page that confirms a certificate is genuine.
+func (s *CertificateService) VerifyURL(code string) string {
+	return fmt.Sprintf("%s/api/v1/certificates/%s/verify", s.publicURL, code)
+}
+
+func certificateKey(kind models.CertificateKind, subjectID string) string {
+	return string(kind) + ":" + subjectID
+}
+
+func (s *CertificateService) issued(ctx context.Context, userID string) (map[string]bool, error) {
+	certificates, err := s.certificates.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := make(map[string]bool, len(certificates))
+	for _, c := range certificates {
+		issued[certificateKey(c.Kind, c.SubjectID)] = true
+	}
+	return issued, nil
+}
+
+// OnSubmission issues the certificates a correct answer may have earned: for
+// topics whose mastery reached the threshold and for finished courses.
+func (s *CertificateService) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("certificate").Start(ctx, "CertificateService.OnSubmission")
+	defer span.End()
+
+	if !submission.IsCorrect {
+		return nil
+	}
+
+	issued, err := s.issued(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	topics, err := s.progress.GetUserProgress(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for _, t := range topics {
+		if t.Solved < TopicCertificateMinSolved || t.Mastery < TopicCertificateMastery {
+			continue
+		}
+		if issued[certificateKey(models.CertificateKindTopic, t.TopicID)] {
+			continue
+		}
+		if _, err := s.issue(ctx, submission.UserID, models.CertificateKindTopic, t.TopicID, t.Title); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	courses, err := s.courses.GetMyCourses(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for _, course := range courses {
+		if issued[certificateKey(models.CertificateKindCourse, course.ID)] {
+			continue
+		}
+
+		progress, err := s.courses.GetProgress(ctx, course.ID, submission.UserID)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		if !progress.Completed {
+			continue
+		}
+
+		if _, err := s.issue(ctx, submission.UserID, models.CertificateKindCourse, course.ID, course.Title); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnCourseCompleted issues the course certificate when the last item of a
+// course is a reading.
+func (s *CertificateService) OnCourseCompleted(ctx context.Context, courseID, userID string) error {
+	ctx, span := otel.Tracer("certificate").Start(ctx, "CertificateService.OnCourseCompleted")
+	defer span.End()
+
+	issued, err := s.issued(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if issued[certificateKey(models.CertificateKindCourse, courseID)] {
+		return nil
+	}
+
+	course, err := s.courses.GetCourseByID(ctx, courseID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.issue(ctx, userID, models.CertificateKindCourse, courseID, course.Title); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// issue renders the certificate, uploads the PDF and emails the link. It
+// returns nil when the user already holds the certificate.
+func (s *CertificateService) issue(ctx context.Context, userID string, kind models.CertificateKind, subjectID, title string) (*models.Certificate, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	code, err := generateCertificateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := &models.Certificate{
+		Code:          code,
+		UserID:        userID,
+		Kind:          kind,
+		SubjectID:     subjectID,
+		Title:         title,
+		RecipientName: user.DisplayName,
+		IssuedAt:      time.Now(),
+	}
+
+	pdf := renderCertificatePDF(certificate, s.VerifyURL(code))
+	url, err := s.storage.UploadBytes(ctx, "certificates/"+code+".pdf", "application/pdf", pdf)
+	if err != nil {
+		return nil, err
+	}
+	certificate.PDFURL = url
+
+	created, err := s.certificates.Create(ctx, certificate)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	what := fmt.Sprintf("completing the course %q", title)
+	if kind == models.CertificateKindTopic {
+		what = fmt.Sprintf("mastering the topic %q", title)
+	}
+
+	s.email.SendAsync(kafka.EmailMessage{
+		Email:   user.Email,
+		Subject: "Your certificate: " + title,
+		Body: fmt.Sprintf("Congratulations, %s! You earned a certificate for %s.\n\nDownload it: %s\n\nVerification code: %s\nAnyone can check it at %s",
+			user.DisplayName, what, url, code, s.VerifyURL(code)),
+	})
+
+	return certificate, nil
+}
+
+func (s *CertificateService) GetMyCertificates(ctx context.Context, userID string) ([]models.Certificate, error) {
+	ctx, span := otel.Tracer("certificate").Start(ctx, "CertificateService.GetMyCertificates")
+	defer span.End()
+
+	certificates, err := s.certificates.FindByUser(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+// Verify looks a certificate up by the code printed on it. Codes are
+// accepted in any case.
+func (s *CertificateService) Verify(ctx context.Context, code string) (*models.Certificate, error) {
+	ctx, span := otel.Tracer("certificate").Start(ctx, "CertificateService.Verify")
+	defer span.End()
+
+	certificate, err := s.certificates.FindByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if certificate == nil {
+		return nil, ErrCertificateNotFound
+	}
+
+	return certificate, nil
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeCertificateRepo struct {
+	certificates []models.Certificate
+}
+
+func (f *fakeCertificateRepo) Create(ctx context.Context, certificate *models.Certificate) (bool, error) {
+	for _, c := range f.certificates {
+		if c.UserID == certificate.UserID && c.Kind == certificate.Kind && c.SubjectID == certificate.SubjectID {
+			return false, nil
+		}
+	}
+	certificate.ID = "cert-" + strconv.Itoa(len(f.certificates)+1)
+	f.certificates = append(f.certificates, *certificate)
+	return true, nil
+}
+
+func (f *fakeCertificateRepo) FindByCode(ctx context.Context, code string) (*models.Certificate, error) {
+	for _, c := range f.certificates {
+		if c.Code == code {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeCertificateRepo) FindByUser(ctx context.Context, userID string) ([]models.Certificate, error) {
+	var out []models.Certificate
+	for _, c := range f.certificates {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+type fakeFileStorage struct {
+	files map[string][]byte
+	err   error
+}
+
+func (f *fakeFileStorage) UploadBytes(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.files[key] = data
+	return "https://files.test/" + key, nil
+}
+
+type certificateFixture struct {
+	svc          *CertificateService
+	courses      *CourseService
+	certificates *fakeCertificateRepo
+	storage      *fakeFileStorage
+	email        *fakeEmailSender
+	submissions  *fakeSubmissionRepo
+	userID       string
+}
+
+func newCertificateFixture(t *testing.T) *certificateFixture {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	user := &models.User{ID: uuid.New(), Email: "anna@example.com", DisplayName: "Анна Петрова"}
+	require.NoError(t, users.Create(context.Background(), user))
+
+	topics := newFakeTopicRepo()
+	topics.topics = []models.Topic{{ID: "algebra", Title: "Algebra"}, {ID: "geometry", Title: "Geometry"}}
+
+	tasks := newFakeTaskRepo()
+	for _, id := range []string{"a1", "a2", "a3", "g1"} {
+		topic := "algebra"
+		if id == "g1" {
+			topic = "geometry"
+		}
+		tasks.all = append(tasks.all, models.Task{ID: id, TopicID: topic, Status: models.TaskStatusPublished, Difficulty: models.DifficultyEasy})
+	}
+
+	submissions := newFakeSubmissionRepo()
+	courseRepo := newFakeCourseRepo()
+	courseRepo.courses["course-1"] = &models.Course{
+		ID:     "course-1",
+		Title:  "Intro to algebra",
+		Status: models.CourseStatusPublished,
+		Modules: []models.CourseModule{
+			{ID: "m1", Position: 1, Items: []models.CourseItem{
+				{ID: "read-1", ItemType: models.CourseItemReading},
+				{ID: "task-1", ItemType: models.CourseItemTask, TaskID: strPtr("a1")},
+			}},
+		},
+	}
+	courseRepo.enrollments["course-1/"+user.ID.String()] = true
+
+	courses := NewCourseService(courseRepo, tasks, submissions)
+	progress := NewProgressService(topics, tasks, submissions)
+	certificates := &fakeCertificateRepo{}
+	storage := &fakeFileStorage{files: make(map[string][]byte)}
+	email := &fakeEmailSender{}
+
+	svc := NewCertificateService(certificates, users, courses, progress, storage, email, "https://learn.test/")
+	courses.AddListener(svc)
+
+	return &certificateFixture{
+		svc:          svc,
+		courses:      courses,
+		certificates: certificates,
+		storage:      storage,
+		email:        email,
+		submissions:  submissions,
+		userID:       user.ID.String(),
+	}
+}
+
+func (f *certificateFixture) solve(taskIDs ...string) {
+	now := time.Now()
+	for _, id := range taskIDs {
+		f.submissions.stats = append(f.submissions.stats, models.TaskAttemptStats{
+			TaskID: id, Attempts: 1, Solved: true, LastAttemptAt: now, LastSolvedAt: &now,
+		})
+	}
+}
+
+func TestGenerateCertificateCode(t *testing.T) {
+	code, err := generateCertificateCode()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z2-9]{4}(-[A-Z2-9]{4}){3}$`), code)
+
+	other, err := generateCertificateCode()
+	require.NoError(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestRenderCertificatePDF(t *testing.T) {
+	certificate := &models.Certificate{
+		Code:          "ABCD-EFGH-JKLM-NPQR",
+		Kind:          models.CertificateKindTopic,
+		Title:         "Дроби (basics)",
+		RecipientName: "Юлия Щукина",
+		IssuedAt:      time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC),
+	}
+
+	pdf := renderCertificatePDF(certificate, "https://learn.test/verify")
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "(Yuliya Shchukina)")
+	assert.Contains(t, string(pdf), `(Drobi \(basics\))`)
+	assert.Contains(t, string(pdf), "(has mastered the topic)")
+	assert.Contains(t, string(pdf), "(Issued on March 5, 2026)")
+	assert.Contains(t, string(pdf), "ABCD-EFGH-JKLM-NPQR")
+
+	// startxref указывает на таблицу xref, а она — на начало каждого объекта
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, m)
+	xref, _ := strconv.Atoi(string(m[1]))
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n0 7\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.Len(t, entries, 6)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(pdf[off:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+}
+
+func TestPdfText_Kazakh(t *testing.T) {
+	assert.Equal(t, "Aygerim Nurgalieva", string(pdfText("Әйгерім Нұрғалиева")))
+	assert.Equal(t, "Kayrat Toleubaev", string(pdfText("Қайрат Төлеубаев")))
+	assert.Equal(t, "Zhangir Hakimuly", string(pdfText("Жәңгір Һәкімұлы")))
+	assert.Equal(t, "Uzynagash", string(pdfText("Үзынағаш")))
+}
+
+func TestCertificateService_TopicMastery(t *testing.T) {
+	ctx := context.Background()
+	f := newCertificateFixture(t)
+	submission := &models.Submission{UserID: f.userID, TaskID: "a2", IsCorrect: true}
+
+	f.solve("a2", "a3")
+	require.NoError(t, f.svc.OnSubmission(ctx, submission))
+	assert.Empty(t, f.certificates.certificates, "two of three tasks is not mastery yet")
+
+	f.solve("a1")
+	require.NoError(t, f.svc.OnSubmission(ctx, submission))
+
+	// тема освоена, а курс ещё не закончен — не прочитан материал
+	require.Len(t, f.certificates.certificates, 1)
+	issued := f.certificates.certificates[0]
+	assert.Equal(t, models.CertificateKindTopic, issued.Kind)
+	assert.Equal(t, "algebra", issued.SubjectID)
+	assert.Equal(t, "Algebra", issued.Title)
+	assert.Equal(t, "Анна Петрова", issued.RecipientName)
+	assert.Equal(t, "https://files.test/certificates/"+issued.Code+".pdf", issued.PDFURL)
+	assert.Contains(t, string(f.storage.files["certificates/"+issued.Code+".pdf"]), "(Anna Petrova)")
+
+	require.Len(t, f.email.sent, 1)
+	assert.Equal(t, "anna@example.com", f.email.sent[0].Email)
+	assert.Contains(t, f.email.sent[0].Body, issued.PDFURL)
+	assert.Contains(t, f.email.sent[0].Body, "https://learn.test/api/v1/certificates/"+issued.Code+"/verify")
+
+	require.NoError(t, f.svc.OnSubmission(ctx, submission))
+	assert.Len(t, f.certificates.certificates, 1, "a topic is certified once")
+	assert.Len(t, f.email.sent, 1)
+
+	require.NoError(t, f.svc.OnSubmission(ctx, &models.Submission{UserID: f.userID, TaskID: "a2"}))
+	assert.Len(t, f.certificates.certificates, 1)
+}
+
+func TestCertificateService_CourseCompletion(t *testing.T) {
+	ctx := context.Background()
+	f := newCertificateFixture(t)
+
+	f.solve("a1")
+	require.NoError(t, f.svc.OnSubmission(ctx, &models.Submission{UserID: f.userID, TaskID: "a1", IsCorrect: true}))
+	assert.Empty(t, f.certificates.certificates)
+
+	// последний элемент курса — чтение, сертификат выдаёт слушатель курса
+	progress, err := f.courses.CompleteReading(ctx, "course-1", "read-1", f.userID)
+	require.NoError(t, err)
+	assert.True(t, progress.Completed)
+
+	require.Len(t, f.certificates.certificates, 1)
+	assert.Equal(t, models.CertificateKindCourse, f.certificates.certificates[0].Kind)
+	assert.Equal(t, "Intro to algebra", f.certificates.certificates[0].Title)
+	assert.Contains(t, f.email.sent[0].Body, `completing the course "Intro to algebra"`)
+
+	_, err = f.courses.CompleteReading(ctx, "course-1", "read-1", f.userID)
+	require.NoError(t, err)
+	require.NoError(t, f.svc.OnSubmission(ctx, &models.Submission{UserID: f.userID, TaskID: "a1", IsCorrect: true}))
+	assert.Len(t, f.certificates.certificates, 1)
+}
+
+func TestCertificateService_CourseFinishedBySubmission(t *testing.T) {
+	ctx := context.Background()
+	f := newCertificateFixture(t)
+
+	_, err := f.courses.CompleteReading(ctx, "course-1", "read-1", f.userID)
+	require.NoError(t, err)
+	assert.Empty(t, f.certificates.certificates)
+
+	f.solve("a1")
+	require.NoError(t, f.svc.OnSubmission(ctx, &models.Submission{UserID: f.userID, TaskID: "a1", IsCorrect: true}))
+	require.Len(t, f.certificates.certificates, 1)
+	assert.Equal(t, "course-1", f.certificates.certificates[0].SubjectID)
+}
+
+func TestCertificateService_UploadFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newCertificateFixture(t)
+	f.storage.err = errors.New("s3 unavailable")
+
+	err := f.svc.OnCourseCompleted(ctx, "course-1", f.userID)
+	assert.Error(t, err)
+	assert.Empty(t, f.certificates.certificates, "nothing is recorded without the PDF")
+	assert.Empty(t, f.email.sent)
+}
+
+func TestCertificateService_Verify(t *testing.T) {
+	ctx := context.Background()
+	f := newCertificateFixture(t)
+	require.NoError(t, f.svc.OnCourseCompleted(ctx, "course-1", f.userID))
+	code := f.certificates.certificates[0].Code
+
+	certificate, err := f.svc.Verify(ctx, " "+strings.ToLower(code))
+	require.NoError(t, err)
+	assert.Equal(t, "Intro to algebra", certificate.Title)
+
+	_, err = f.svc.Verify(ctx, "NOPE-NOPE-NOPE-NOPE")
+	assert.ErrorIs(t, err, ErrCertificateNotFound)
+
+	mine, err := f.svc.GetMyCertificates(ctx, f.userID)
+	require.NoError(t, err)
+	assert.Len(t, mine, 1)
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	joinCodeLength   = 8
+	joinCodeAttempts = 5
+)
+
+var (
+	ErrClassroomForbidden      = errors.New("you can't manage this classroom")
+	ErrClassroomMemberNotFound = errors.New("classroom member not found")
+	ErrInvalidJoinCode         = errors.New("invalid join code")
+	ErrOwnerCannotLeave        = errors.New("owner must transfer ownership before leaving")
+	ErrCoTeacherRequired       = errors.New("new owner must be a co-teacher of the classroom")
+	ErrCoTeacherMustBeTeacher  = errors.New("only teachers can be added as co-teachers")
+)
+
+type ClassroomService struct {
+	classrooms repository.IClassroomRepository
+	users      repository.IUserRepository
+}
+
+func NewClassroomService(classrooms repository.IClassroomRepository, users repository.IUserRepository) *ClassroomService {
+	return &ClassroomService{
+		classrooms: classrooms,
+		users:      users,
+	}
+}
+
+func generateJoinCode() (string, error) {
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	code := make([]byte, joinCodeLength)
+	for i := range code {
+		n, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = joinCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func isAdmin(role string) bool {
+	return role == string(models.UserRoleAdmin)
+}
+
+// membership returns the caller's membership, or a synthetic owner membership for admins.
+func (s *ClassroomService) membership(ctx context.Context, classroomID, userID, role string) (*models.ClassroomMember, error) {
+	member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member == nil && isAdmin(role) {
+		return &models.ClassroomMember{ClassroomID: classroomID, UserID: userID, Role: models.ClassroomRoleOwner}, nil
+	}
+
+	return member, nil
+}
+
+func (s *ClassroomService) requireTeacher(ctx context.Context, classroomID, userID, role string) (*models.ClassroomMember, error) {
+	if _, err := s.classrooms.FindByID(ctx, classroomID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.membership(ctx, classroomID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if member == nil || !member.IsTeacher() {
+		return nil, ErrClassroomForbidden
+	}
+
+	return member, nil
+}
+
+func (s *ClassroomService) requireOwner(ctx context.Context, classroomID, userID, role string) error {
+	member, err := s.requireTeacher(ctx, classroomID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if member.Role != models.ClassroomRoleOwner {
+		return ErrClassroomForbidden
+	}
+
+	return nil
+}
+
+func (s *ClassroomService) CreateClassroom(ctx context.Context, classroom *models.Classroom) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.CreateClassroom")
+	defer span.End()
+
+	var err error
+	for attempt := 0; attempt < joinCodeAttempts; attempt++ {
+		classroom.JoinCode, err = generateJoinCode()
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		err = s.classrooms.Create(ctx, classroom)
+		if err == nil || !strings.Contains(err.Error(), "duplicate key value") {
+			break
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ClassroomService) GetMyClassrooms(ctx context.Context, userID string) ([]models.Classroom, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.GetMyClassrooms")
+	defer span.End()
+
+	classrooms, err := s.classrooms.FindByMember(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// join codes are only exposed through GetClassroom, where the caller's role is known
+	for i := range classrooms {
+		classrooms[i].JoinCode = ""
+	}
+
+	return classrooms, nil
+}
+
+// GetClassroom returns the classroom and the caller's role in it. The join code
+// is blanked for students.
+func (s *ClassroomService) GetClassroom(ctx context.Context, id, userID, role string) (*models.Classroom, models.ClassroomRole, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.GetClassroom")
+	defer span.End()
+
+	classroom, err := s.classrooms.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+
+	member, err := s.membership(ctx, id, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+
+	if member == nil {
+		return nil, "", ErrClassroomForbidden
+	}
+
+	if !member.IsTeacher() {
+		classroom.JoinCode = ""
+	}
+
+	return classroom, member.Role, nil
+}
+
+func (s *ClassroomService) UpdateClassroom(ctx context.Context, userID, role string, classroom *models.Classroom) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.UpdateClassroom")
+	defer span.End()
+
+	if _, err := s.requireTeacher(ctx, classroom.ID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	existing, err := s.classrooms.FindByID(ctx, classroom.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	existing.Name = classroom.Name
+	existing.Description = classroom.Description
+	existing.SchoolClass = classroom.SchoolClass
+
+	if err := s.classrooms.Update(ctx, existing); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	*classroom = *existing
+	return nil
+}
+
+func (s *ClassroomService) DeleteClassroom(ctx context.Context, userID, role, id string) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.DeleteClassroom")
+	defer span.End()
+
+	if err := s.requireOwner(ctx, id, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.classrooms.Delete(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ClassroomService) RegenerateJoinCode(ctx context.Context, userID, role, id string) (*models.Classroom, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.RegenerateJoinCode")
+	defer span.End()
+
+	if _, err := s.requireTeacher(ctx, id, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	classroom, err := s.classrooms.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	for attempt := 0; attempt < joinCodeAttempts; attempt++ {
+		classroom.JoinCode, err = generateJoinCode()
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		err = s.classrooms.Update(ctx, classroom)
+		if err == nil || !strings.Contains(err.Error(), "duplicate key value") {
+			break
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return classroom, nil
+}
+
+// Join adds the user as a student. Joining a classroom the user already
+// belongs to is a no-op and keeps the existing role.
+func (s *ClassroomService) Join(ctx context.Context, code, userID string) (*models.Classroom, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.Join")
+	defer span.End()
+
+	classroom, err := s.classrooms.FindByJoinCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if classroom == nil {
+		return nil, ErrInvalidJoinCode
+	}
+
+	existing, err := s.classrooms.FindMember(ctx, classroom.ID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if existing == nil {
+		err = s.classrooms.AddMember(ctx, &models.ClassroomMember{
+			ClassroomID: classroom.ID,
+			UserID:      userID,
+			Role:        models.ClassroomRoleStudent,
+		})
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if existing == nil || !existing.IsTeacher() {
+		classroom.JoinCode = ""
+	}
+
+	return classroom, nil
+}
+
+func (s *ClassroomService) GetMembers(ctx context.Context, classroomID, userID, role string) ([]models.ClassroomMember, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.GetMembers")
+	defer span.End()
+
+	if _, err := s.requireTeacher(ctx, classroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	members, err := s.classrooms.FindMembers(ctx, classroomID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// RemoveMember lets teachers remove students, the owner remove co-teachers
+// and any non-owner member leave on their own.
+func (s *ClassroomService) RemoveMember(ctx context.Context, classroomID, userID, role, memberID string) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.RemoveMember")
+	defer span.End()
+
+	target, err := s.classrooms.FindMember(ctx, classroomID, memberID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if target == nil {
+		return ErrClassroomMemberNotFound
+	}
+
+	if target.Role == models.ClassroomRoleOwner {
+		return ErrOwnerCannotLeave
+	}
+
+	if memberID != userID {
+		actor, err := s.requireTeacher(ctx, classroomID, userID, role)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		if target.Role == models.ClassroomRoleTeacher && actor.Role != models.ClassroomRoleOwner {
+			return ErrClassroomForbidden
+		}
+	}
+
+	if err := s.classrooms.RemoveMember(ctx, classroomID, memberID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ClassroomService) AddCoTeacher(ctx context.Context, classroomID, userID, role, teacherID string) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.AddCoTeacher")
+	defer span.End()
+
+	if err := s.requireOwner(ctx, classroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	_, userSpan := otel.Tracer("classroom").Start(ctx, "DB.FindUser")
+	teacher, err := s.users.FindByID(ctx, teacherID)
+	userSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if teacher == nil {
+		return ErrClassroomMemberNotFound
+	}
+
+	if teacher.Role != models.UserRoleTeacher && teacher.Role != models.UserRoleAdmin {
+		return ErrCoTeacherMustBeTeacher
+	}
+
+	existing, err := s.classrooms.FindMember(ctx, classroomID, teacherID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	switch {
+	case existing == nil:
+		err = s.classrooms.AddMember(ctx, &models.ClassroomMember{
+			ClassroomID: classroomID,
+			UserID:      teacherID,
+			Role:        models.ClassroomRoleTeacher,
+		})
+	case existing.Role == models.ClassroomRoleStudent:
+		err = s.classrooms.UpdateMemberRole(ctx, classroomID, teacherID, models.ClassroomRoleTeacher)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ClassroomService) TransferOwnership(ctx context.Context, classroomID, userID, role, newOwnerID string) error {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.TransferOwnership")
+	defer span.End()
+
+	if err := s.requireOwner(ctx, classroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	classroom, err := s.classrooms.FindByID(ctx, classroomID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	target, err := s.classrooms.FindMember(ctx, classroomID, newOwnerID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if target == nil || target.Role != models.ClassroomRoleTeacher {
+		return ErrCoTeacherRequired
+	}
+
+	if err := s.classrooms.TransferOwnership(ctx, classroomID, classroom.OwnerID, newOwnerID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetVisibleStudents returns the students a teacher can see through their classrooms.
+func (s *ClassroomService) GetVisibleStudents(ctx context.Context, teacherID string) ([]models.User, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.GetVisibleStudents")
+	defer span.End()
+
+	users, err := s.classrooms.FindStudentsForTeacher(ctx, teacherID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// CanViewStudent reports whether the viewer may see data about the student:
+// admins always, users about themselves, teachers only about their classroom students.
+func (s *ClassroomService) CanViewStudent(ctx context.Context, viewerID, role, studentID string) (bool, error) {
+	ctx, span := otel.Tracer("classroom").Start(ctx, "ClassroomService.CanViewStudent")
+	defer span.End()
+
+	if isAdmin(role) || viewerID == studentID {
+		return true, nil
+	}
+
+	if role != string(models.UserRoleTeacher) {
+		return false, nil
+	}
+
+	ok, err := s.classrooms.IsStudentOfTeacher(ctx, viewerID, studentID)
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return ok, nil
+}
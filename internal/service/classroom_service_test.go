@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeClassroomRepo struct {
+	classrooms map[string]*models.Classroom
+	members    map[string]map[string]*models.ClassroomMember
+}
+
+func newFakeClassroomRepo() *fakeClassroomRepo {
+	return &fakeClassroomRepo{
+		classrooms: make(map[string]*models.Classroom),
+		members:    make(map[string]map[string]*models.ClassroomMember),
+	}
+}
+
+func (f *fakeClassroomRepo) Create(ctx context.Context, classroom *models.Classroom) error {
+	if classroom.ID == "" {
+		classroom.ID = uuid.NewString()
+	}
+	f.classrooms[classroom.ID] = classroom
+	f.members[classroom.ID] = map[string]*models.ClassroomMember{
+		classroom.OwnerID: {ClassroomID: classroom.ID, UserID: classroom.OwnerID, Role: models.ClassroomRoleOwner},
+	}
+	return nil
+}
+
+func (f *fakeClassroomRepo) FindByID(ctx context.Context, id string) (*models.Classroom, error) {
+	c, ok := f.classrooms[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *fakeClassroomRepo) FindByJoinCode(ctx context.Context, code string) (*models.Classroom, error) {
+	for _, c := range f.classrooms {
+		if c.JoinCode == code {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeClassroomRepo) FindByMember(ctx context.Context, userID string) ([]models.Classroom, error) {
+	var out []models.Classroom
+	for id, members := range f.members {
+		if _, ok := members[userID]; ok {
+			out = append(out, *f.classrooms[id])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeClassroomRepo) Update(ctx context.Context, classroom *models.Classroom) error {
+	cp := *classroom
+	f.classrooms[classroom.ID] = &cp
+	return nil
+}
+
+func (f *fakeClassroomRepo) Delete(ctx context.Context, id string) error {
+	delete(f.classrooms, id)
+	delete(f.members, id)
+	return nil
+}
+
+func (f *fakeClassroomRepo) AddMember(ctx context.Context, member *models.ClassroomMember) error {
+	f.members[member.ClassroomID][member.UserID] = member
+	return nil
+}
+
+func (f *fakeClassroomRepo) FindMember(ctx context.Context, classroomID, userID string) (*models.ClassroomMember, error) {
+	m, ok := f.members[classroomID][userID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (f *fakeClassroomRepo) FindMembers(ctx context.Context, classroomID string) ([]models.ClassroomMember, error) {
+	var out []models.ClassroomMember
+	for _, m := range f.members[classroomID] {
+		out = append(out, *m)
+	}
+	return out, nil
+}
+
+func (f *fakeClassroomRepo) UpdateMemberRole(ctx context.Context, classroomID, userID string, role models.ClassroomRole) error {
+	f.members[classroomID][userID].Role = role
+	return nil
+}
+
+func (f *fakeClassroomRepo) RemoveMember(ctx context.Context, classroomID, userID string) error {
+	delete(f.members[classroomID], userID)
+	return nil
+}
+
+func (f *fakeClassroomRepo) TransferOwnership(ctx context.Context, classroomID, fromUserID, toUserID string) error {
+	f.members[classroomID][fromUserID].Role = models.ClassroomRoleTeacher
+	f.members[classroomID][toUserID].Role = models.ClassroomRoleOwner
+	f.classrooms[classroomID].OwnerID = toUserID
+	return nil
+}
+
+func (f *fakeClassroomRepo) FindStudentsForTeacher(ctx context.Context, teacherID string) ([]models.User, error) {
+	var out []models.User
+	for _, members := range f.members {
+		if m, ok := members[teacherID]; !ok || !m.IsTeacher() {
+			continue
+		}
+		for _, m := range members {
+			if m.Role == models.ClassroomRoleStudent {
+				out = append(out, models.User{ID: uuid.MustParse(m.UserID)})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeClassroomRepo) FindStudentMemberships(ctx context.Context, userID string) ([]models.ClassroomMember, error) {
+	var out []models.ClassroomMember
+	for _, members := range f.members {
+		for _, m := range members {
+			if m.Role == models.ClassroomRoleStudent && (userID == "" || m.UserID == userID) {
+				out = append(out, *m)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeClassroomRepo) IsStudentOfTeacher(ctx context.Context, teacherID, studentID string) (bool, error) {
+	for _, members := range f.members {
+		t, ok := members[teacherID]
+		s, ok2 := members[studentID]
+		if ok && ok2 && t.IsTeacher() && s.Role == models.ClassroomRoleStudent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newClassroomFixture(t *testing.T) (*ClassroomService, *fakeClassroomRepo, *fakeUserRepo, *models.Classroom) {
+	t.Helper()
+
+	repo := newFakeClassroomRepo()
+	users := newFakeUserRepo()
+	svc := NewClassroomService(repo, users)
+
+	classroom := &models.Classroom{Name: "7A", OwnerID: "owner"}
+	require.NoError(t, svc.CreateClassroom(context.Background(), classroom))
+
+	return svc, repo, users, classroom
+}
+
+func TestClassroomService_CreateAndJoin(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, classroom := newClassroomFixture(t)
+
+	assert.Len(t, classroom.JoinCode, joinCodeLength)
+	assert.Equal(t, models.ClassroomRoleOwner, repo.members[classroom.ID]["owner"].Role)
+
+	joined, err := svc.Join(ctx, " "+classroom.JoinCode+" ", "student-1")
+	require.NoError(t, err)
+	assert.Equal(t, classroom.ID, joined.ID)
+	assert.Empty(t, joined.JoinCode, "students must not see the join code")
+	assert.Equal(t, models.ClassroomRoleStudent, repo.members[classroom.ID]["student-1"].Role)
+
+	_, err = svc.Join(ctx, "WRONG", "student-2")
+	assert.ErrorIs(t, err, ErrInvalidJoinCode)
+
+	// повторное вступление владельца не понижает его роль
+	_, err = svc.Join(ctx, classroom.JoinCode, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, models.ClassroomRoleOwner, repo.members[classroom.ID]["owner"].Role)
+}
+
+func TestClassroomService_RemoveMember_Permissions(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, classroom := newClassroomFixture(t)
+
+	repo.members[classroom.ID]["co"] = &models.ClassroomMember{ClassroomID: classroom.ID, UserID: "co", Role: models.ClassroomRoleTeacher}
+	repo.members[classroom.ID]["s1"] = &models.ClassroomMember{ClassroomID: classroom.ID, UserID: "s1", Role: models.ClassroomRoleStudent}
+	repo.members[classroom.ID]["s2"] = &models.ClassroomMember{ClassroomID: classroom.ID, UserID: "s2", Role: models.ClassroomRoleStudent}
+
+	assert.ErrorIs(t, svc.RemoveMember(ctx, classroom.ID, "s1", "Student", "s2"), ErrClassroomForbidden)
+	assert.ErrorIs(t, svc.RemoveMember(ctx, classroom.ID, "co", "Teacher", "owner"), ErrOwnerCannotLeave)
+
+	// соучитель не может удалить другого учителя, но может удалить ученика
+	repo.members[classroom.ID]["co2"] = &models.ClassroomMember{ClassroomID: classroom.ID, UserID: "co2", Role: models.ClassroomRoleTeacher}
+	assert.ErrorIs(t, svc.RemoveMember(ctx, classroom.ID, "co", "Teacher", "co2"), ErrClassroomForbidden)
+	require.NoError(t, svc.RemoveMember(ctx, classroom.ID, "co", "Teacher", "s1"))
+
+	// ученик выходит из класса сам
+	require.NoError(t, svc.RemoveMember(ctx, classroom.ID, "s2", "Student", "s2"))
+
+	require.NoError(t, svc.RemoveMember(ctx, classroom.ID, "owner", "Teacher", "co2"))
+	assert.ErrorIs(t, svc.RemoveMember(ctx, classroom.ID, "owner", "Teacher", "missing"), ErrClassroomMemberNotFound)
+
+	assert.NotContains(t, repo.members[classroom.ID], "s1")
+	assert.NotContains(t, repo.members[classroom.ID], "s2")
+	assert.NotContains(t, repo.members[classroom.ID], "co2")
+}
+
+func TestClassroomService_CoTeacherAndTransfer(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, users, classroom := newClassroomFixture(t)
+
+	teacher := &models.User{Role: models.UserRoleTeacher, Email: "t@test.com"}
+	student := &models.User{Role: models.UserRoleStudent, Email: "s@test.com"}
+	require.NoError(t, users.Create(ctx, teacher))
+	require.NoError(t, users.Create(ctx, student))
+
+	assert.ErrorIs(t, svc.AddCoTeacher(ctx, classroom.ID, "owner", "Teacher", student.ID.String()), ErrCoTeacherMustBeTeacher)
+	assert.ErrorIs(t, svc.TransferOwnership(ctx, classroom.ID, "owner", "Teacher", teacher.ID.String()), ErrCoTeacherRequired)
+
+	require.NoError(t, svc.AddCoTeacher(ctx, classroom.ID, "owner", "Teacher", teacher.ID.String()))
+	assert.ErrorIs(t, svc.AddCoTeacher(ctx, classroom.ID, teacher.ID.String(), "Teacher", student.ID.String()), ErrClassroomForbidden)
+
+	require.NoError(t, svc.TransferOwnership(ctx, classroom.ID, "owner", "Teacher", teacher.ID.String()))
+	assert.Equal(t, models.ClassroomRoleOwner, repo.members[classroom.ID][teacher.ID.String()].Role)
+	assert.Equal(t, models.ClassroomRoleTeacher, repo.members[classroom.ID]["owner"].Role)
+	assert.Equal(t, teacher.ID.String(), repo.classrooms[classroom.ID].OwnerID)
+
+	// бывший владелец больше не может удалить класс
+	assert.ErrorIs(t, svc.DeleteClassroom(ctx, "owner", "Teacher", classroom.ID), ErrClassroomForbidden)
+}
+
+func TestClassroomService_CanViewStudent(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, classroom := newClassroomFixture(t)
+
+	_, err := svc.Join(ctx, classroom.JoinCode, "student-1")
+	require.NoError(t, err)
+
+	ok, err := svc.CanViewStudent(ctx, "owner", "Teacher", "student-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _ = svc.CanViewStudent(ctx, "other-teacher", "Teacher", "student-1")
+	assert.False(t, ok)
+
+	ok, _ = svc.CanViewStudent(ctx, "student-2", "Student", "student-1")
+	assert.False(t, ok)
+
+	ok, _ = svc.CanViewStudent(ctx, "student-1", "Student", "student-1")
+	assert.True(t, ok)
+
+	ok, _ = svc.CanViewStudent(ctx, "admin", "Admin", "student-1")
+	assert.True(t, ok)
+}
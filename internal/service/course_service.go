@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrCourseForbidden   = errors.New("you can't manage this course")
+	ErrCourseNotEnrolled = errors.New("not enrolled in this course")
+	ErrModuleLocked      = errors.New("module is locked")
+)
+
+// CourseCompletionListener is notified when marking a reading item complete
+// finishes a course. Courses finished by solving a task are seen by
+// submission listeners instead.
+type CourseCompletionListener interface {
+	OnCourseCompleted(ctx context.Context, courseID, userID string) error
+}
+
+type CourseService struct {
+	courses     repository.ICourseRepository
+	tasks       repository.ITaskRepository
+	submissions repository.ISubmissionRepository
+	listeners   []CourseCompletionListener
+}
+
+func NewCourseService(courses repository.ICourseRepository, tasks repository.ITaskRepository, submissions repository.ISubmissionRepository) *CourseService {
+	return &CourseService{
+		courses:     courses,
+		tasks:       tasks,
+		submissions: submissions,
+	}
+}
+
+// AddListener registers a listener for completed courses. Listener errors are
+// recorded on the span but do not fail the request.
+func (s *CourseService) AddListener(l CourseCompletionListener) {
+	s.listeners = append(s.listeners, l)
+}
+
+func canManageCourse(course *models.Course, userID, role string) bool {
+	return role == string(models.UserRoleAdmin) || course.AuthorID == userID
+}
+
+func (s *CourseService) CreateCourse(ctx context.Context, course *models.Course) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.CreateCourse")
+	defer span.End()
+
+	if course.Status == "" {
+		course.Status = models.CourseStatusDraft
+	}
+
+	err := s.courses.Create(ctx, course)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) GetCourses(ctx context.Context, includeDrafts bool) ([]models.Course, error) {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.GetCourses")
+	defer span.End()
+
+	var status *models.CourseStatus
+	if !includeDrafts {
+		published := models.CourseStatusPublished
+		status = &published
+	}
+
+	courses, err := s.courses.FindAll(ctx, status)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (s *CourseService) GetMyCourses(ctx context.Context, userID string) ([]models.Course, error) {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.GetMyCourses")
+	defer span.End()
+
+	courses, err := s.courses.FindEnrolledCourses(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (s *CourseService) GetCourseByID(ctx context.Context, id string) (*models.Course, error) {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.GetCourseByID")
+	defer span.End()
+
+	course, err := s.courses.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (s *CourseService) loadManaged(ctx context.Context, courseID, userID, role string) (*models.Course, error) {
+	course, err := s.courses.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageCourse(course, userID, role) {
+		return nil, ErrCourseForbidden
+	}
+	return course, nil
+}
+
+func (s *CourseService) UpdateCourse(ctx context.Context, userID, role string, course *models.Course) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.UpdateCourse")
+	defer span.End()
+
+	existing, err := s.loadManaged(ctx, course.ID, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	course.AuthorID = existing.AuthorID
+	course.CreatedAt = existing.CreatedAt
+
+	err = s.courses.Update(ctx, course)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) DeleteCourse(ctx context.Context, userID, role, id string) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.DeleteCourse")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, id, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.courses.Delete(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) AddModule(ctx context.Context, userID, role string, module *models.CourseModule) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.AddModule")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, module.CourseID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if module.Position <= 0 {
+		max, err := s.courses.MaxModulePosition(ctx, module.CourseID)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		module.Position = max + 1
+	}
+
+	err := s.courses.CreateModule(ctx, module)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) UpdateModule(ctx context.Context, userID, role string, module *models.CourseModule) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.UpdateModule")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, module.CourseID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	existing, err := s.courses.FindModule(ctx, module.CourseID, module.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	module.CreatedAt = existing.CreatedAt
+	if module.Position <= 0 {
+		module.Position = existing.Position
+	}
+
+	err = s.courses.UpdateModule(ctx, module)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) DeleteModule(ctx context.Context, userID, role, courseID, moduleID string) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.DeleteModule")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, courseID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.courses.FindModule(ctx, courseID, moduleID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.courses.DeleteModule(ctx, moduleID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) AddItem(ctx context.Context, userID, role, courseID string, item *models.CourseItem) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.AddItem")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, courseID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.courses.FindModule(ctx, courseID, item.ModuleID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	switch item.ItemType {
+	case models.CourseItemTopic:
+		if item.TopicID == nil {
+			err := errors.New("topicId is required for TOPIC items")
+			span.RecordError(err)
+			return err
+		}
+		item.TaskID = nil
+	case models.CourseItemTask:
+		if item.TaskID == nil {
+			err := errors.New("taskId is required for TASK items")
+			span.RecordError(err)
+			return err
+		}
+		item.TopicID = nil
+	case models.CourseItemReading:
+		if item.BodyMD == "" {
+			err := errors.New("bodyMd is required for READING items")
+			span.RecordError(err)
+			return err
+		}
+		item.TopicID = nil
+		item.TaskID = nil
+	default:
+		err := errors.New("invalid item type")
+		span.RecordError(err)
+		return err
+	}
+
+	if item.Position <= 0 {
+		max, err := s.courses.MaxItemPosition(ctx, item.ModuleID)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		item.Position = max + 1
+	}
+
+	err := s.courses.CreateItem(ctx, item)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) DeleteItem(ctx context.Context, userID, role, courseID, moduleID, itemID string) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.DeleteItem")
+	defer span.End()
+
+	if _, err := s.loadManaged(ctx, courseID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.courses.FindModule(ctx, courseID, moduleID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.courses.FindItem(ctx, moduleID, itemID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.courses.DeleteItem(ctx, itemID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) Enroll(ctx context.Context, courseID, userID string) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.Enroll")
+	defer span.End()
+
+	course, err := s.courses.FindByID(ctx, courseID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if course.Status != models.CourseStatusPublished {
+		err := errors.New("course is not open for enrollment")
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.courses.Enroll(ctx, &models.CourseEnrollment{
+		CourseID: courseID,
+		UserID:   userID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) Unenroll(ctx context.Context, courseID, userID string) error {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.Unenroll")
+	defer span.End()
+
+	err := s.courses.Unenroll(ctx, courseID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *CourseService) CompleteReading(ctx context.Context, courseID, itemID, userID string) (*models.CourseProgress, error) {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.CompleteReading")
+	defer span.End()
+
+	progress, course, err := s.progress(ctx, courseID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	for mi, module := range course.Modules {
+		for _, item := range module.Items {
+			if item.ID != itemID {
+				continue
+			}
+
+			if item.ItemType != models.CourseItemReading {
+				err := errors.New("only reading items can be marked as complete")
+				span.RecordError(err)
+				return nil, err
+			}
+
+			if !progress.Modules[mi].Unlocked {
+				span.RecordError(ErrModuleLocked)
+				return nil, ErrModuleLocked
+			}
+
+			err := s.courses.CompleteItem(ctx, &models.CourseItemCompletion{
+				ItemID: itemID,
+				UserID: userID,
+			})
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+
+			wasCompleted := progress.Completed
+			progress, _, err = s.progress(ctx, courseID, userID)
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+
+			if progress.Completed && !wasCompleted {
+				for _, l := range s.listeners {
+					if err := l.OnCourseCompleted(ctx, courseID, userID); err != nil {
+						span.RecordError(err)
+					}
+				}
+			}
+			return progress, nil
+		}
+	}
+
+	err = errors.New("item not found")
+	span.RecordError(err)
+	return nil, err
+}
+
+func (s *CourseService) GetProgress(ctx context.Context, courseID, userID string) (*models.CourseProgress, error) {
+	ctx, span := otel.Tracer("course").Start(ctx, "CourseService.GetProgress")
+	defer span.End()
+
+	progress, _, err := s.progress(ctx, courseID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (s *CourseService) progress(ctx context.Context, courseID, userID string) (*models.CourseProgress, *models.Course, error) {
+	course, err := s.courses.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enrollment, err := s.courses.FindEnrollment(ctx, courseID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enrollment == nil {
+		return nil, nil, ErrCourseNotEnrolled
+	}
+
+	tasks, err := s.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := s.submissions.GetStatsByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	completed, err := s.courses.GetCompletedItemIDs(ctx, courseID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return computeCourseProgress(course, tasks, stats, completed, time.Now()), course, nil
+}
+
+func computeCourseProgress(course *models.Course, tasks []models.Task, stats []models.TaskAttemptStats, completedItems []string, now time.Time) *models.CourseProgress {
+	solved := make(map[string]bool, len(stats))
+	for _, st := range stats {
+		if st.Solved {
+			solved[st.TaskID] = true
+		}
+	}
+
+	topicTasks := make(map[string][]string)
+	for _, t := range tasks {
+		if t.Status == models.TaskStatusPublished {
+			topicTasks[t.TopicID] = append(topicTasks[t.TopicID], t.ID)
+		}
+	}
+
+	done := make(map[string]bool, len(completedItems))
+	for _, id := range completedItems {
+		done[id] = true
+	}
+
+	itemCompleted := func(item models.CourseItem) bool {
+		switch item.ItemType {
+		case models.CourseItemTask:
+			return item.TaskID != nil && solved[*item.TaskID]
+		case models.CourseItemTopic:
+			if item.TopicID == nil {
+				return false
+			}
+			for _, taskID := range topicTasks[*item.TopicID] {
+				if !solved[taskID] {
+					return false
+				}
+			}
+			return true
+		default:
+			return done[item.ID]
+		}
+	}
+
+	progress := &models.CourseProgress{
+		CourseID: course.ID,
+		Modules:  make([]models.CourseModuleProgress, 0, len(course.Modules)),
+	}
+
+	previousCompleted := true
+	for _, module := range course.Modules {
+		mp := models.CourseModuleProgress{
+			ModuleID:   module.ID,
+			Title:      module.Title,
+			Position:   module.Position,
+			UnlockAt:   module.UnlockAt,
+			TotalItems: len(module.Items),
+			Items:      make([]models.CourseItemProgress, 0, len(module.Items)),
+		}
+
+		mp.Unlocked = (!module.RequiresPrevious || previousCompleted) &&
+			(module.UnlockAt == nil || !now.Before(*module.UnlockAt))
+
+		for _, item := range module.Items {
+			c := itemCompleted(item)
+			if c {
+				mp.CompletedItems++
+			}
+			mp.Items = append(mp.Items, models.CourseItemProgress{
+				ItemID:    item.ID,
+				ItemType:  item.ItemType,
+				Completed: c,
+			})
+		}
+
+		mp.Completed = mp.CompletedItems == mp.TotalItems
+		previousCompleted = mp.Completed
+
+		progress.TotalItems += mp.TotalItems
+		progress.CompletedItems += mp.CompletedItems
+		progress.Modules = append(progress.Modules, mp)
+	}
+
+	progress.Completed = progress.TotalItems > 0 && progress.CompletedItems == progress.TotalItems
+
+	return progress
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeCourseRepo struct {
+	courses     map[string]*models.Course
+	enrollments map[string]bool
+	completions map[string][]string
+}
+
+func newFakeCourseRepo() *fakeCourseRepo {
+	return &fakeCourseRepo{
+		courses:     make(map[string]*models.Course),
+		enrollments: make(map[string]bool),
+		completions: make(map[string][]string),
+	}
+}
+
+var errFakeNotFound = errors.New("record not found")
+
+func (f *fakeCourseRepo) Create(ctx context.Context, course *models.Course) error {
+	if course.ID == "" {
+		course.ID = "course-" + time.Now().Format("150405.000000")
+	}
+	f.courses[course.ID] = course
+	return nil
+}
+
+func (f *fakeCourseRepo) FindAll(ctx context.Context, status *models.CourseStatus) ([]models.Course, error) {
+	var out []models.Course
+	for _, c := range f.courses {
+		if status == nil || c.Status == *status {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCourseRepo) FindByID(ctx context.Context, id string) (*models.Course, error) {
+	c, ok := f.courses[id]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeCourseRepo) Update(ctx context.Context, course *models.Course) error {
+	course.Modules = f.courses[course.ID].Modules
+	f.courses[course.ID] = course
+	return nil
+}
+
+func (f *fakeCourseRepo) Delete(ctx context.Context, id string) error {
+	delete(f.courses, id)
+	return nil
+}
+
+func (f *fakeCourseRepo) CreateModule(ctx context.Context, module *models.CourseModule) error {
+	c := f.courses[module.CourseID]
+	if module.ID == "" {
+		module.ID = "module-" + time.Now().Format("150405.000000")
+	}
+	c.Modules = append(c.Modules, *module)
+	return nil
+}
+
+func (f *fakeCourseRepo) FindModule(ctx context.Context, courseID, moduleID string) (*models.CourseModule, error) {
+	c, ok := f.courses[courseID]
+	if !ok {
+		return nil, errFakeNotFound
+	}
+	for i := range c.Modules {
+		if c.Modules[i].ID == moduleID {
+			return &c.Modules[i], nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (f *fakeCourseRepo) UpdateModule(ctx context.Context, module *models.CourseModule) error {
+	c := f.courses[module.CourseID]
+	for i := range c.Modules {
+		if c.Modules[i].ID == module.ID {
+			module.Items = c.Modules[i].Items
+			c.Modules[i] = *module
+		}
+	}
+	return nil
+}
+
+func (f *fakeCourseRepo) DeleteModule(ctx context.Context, moduleID string) error {
+	for _, c := range f.courses {
+		for i := range c.Modules {
+			if c.Modules[i].ID == moduleID {
+				c.Modules = append(c.Modules[:i], c.Modules[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeCourseRepo) MaxModulePosition(ctx context.Context, courseID string) (int, error) {
+	max := 0
+	for _, m := range f.courses[courseID].Modules {
+		if m.Position > max {
+			max = m.Position
+		}
+	}
+	return max, nil
+}
+
+func (f *fakeCourseRepo) CreateItem(ctx context.Context, item *models.CourseItem) error {
+	for _, c := range f.courses {
+		for i := range c.Modules {
+			if c.Modules[i].ID == item.ModuleID {
+				c.Modules[i].Items = append(c.Modules[i].Items, *item)
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeCourseRepo) FindItem(ctx context.Context, moduleID, itemID string) (*models.CourseItem, error) {
+	for _, c := range f.courses {
+		for _, m := range c.Modules {
+			for i := range m.Items {
+				if m.ID == moduleID && m.Items[i].ID == itemID {
+					return &m.Items[i], nil
+				}
+			}
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (f *fakeCourseRepo) DeleteItem(ctx context.Context, itemID string) error {
+	return nil
+}
+
+func (f *fakeCourseRepo) MaxItemPosition(ctx context.Context, moduleID string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeCourseRepo) Enroll(ctx context.Context, enrollment *models.CourseEnrollment) error {
+	f.enrollments[enrollment.CourseID+"/"+enrollment.UserID] = true
+	return nil
+}
+
+func (f *fakeCourseRepo) Unenroll(ctx context.Context, courseID, userID string) error {
+	delete(f.enrollments, courseID+"/"+userID)
+	return nil
+}
+
+func (f *fakeCourseRepo) FindEnrollment(ctx context.Context, courseID, userID string) (*models.CourseEnrollment, error) {
+	if !f.enrollments[courseID+"/"+userID] {
+		return nil, nil
+	}
+	return &models.CourseEnrollment{CourseID: courseID, UserID: userID}, nil
+}
+
+func (f *fakeCourseRepo) FindEnrolledCourses(ctx context.Context, userID string) ([]models.Course, error) {
+	var out []models.Course
+	for id, c := range f.courses {
+		if f.enrollments[id+"/"+userID] {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCourseRepo) CompleteItem(ctx context.Context, completion *models.CourseItemCompletion) error {
+	f.completions[completion.UserID] = append(f.completions[completion.UserID], completion.ItemID)
+	return nil
+}
+
+func (f *fakeCourseRepo) GetCompletedItemIDs(ctx context.Context, courseID, userID string) ([]string, error) {
+	return f.completions[userID], nil
+}
+
+func strRef(s string) *string { return &s }
+
+func TestComputeCourseProgress_UnlockRules(t *testing.T) {
+	now := time.Now()
+	future := now.Add(24 * time.Hour)
+
+	course := &models.Course{
+		ID: "course-1",
+		Modules: []models.CourseModule{
+			{ID: "m1", Position: 1, Items: []models.CourseItem{
+				{ID: "i1", ItemType: models.CourseItemTask, TaskID: strRef("t1")},
+				{ID: "i2", ItemType: models.CourseItemReading},
+			}},
+			{ID: "m2", Position: 2, RequiresPrevious: true, Items: []models.CourseItem{
+				{ID: "i3", ItemType: models.CourseItemTopic, TopicID: strRef("topic-1")},
+			}},
+			{ID: "m3", Position: 3, UnlockAt: &future, Items: []models.CourseItem{
+				{ID: "i4", ItemType: models.CourseItemReading},
+			}},
+		},
+	}
+
+	tasks := []models.Task{
+		{ID: "t1", TopicID: "topic-0", Status: models.TaskStatusPublished},
+		{ID: "t2", TopicID: "topic-1", Status: models.TaskStatusPublished},
+		{ID: "t3", TopicID: "topic-1", Status: models.TaskStatusDraft},
+	}
+	stats := []models.TaskAttemptStats{{TaskID: "t1", Solved: true}}
+
+	progress := computeCourseProgress(course, tasks, stats, nil, now)
+	require.Len(t, progress.Modules, 3)
+	assert.True(t, progress.Modules[0].Unlocked)
+	assert.False(t, progress.Modules[0].Completed)
+	assert.False(t, progress.Modules[1].Unlocked, "previous module is not complete")
+	assert.False(t, progress.Modules[2].Unlocked, "unlock date is in the future")
+	assert.Equal(t, 1, progress.CompletedItems)
+
+	stats = append(stats, models.TaskAttemptStats{TaskID: "t2", Solved: true})
+	progress = computeCourseProgress(course, tasks, stats, []string{"i2"}, now)
+	assert.True(t, progress.Modules[0].Completed)
+	assert.True(t, progress.Modules[1].Unlocked)
+	assert.True(t, progress.Modules[1].Completed, "draft tasks do not count towards topic completion")
+	assert.False(t, progress.Completed)
+
+	progress = computeCourseProgress(course, tasks, stats, []string{"i2", "i4"}, future)
+	assert.True(t, progress.Modules[2].Unlocked)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, 4, progress.TotalItems)
+}
+
+func TestCourseService_UpdateCourse_OnlyAuthorOrAdmin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCourseRepo()
+	repo.courses["course-1"] = &models.Course{ID: "course-1", AuthorID: "teacher-1", Title: "Old"}
+
+	svc := NewCourseService(repo, newFakeTaskRepo(), newFakeSubmissionRepo())
+
+	err := svc.UpdateCourse(ctx, "teacher-2", "Teacher", &models.Course{ID: "course-1", Title: "Hijack"})
+	assert.ErrorIs(t, err, ErrCourseForbidden)
+
+	err = svc.UpdateCourse(ctx, "admin-1", "Admin", &models.Course{ID: "course-1", Title: "By admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "teacher-1", repo.courses["course-1"].AuthorID)
+	assert.Equal(t, "By admin", repo.courses["course-1"].Title)
+}
+
+func TestCourseService_Enroll_RequiresPublished(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCourseRepo()
+	repo.courses["draft"] = &models.Course{ID: "draft", Status: models.CourseStatusDraft}
+	repo.courses["live"] = &models.Course{ID: "live", Status: models.CourseStatusPublished}
+
+	svc := NewCourseService(repo, newFakeTaskRepo(), newFakeSubmissionRepo())
+
+	assert.Error(t, svc.Enroll(ctx, "draft", "student-1"))
+	require.NoError(t, svc.Enroll(ctx, "live", "student-1"))
+
+	_, err := svc.GetProgress(ctx, "live", "student-2")
+	assert.ErrorIs(t, err, ErrCourseNotEnrolled)
+
+	_, err = svc.GetProgress(ctx, "live", "student-1")
+	assert.NoError(t, err)
+}
+
+func TestCourseService_CompleteReading_RespectsLocks(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCourseRepo()
+	repo.courses["course-1"] = &models.Course{
+		ID:     "course-1",
+		Status: models.CourseStatusPublished,
+		Modules: []models.CourseModule{
+			{ID: "m1", Position: 1, Items: []models.CourseItem{
+				{ID: "read-1", ItemType: models.CourseItemReading},
+			}},
+			{ID: "m2", Position: 2, RequiresPrevious: true, Items: []models.CourseItem{
+				{ID: "read-2", ItemType: models.CourseItemReading},
+			}},
+		},
+	}
+	repo.enrollments["course-1/student-1"] = true
+
+	svc := NewCourseService(repo, newFakeTaskRepo(), newFakeSubmissionRepo())
+
+	_, err := svc.CompleteReading(ctx, "course-1", "read-2", "student-1")
+	assert.ErrorIs(t, err, ErrModuleLocked)
+
+	progress, err := svc.CompleteReading(ctx, "course-1", "read-1", "student-1")
+	require.NoError(t, err)
+	assert.True(t, progress.Modules[0].Completed)
+	assert.True(t, progress.Modules[1].Unlocked)
+
+	_, err = svc.CompleteReading(ctx, "course-1", "missing", "student-1")
+	assert.EqualError(t, err, "item not found")
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+const (
+	// dailyChallengeRecentDays keeps the automatic selector from repeating
+	// tasks the class had recently, as long as there are others to pick.
+	dailyChallengeRecentDays = 30
+
+	DefaultDailyChallengeHistory = 30
+	MaxDailyChallengeHistory     = 100
+)
+
+var (
+	ErrInvalidDailyChallenge  = errors.New("invalid daily challenge")
+	ErrDailyChallengeNotFound = errors.New("daily challenge not found")
+	ErrNoDailyChallengeTasks  = errors.New("no published tasks for this school class")
+)
+
+// DailyChallengeService serves one task per UTC day for each school class.
+// Admins may pick the task for today or a future day; otherwise it is picked
+// automatically the first time the day's challenge is requested. Solves are
+// ordinary task submissions, ranked by how soon after midnight UTC the first
+// correct answer came in.
+type DailyChallengeService struct {
+	challenges repository.IDailyChallengeRepository
+	tasks      repository.ITaskRepository
+	classes    repository.ISchoolClassRepository
+	users      repository.IUserRepository
+}
+
+func NewDailyChallengeService(challenges repository.IDailyChallengeRepository, tasks repository.ITaskRepository, classes repository.ISchoolClassRepository, users repository.IUserRepository) *DailyChallengeService {
+	return &DailyChallengeService{
+		challenges: challenges,
+		tasks:      tasks,
+		classes:    classes,
+		users:      users,
+	}
+}
+
+func challengeDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseChallengeDay parses a "2025-03-10" day; empty means today.
+func parseChallengeDay(day string, now time.Time) (time.Time, error) {
+	if day == "" {
+		return challengeDay(now), nil
+	}
+	parsed, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must look like 2025-03-10", ErrInvalidDailyChallenge)
+	}
+	return parsed, nil
+}
+
+func (s *DailyChallengeService) validateSchoolClass(ctx context.Context, code string) error {
+	class, err := s.classes.FindByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if class == nil || !class.IsActive {
+		return ErrInvalidSchoolClass
+	}
+	return nil
+}
+
+// pickDailyTask deterministically picks one of the auto-graded tasks for the
+// class and day, preferring tasks that were not used recently.
+func pickDailyTask(tasks []models.Task, recent map[string]bool, schoolClass string, day time.Time) *models.Task {
+	gradable := make([]models.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.GradingMode != models.GradingModeManual {
+			gradable = append(gradable, t)
+		}
+	}
+
+	candidates := make([]models.Task, 0, len(gradable))
+	for _, t := range gradable {
+		if !recent[t.ID] {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = gradable
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	h := fnv.New32a()
+	h.Write([]byte(schoolClass + "/" + day.Format("2006-01-02")))
+	return &candidates[h.Sum32()%uint32(len(candidates))]
+}
+
+// GetToday returns today's challenge for the school class, picking a task
+// automatically if nobody has yet.
+func (s *DailyChallengeService) GetToday(ctx context.Context, schoolClass string) (*models.DailyChallenge, error) {
+	ctx, span := otel.Tracer("daily-challenge").Start(ctx, "DailyChallengeService.GetToday")
+	defer span.End()
+
+	schoolClass = normalizeSchoolClassCode(schoolClass)
+
+	if err := s.validateSchoolClass(ctx, schoolClass); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	day := challengeDay(time.Now())
+
+	challenge, err := s.challenges.FindByDay(ctx, schoolClass, day)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	tasks, err := s.tasks.GetBySchoolClass(ctx, schoolClass)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	history, err := s.challenges.FindHistory(ctx, schoolClass, day, dailyChallengeRecentDays)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	recent := make(map[string]bool, len(history))
+	for _, c := range history {
+		recent[c.TaskID] = true
+	}
+
+	task := pickDailyTask(tasks, recent, schoolClass, day)
+	if task == nil {
+		return nil, ErrNoDailyChallengeTasks
+	}
+
+	// a concurrent request may have stored the challenge first; either way
+	// the stored one wins
+	_, err = s.challenges.Create(ctx, &models.DailyChallenge{
+		SchoolClass: schoolClass,
+		Day:         day,
+		TaskID:      task.ID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	challenge, err = s.challenges.FindByDay(ctx, schoolClass, day)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrDailyChallengeNotFound
+	}
+
+	return challenge, nil
+}
+
+// SetChallenge makes an admin's pick the challenge of the class for today
+// (empty day) or a future day, replacing an automatic or earlier pick.
+func (s *DailyChallengeService) SetChallenge(ctx context.Context, schoolClass, day, taskID, adminID string) (*models.DailyChallenge, error) {
+	ctx, span := otel.Tracer("daily-challenge").Start(ctx, "DailyChallengeService.SetChallenge")
+	defer span.End()
+
+	schoolClass = normalizeSchoolClassCode(schoolClass)
+
+	if err := s.validateSchoolClass(ctx, schoolClass); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	date, err := parseChallengeDay(day, now)
+	if err != nil {
+		return nil, err
+	}
+	if date.Before(challengeDay(now)) {
+		return nil, fmt.Errorf("%w: past challenges cannot be changed", ErrInvalidDailyChallenge)
+	}
+
+	task, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("%w: task not found", ErrInvalidDailyChallenge)
+	}
+	if task.Status != models.TaskStatusPublished {
+		return nil, fmt.Errorf("%w: task is not published", ErrInvalidDailyChallenge)
+	}
+	if task.GradingMode == models.GradingModeManual {
+		return nil, fmt.Errorf("%w: manually graded tasks cannot be daily challenges", ErrInvalidDailyChallenge)
+	}
+	if task.Topic != nil && task.Topic.SchoolClass != schoolClass {
+		return nil, fmt.Errorf("%w: task belongs to another school class", ErrInvalidDailyChallenge)
+	}
+
+	err = s.challenges.Upsert(ctx, &models.DailyChallenge{
+		SchoolClass: schoolClass,
+		Day:         date,
+		TaskID:      task.ID,
+		SelectedBy:  &adminID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	challenge, err := s.challenges.FindByDay(ctx, schoolClass, date)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrDailyChallengeNotFound
+	}
+
+	return challenge, nil
+}
+
+// GetLeaderboard ranks the users who solved the class's challenge of the
+// given day (today when empty) by solve time, with the caller's own entry.
+func (s *DailyChallengeService) GetLeaderboard(ctx context.Context, schoolClass, day, userID string, limit int) (*models.DailyChallengeLeaderboard, error) {
+	ctx, span := otel.Tracer("daily-challenge").Start(ctx, "DailyChallengeService.GetLeaderboard")
+	defer span.End()
+
+	schoolClass = normalizeSchoolClassCode(schoolClass)
+
+	now := time.Now()
+	date, err := parseChallengeDay(day, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge *models.DailyChallenge
+	if date.Equal(challengeDay(now)) {
+		challenge, err = s.GetToday(ctx, schoolClass)
+	} else {
+		if err = s.validateSchoolClass(ctx, schoolClass); err == nil {
+			challenge, err = s.challenges.FindByDay(ctx, schoolClass, date)
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrDailyChallengeNotFound
+	}
+
+	solves, err := s.challenges.GetSolves(ctx, challenge.TaskID, challenge.Day, challenge.Day.AddDate(0, 0, 1))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(solves))
+	for _, solve := range solves {
+		ids = append(ids, solve.UserID)
+	}
+	users, err := s.users.FindByIDs(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.ID.String()] = u.DisplayName
+	}
+
+	board := &models.DailyChallengeLeaderboard{
+		Challenge: challenge,
+		Entries:   make([]models.DailyChallengeEntry, 0, min(limit, len(solves))),
+	}
+	for i, solve := range solves {
+		entry := models.DailyChallengeEntry{
+			Rank:        i + 1,
+			UserID:      solve.UserID,
+			DisplayName: names[solve.UserID],
+			SolvedAt:    solve.SolvedAt,
+			SolveTime:   solve.SolvedAt.Sub(challenge.Day),
+			Attempts:    solve.Attempts,
+		}
+		if i < limit {
+			board.Entries = append(board.Entries, entry)
+		}
+		if solve.UserID == userID {
+			board.Me = &entry
+		}
+	}
+
+	return board, nil
+}
+
+// GetHistory returns the class's past challenges, newest first.
+func (s *DailyChallengeService) GetHistory(ctx context.Context, schoolClass string, limit int) ([]models.DailyChallenge, error) {
+	ctx, span := otel.Tracer("daily-challenge").Start(ctx, "DailyChallengeService.GetHistory")
+	defer span.End()
+
+	schoolClass = normalizeSchoolClassCode(schoolClass)
+
+	if err := s.validateSchoolClass(ctx, schoolClass); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	challenges, err := s.challenges.FindHistory(ctx, schoolClass, challengeDay(time.Now()), limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return challenges, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeDailyChallengeRepo struct {
+	tasks      *fakeTaskRepo
+	challenges []models.DailyChallenge
+	solves     map[string][]models.DailyChallengeSolve
+}
+
+func newFakeDailyChallengeRepo(tasks *fakeTaskRepo) *fakeDailyChallengeRepo {
+	return &fakeDailyChallengeRepo{tasks: tasks, solves: make(map[string][]models.DailyChallengeSolve)}
+}
+
+func (f *fakeDailyChallengeRepo) FindByDay(ctx context.Context, schoolClass string, day time.Time) (*models.DailyChallenge, error) {
+	for _, c := range f.challenges {
+		if c.SchoolClass == schoolClass && c.Day.Equal(day) {
+			c.Task = f.tasks.byID[c.TaskID]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeDailyChallengeRepo) Create(ctx context.Context, challenge *models.DailyChallenge) (bool, error) {
+	if existing, _ := f.FindByDay(ctx, challenge.SchoolClass, challenge.Day); existing != nil {
+		return false, nil
+	}
+	challenge.ID = "challenge-" + challenge.SchoolClass + "-" + challenge.Day.Format("2006-01-02")
+	f.challenges = append(f.challenges, *challenge)
+	return true, nil
+}
+
+func (f *fakeDailyChallengeRepo) Upsert(ctx context.Context, challenge *models.DailyChallenge) error {
+	for i := range f.challenges {
+		if f.challenges[i].SchoolClass == challenge.SchoolClass && f.challenges[i].Day.Equal(challenge.Day) {
+			f.challenges[i].TaskID = challenge.TaskID
+			f.challenges[i].SelectedBy = challenge.SelectedBy
+			return nil
+		}
+	}
+	_, err := f.Create(ctx, challenge)
+	return err
+}
+
+func (f *fakeDailyChallengeRepo) FindHistory(ctx context.Context, schoolClass string, before time.Time, limit int) ([]models.DailyChallenge, error) {
+	var out []models.DailyChallenge
+	for i := len(f.challenges) - 1; i >= 0 && len(out) < limit; i-- {
+		c := f.challenges[i]
+		if c.SchoolClass == schoolClass && c.Day.Before(before) {
+			c.Task = f.tasks.byID[c.TaskID]
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDailyChallengeRepo) GetSolves(ctx context.Context, taskID string, from, to time.Time) ([]models.DailyChallengeSolve, error) {
+	return f.solves[taskID], nil
+}
+
+func newDailyChallengeFixture(t *testing.T) (*DailyChallengeService, *fakeDailyChallengeRepo, *fakeTaskRepo, *fakeUserRepo) {
+	t.Helper()
+
+	seven := &models.Topic{ID: "topic-seven", SchoolClass: "SEVEN"}
+	eight := &models.Topic{ID: "topic-eight", SchoolClass: "EIGHT"}
+
+	tasks := newFakeTaskRepo()
+	for _, task := range []*models.Task{
+		{ID: "t1", TopicID: seven.ID, Topic: seven, Status: models.TaskStatusPublished, CorrectAnswer: "1"},
+		{ID: "t2", TopicID: seven.ID, Topic: seven, Status: models.TaskStatusPublished, CorrectAnswer: "2"},
+		{ID: "t3", TopicID: seven.ID, Topic: seven, Status: models.TaskStatusDraft},
+		{ID: "t4", TopicID: eight.ID, Topic: eight, Status: models.TaskStatusPublished},
+	} {
+		tasks.byID[task.ID] = task
+	}
+
+	challenges := newFakeDailyChallengeRepo(tasks)
+	users := newFakeUserRepo()
+	svc := NewDailyChallengeService(challenges, tasks, newFakeSchoolClassRepo("SEVEN", "EIGHT", "NINE"), users)
+
+	return svc, challenges, tasks, users
+}
+
+func TestPickDailyTask(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	tasks := []models.Task{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	first := pickDailyTask(tasks, nil, "SEVEN", day)
+	require.NotNil(t, first)
+	// порядок задач не влияет на выбор
+	again := pickDailyTask([]models.Task{{ID: "c"}, {ID: "a"}, {ID: "b"}}, nil, "SEVEN", day)
+	assert.Equal(t, first.ID, again.ID)
+
+	picked := pickDailyTask(tasks, map[string]bool{"a": true, "b": true}, "SEVEN", day)
+	assert.Equal(t, "c", picked.ID)
+
+	// все задачи недавно были — выбираем из всех
+	picked = pickDailyTask(tasks, map[string]bool{"a": true, "b": true, "c": true}, "SEVEN", day)
+	assert.NotNil(t, picked)
+
+	assert.Nil(t, pickDailyTask(nil, nil, "SEVEN", day))
+
+	// задачи с ручной проверкой не выбираются
+	manual := []models.Task{{ID: "a", GradingMode: models.GradingModeManual}, {ID: "b"}}
+	assert.Equal(t, "b", pickDailyTask(manual, nil, "SEVEN", day).ID)
+	assert.Nil(t, pickDailyTask(manual[:1], nil, "SEVEN", day))
+}
+
+func TestDailyChallengeService_GetToday(t *testing.T) {
+	ctx := context.Background()
+	svc, challenges, _, _ := newDailyChallengeFixture(t)
+
+	today := challengeDay(time.Now())
+	// вчерашняя задача не должна повториться сегодня
+	challenges.challenges = append(challenges.challenges, models.DailyChallenge{
+		SchoolClass: "SEVEN", Day: today.AddDate(0, 0, -1), TaskID: "t1",
+	})
+
+	challenge, err := svc.GetToday(ctx, "SEVEN")
+	require.NoError(t, err)
+	assert.Equal(t, "t2", challenge.TaskID)
+	assert.Equal(t, today, challenge.Day)
+	assert.Nil(t, challenge.SelectedBy)
+	require.NotNil(t, challenge.Task)
+
+	again, err := svc.GetToday(ctx, "SEVEN")
+	require.NoError(t, err)
+	assert.Equal(t, challenge.ID, again.ID)
+	assert.Len(t, challenges.challenges, 2)
+
+	eight, err := svc.GetToday(ctx, "EIGHT")
+	require.NoError(t, err)
+	assert.Equal(t, "t4", eight.TaskID)
+
+	_, err = svc.GetToday(ctx, "NINE")
+	assert.ErrorIs(t, err, ErrNoDailyChallengeTasks)
+
+	_, err = svc.GetToday(ctx, "TWELVE")
+	assert.ErrorIs(t, err, ErrInvalidSchoolClass)
+}
+
+func TestDailyChallengeService_SetChallenge(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newDailyChallengeFixture(t)
+
+	auto, err := svc.GetToday(ctx, "SEVEN")
+	require.NoError(t, err)
+	other := "t1"
+	if auto.TaskID == "t1" {
+		other = "t2"
+	}
+
+	challenge, err := svc.SetChallenge(ctx, "seven ", "", other, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, other, challenge.TaskID)
+	assert.Equal(t, "SEVEN", challenge.SchoolClass)
+	require.NotNil(t, challenge.SelectedBy)
+	assert.Equal(t, "admin-1", *challenge.SelectedBy)
+
+	tomorrow := challengeDay(time.Now()).AddDate(0, 0, 1).Format("2006-01-02")
+	challenge, err = svc.SetChallenge(ctx, "SEVEN", tomorrow, "t1", "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, tomorrow, challenge.Day.Format("2006-01-02"))
+
+	yesterday := challengeDay(time.Now()).AddDate(0, 0, -1).Format("2006-01-02")
+	_, err = svc.SetChallenge(ctx, "SEVEN", yesterday, "t1", "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge)
+
+	_, err = svc.SetChallenge(ctx, "SEVEN", "10.03.2025", "t1", "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge)
+
+	_, err = svc.SetChallenge(ctx, "SEVEN", "", "t3", "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge, "draft tasks cannot be picked")
+
+	_, err = svc.SetChallenge(ctx, "SEVEN", "", "t4", "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge, "task of another class")
+
+	_, err = svc.SetChallenge(ctx, "SEVEN", "", "missing", "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge)
+}
+
+func TestDailyChallengeService_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	svc, challenges, _, users := newDailyChallengeFixture(t)
+
+	challenge, err := svc.GetToday(ctx, "SEVEN")
+	require.NoError(t, err)
+
+	var ids []string
+	for _, name := range []string{"Fast", "Slow", "Me"} {
+		u := &models.User{Email: name + "@example.com", DisplayName: name}
+		require.NoError(t, users.Create(ctx, u))
+		ids = append(ids, u.ID.String())
+	}
+	challenges.solves[challenge.TaskID] = []models.DailyChallengeSolve{
+		{UserID: ids[0], SolvedAt: challenge.Day.Add(90 * time.Second), Attempts: 1},
+		{UserID: ids[1], SolvedAt: challenge.Day.Add(time.Hour), Attempts: 3},
+		{UserID: ids[2], SolvedAt: challenge.Day.Add(2 * time.Hour), Attempts: 2},
+	}
+
+	board, err := svc.GetLeaderboard(ctx, "SEVEN", "", ids[2], 2)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.ID, board.Challenge.ID)
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, models.DailyChallengeEntry{
+		Rank:        1,
+		UserID:      ids[0],
+		DisplayName: "Fast",
+		SolvedAt:    challenge.Day.Add(90 * time.Second),
+		SolveTime:   90 * time.Second,
+		Attempts:    1,
+	}, board.Entries[0])
+	require.NotNil(t, board.Me)
+	assert.Equal(t, 3, board.Me.Rank)
+	assert.Equal(t, "Me", board.Me.DisplayName)
+
+	board, err = svc.GetLeaderboard(ctx, "SEVEN", "", "someone-else", 10)
+	require.NoError(t, err)
+	assert.Len(t, board.Entries, 3)
+	assert.Nil(t, board.Me)
+
+	_, err = svc.GetLeaderboard(ctx, "SEVEN", "2020-01-01", ids[0], 10)
+	assert.ErrorIs(t, err, ErrDailyChallengeNotFound)
+
+	_, err = svc.GetLeaderboard(ctx, "SEVEN", "yesterday", ids[0], 10)
+	assert.ErrorIs(t, err, ErrInvalidDailyChallenge)
+}
+
+func TestDailyChallengeService_GetHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, challenges, _, _ := newDailyChallengeFixture(t)
+
+	today := challengeDay(time.Now())
+	challenges.challenges = append(challenges.challenges,
+		models.DailyChallenge{SchoolClass: "SEVEN", Day: today.AddDate(0, 0, -2), TaskID: "t1"},
+		models.DailyChallenge{SchoolClass: "EIGHT", Day: today.AddDate(0, 0, -1), TaskID: "t4"},
+		models.DailyChallenge{SchoolClass: "SEVEN", Day: today.AddDate(0, 0, -1), TaskID: "t2"},
+	)
+	_, err := svc.GetToday(ctx, "SEVEN")
+	require.NoError(t, err)
+
+	history, err := svc.GetHistory(ctx, "SEVEN", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2, "today's challenge is not history yet")
+	assert.Equal(t, "t2", history[0].TaskID)
+	assert.Equal(t, "t1", history[1].TaskID)
+	assert.True(t, history[0].IsOver(time.Now()))
+
+	_, err = svc.GetHistory(ctx, "TWELVE", 10)
+	assert.ErrorIs(t, err, ErrInvalidSchoolClass)
+}
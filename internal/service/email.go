@@ -0,0 +1,8 @@
+package service
+
+import "learning-platform/internal/kafka"
+
+// EmailSender queues outgoing emails. *kafka.EmailProducer implements it.
+type EmailSender interface {
+	SendAsync(msg kafka.EmailMessage)
+}
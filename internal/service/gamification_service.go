@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// A streak freeze is earned for every streakFreezeEvery days of streak,
+	// up to maxStreakFreezes held at once.
+	streakFreezeEvery = 7
+	maxStreakFreezes  = 2
+)
+
+var ErrInvalidCalendarMonth = errors.New("month must look like 2025-03")
+
+// GamificationService awards XP for solved tasks and keeps daily streaks in
+// the user's timezone. A day counts when the user answers a task correctly;
+// missed days are covered by streak freezes when enough are available.
+type GamificationService struct {
+	stats       repository.IUserStatsRepository
+	submissions repository.ISubmissionRepository
+	users       repository.IUserRepository
+}
+
+func NewGamificationService(stats repository.IUserStatsRepository, submissions repository.ISubmissionRepository, users repository.IUserRepository) *GamificationService {
+	return &GamificationService{
+		stats:       stats,
+		submissions: submissions,
+		users:       users,
+	}
+}
+
+// userLocation returns the user's timezone, falling back to UTC for unknown
+// names.
+func userLocation(user *models.User) *time.Location {
+	if user == nil || user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// localDate returns the calendar day of t in loc as midnight UTC.
+func localDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// applyActivity records activity on today and returns the streak days to
+// store: today plus any missed days covered by freezes.
+func applyActivity(s *models.UserStats, today time.Time) []models.StreakDay {
+	if s.LastActiveDate != nil && !s.LastActiveDate.Before(today) {
+		return nil
+	}
+
+	var days []models.StreakDay
+
+	switch {
+	case s.LastActiveDate == nil:
+		s.CurrentStreak = 1
+	default:
+		missed := daysBetween(*s.LastActiveDate, today) - 1
+		switch {
+		case missed == 0:
+			s.CurrentStreak++
+		case missed <= s.StreakFreezes && s.CurrentStreak > 0:
+			s.StreakFreezes -= missed
+			for i := 1; i <= missed; i++ {
+				days = append(days, models.StreakDay{
+					UserID: s.UserID,
+					Day:    s.LastActiveDate.AddDate(0, 0, i),
+					Kind:   models.StreakDayFrozen,
+				})
+			}
+			s.CurrentStreak++
+		default:
+			s.CurrentStreak = 1
+		}
+	}
+
+	if s.CurrentStreak%streakFreezeEvery == 0 && s.StreakFreezes < maxStreakFreezes {
+		s.StreakFreezes++
+	}
+	s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+
+	active := today
+	s.LastActiveDate = &active
+
+	return append(days, models.StreakDay{UserID: s.UserID, Day: today, Kind: models.StreakDayActive})
+}
+
+// effectiveStreak is the streak as of today: it is still alive when the user
+// was active today or yesterday, or the gap can be covered by freezes.
+func effectiveStreak(s *models.UserStats, today time.Time) int {
+	if s == nil || s.LastActiveDate == nil {
+		return 0
+	}
+	missed := daysBetween(*s.LastActiveDate, today) - 1
+	if missed <= 0 || missed <= s.StreakFreezes {
+		return s.CurrentStreak
+	}
+	return 0
+}
+
+// OnSubmission awards XP for the first correct solve of a task and extends
+// the streak on every correct answer.
+func (s *GamificationService) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("gamification").Start(ctx, "GamificationService.OnSubmission")
+	defer span.End()
+
+	if !submission.IsCorrect || submission.Task == nil {
+		return nil
+	}
+
+	solves, err := s.submissions.CountCorrect(ctx, submission.UserID, submission.TaskID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	var xp int64
+	if solves == 1 {
+		xp = leaderboardPoints(submission.Task.Difficulty)
+	}
+
+	user, err := s.users.FindByID(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	today := localDate(time.Now(), userLocation(user))
+
+	_, err = s.stats.Apply(ctx, submission.UserID, func(stats *models.UserStats) []models.StreakDay {
+		stats.XP += xp
+		return applyActivity(stats, today)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetStats returns the user's XP and streak as of now; users without any
+// activity get zero stats.
+func (s *GamificationService) GetStats(ctx context.Context, user *models.User) (*models.UserStats, error) {
+	ctx, span := otel.Tracer("gamification").Start(ctx, "GamificationService.GetStats")
+	defer span.End()
+
+	userID := user.ID.String()
+	stats, err := s.stats.Find(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if stats == nil {
+		return &models.UserStats{UserID: userID}, nil
+	}
+
+	stats.CurrentStreak = effectiveStreak(stats, localDate(time.Now(), userLocation(user)))
+	return stats, nil
+}
+
+// GetCalendar returns the streak days of the given month ("2025-03") in the
+// user's timezone; an empty month means the current one.
+func (s *GamificationService) GetCalendar(ctx context.Context, userID, month string) (*models.StreakCalendar, error) {
+	ctx, span := otel.Tracer("gamification").Start(ctx, "GamificationService.GetCalendar")
+	defer span.End()
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	loc := userLocation(user)
+
+	var first time.Time
+	if month == "" {
+		today := localDate(time.Now(), loc)
+		first = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		first, err = time.Parse("2006-01", month)
+		if err != nil {
+			return nil, ErrInvalidCalendarMonth
+		}
+	}
+	last := first.AddDate(0, 1, -1)
+
+	days, err := s.stats.FindDays(ctx, userID, first, last)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	stats, err := s.GetStats(ctx, user)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &models.StreakCalendar{
+		Month:    fmt.Sprintf("%04d-%02d", first.Year(), first.Month()),
+		Timezone: loc.String(),
+		Days:     days,
+		Stats:    stats,
+	}, nil
+}
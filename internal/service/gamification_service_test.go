@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeUserStatsRepo struct {
+	stats map[string]*models.UserStats
+	days  []models.StreakDay
+}
+
+func newFakeUserStatsRepo() *fakeUserStatsRepo {
+	return &fakeUserStatsRepo{stats: make(map[string]*models.UserStats)}
+}
+
+func (f *fakeUserStatsRepo) Find(ctx context.Context, userID string) (*models.UserStats, error) {
+	s, ok := f.stats[userID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (f *fakeUserStatsRepo) Apply(ctx context.Context, userID string, apply func(stats *models.UserStats) []models.StreakDay) (*models.UserStats, error) {
+	s, ok := f.stats[userID]
+	if !ok {
+		s = &models.UserStats{UserID: userID}
+		f.stats[userID] = s
+	}
+	for _, d := range apply(s) {
+		exists := false
+		for _, existing := range f.days {
+			if existing.UserID == d.UserID && existing.Day.Equal(d.Day) {
+				exists = true
+			}
+		}
+		if !exists {
+			f.days = append(f.days, d)
+		}
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (f *fakeUserStatsRepo) FindDays(ctx context.Context, userID string, from, to time.Time) ([]models.StreakDay, error) {
+	var out []models.StreakDay
+	for _, d := range f.days {
+		if d.UserID == userID && !d.Day.Before(from) && !d.Day.After(to) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func TestLocalDate_UsesUserTimezone(t *testing.T) {
+	// 22:30 UTC — в Москве уже следующий день
+	now := time.Date(2025, 3, 10, 22, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), localDate(now, userLocation(&models.User{})))
+	assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), localDate(now, userLocation(&models.User{Timezone: "Europe/Moscow"})))
+	assert.Equal(t, time.UTC, userLocation(&models.User{Timezone: "Mars/Olympus"}))
+}
+
+func TestApplyActivity_StreakAndFreezes(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+	}
+	s := &models.UserStats{UserID: "u1"}
+
+	days := applyActivity(s, day(0))
+	assert.Equal(t, 1, s.CurrentStreak)
+	require.Len(t, days, 1)
+	assert.Equal(t, models.StreakDayActive, days[0].Kind)
+
+	assert.Nil(t, applyActivity(s, day(0)), "second answer on the same day changes nothing")
+
+	for i := 1; i < 7; i++ {
+		applyActivity(s, day(i))
+	}
+	assert.Equal(t, 7, s.CurrentStreak)
+	assert.Equal(t, 1, s.StreakFreezes, "a freeze is earned every 7 days")
+
+	// один пропущенный день закрывается заморозкой
+	days = applyActivity(s, day(8))
+	assert.Equal(t, 8, s.CurrentStreak)
+	assert.Equal(t, 0, s.StreakFreezes)
+	require.Len(t, days, 2)
+	assert.Equal(t, models.StreakDay{UserID: "u1", Day: day(7), Kind: models.StreakDayFrozen}, days[0])
+
+	// без заморозок серия начинается заново
+	applyActivity(s, day(10))
+	assert.Equal(t, 1, s.CurrentStreak)
+	assert.Equal(t, 8, s.LongestStreak)
+}
+
+func TestApplyActivity_FreezesAreCapped(t *testing.T) {
+	s := &models.UserStats{UserID: "u1"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 28; i++ {
+		applyActivity(s, start.AddDate(0, 0, i))
+	}
+	assert.Equal(t, maxStreakFreezes, s.StreakFreezes)
+}
+
+func TestEffectiveStreak(t *testing.T) {
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	last := func(n int) *time.Time {
+		d := today.AddDate(0, 0, -n)
+		return &d
+	}
+
+	assert.Equal(t, 0, effectiveStreak(nil, today))
+	assert.Equal(t, 5, effectiveStreak(&models.UserStats{CurrentStreak: 5, LastActiveDate: last(0)}, today))
+	assert.Equal(t, 5, effectiveStreak(&models.UserStats{CurrentStreak: 5, LastActiveDate: last(1)}, today))
+	assert.Equal(t, 0, effectiveStreak(&models.UserStats{CurrentStreak: 5, LastActiveDate: last(2)}, today))
+	assert.Equal(t, 5, effectiveStreak(&models.UserStats{CurrentStreak: 5, StreakFreezes: 1, LastActiveDate: last(2)}, today))
+}
+
+func TestGamificationService_OnSubmissionAndCalendar(t *testing.T) {
+	ctx := context.Background()
+
+	users := newFakeUserRepo()
+	user := &models.User{Email: "kid@example.com", Timezone: "Asia/Tokyo"}
+	require.NoError(t, users.Create(ctx, user))
+	userID := user.ID.String()
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["hard"] = &models.Task{ID: "hard", Difficulty: models.DifficultyHard, CorrectAnswer: "2"}
+
+	submissions := newFakeSubmissionRepo()
+	stats := newFakeUserStatsRepo()
+	taskService := NewTaskService(tasks, submissions, newTestRedis(t))
+	svc := NewGamificationService(stats, submissions, users)
+	taskService.AddListener(svc)
+
+	for _, answer := range []string{"1", "2", "2"} {
+		_, err := taskService.SubmitAnswer(ctx, "hard", userID, answer)
+		require.NoError(t, err)
+	}
+
+	got, err := svc.GetStats(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), got.XP, "XP is only awarded for the first solve")
+	assert.Equal(t, 1, got.CurrentStreak)
+	require.NotNil(t, got.LastActiveDate)
+	assert.Equal(t, localDate(time.Now(), userLocation(user)), *got.LastActiveDate)
+
+	calendar, err := svc.GetCalendar(ctx, userID, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", calendar.Timezone)
+	assert.Len(t, calendar.Days, 1)
+
+	calendar, err = svc.GetCalendar(ctx, userID, "2020-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2020-01", calendar.Month)
+	assert.Empty(t, calendar.Days)
+
+	_, err = svc.GetCalendar(ctx, userID, "January")
+	assert.ErrorIs(t, err, ErrInvalidCalendarMonth)
+}
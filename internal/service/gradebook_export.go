@@ -0,0 +1,120 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"learning-platform/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const gradebookSheet = "Gradebook"
+
+// escapeCell keeps spreadsheet apps from running user supplied text, such as
+// a display name of "=HYPERLINK(...)", as a formula.
+func escapeCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func gradebookHeader(gb *models.Gradebook) []string {
+	header := []string{"Student", "Email"}
+	for _, a := range gb.Assignments {
+		header = append(header, escapeCell(a.Title))
+	}
+	return append(header, "Weighted total")
+}
+
+func gradebookStudent(row *models.GradebookRow) (string, string) {
+	if row.Student == nil {
+		return row.UserID, ""
+	}
+	return escapeCell(row.Student.DisplayName), escapeCell(row.Student.Email)
+}
+
+func formatScore(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteGradebookCSV streams the gradebook as CSV, one row per student.
+func WriteGradebookCSV(w io.Writer, gb *models.Gradebook) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(gradebookHeader(gb)); err != nil {
+		return err
+	}
+
+	for i := range gb.Rows {
+		row := &gb.Rows[i]
+		name, email := gradebookStudent(row)
+
+		record := []string{name, email}
+		for _, cell := range row.Cells {
+			record = append(record, formatScore(cell.Score))
+		}
+		record = append(record, formatScore(row.Weighted))
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteGradebookXLSX writes the gradebook as a single-sheet workbook using the
+// excelize stream writer so large classrooms don't build the whole sheet in memory.
+func WriteGradebookXLSX(w io.Writer, gb *models.Gradebook) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", gradebookSheet); err != nil {
+		return err
+	}
+
+	sw, err := f.NewStreamWriter(gradebookSheet)
+	if err != nil {
+		return err
+	}
+
+	header := gradebookHeader(gb)
+	headerRow := make([]interface{}, len(header))
+	for i, h := range header {
+		headerRow[i] = h
+	}
+	if err := sw.SetRow("A1", headerRow); err != nil {
+		return err
+	}
+
+	for i := range gb.Rows {
+		row := &gb.Rows[i]
+		name, email := gradebookStudent(row)
+
+		values := make([]interface{}, 0, len(row.Cells)+3)
+		values = append(values, name, email)
+		for _, cell := range row.Cells {
+			values = append(values, cell.Score)
+		}
+		values = append(values, row.Weighted)
+
+		axis, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(axis, values); err != nil {
+			return err
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+var ErrInvalidWeights = errors.New("weights must not be negative")
+
+type GradebookService struct {
+	assignments repository.IAssignmentRepository
+	classrooms  repository.IClassroomRepository
+	submissions repository.ISubmissionRepository
+}
+
+func NewGradebookService(assignments repository.IAssignmentRepository, classrooms repository.IClassroomRepository, submissions repository.ISubmissionRepository) *GradebookService {
+	return &GradebookService{
+		assignments: assignments,
+		classrooms:  classrooms,
+		submissions: submissions,
+	}
+}
+
+func (s *GradebookService) requireTeacher(ctx context.Context, classroomID, userID, role string) error {
+	if _, err := s.classrooms.FindByID(ctx, classroomID); err != nil {
+		return err
+	}
+
+	if isAdmin(role) {
+		return nil
+	}
+
+	member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member == nil || !member.IsTeacher() {
+		return ErrClassroomForbidden
+	}
+
+	return nil
+}
+
+// GetGradebook returns per-student scores for every opened assignment of the
+// classroom and the weighted total over assignments whose due date has passed.
+func (s *GradebookService) GetGradebook(ctx context.Context, userID, role, classroomID string) (*models.Gradebook, error) {
+	ctx, span := otel.Tracer("gradebook").Start(ctx, "GradebookService.GetGradebook")
+	defer span.End()
+
+	if err := s.requireTeacher(ctx, classroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, assignmentsSpan := otel.Tracer("gradebook").Start(ctx, "Assignments.FindByClassroom")
+	assignments, err := s.assignments.FindByClassroom(ctx, classroomID)
+	assignmentsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, membersSpan := otel.Tracer("gradebook").Start(ctx, "Classrooms.FindMembers")
+	members, err := s.classrooms.FindMembers(ctx, classroomID)
+	membersSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	opened := make([]models.Assignment, 0, len(assignments))
+	ids := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		if now.Before(a.OpensAt) {
+			continue
+		}
+		opened = append(opened, a)
+		ids = append(ids, a.ID)
+	}
+
+	_, statsSpan := otel.Tracer("gradebook").Start(ctx, "Submissions.GetAssignmentStats")
+	stats, err := s.submissions.GetAssignmentStats(ctx, ids, "")
+	statsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return buildGradebook(classroomID, opened, members, stats, now), nil
+}
+
+func (s *GradebookService) UpdateWeights(ctx context.Context, userID, role, classroomID string, weights map[string]float64) error {
+	ctx, span := otel.Tracer("gradebook").Start(ctx, "GradebookService.UpdateWeights")
+	defer span.End()
+
+	if err := s.requireTeacher(ctx, classroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return ErrInvalidWeights
+		}
+	}
+
+	if err := s.assignments.UpdateWeights(ctx, classroomID, weights); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func buildGradebook(classroomID string, assignments []models.Assignment, members []models.ClassroomMember, stats []models.AssignmentTaskStats, now time.Time) *models.Gradebook {
+	byKey := make(map[string][]models.AssignmentTaskStats)
+	for _, st := range stats {
+		key := st.AssignmentID + "/" + st.UserID
+		byKey[key] = append(byKey[key], st)
+	}
+
+	gradebook := &models.Gradebook{
+		ClassroomID: classroomID,
+		Assignments: assignments,
+	}
+
+	for _, m := range members {
+		if m.Role != models.ClassroomRoleStudent {
+			continue
+		}
+
+		row := models.GradebookRow{
+			Student: m.User,
+			UserID:  m.UserID,
+			Cells:   make([]models.GradebookCell, 0, len(assignments)),
+		}
+
+		var earned, total float64
+		for i := range assignments {
+			a := &assignments[i]
+			progress := computeAssignmentProgress(a, m.UserID, byKey[a.ID+"/"+m.UserID], now)
+			row.Cells = append(row.Cells, models.GradebookCell{
+				AssignmentID: a.ID,
+				Score:        progress.Score,
+				Status:       progress.Status,
+			})
+
+			if now.After(a.DueAt) {
+				earned += progress.Score * a.Weight
+				total += a.Weight
+			}
+		}
+
+		if total > 0 {
+			row.Weighted = math.Round(earned/total*10) / 10
+		}
+
+		gradebook.Rows = append(gradebook.Rows, row)
+	}
+
+	return gradebook
+}
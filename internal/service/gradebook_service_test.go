@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"learning-platform/internal/models"
+)
+
+func TestBuildGradebook_WeightedTotal(t *testing.T) {
+	now := time.Now()
+
+	assignments := []models.Assignment{
+		{ID: "a1", Title: "HW 1", OpensAt: now.Add(-72 * time.Hour), DueAt: now.Add(-48 * time.Hour), Weight: 1,
+			Tasks: []models.AssignmentTask{{TaskID: "t1"}, {TaskID: "t2"}}},
+		{ID: "a2", Title: "Test", OpensAt: now.Add(-72 * time.Hour), DueAt: now.Add(-24 * time.Hour), Weight: 3,
+			Tasks: []models.AssignmentTask{{TaskID: "t3"}}},
+		// срок ещё не наступил — в итог не входит
+		{ID: "a3", Title: "HW 2", OpensAt: now.Add(-time.Hour), DueAt: now.Add(24 * time.Hour), Weight: 1,
+			Tasks: []models.AssignmentTask{{TaskID: "t4"}}},
+	}
+
+	members := []models.ClassroomMember{
+		{UserID: "teacher", Role: models.ClassroomRoleOwner},
+		{UserID: "s1", Role: models.ClassroomRoleStudent, User: &models.User{DisplayName: "Alice", Email: "alice@test.com"}},
+	}
+
+	stats := []models.AssignmentTaskStats{
+		{AssignmentID: "a1", UserID: "s1", TaskID: "t1", Attempts: 1, Solved: true, SolvedOnTime: true},
+		{AssignmentID: "a2", UserID: "s1", TaskID: "t3", Attempts: 2, Solved: true, SolvedOnTime: true},
+		{AssignmentID: "a3", UserID: "s1", TaskID: "t4", Attempts: 1, Solved: true, SolvedOnTime: true},
+	}
+
+	gb := buildGradebook("c1", assignments, members, stats, now)
+
+	require.Len(t, gb.Rows, 1, "only students get a row")
+	row := gb.Rows[0]
+	require.Len(t, row.Cells, 3)
+	assert.Equal(t, 50.0, row.Cells[0].Score)
+	assert.Equal(t, 100.0, row.Cells[1].Score)
+	assert.Equal(t, 100.0, row.Cells[2].Score)
+
+	// (50*1 + 100*3) / 4
+	assert.Equal(t, 87.5, row.Weighted)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGradebookCSV(&buf, gb))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Student,Email,HW 1,Test,HW 2,Weighted total", lines[0])
+	assert.Equal(t, "Alice,alice@test.com,50,100,100,87.5", lines[1])
+}
+
+func TestWriteGradebook_EscapesFormulas(t *testing.T) {
+	gb := &models.Gradebook{
+		Assignments: []models.Assignment{{ID: "a1", Title: "+SUM(A1:A9)"}},
+		Rows: []models.GradebookRow{{
+			UserID:   "s1",
+			Student:  &models.User{DisplayName: "=HYPERLINK(\"http://evil.example\")", Email: "@evil.example"},
+			Cells:    []models.GradebookCell{{Score: -5}},
+			Weighted: -5,
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGradebookCSV(&buf, gb))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Student,Email,'+SUM(A1:A9),Weighted total", lines[0])
+	// оценки остаются числами
+	assert.Equal(t, `"'=HYPERLINK(""http://evil.example"")",'@evil.example,-5,-5`, lines[1])
+
+	buf.Reset()
+	require.NoError(t, WriteGradebookXLSX(&buf, gb))
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	name, err := f.GetCellValue(gradebookSheet, "A2")
+	require.NoError(t, err)
+	assert.Equal(t, `'=HYPERLINK("http://evil.example")`, name)
+	title, err := f.GetCellValue(gradebookSheet, "C1")
+	require.NoError(t, err)
+	assert.Equal(t, "'+SUM(A1:A9)", title)
+}
+
+func TestGradebookService_UpdateWeights(t *testing.T) {
+	ctx := context.Background()
+
+	classrooms := newFakeClassroomRepo()
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	classrooms.members[classroom.ID]["student"] = &models.ClassroomMember{
+		ClassroomID: classroom.ID, UserID: "student", Role: models.ClassroomRoleStudent,
+	}
+
+	assignments := newFakeAssignmentRepo()
+	require.NoError(t, assignments.Create(ctx, &models.Assignment{ID: "a1", ClassroomID: classroom.ID, Weight: 1}))
+	require.NoError(t, assignments.Create(ctx, &models.Assignment{ID: "other", ClassroomID: "another", Weight: 1}))
+
+	svc := NewGradebookService(assignments, classrooms, newFakeSubmissionRepo())
+
+	err := svc.UpdateWeights(ctx, "student", "Student", classroom.ID, map[string]float64{"a1": 2})
+	assert.ErrorIs(t, err, ErrClassroomForbidden)
+
+	err = svc.UpdateWeights(ctx, "teacher", "Teacher", classroom.ID, map[string]float64{"a1": -1})
+	assert.ErrorIs(t, err, ErrInvalidWeights)
+
+	// задание из чужого класса
+	err = svc.UpdateWeights(ctx, "teacher", "Teacher", classroom.ID, map[string]float64{"other": 2})
+	assert.Error(t, err)
+
+	require.NoError(t, svc.UpdateWeights(ctx, "teacher", "Teacher", classroom.ID, map[string]float64{"a1": 2.5}))
+	assert.Equal(t, 2.5, assignments.assignments["a1"].Weight)
+	assert.Equal(t, 1.0, assignments.assignments["other"].Weight)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrInvalidGrade            = errors.New("invalid grade")
+	ErrGradingForbidden        = errors.New("you can't grade this submission")
+	ErrSubmissionAlreadyGraded = errors.New("submission is already graded")
+)
+
+// GradingService runs the teacher grading queue for MANUAL tasks. A teacher
+// may grade answers to tasks they authored and answers submitted for
+// assignments of classrooms they teach; admins may grade everything. Graded
+// submissions are passed on to the TaskService submission listeners.
+type GradingService struct {
+	submissions repository.ISubmissionRepository
+	assignments repository.IAssignmentRepository
+	classrooms  repository.IClassroomRepository
+	tasks       *TaskService
+}
+
+func NewGradingService(submissions repository.ISubmissionRepository, assignments repository.IAssignmentRepository, classrooms repository.IClassroomRepository, tasks *TaskService) *GradingService {
+	return &GradingService{
+		submissions: submissions,
+		assignments: assignments,
+		classrooms:  classrooms,
+		tasks:       tasks,
+	}
+}
+
+// GetQueue returns the submissions the caller can grade, oldest first,
+// optionally limited to one task.
+func (s *GradingService) GetQueue(ctx context.Context, userID, role, taskID string) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("grading").Start(ctx, "GradingService.GetQueue")
+	defer span.End()
+
+	graderID := userID
+	if isAdmin(role) {
+		graderID = ""
+	}
+
+	submissions, err := s.submissions.FindPendingReview(ctx, graderID, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+func (s *GradingService) canGrade(ctx context.Context, submission *models.Submission, userID, role string) (bool, error) {
+	if isAdmin(role) {
+		return true, nil
+	}
+	if submission.Task != nil && submission.Task.AuthorID == userID {
+		return true, nil
+	}
+	if submission.AssignmentID == nil {
+		return false, nil
+	}
+
+	assignment, err := s.assignments.FindByID(ctx, *submission.AssignmentID)
+	if err != nil {
+		return false, err
+	}
+
+	member, err := s.classrooms.FindMember(ctx, assignment.ClassroomID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return member != nil && member.IsTeacher(), nil
+}
+
+func applyGrade(submission *models.Submission, graderID string, score int, feedback string) {
+	now := time.Now()
+	submission.Status = models.SubmissionStatusGraded
+	submission.IsCorrect = score >= models.ManualPassingScore
+	submission.Score = &score
+	submission.GradedBy = &graderID
+	submission.GradedAt = &now
+	submission.Feedback = nil
+	if feedback = strings.TrimSpace(feedback); feedback != "" {
+		submission.Feedback = &feedback
+	}
+}
+
+// Grade scores a submission pending review from 0 to 100 with optional
+// feedback. Scores of at least models.ManualPassingScore count as correct.
+func (s *GradingService) Grade(ctx context.Context, submissionID, userID, role string, score int, feedback string) (*models.Submission, error) {
+	ctx, span := otel.Tracer("grading").Start(ctx, "GradingService.Grade")
+	defer span.End()
+
+	if score < 0 || score > 100 {
+		return nil, fmt.Errorf("%w: score must be between 0 and 100", ErrInvalidGrade)
+	}
+
+	submission, err := s.submissions.FindByID(ctx, submissionID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	allowed, err := s.canGrade(ctx, submission, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrGradingForbidden
+	}
+
+	if submission.Status != models.SubmissionStatusPendingReview {
+		return nil, ErrSubmissionAlreadyGraded
+	}
+
+	applyGrade(submission, userID, score, feedback)
+
+	graded, err := s.submissions.Grade(ctx, submission)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !graded {
+		return nil, ErrSubmissionAlreadyGraded
+	}
+
+	s.tasks.NotifyListeners(ctx, submission)
+
+	return submission, nil
+}
+
+// Override replaces the grade of an answer to a MANUAL task, whether it is
+// still pending or was graded by peers or another teacher. Listeners only
+// hear about it when the answer is graded for the first time or becomes
+// correct, so solves are not counted twice.
+func (s *GradingService) Override(ctx context.Context, submissionID, userID, role string, score int, feedback string) (*models.Submission, error) {
+	ctx, span := otel.Tracer("grading").Start(ctx, "GradingService.Override")
+	defer span.End()
+
+	if score < 0 || score > 100 {
+		return nil, fmt.Errorf("%w: score must be between 0 and 100", ErrInvalidGrade)
+	}
+
+	submission, err := s.submissions.FindByID(ctx, submissionID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	allowed, err := s.canGrade(ctx, submission, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrGradingForbidden
+	}
+
+	if submission.Task == nil || submission.Task.GradingMode != models.GradingModeManual {
+		return nil, fmt.Errorf("%w: only answers to manually graded tasks can be overridden", ErrInvalidGrade)
+	}
+
+	wasPending := submission.Status == models.SubmissionStatusPendingReview
+	wasCorrect := submission.IsCorrect
+
+	applyGrade(submission, userID, score, feedback)
+
+	if err := s.submissions.Regrade(ctx, submission); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if wasPending || (!wasCorrect && submission.IsCorrect) {
+		s.tasks.NotifyListeners(ctx, submission)
+	}
+
+	return submission, nil
+}
+
+// GetMySubmissions returns the user's submissions for a task, newest first,
+// including grades and feedback once they are given.
+func (s *GradingService) GetMySubmissions(ctx context.Context, userID, taskID string) ([]models.Submission, error) {
+	ctx, span := otel.Tracer("grading").Start(ctx, "GradingService.GetMySubmissions")
+	defer span.End()
+
+	submissions, err := s.submissions.FindByUserAndTask(ctx, userID, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return submissions, nil
+}
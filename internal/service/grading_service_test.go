@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type countingListener struct {
+	submissions []models.Submission
+}
+
+func (l *countingListener) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	l.submissions = append(l.submissions, *submission)
+	return nil
+}
+
+func newGradingFixture(t *testing.T) (*GradingService, *TaskService, *fakeSubmissionRepo, *countingListener, *fakeClassroomRepo) {
+	t.Helper()
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["essay"] = &models.Task{
+		ID: "essay", AuthorID: "author", Status: models.TaskStatusPublished,
+		AnswerType: models.AnswerTypeText, GradingMode: models.GradingModeManual,
+	}
+	tasks.byID["auto"] = &models.Task{ID: "auto", AuthorID: "author", Status: models.TaskStatusPublished, CorrectAnswer: "42"}
+
+	submissions := newFakeSubmissionRepo()
+	submissions.tasks = tasks
+	taskService := NewTaskService(tasks, submissions, newTestRedis(t))
+	listener := &countingListener{}
+	taskService.AddListener(listener)
+
+	classrooms := newFakeClassroomRepo()
+	svc := NewGradingService(submissions, newFakeAssignmentRepo(), classrooms, taskService)
+
+	return svc, taskService, submissions, listener, classrooms
+}
+
+func TestValidateGradingMode(t *testing.T) {
+	task := &models.Task{AnswerType: models.AnswerTypeNumber}
+	require.NoError(t, validateGradingMode(task))
+	assert.Equal(t, models.GradingModeAuto, task.GradingMode)
+
+	task.GradingMode = models.GradingModeManual
+	assert.ErrorIs(t, validateGradingMode(task), ErrInvalidGradingMode)
+
+	task.AnswerType = models.AnswerTypeText
+	assert.NoError(t, validateGradingMode(task))
+}
+
+func TestTaskService_Submit_ManualTaskIsPending(t *testing.T) {
+	ctx := context.Background()
+	_, taskService, submissions, listener, _ := newGradingFixture(t)
+
+	submission := &models.Submission{TaskID: "essay", UserID: "student", Answer: "Because the sum of angles is 180"}
+	require.NoError(t, taskService.Submit(ctx, submission))
+	assert.Equal(t, models.SubmissionStatusPendingReview, submission.Status)
+	assert.False(t, submission.IsCorrect)
+	assert.Empty(t, listener.submissions, "pending answers are not passed to listeners")
+
+	require.NoError(t, taskService.Submit(ctx, &models.Submission{TaskID: "auto", UserID: "student", Answer: "42"}))
+	assert.Len(t, listener.submissions, 1)
+	assert.Equal(t, models.SubmissionStatusGraded, submissions.submissions[1].Status)
+}
+
+func TestGradingService_Grade(t *testing.T) {
+	ctx := context.Background()
+	svc, taskService, submissions, listener, _ := newGradingFixture(t)
+
+	submission := &models.Submission{TaskID: "essay", UserID: "student", Answer: "essay text"}
+	require.NoError(t, taskService.Submit(ctx, submission))
+
+	queue, err := svc.GetQueue(ctx, "author", "Teacher", "")
+	require.NoError(t, err)
+	assert.Len(t, queue, 1)
+
+	queue, err = svc.GetQueue(ctx, "stranger", "Teacher", "")
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	_, err = svc.Grade(ctx, submission.ID, "author", "Teacher", 101, "")
+	assert.ErrorIs(t, err, ErrInvalidGrade)
+
+	_, err = svc.Grade(ctx, submission.ID, "stranger", "Teacher", 80, "")
+	assert.ErrorIs(t, err, ErrGradingForbidden)
+
+	graded, err := svc.Grade(ctx, submission.ID, "author", "Teacher", 75, "  Good argument  ")
+	require.NoError(t, err)
+	assert.True(t, graded.IsCorrect)
+	assert.Equal(t, models.SubmissionStatusGraded, graded.Status)
+	require.NotNil(t, graded.Feedback)
+	assert.Equal(t, "Good argument", *graded.Feedback)
+	require.NotNil(t, graded.GradedBy)
+	assert.Equal(t, "author", *graded.GradedBy)
+
+	require.Len(t, listener.submissions, 1, "graded answers reach the listeners")
+	assert.True(t, listener.submissions[0].IsCorrect)
+	require.NotNil(t, listener.submissions[0].Task)
+
+	_, err = svc.Grade(ctx, submission.ID, "admin", "Admin", 10, "")
+	assert.ErrorIs(t, err, ErrSubmissionAlreadyGraded)
+
+	mine, err := svc.GetMySubmissions(ctx, "student", "essay")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	require.NotNil(t, mine[0].Score)
+	assert.Equal(t, 75, *mine[0].Score)
+	assert.Equal(t, models.SubmissionStatusGraded, submissions.submissions[0].Status)
+}
+
+func TestGradingService_Grade_LowScoreAndClassroomTeacher(t *testing.T) {
+	ctx := context.Background()
+	svc, taskService, _, listener, classrooms := newGradingFixture(t)
+
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	assignment := &models.Assignment{ClassroomID: classroom.ID, DueAt: time.Now().Add(time.Hour)}
+	require.NoError(t, svc.assignments.Create(ctx, assignment))
+
+	submission := &models.Submission{TaskID: "essay", UserID: "student", Answer: "short", AssignmentID: &assignment.ID}
+	require.NoError(t, taskService.Submit(ctx, submission))
+
+	// учитель класса может оценить ответ на задание, даже если задача не его
+	graded, err := svc.Grade(ctx, submission.ID, "teacher", "Teacher", 40, "")
+	require.NoError(t, err)
+	assert.False(t, graded.IsCorrect)
+	assert.Nil(t, graded.Feedback)
+	require.Len(t, listener.submissions, 1)
+	assert.False(t, listener.submissions[0].IsCorrect)
+
+	_, err = svc.Grade(ctx, "missing", "teacher", "Teacher", 40, "")
+	assert.Error(t, err)
+}
+
+func TestGradingService_Override(t *testing.T) {
+	ctx := context.Background()
+	svc, taskService, submissions, listener, _ := newGradingFixture(t)
+
+	require.NoError(t, taskService.Submit(ctx, &models.Submission{TaskID: "essay", UserID: "s1", Answer: "essay"}))
+	target := submissions.submissions[0]
+
+	_, err := svc.Override(ctx, target.ID, "stranger", "Teacher", 80, "")
+	assert.ErrorIs(t, err, ErrGradingForbidden)
+
+	graded, err := svc.Override(ctx, target.ID, "author", "Teacher", 40, "")
+	require.NoError(t, err)
+	assert.False(t, graded.IsCorrect)
+	assert.Len(t, listener.submissions, 1, "first grade is passed on")
+
+	_, err = svc.Override(ctx, target.ID, "author", "Teacher", 30, "")
+	require.NoError(t, err)
+	assert.Len(t, listener.submissions, 1, "still wrong, nothing to tell")
+
+	graded, err = svc.Override(ctx, target.ID, "admin", "Admin", 85, "Reconsidered")
+	require.NoError(t, err)
+	assert.True(t, graded.IsCorrect)
+	require.Len(t, listener.submissions, 2, "becoming correct counts as a solve")
+	assert.Equal(t, 85, *submissions.submissions[0].Score)
+
+	_, err = svc.Override(ctx, target.ID, "admin", "Admin", 20, "")
+	require.NoError(t, err)
+	assert.Len(t, listener.submissions, 2)
+
+	require.NoError(t, taskService.Submit(ctx, &models.Submission{TaskID: "auto", UserID: "s1", Answer: "41"}))
+	auto := submissions.submissions[len(submissions.submissions)-1]
+	_, err = svc.Override(ctx, auto.ID, "admin", "Admin", 100, "")
+	assert.ErrorIs(t, err, ErrInvalidGrade)
+
+	_, err = svc.Override(ctx, target.ID, "admin", "Admin", -1, "")
+	assert.ErrorIs(t, err, ErrInvalidGrade)
+}
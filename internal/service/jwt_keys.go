@@ -0,0 +1,247 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultJWTIssuer   = "learning-platform"
+	defaultJWTAudience = "learning-platform-api"
+)
+
+var ErrInvalidAccessToken = errors.New("invalid or expired token")
+
+// SigningKey is one key of the key set. ID is sent as the kid header.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Signer crypto.Signer
+}
+
+// ParseSigningKey reads an RSA (signs RS256) or Ed25519 (signs EdDSA) private
+// key in PEM form.
+func ParseSigningKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %s: no PEM data", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s: %w", id, err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("jwt key %s: RSA keys need at least 2048 bits", id)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Signer: key}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Signer: key}, nil
+	default:
+		return nil, fmt.Errorf("jwt key %s: only RSA and Ed25519 keys are supported", id)
+	}
+}
+
+// GenerateSigningKey creates an Ed25519 key that only lives in memory.
+func GenerateSigningKey(id string) (*SigningKey, error) {
+	_, key, err := ed25519.GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Signer: key}, nil
+}
+
+// KeySet signs access tokens with its active key and verifies tokens signed
+// by any of its keys. Rotating means adding a new key, making it active and
+// dropping the old one once the tokens it signed have expired.
+type KeySet struct {
+	issuer   string
+	audience string
+	active   *SigningKey
+	keys     map[string]*SigningKey
+	order    []string
+}
+
+func NewKeySet(issuer, audience string, keys []*SigningKey, activeID string) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: no signing keys")
+	}
+	// an empty issuer or audience turns the matching check in Parse off
+	if issuer == "" || audience == "" {
+		return nil, errors.New("jwt: issuer and audience are required")
+	}
+
+	set := &KeySet{
+		issuer:   issuer,
+		audience: audience,
+		keys:     make(map[string]*SigningKey, len(keys)),
+	}
+	for _, key := range keys {
+		if _, dup := set.keys[key.ID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", key.ID)
+		}
+		set.keys[key.ID] = key
+		set.order = append(set.order, key.ID)
+	}
+
+	if activeID == "" {
+		activeID = keys[0].ID
+	}
+	active, ok := set.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: active key %q is not in the key set", activeID)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// LoadKeySetFromEnv builds the key set from JWT_SIGNING_KEYS, a comma
+// separated list of PEM files whose names (without extension) are the key
+// ids. JWT_ACTIVE_KEY_ID picks the signing key, by default the first one.
+// Without keys an in-memory key is generated, so tokens do not survive a
+// restart; that is only meant for development. The issuer is JWT_ISSUER,
+// then PUBLIC_URL, then a fixed default.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		issuer = os.Getenv("PUBLIC_URL")
+	}
+	if issuer == "" {
+		issuer = defaultJWTIssuer
+	}
+	audience := os.Getenv("JWT_AUDIENCE")
+	if audience == "" {
+		audience = defaultJWTAudience
+	}
+
+	var keys []*SigningKey
+	for _, path := range strings.Split(os.Getenv("JWT_SIGNING_KEYS"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := ParseSigningKey(id, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		log.Printf("[JWT] JWT_SIGNING_KEYS is not set, using a temporary key; tokens stop working on restart")
+		key, err := GenerateSigningKey("dev")
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(issuer, audience, keys, os.Getenv("JWT_ACTIVE_KEY_ID"))
+}
+
+// Sign adds iss, aud and the kid header and signs with the active key.
+func (k *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	claims["iss"] = k.issuer
+	claims["aud"] = k.audience
+
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	token.Header["kid"] = k.active.ID
+	return token.SignedString(k.active.Signer)
+}
+
+// Parse verifies the signature with the key named by the kid header and
+// checks expiry, issuer and audience.
+func (k *KeySet) Parse(tokenStr string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		// the algorithm is bound to the key, not taken from the token
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("key %q does not sign %s", kid, token.Method.Alg())
+		}
+		return key.Signer.Public(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(k.issuer),
+		jwt.WithAudience(k.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidAccessToken
+	}
+	return claims, nil
+}
+
+// JWK is a public key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys of all keys in the set, so other services can
+// verify tokens without holding a secret.
+func (k *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(k.order))}
+	for _, id := range k.order {
+		key := k.keys[id]
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.Signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	leaderboardKeyPrefix      = "leaderboard:"
+	leaderboardRebuildLockKey = "leaderboard:rebuild:lock"
+	leaderboardRebuildLockTTL = 30 * time.Minute
+
+	// weeklyLeaderboardTTL keeps a few past weeks around for history.
+	weeklyLeaderboardTTL = 5 * 7 * 24 * time.Hour
+
+	// leaderboardPointsPerWeight turns a difficulty weight into points.
+	leaderboardPointsPerWeight = 10
+
+	DefaultLeaderboardSize = 10
+	MaxLeaderboardSize     = 100
+)
+
+var (
+	ErrInvalidLeaderboard   = errors.New("invalid leaderboard")
+	ErrLeaderboardForbidden = errors.New("you don't have access to this leaderboard")
+)
+
+// LeaderboardService keeps score leaderboards in Redis sorted sets. Scores are
+// updated incrementally on every first correct solve of a task and rebuilt
+// from Postgres nightly, which also picks up classroom membership changes.
+type LeaderboardService struct {
+	submissions repository.ISubmissionRepository
+	classrooms  repository.IClassroomRepository
+	users       repository.IUserRepository
+	redis       *redis.Client
+}
+
+func NewLeaderboardService(submissions repository.ISubmissionRepository, classrooms repository.IClassroomRepository, users repository.IUserRepository, rdb *redis.Client) *LeaderboardService {
+	return &LeaderboardService{
+		submissions: submissions,
+		classrooms:  classrooms,
+		users:       users,
+		redis:       rdb,
+	}
+}
+
+func leaderboardPoints(d models.Difficulty) int64 {
+	return int64(difficultyWeight(d) * leaderboardPointsPerWeight)
+}
+
+func leaderboardKey(scope models.LeaderboardScope, id string) string {
+	if id == "" {
+		return leaderboardKeyPrefix + string(scope)
+	}
+	return leaderboardKeyPrefix + string(scope) + ":" + id
+}
+
+// leaderboardWeek returns the ISO week of t in UTC, e.g. "2025-W07".
+func leaderboardWeek(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// weekStart returns Monday 00:00 UTC of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func validLeaderboardWeek(week string) bool {
+	var year, w int
+	var rest string
+	n, _ := fmt.Sscanf(week, "%4d-W%2d%s", &year, &w, &rest)
+	return n == 2 && w >= 1 && w <= 53 && week == fmt.Sprintf("%d-W%02d", year, w)
+}
+
+// OnSubmission adds points to every leaderboard the user is on when a task is
+// solved for the first time. Repeated solves of the same task score nothing.
+func (s *LeaderboardService) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("leaderboard").Start(ctx, "LeaderboardService.OnSubmission")
+	defer span.End()
+
+	if !submission.IsCorrect || submission.Task == nil {
+		return nil
+	}
+
+	solves, err := s.submissions.CountCorrect(ctx, submission.UserID, submission.TaskID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if solves != 1 {
+		return nil
+	}
+
+	memberships, err := s.classrooms.FindStudentMemberships(ctx, submission.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	points := float64(leaderboardPoints(submission.Task.Difficulty))
+	weekly := leaderboardKey(models.LeaderboardScopeWeekly, leaderboardWeek(time.Now()))
+
+	_, err = s.redis.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZIncrBy(ctx, leaderboardKey(models.LeaderboardScopeGlobal, ""), points, submission.UserID)
+		p.ZIncrBy(ctx, leaderboardKey(models.LeaderboardScopeTopic, submission.Task.TopicID), points, submission.UserID)
+		p.ZIncrBy(ctx, weekly, points, submission.UserID)
+		p.Expire(ctx, weekly, weeklyLeaderboardTTL)
+		for _, m := range memberships {
+			p.ZIncrBy(ctx, leaderboardKey(models.LeaderboardScopeClassroom, m.ClassroomID), points, submission.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetLeaderboard returns the top entries of a leaderboard and the caller's
+// own position. scopeID is the topic or classroom ID, or an ISO week such as
+// "2025-W07" for the weekly scope (the current week when empty).
+func (s *LeaderboardService) GetLeaderboard(ctx context.Context, scope models.LeaderboardScope, scopeID, userID, role string, limit int) (*models.Leaderboard, error) {
+	ctx, span := otel.Tracer("leaderboard").Start(ctx, "LeaderboardService.GetLeaderboard")
+	defer span.End()
+
+	if limit <= 0 || limit > MaxLeaderboardSize {
+		limit = DefaultLeaderboardSize
+	}
+
+	switch scope {
+	case models.LeaderboardScopeGlobal:
+		scopeID = ""
+	case models.LeaderboardScopeWeekly:
+		if scopeID == "" {
+			scopeID = leaderboardWeek(time.Now())
+		} else if !validLeaderboardWeek(scopeID) {
+			return nil, fmt.Errorf("%w: week must look like 2025-W07", ErrInvalidLeaderboard)
+		}
+	case models.LeaderboardScopeTopic:
+		if scopeID == "" {
+			return nil, fmt.Errorf("%w: topic id is required", ErrInvalidLeaderboard)
+		}
+	case models.LeaderboardScopeClassroom:
+		if scopeID == "" {
+			return nil, fmt.Errorf("%w: classroom id is required", ErrInvalidLeaderboard)
+		}
+		if err := s.checkClassroomAccess(ctx, scopeID, userID, role); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidLeaderboard, scope)
+	}
+
+	key := leaderboardKey(scope, scopeID)
+
+	_, rangeSpan := otel.Tracer("leaderboard").Start(ctx, "Redis.ZRevRange")
+	top, err := s.redis.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+	rangeSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	board := &models.Leaderboard{
+		Scope:   scope,
+		ScopeID: scopeID,
+		Entries: make([]models.LeaderboardEntry, 0, len(top)),
+	}
+
+	ids := make([]string, 0, len(top)+1)
+	for i, z := range top {
+		member, _ := z.Member.(string)
+		board.Entries = append(board.Entries, models.LeaderboardEntry{
+			Rank:   int64(i) + 1,
+			UserID: member,
+			Score:  int64(z.Score),
+		})
+		ids = append(ids, member)
+	}
+
+	me, err := s.position(ctx, key, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if me != nil {
+		board.Me = me
+		ids = append(ids, userID)
+	}
+
+	_, usersSpan := otel.Tracer("leaderboard").Start(ctx, "Users.FindByIDs")
+	users, err := s.users.FindByIDs(ctx, ids)
+	usersSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.ID.String()] = u.DisplayName
+	}
+	for i := range board.Entries {
+		board.Entries[i].DisplayName = names[board.Entries[i].UserID]
+	}
+	if board.Me != nil {
+		board.Me.DisplayName = names[userID]
+	}
+
+	return board, nil
+}
+
+// position returns the user's rank and score, or nil if the user has not
+// scored on this leaderboard yet.
+func (s *LeaderboardService) position(ctx context.Context, key, userID string) (*models.LeaderboardEntry, error) {
+	rank, err := s.redis.ZRevRank(ctx, key, userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.redis.ZScore(ctx, key, userID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return &models.LeaderboardEntry{Rank: rank + 1, UserID: userID, Score: int64(score)}, nil
+}
+
+func (s *LeaderboardService) checkClassroomAccess(ctx context.Context, classroomID, userID, role string) error {
+	if _, err := s.classrooms.FindByID(ctx, classroomID); err != nil {
+		return err
+	}
+	if isAdmin(role) {
+		return nil
+	}
+
+	member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrLeaderboardForbidden
+	}
+
+	return nil
+}
+
+// buildLeaderboards computes every leaderboard from first solves. Only solves
+// made since the start of now's week count towards the weekly board.
+func buildLeaderboards(solves []models.SolvedTask, memberships []models.ClassroomMember, now time.Time) map[string]map[string]int64 {
+	classrooms := make(map[string][]string)
+	for _, m := range memberships {
+		classrooms[m.UserID] = append(classrooms[m.UserID], m.ClassroomID)
+	}
+
+	boards := make(map[string]map[string]int64)
+	add := func(key, userID string, points int64) {
+		if boards[key] == nil {
+			boards[key] = make(map[string]int64)
+		}
+		boards[key][userID] += points
+	}
+
+	since := weekStart(now)
+	weekly := leaderboardKey(models.LeaderboardScopeWeekly, leaderboardWeek(now))
+
+	for _, solve := range solves {
+		points := leaderboardPoints(solve.Difficulty)
+		add(leaderboardKey(models.LeaderboardScopeGlobal, ""), solve.UserID, points)
+		add(leaderboardKey(models.LeaderboardScopeTopic, solve.TopicID), solve.UserID, points)
+		for _, classroomID := range classrooms[solve.UserID] {
+			add(leaderboardKey(models.LeaderboardScopeClassroom, classroomID), solve.UserID, points)
+		}
+		if !solve.SolvedAt.Before(since) {
+			add(weekly, solve.UserID, points)
+		}
+	}
+
+	return boards
+}
+
+// Rebuild recomputes all leaderboards from Postgres and swaps them in. Only
+// one instance rebuilds at a time; the others return without doing anything.
+func (s *LeaderboardService) Rebuild(ctx context.Context, now time.Time) error {
+	ctx, span := otel.Tracer("leaderboard").Start(ctx, "LeaderboardService.Rebuild")
+	defer span.End()
+
+	locked, err := s.redis.SetNX(ctx, leaderboardRebuildLockKey, now.Unix(), leaderboardRebuildLockTTL).Result()
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !locked {
+		return nil
+	}
+	defer s.redis.Del(context.WithoutCancel(ctx), leaderboardRebuildLockKey)
+
+	solves, err := s.submissions.GetFirstSolves(ctx, "")
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	memberships, err := s.classrooms.FindStudentMemberships(ctx, "")
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	boards := buildLeaderboards(solves, memberships, now)
+	weekly := leaderboardKey(models.LeaderboardScopeWeekly, leaderboardWeek(now))
+
+	for key, scores := range boards {
+		tmp := key + ":rebuild"
+		members := make([]redis.Z, 0, len(scores))
+		for userID, score := range scores {
+			members = append(members, redis.Z{Score: float64(score), Member: userID})
+		}
+
+		_, err := s.redis.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.Del(ctx, tmp)
+			p.ZAdd(ctx, tmp, members...)
+			p.Rename(ctx, tmp, key)
+			if key == weekly {
+				p.Expire(ctx, key, weeklyLeaderboardTTL)
+			}
+			return nil
+		})
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	stale := []string{leaderboardKey(models.LeaderboardScopeGlobal, ""), weekly}
+	for _, scope := range []models.LeaderboardScope{models.LeaderboardScopeTopic, models.LeaderboardScopeClassroom} {
+		keys, err := s.scanKeys(ctx, leaderboardKey(scope, "*"))
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		stale = append(stale, keys...)
+	}
+
+	for _, key := range stale {
+		if _, ok := boards[key]; ok {
+			continue
+		}
+		if err := s.redis.Del(ctx, key).Err(); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *LeaderboardService) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := s.redis.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// nextRebuildAt returns the next time after now at hour:00 UTC.
+func nextRebuildAt(now time.Time, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunRebuildWorker rebuilds the leaderboards every night at hour:00 UTC until
+// ctx is cancelled. Missing leaderboards are rebuilt right away.
+func (s *LeaderboardService) RunRebuildWorker(ctx context.Context, hour int) {
+	exists, err := s.redis.Exists(ctx, leaderboardKey(models.LeaderboardScopeGlobal, "")).Result()
+	if err != nil {
+		log.Printf("leaderboard: failed to check leaderboards: %v", err)
+	} else if exists == 0 {
+		if err := s.Rebuild(ctx, time.Now()); err != nil {
+			log.Printf("leaderboard: failed to rebuild: %v", err)
+		}
+	}
+
+	for {
+		timer := time.NewTimer(time.Until(nextRebuildAt(time.Now(), hour)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			if err := s.Rebuild(ctx, now); err != nil {
+				log.Printf("leaderboard: failed to rebuild: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeSolvesRepo struct {
+	*fakeSubmissionRepo
+	solves []models.SolvedTask
+}
+
+func (f *fakeSolvesRepo) GetFirstSolves(ctx context.Context, userID string) ([]models.SolvedTask, error) {
+	var out []models.SolvedTask
+	for _, s := range f.solves {
+		if userID == "" || s.UserID == userID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestLeaderboardWeek(t *testing.T) {
+	// 2025-01-01 — среда первой ISO-недели 2025 года
+	wed := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2025-W01", leaderboardWeek(wed))
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), weekStart(wed))
+
+	assert.True(t, validLeaderboardWeek("2025-W07"))
+	assert.False(t, validLeaderboardWeek("2025-W7"))
+	assert.False(t, validLeaderboardWeek("2025-W54"))
+	assert.False(t, validLeaderboardWeek("2025-W07x"))
+
+	assert.Equal(t, time.Date(2025, 3, 11, 3, 0, 0, 0, time.UTC),
+		nextRebuildAt(time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC), 3))
+}
+
+func TestLeaderboardService_IncrementalScores(t *testing.T) {
+	ctx := context.Background()
+
+	users := newFakeUserRepo()
+	alice := &models.User{DisplayName: "Alice"}
+	bob := &models.User{DisplayName: "Bob"}
+	require.NoError(t, users.Create(ctx, alice))
+	require.NoError(t, users.Create(ctx, bob))
+	aliceID, bobID := alice.ID.String(), bob.ID.String()
+
+	classrooms := newFakeClassroomRepo()
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	classrooms.members[classroom.ID][aliceID] = &models.ClassroomMember{
+		ClassroomID: classroom.ID, UserID: aliceID, Role: models.ClassroomRoleStudent,
+	}
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["easy"] = &models.Task{ID: "easy", TopicID: "algebra", Difficulty: models.DifficultyEasy, CorrectAnswer: "1"}
+	tasks.byID["hard"] = &models.Task{ID: "hard", TopicID: "geometry", Difficulty: models.DifficultyHard, CorrectAnswer: "2"}
+
+	submissions := newFakeSubmissionRepo()
+	rdb := newTestRedis(t)
+	taskService := NewTaskService(tasks, submissions, rdb)
+	svc := NewLeaderboardService(submissions, classrooms, users, rdb)
+	taskService.AddListener(svc)
+
+	submit := func(userID, taskID, answer string) {
+		_, err := taskService.SubmitAnswer(ctx, taskID, userID, answer)
+		require.NoError(t, err)
+	}
+
+	submit(aliceID, "easy", "1")
+	submit(aliceID, "easy", "1") // повторное решение очков не даёт
+	submit(aliceID, "hard", "0")
+	submit(bobID, "hard", "2")
+
+	board, err := svc.GetLeaderboard(ctx, models.LeaderboardScopeGlobal, "", aliceID, "Student", 10)
+	require.NoError(t, err)
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, models.LeaderboardEntry{Rank: 1, UserID: bobID, DisplayName: "Bob", Score: 30}, board.Entries[0])
+	assert.Equal(t, int64(10), board.Entries[1].Score)
+	require.NotNil(t, board.Me)
+	assert.Equal(t, int64(2), board.Me.Rank)
+	assert.Equal(t, "Alice", board.Me.DisplayName)
+
+	board, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeGlobal, "", aliceID, "Student", 1)
+	require.NoError(t, err)
+	assert.Len(t, board.Entries, 1)
+	assert.Equal(t, int64(2), board.Me.Rank, "own rank is returned even outside the top")
+
+	board, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeTopic, "geometry", aliceID, "Student", 10)
+	require.NoError(t, err)
+	require.Len(t, board.Entries, 1)
+	assert.Nil(t, board.Me)
+
+	board, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeClassroom, classroom.ID, aliceID, "Student", 10)
+	require.NoError(t, err)
+	require.Len(t, board.Entries, 1)
+	assert.Equal(t, aliceID, board.Entries[0].UserID)
+
+	board, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeWeekly, "", bobID, "Student", 10)
+	require.NoError(t, err)
+	assert.Equal(t, leaderboardWeek(time.Now()), board.ScopeID)
+	assert.Len(t, board.Entries, 2)
+
+	_, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeClassroom, classroom.ID, bobID, "Student", 10)
+	assert.ErrorIs(t, err, ErrLeaderboardForbidden)
+
+	_, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeClassroom, classroom.ID, bobID, "Admin", 10)
+	assert.NoError(t, err)
+
+	_, err = svc.GetLeaderboard(ctx, models.LeaderboardScopeTopic, "", aliceID, "Student", 10)
+	assert.ErrorIs(t, err, ErrInvalidLeaderboard)
+
+	_, err = svc.GetLeaderboard(ctx, "monthly", "", aliceID, "Student", 10)
+	assert.ErrorIs(t, err, ErrInvalidLeaderboard)
+}
+
+func TestLeaderboardService_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 12, 3, 0, 0, 0, time.UTC)
+
+	classrooms := newFakeClassroomRepo()
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	classrooms.members[classroom.ID]["s1"] = &models.ClassroomMember{
+		ClassroomID: classroom.ID, UserID: "s1", Role: models.ClassroomRoleStudent,
+	}
+
+	submissions := &fakeSolvesRepo{
+		fakeSubmissionRepo: newFakeSubmissionRepo(),
+		solves: []models.SolvedTask{
+			{UserID: "s1", TaskID: "t1", TopicID: "algebra", Difficulty: models.DifficultyEasy, SolvedAt: now.AddDate(0, 0, -30)},
+			{UserID: "s1", TaskID: "t2", TopicID: "algebra", Difficulty: models.DifficultyExtreme, SolvedAt: now.Add(-time.Hour)},
+			{UserID: "s2", TaskID: "t1", TopicID: "algebra", Difficulty: models.DifficultyEasy, SolvedAt: now.Add(-time.Hour)},
+		},
+	}
+
+	rdb := newTestRedis(t)
+	svc := NewLeaderboardService(submissions, classrooms, newFakeUserRepo(), rdb)
+
+	// устаревшие данные: лишние очки и доска удалённой темы
+	require.NoError(t, rdb.ZAdd(ctx, "leaderboard:global", redis.Z{Member: "s1", Score: 999}).Err())
+	require.NoError(t, rdb.ZAdd(ctx, "leaderboard:topic:removed", redis.Z{Member: "s1", Score: 10}).Err())
+
+	require.NoError(t, svc.Rebuild(ctx, now))
+
+	global, err := rdb.ZRevRangeWithScores(ctx, "leaderboard:global", 0, -1).Result()
+	require.NoError(t, err)
+	require.Len(t, global, 2)
+	assert.Equal(t, "s1", global[0].Member)
+	assert.Equal(t, 60.0, global[0].Score)
+
+	weekly, err := rdb.ZScore(ctx, "leaderboard:weekly:"+leaderboardWeek(now), "s1").Result()
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, weekly, "only this week's solves count")
+
+	members, err := rdb.ZRange(ctx, "leaderboard:classroom:"+classroom.ID, 0, -1).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, members)
+
+	exists, err := rdb.Exists(ctx, "leaderboard:topic:removed", leaderboardRebuildLockKey).Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	// пока держится блокировка, повторная перестройка ничего не делает
+	require.NoError(t, rdb.Set(ctx, leaderboardRebuildLockKey, 1, time.Minute).Err())
+	submissions.solves = nil
+	require.NoError(t, svc.Rebuild(ctx, now))
+	count, err := rdb.ZCard(ctx, "leaderboard:global").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
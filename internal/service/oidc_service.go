@@ -0,0 +1,573 @@
+package service
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+)
+
+const (
+	// oidcStateTTL is how long the user has to sign in at the provider.
+	oidcStateTTL       = 10 * time.Minute
+	oidcStateKeyPrefix = "oidc:state:"
+	oidcDefaultScopes  = "openid email profile"
+)
+
+var (
+	ErrOIDCProviderNotFound = errors.New("unknown identity provider")
+	ErrOIDCInvalidState     = errors.New("invalid or expired login state, start the sign in again")
+	ErrOIDCEmailNotVerified = errors.New("the identity provider has not verified the email address")
+)
+
+// OIDCProviderConfig is one OpenID Connect provider, usually a school's
+// identity provider. Name is used in the URLs of the login endpoints.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvidersFromEnv reads the providers listed in OIDC_PROVIDERS. Each
+// provider is configured with OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _REDIRECT_URL and optionally _SCOPES.
+func OIDCProvidersFromEnv() []OIDCProviderConfig {
+	var configs []OIDCProviderConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+		}
+		scopes := os.Getenv(prefix + "SCOPES")
+		if scopes == "" {
+			scopes = oidcDefaultScopes
+		}
+		cfg.Scopes = strings.Fields(scopes)
+
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			log.Printf("[OIDC] provider %q is missing issuer, client id or redirect url, skipped", name)
+			continue
+		}
+		configs = append(configs, cfg)
+	}
+	return configs
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k oidcJWK) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// oidcBool accepts both true and "true", some providers send email_verified
+// as a string.
+type oidcBool bool
+
+func (b *oidcBool) UnmarshalJSON(data []byte) error {
+	*b = oidcBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+type oidcClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified oidcBool `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// oidcLoginState is kept in Redis between redirecting the user to the
+// provider and the callback. BindingHash ties the state to the browser that
+// started the login, so a callback link can't be replayed in another one.
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"codeVerifier"`
+	Nonce        string `json:"nonce"`
+	BindingHash  string `json:"bindingHash"`
+}
+
+type oidcProvider struct {
+	config OIDCProviderConfig
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// OIDCService signs users in through OpenID Connect providers with the
+// authorization code flow and PKCE. Provider accounts are linked to users by
+// subject, and on the first login by verified email.
+type OIDCService struct {
+	providers  map[string]*oidcProvider
+	identities repository.IUserIdentityRepository
+	users      repository.IUserRepository
+	auth       *AuthService
+	redis      *redis.Client
+	httpClient *http.Client
+}
+
+func NewOIDCService(configs []OIDCProviderConfig, identities repository.IUserIdentityRepository, users repository.IUserRepository, auth *AuthService, rdb *redis.Client) *OIDCService {
+	providers := make(map[string]*oidcProvider, len(configs))
+	for _, cfg := range configs {
+		providers[cfg.Name] = &oidcProvider{config: cfg}
+	}
+
+	return &OIDCService{
+		providers:  providers,
+		identities: identities,
+		users:      users,
+		auth:       auth,
+		redis:      rdb,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func randomURLToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Providers lists the names of the configured providers.
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *OIDCService) provider(name string) (*oidcProvider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+	return p, nil
+}
+
+func (s *OIDCService) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// discover loads the provider metadata once.
+func (s *OIDCService) discover(ctx context.Context, p *oidcProvider) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d oidcDiscovery
+	endpoint := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := s.getJSON(ctx, endpoint, &d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc: provider %s reports issuer %q", p.config.Name, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: provider %s metadata is incomplete", p.config.Name)
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// publicKey returns the signing key with the given id. The key set is fetched
+// again when the id is unknown, since providers rotate their keys.
+func (s *OIDCService) publicKey(ctx context.Context, p *oidcProvider, d *oidcDiscovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := s.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// AuthorizationURL starts a login: the user is sent to the returned URL and
+// comes back to the provider's redirect URL with a code and the state. The
+// returned binding has to be kept by the browser and passed to Callback.
+func (s *OIDCService) AuthorizationURL(ctx context.Context, providerName string) (string, string, error) {
+	ctx, span := otel.Tracer("oidc").Start(ctx, "OIDCService.AuthorizationURL")
+	defer span.End()
+
+	p, err := s.provider(providerName)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+
+	d, err := s.discover(ctx, p)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+
+	state, err := randomURLToken(32)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+	nonce, err := randomURLToken(32)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+	verifier, err := randomURLToken(32)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+	binding, err := randomURLToken(32)
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+
+	bindingHash := sha256.Sum256([]byte(binding))
+	payload, err := json.Marshal(oidcLoginState{
+		Provider:     p.config.Name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		BindingHash:  base64.RawURLEncoding.EncodeToString(bindingHash[:]),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+	if err := s.redis.Set(ctx, oidcStateKeyPrefix+state, payload, oidcStateTTL).Err(); err != nil {
+		span.RecordError(err)
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return d.AuthorizationEndpoint + separator + params.Encode(), binding, nil
+}
+
+// exchangeCode redeems the authorization code for the ID token.
+func (s *OIDCService) exchangeCode(ctx context.Context, p *oidcProvider, d *oidcDiscovery, code, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return "", fmt.Errorf("oidc: token endpoint returned %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return "", fmt.Errorf("oidc: code exchange failed: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("oidc: token response has no id_token")
+	}
+
+	return tokens.IDToken, nil
+}
+
+func (s *OIDCService) verifyIDToken(ctx context.Context, p *oidcProvider, d *oidcDiscovery, raw, nonce string) (*oidcClaims, error) {
+	var claims oidcClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.publicKey(ctx, p, d, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("oidc: id token nonce does not match")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("oidc: id token has no subject")
+	}
+
+	return &claims, nil
+}
+
+// linkUser finds the user of a provider account. On the first login the
+// account is linked to the user with the same verified email, or a new user
+// is created. Users created here have no password until they reset one.
+func (s *OIDCService) linkUser(ctx context.Context, providerName string, claims *oidcClaims) (*models.User, error) {
+	identity, err := s.identities.FindByProviderSubject(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		if err := s.identities.TouchLogin(ctx, identity.ID); err != nil {
+			return nil, err
+		}
+		user, err := s.users.FindByID(ctx, identity.UserID.String())
+		if err != nil || user == nil {
+			return nil, errors.New("user not found")
+		}
+		return user, nil
+	}
+
+	if claims.Email == "" || !bool(claims.EmailVerified) {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.users.FindByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		name := claims.Name
+		if name == "" {
+			name = strings.Split(claims.Email, "@")[0]
+		}
+		user = &models.User{
+			Email:       claims.Email,
+			DisplayName: name,
+			Status:      models.UserStatusActive,
+		}
+		if err := s.users.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	} else if user.Status != models.UserStatusActive {
+		// The provider has verified the address, no code needed. Whoever
+		// registered it never proved they own it, so their password and any
+		// sessions go: otherwise they could sign in to the account later.
+		if err := s.users.Update(ctx, user.ID.String(), map[string]interface{}{
+			"status":        models.UserStatusActive,
+			"password_hash": "",
+		}); err != nil {
+			return nil, err
+		}
+		if err := s.auth.tokens.RevokeAllForUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.identities.Create(ctx, &models.UserIdentity{
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// linked by a concurrent login in the meantime
+		return s.linkUser(ctx, providerName, claims)
+	}
+
+	return user, nil
+}
+
+// Callback finishes a login at the provider's redirect URL and signs the
+// user in. The binding must be the one AuthorizationURL returned for the
+// state. Users with two-factor authentication get a challenge token as with
+// a password login.
+func (s *OIDCService) Callback(ctx context.Context, providerName, code, state, binding string, client ClientInfo) (*LoginResult, error) {
+	ctx, span := otel.Tracer("oidc").Start(ctx, "OIDCService.Callback")
+	defer span.End()
+
+	p, err := s.provider(providerName)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	payload, err := s.redis.GetDel(ctx, oidcStateKeyPrefix+state).Bytes()
+	if err != nil {
+		span.RecordError(err)
+		return nil, ErrOIDCInvalidState
+	}
+
+	var login oidcLoginState
+	if err := json.Unmarshal(payload, &login); err != nil || login.Provider != p.config.Name {
+		span.RecordError(ErrOIDCInvalidState)
+		return nil, ErrOIDCInvalidState
+	}
+
+	bindingHash := sha256.Sum256([]byte(binding))
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(bindingHash[:])), []byte(login.BindingHash)) != 1 {
+		span.RecordError(ErrOIDCInvalidState)
+		return nil, ErrOIDCInvalidState
+	}
+
+	d, err := s.discover(ctx, p)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, exchangeSpan := otel.Tracer("oidc").Start(ctx, "OIDC.ExchangeCode")
+	rawIDToken, err := s.exchangeCode(ctx, p, d, code, login.CodeVerifier)
+	exchangeSpan.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	claims, err := s.verifyIDToken(ctx, p, d, rawIDToken, login.Nonce)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	user, err := s.linkUser(ctx, p.config.Name, claims)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return s.auth.signIn(ctx, user, client)
+}
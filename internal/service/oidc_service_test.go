@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+// mockOIDCProvider — локальный OpenID Connect провайдер: discovery, JWKS и
+// token endpoint с проверкой PKCE. Вход пользователя у провайдера заменяет login.
+type mockOIDCProvider struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	clientID string
+	secret   string
+
+	mu    sync.Mutex
+	codes map[string]mockOIDCCode
+
+	// audience подменяет aud в ID-токене
+	audience string
+}
+
+type mockOIDCCode struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	claims      jwt.MapClaims
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockOIDCProvider{
+		key:      key,
+		kid:      "key-1",
+		clientID: "learning-platform",
+		secret:   "client-secret",
+		codes:    make(map[string]mockOIDCCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": m.kid,
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", m.token)
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockOIDCProvider) config() OIDCProviderConfig {
+	return OIDCProviderConfig{
+		Name:         "school",
+		Issuer:       m.server.URL,
+		ClientID:     m.clientID,
+		ClientSecret: m.secret,
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+// login имитирует вход пользователя у провайдера по ссылке авторизации и
+// возвращает code и state для callback.
+func (m *mockOIDCProvider) login(t *testing.T, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, m.clientID, q.Get("client_id"))
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	code := uuid.NewString()
+	m.mu.Lock()
+	m.codes[code] = mockOIDCCode{
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		redirectURI: q.Get("redirect_uri"),
+		claims:      claims,
+	}
+	m.mu.Unlock()
+
+	return code, q.Get("state")
+}
+
+func (m *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	fail := func(reason string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": reason})
+	}
+
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != m.clientID || secret != m.secret {
+		fail("invalid_client")
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		fail("unsupported_grant_type")
+		return
+	}
+
+	m.mu.Lock()
+	grant, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || grant.redirectURI != r.PostForm.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(verifier[:]) != grant.challenge {
+		fail("invalid_grant")
+		return
+	}
+
+	audience := m.clientID
+	if m.audience != "" {
+		audience = m.audience
+	}
+	claims := jwt.MapClaims{
+		"iss":   m.server.URL,
+		"aud":   audience,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for k, v := range grant.claims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		fail("server_error")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+type fakeUserIdentityRepo struct {
+	identities []*models.UserIdentity
+}
+
+func (f *fakeUserIdentityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	for _, i := range f.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeUserIdentityRepo) Create(ctx context.Context, identity *models.UserIdentity) (bool, error) {
+	if existing, _ := f.FindByProviderSubject(ctx, identity.Provider, identity.Subject); existing != nil {
+		return false, nil
+	}
+	identity.ID = uuid.New()
+	f.identities = append(f.identities, identity)
+	return true, nil
+}
+func (f *fakeUserIdentityRepo) TouchLogin(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func newOIDCFixture(t *testing.T) (*OIDCService, *mockOIDCProvider, *fakeUserRepo, *fakeTokenRepo, *fakeUserIdentityRepo) {
+	t.Helper()
+
+	provider := newMockOIDCProvider(t)
+	users := newFakeUserRepo()
+	tokens := newFakeTokenRepo()
+	identities := &fakeUserIdentityRepo{}
+	auth := NewAuthService(users, nil, tokens, nil, newFakeRecoveryCodeRepo(), nil, nil, newTestKeySet(t))
+
+	svc := NewOIDCService([]OIDCProviderConfig{provider.config()}, identities, users, auth, newTestRedis(t))
+	return svc, provider, users, tokens, identities
+}
+
+func oidcLogin(t *testing.T, svc *OIDCService, provider *mockOIDCProvider, claims jwt.MapClaims) (*LoginResult, error) {
+	t.Helper()
+
+	authURL, binding, err := svc.AuthorizationURL(context.Background(), "school")
+	require.NoError(t, err)
+	code, state := provider.login(t, authURL, claims)
+	return svc.Callback(context.Background(), "school", code, state, binding, ClientInfo{IPAddress: "10.0.0.1"})
+}
+
+func TestOIDCService_Callback_CreatesAndLinksUser(t *testing.T) {
+	svc, provider, users, tokens, identities := newOIDCFixture(t)
+
+	result, err := oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":            "student-42",
+		"email":          "pupil@school.example",
+		"email_verified": true,
+		"name":           "Pupil",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.Len(t, tokens.byHash, 1)
+
+	user := users.byEmail["pupil@school.example"]
+	require.NotNil(t, user)
+	assert.Equal(t, "Pupil", user.DisplayName)
+	assert.Equal(t, models.UserStatusActive, user.Status)
+	require.Len(t, identities.identities, 1)
+	assert.Equal(t, user.ID, identities.identities[0].UserID)
+
+	// повторный вход находит пользователя по subject, даже если почта изменилась
+	_, err = oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":            "student-42",
+		"email":          "renamed@school.example",
+		"email_verified": "true",
+	})
+	require.NoError(t, err)
+	assert.Len(t, users.byID, 1)
+	assert.Len(t, identities.identities, 1)
+}
+
+func TestOIDCService_Callback_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	svc, provider, users, _, identities := newOIDCFixture(t)
+
+	existing := &models.User{Email: "teacher@school.example", Status: models.UserStatusPending}
+	require.NoError(t, users.Create(context.Background(), existing))
+
+	_, err := oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":   "teacher-1",
+		"email": "teacher@school.example",
+	})
+	assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+	assert.Empty(t, identities.identities)
+
+	_, err = oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":            "teacher-1",
+		"email":          "teacher@school.example",
+		"email_verified": true,
+	})
+	require.NoError(t, err)
+	require.Len(t, identities.identities, 1)
+	assert.Equal(t, existing.ID, identities.identities[0].UserID)
+	assert.Equal(t, models.UserStatusActive, existing.Status, "the provider verified the address")
+	assert.Len(t, users.byID, 1)
+}
+
+func TestOIDCService_Callback_PendingAccountLosesRegistrantPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, provider, users, tokens, _ := newOIDCFixture(t)
+
+	// кто-то заранее зарегистрировал чужую почту и не подтвердил её
+	squatter := &models.User{Email: "victim@school.example", PasswordHash: "$2a$10$squatter", Status: models.UserStatusPending}
+	require.NoError(t, users.Create(ctx, squatter))
+	require.NoError(t, tokens.Save(ctx, &models.RefreshToken{UserID: squatter.ID, TokenHash: "squatter-session", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	_, err := oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":            "victim-1",
+		"email":          "victim@school.example",
+		"email_verified": true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.UserStatusActive, squatter.Status)
+	assert.Empty(t, squatter.PasswordHash, "the registrant never proved they own the address")
+	assert.Contains(t, tokens.revokeAllFor, squatter.ID)
+	assert.NotContains(t, tokens.byHash, "squatter-session")
+
+	_, err = svc.auth.Login(ctx, "victim@school.example", "squatter-password", ClientInfo{})
+	assert.Error(t, err)
+}
+
+func TestOIDCService_Callback_TwoFactorChallenge(t *testing.T) {
+	svc, provider, users, tokens, _ := newOIDCFixture(t)
+
+	secret := "JBSWY3DPEHPK3PXP"
+	enabledAt := time.Now()
+	require.NoError(t, users.Create(context.Background(), &models.User{
+		Email:         "admin@school.example",
+		Role:          models.UserRoleAdmin,
+		Status:        models.UserStatusActive,
+		TOTPSecret:    &secret,
+		TOTPEnabledAt: &enabledAt,
+	}))
+
+	result, err := oidcLogin(t, svc, provider, jwt.MapClaims{
+		"sub":            "admin-1",
+		"email":          "admin@school.example",
+		"email_verified": true,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.ChallengeToken)
+	assert.Empty(t, result.AccessToken)
+	assert.Empty(t, tokens.byHash)
+}
+
+func TestOIDCService_Callback_Rejects(t *testing.T) {
+	ctx := context.Background()
+	svc, provider, _, tokens, _ := newOIDCFixture(t)
+	claims := jwt.MapClaims{"sub": "student-1", "email": "s@school.example", "email_verified": true}
+
+	_, _, err := svc.AuthorizationURL(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrOIDCProviderNotFound)
+
+	authURL, binding, err := svc.AuthorizationURL(ctx, "school")
+	require.NoError(t, err)
+	code, state := provider.login(t, authURL, claims)
+
+	_, err = svc.Callback(ctx, "school", code, "forged-state", binding, ClientInfo{})
+	assert.ErrorIs(t, err, ErrOIDCInvalidState)
+
+	_, err = svc.Callback(ctx, "school", code, state, binding, ClientInfo{})
+	require.NoError(t, err)
+
+	_, err = svc.Callback(ctx, "school", code, state, binding, ClientInfo{})
+	assert.ErrorIs(t, err, ErrOIDCInvalidState, "state is single use")
+
+	// ссылку с code и state, начатую в другом браузере, подсунуть нельзя
+	authURL, _, err = svc.AuthorizationURL(ctx, "school")
+	require.NoError(t, err)
+	_, victimBinding, err := svc.AuthorizationURL(ctx, "school")
+	require.NoError(t, err)
+	code, state = provider.login(t, authURL, claims)
+	_, err = svc.Callback(ctx, "school", code, state, victimBinding, ClientInfo{})
+	assert.ErrorIs(t, err, ErrOIDCInvalidState)
+	_, err = svc.Callback(ctx, "school", code, state, "", ClientInfo{})
+	assert.ErrorIs(t, err, ErrOIDCInvalidState)
+
+	// код, полученный по другой ссылке, не проходит проверку PKCE
+	first, _, err := svc.AuthorizationURL(ctx, "school")
+	require.NoError(t, err)
+	second, secondBinding, err := svc.AuthorizationURL(ctx, "school")
+	require.NoError(t, err)
+	code, _ = provider.login(t, first, claims)
+	_, state = provider.login(t, second, claims)
+	_, err = svc.Callback(ctx, "school", code, state, secondBinding, ClientInfo{})
+	assert.ErrorContains(t, err, "invalid_grant")
+
+	// ID-токен для другого клиента
+	provider.audience = "another-client"
+	_, err = oidcLogin(t, svc, provider, claims)
+	assert.ErrorContains(t, err, "invalid id token")
+
+	assert.Len(t, tokens.byHash, 1)
+}
+
+func TestOIDCProvidersFromEnv(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "School, broken")
+	t.Setenv("OIDC_SCHOOL_ISSUER", "https://idp.school.example")
+	t.Setenv("OIDC_SCHOOL_CLIENT_ID", "client")
+	t.Setenv("OIDC_SCHOOL_REDIRECT_URL", "https://app.example/callback")
+	t.Setenv("OIDC_BROKEN_ISSUER", "https://idp.broken.example")
+
+	configs := OIDCProvidersFromEnv()
+	require.Len(t, configs, 1)
+	assert.Equal(t, "school", configs[0].Name)
+	assert.Equal(t, []string{"openid", "email", "profile"}, configs[0].Scopes)
+}
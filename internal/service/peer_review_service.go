@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	MaxPeerReviews    = 10
+	MaxRubricCriteria = 20
+	MaxCriterionScore = 100
+)
+
+var (
+	ErrInvalidRubric       = errors.New("invalid rubric")
+	ErrRubricNotFound      = errors.New("rubric not found")
+	ErrRubricLocked        = errors.New("rubric can't be changed after peer reviews were assigned")
+	ErrPeerReviewDisabled  = errors.New("peer review is not enabled for this task")
+	ErrNotEnoughPeers      = errors.New("not enough students answered the task")
+	ErrPeerReviewForbidden = errors.New("you can't access this peer review")
+	ErrInvalidPeerReview   = errors.New("invalid peer review")
+	ErrPeerReviewSubmitted = errors.New("peer review is already submitted")
+)
+
+// PeerReviewService lets students review each other's answers to MANUAL
+// tasks against the task's rubric. Every answer gets the rubric's number of
+// reviewers, never its own author, and reviewers do not learn whose answer
+// they score. Once all reviews of an answer are in, the average becomes its
+// peer score and grades it unless a teacher already did; teachers can still
+// override the grade through the GradingService.
+type PeerReviewService struct {
+	reviews     repository.IPeerReviewRepository
+	submissions repository.ISubmissionRepository
+	tasks       repository.ITaskRepository
+	grading     *GradingService
+}
+
+func NewPeerReviewService(reviews repository.IPeerReviewRepository, submissions repository.ISubmissionRepository, tasks repository.ITaskRepository, grading *GradingService) *PeerReviewService {
+	return &PeerReviewService{
+		reviews:     reviews,
+		submissions: submissions,
+		tasks:       tasks,
+		grading:     grading,
+	}
+}
+
+// findTaskForAuthor loads a task the user may manage peer review of: its
+// author or an admin.
+func (s *PeerReviewService) findTaskForAuthor(ctx context.Context, taskID, userID, role string) (*models.Task, error) {
+	task, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(role) && task.AuthorID != userID {
+		return nil, ErrPeerReviewForbidden
+	}
+	return task, nil
+}
+
+func validateRubric(task *models.Task, rubric *models.Rubric) error {
+	if task.GradingMode != models.GradingModeManual {
+		return fmt.Errorf("%w: peer review is only available for manually graded tasks", ErrInvalidRubric)
+	}
+	if rubric.PeerReviews < 0 || rubric.PeerReviews > MaxPeerReviews {
+		return fmt.Errorf("%w: peer reviews must be between 0 and %d", ErrInvalidRubric, MaxPeerReviews)
+	}
+	if len(rubric.Criteria) == 0 || len(rubric.Criteria) > MaxRubricCriteria {
+		return fmt.Errorf("%w: a rubric needs between 1 and %d criteria", ErrInvalidRubric, MaxRubricCriteria)
+	}
+	for i := range rubric.Criteria {
+		c := &rubric.Criteria[i]
+		c.Title = strings.TrimSpace(c.Title)
+		c.Description = strings.TrimSpace(c.Description)
+		if c.Title == "" {
+			return fmt.Errorf("%w: criterion title is required", ErrInvalidRubric)
+		}
+		if c.MaxPoints < 1 || c.MaxPoints > MaxCriterionScore {
+			return fmt.Errorf("%w: criterion points must be between 1 and %d", ErrInvalidRubric, MaxCriterionScore)
+		}
+		c.ID = ""
+		c.TaskID = task.ID
+		c.Position = i + 1
+	}
+	return nil
+}
+
+// GetRubric returns the rubric of a task.
+func (s *PeerReviewService) GetRubric(ctx context.Context, taskID string) (*models.Rubric, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.GetRubric")
+	defer span.End()
+
+	rubric, err := s.reviews.FindRubric(ctx, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if rubric == nil {
+		return nil, ErrRubricNotFound
+	}
+
+	return rubric, nil
+}
+
+// SaveRubric replaces the rubric of a task. Criteria are locked once reviews
+// have been assigned, since submitted scores refer to them.
+func (s *PeerReviewService) SaveRubric(ctx context.Context, userID, role string, rubric *models.Rubric) error {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.SaveRubric")
+	defer span.End()
+
+	task, err := s.findTaskForAuthor(ctx, rubric.TaskID, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := validateRubric(task, rubric); err != nil {
+		return err
+	}
+
+	assigned, err := s.reviews.CountByTask(ctx, task.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if assigned > 0 {
+		return ErrRubricLocked
+	}
+
+	if err := s.reviews.SaveRubric(ctx, rubric); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// pickReviewers chooses up to need reviewers for a submission from the pool,
+// skipping its author and reviewers it already has. Those with the fewest
+// assigned reviews come first; ties go round-robin from the author's place in
+// the pool so a fresh round spreads reviews evenly.
+func pickReviewers(pool []string, load map[string]int, author string, existing map[string]bool, need int) []string {
+	start := 0
+	for i, id := range pool {
+		if id == author {
+			start = i + 1
+			break
+		}
+	}
+
+	candidates := make([]string, 0, len(pool))
+	for i := range pool {
+		id := pool[(start+i)%len(pool)]
+		if id != author && !existing[id] {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return load[candidates[i]] < load[candidates[j]] })
+	if len(candidates) > need {
+		candidates = candidates[:need]
+	}
+	return candidates
+}
+
+// AssignReviewers gives every answer pending review the rubric's number of
+// reviewers, picked among the other students who answered the task. It can
+// be run again as more answers come in; it returns the number of reviews
+// assigned.
+func (s *PeerReviewService) AssignReviewers(ctx context.Context, taskID, userID, role string) (int, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.AssignReviewers")
+	defer span.End()
+
+	if _, err := s.findTaskForAuthor(ctx, taskID, userID, role); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	rubric, err := s.reviews.FindRubric(ctx, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	if rubric == nil || rubric.PeerReviews == 0 {
+		return 0, ErrPeerReviewDisabled
+	}
+
+	latest, err := s.reviews.FindLatestSubmissions(ctx, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	if len(latest) <= rubric.PeerReviews {
+		return 0, fmt.Errorf("%w: at least %d students must answer before %d peers can review each answer",
+			ErrNotEnoughPeers, rubric.PeerReviews+1, rubric.PeerReviews)
+	}
+
+	existing, err := s.reviews.FindByTask(ctx, taskID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	load := make(map[string]int)
+	assigned := make(map[string]map[string]bool)
+	for _, r := range existing {
+		load[r.ReviewerID]++
+		if assigned[r.SubmissionID] == nil {
+			assigned[r.SubmissionID] = make(map[string]bool)
+		}
+		assigned[r.SubmissionID][r.ReviewerID] = true
+	}
+
+	sort.Slice(latest, func(i, j int) bool {
+		if !latest[i].CreatedAt.Equal(latest[j].CreatedAt) {
+			return latest[i].CreatedAt.Before(latest[j].CreatedAt)
+		}
+		return latest[i].ID < latest[j].ID
+	})
+	pool := make([]string, 0, len(latest))
+	for _, sub := range latest {
+		pool = append(pool, sub.UserID)
+	}
+
+	now := time.Now()
+	var created []models.PeerReview
+	for _, sub := range latest {
+		if sub.Status != models.SubmissionStatusPendingReview {
+			continue
+		}
+		need := rubric.PeerReviews - len(assigned[sub.ID])
+		if need <= 0 {
+			continue
+		}
+		for _, reviewer := range pickReviewers(pool, load, sub.UserID, assigned[sub.ID], need) {
+			load[reviewer]++
+			created = append(created, models.PeerReview{
+				SubmissionID: sub.ID,
+				ReviewerID:   reviewer,
+				Status:       models.PeerReviewAssigned,
+				AssignedAt:   now,
+			})
+		}
+	}
+
+	if err := s.reviews.CreateReviews(ctx, created); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return len(created), nil
+}
+
+// GetMyReviews returns the reviews assigned to the user, oldest first.
+func (s *PeerReviewService) GetMyReviews(ctx context.Context, userID string) ([]models.PeerReview, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.GetMyReviews")
+	defer span.End()
+
+	reviews, err := s.reviews.FindByReviewer(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// GetReview returns a review assigned to the user with the rubric to score
+// the answer by.
+func (s *PeerReviewService) GetReview(ctx context.Context, id, userID string) (*models.PeerReview, *models.Rubric, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.GetReview")
+	defer span.End()
+
+	review, err := s.reviews.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+	if review.ReviewerID != userID {
+		return nil, nil, ErrPeerReviewForbidden
+	}
+
+	rubric, err := s.GetRubric(ctx, review.Submission.TaskID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	return review, rubric, nil
+}
+
+// SubmitReview scores every criterion of the rubric for an assigned review.
+func (s *PeerReviewService) SubmitReview(ctx context.Context, id, userID string, points map[string]int, comment string) (*models.PeerReview, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.SubmitReview")
+	defer span.End()
+
+	review, rubric, err := s.GetReview(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == models.PeerReviewSubmitted {
+		return nil, ErrPeerReviewSubmitted
+	}
+
+	if len(points) != len(rubric.Criteria) {
+		return nil, fmt.Errorf("%w: every criterion of the rubric must be scored once", ErrInvalidPeerReview)
+	}
+	scores := make([]models.PeerReviewScore, 0, len(rubric.Criteria))
+	for _, c := range rubric.Criteria {
+		p, ok := points[c.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: criterion %q is not scored", ErrInvalidPeerReview, c.Title)
+		}
+		if p < 0 || p > c.MaxPoints {
+			return nil, fmt.Errorf("%w: %q is scored from 0 to %d", ErrInvalidPeerReview, c.Title, c.MaxPoints)
+		}
+		scores = append(scores, models.PeerReviewScore{ReviewID: review.ID, CriterionID: c.ID, Points: p})
+	}
+
+	now := time.Now()
+	review.Status = models.PeerReviewSubmitted
+	review.Comment = strings.TrimSpace(comment)
+	review.SubmittedAt = &now
+	review.Scores = scores
+
+	submitted, err := s.reviews.Submit(ctx, review)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !submitted {
+		return nil, ErrPeerReviewSubmitted
+	}
+
+	if err := s.aggregate(ctx, review.SubmissionID, rubric); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// peerScore averages the reviews as percentages of the rubric's points.
+func peerScore(reviews []models.PeerReview, maxPoints int) int {
+	if len(reviews) == 0 || maxPoints == 0 {
+		return 0
+	}
+	total := 0.0
+	for i := range reviews {
+		total += float64(reviews[i].Points()) / float64(maxPoints) * 100
+	}
+	return int(math.Round(total / float64(len(reviews))))
+}
+
+// aggregate stores the peer score of a submission once all its reviews are
+// in and grades it if no teacher has.
+func (s *PeerReviewService) aggregate(ctx context.Context, submissionID string, rubric *models.Rubric) error {
+	reviews, err := s.reviews.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+	if len(reviews) < rubric.PeerReviews {
+		return nil
+	}
+	for _, r := range reviews {
+		if r.Status != models.PeerReviewSubmitted {
+			return nil
+		}
+	}
+
+	score := peerScore(reviews, rubric.MaxPoints())
+	if err := s.reviews.SavePeerScore(ctx, submissionID, score); err != nil {
+		return err
+	}
+
+	submission, err := s.submissions.FindByID(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+	if submission.Status != models.SubmissionStatusPendingReview {
+		return nil
+	}
+
+	now := time.Now()
+	submission.PeerScore = &score
+	submission.Status = models.SubmissionStatusGraded
+	submission.IsCorrect = score >= models.ManualPassingScore
+	submission.Score = &score
+	submission.GradedAt = &now
+
+	graded, err := s.submissions.Grade(ctx, submission)
+	if err != nil {
+		return err
+	}
+	if graded {
+		s.grading.tasks.NotifyListeners(ctx, submission)
+	}
+
+	return nil
+}
+
+// GetSubmissionReviews returns the reviews of a submission. Its author sees
+// the submitted ones without reviewer identities; teachers who may grade it
+// see all of them.
+func (s *PeerReviewService) GetSubmissionReviews(ctx context.Context, submissionID, userID, role string) ([]models.PeerReview, error) {
+	ctx, span := otel.Tracer("peer-review").Start(ctx, "PeerReviewService.GetSubmissionReviews")
+	defer span.End()
+
+	submission, err := s.submissions.FindByID(ctx, submissionID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	grader, err := s.grading.canGrade(ctx, submission, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !grader && submission.UserID != userID {
+		return nil, ErrPeerReviewForbidden
+	}
+
+	reviews, err := s.reviews.FindBySubmission(ctx, submissionID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if grader {
+		return reviews, nil
+	}
+
+	anonymous := make([]models.PeerReview, 0, len(reviews))
+	for _, r := range reviews {
+		if r.Status != models.PeerReviewSubmitted {
+			continue
+		}
+		r.ReviewerID = ""
+		r.Reviewer = nil
+		anonymous = append(anonymous, r)
+	}
+
+	return anonymous, nil
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakePeerReviewRepo struct {
+	submissions *fakeSubmissionRepo
+	rubrics     map[string]*models.Rubric
+	reviews     []models.PeerReview
+}
+
+func newFakePeerReviewRepo(submissions *fakeSubmissionRepo) *fakePeerReviewRepo {
+	return &fakePeerReviewRepo{submissions: submissions, rubrics: make(map[string]*models.Rubric)}
+}
+
+func (f *fakePeerReviewRepo) FindRubric(ctx context.Context, taskID string) (*models.Rubric, error) {
+	r, ok := f.rubrics[taskID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (f *fakePeerReviewRepo) SaveRubric(ctx context.Context, rubric *models.Rubric) error {
+	for i := range rubric.Criteria {
+		rubric.Criteria[i].ID = fmt.Sprintf("%s-c%d", rubric.TaskID, i+1)
+	}
+	copied := *rubric
+	f.rubrics[rubric.TaskID] = &copied
+	return nil
+}
+
+func (f *fakePeerReviewRepo) taskOf(submissionID string) string {
+	for _, s := range f.submissions.submissions {
+		if s.ID == submissionID {
+			return s.TaskID
+		}
+	}
+	return ""
+}
+
+func (f *fakePeerReviewRepo) CountByTask(ctx context.Context, taskID string) (int64, error) {
+	reviews, _ := f.FindByTask(ctx, taskID)
+	return int64(len(reviews)), nil
+}
+
+func (f *fakePeerReviewRepo) FindLatestSubmissions(ctx context.Context, taskID string) ([]models.Submission, error) {
+	latest := make(map[string]models.Submission)
+	var order []string
+	for _, s := range f.submissions.submissions {
+		if s.TaskID != taskID {
+			continue
+		}
+		if _, ok := latest[s.UserID]; !ok {
+			order = append(order, s.UserID)
+		}
+		latest[s.UserID] = s
+	}
+	out := make([]models.Submission, 0, len(order))
+	for _, id := range order {
+		out = append(out, latest[id])
+	}
+	return out, nil
+}
+
+func (f *fakePeerReviewRepo) FindByTask(ctx context.Context, taskID string) ([]models.PeerReview, error) {
+	var out []models.PeerReview
+	for _, r := range f.reviews {
+		if f.taskOf(r.SubmissionID) == taskID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePeerReviewRepo) CreateReviews(ctx context.Context, reviews []models.PeerReview) error {
+	for _, r := range reviews {
+		r.ID = fmt.Sprintf("review-%d", len(f.reviews)+1)
+		f.reviews = append(f.reviews, r)
+	}
+	return nil
+}
+
+func (f *fakePeerReviewRepo) FindByID(ctx context.Context, id string) (*models.PeerReview, error) {
+	for _, r := range f.reviews {
+		if r.ID == id {
+			sub, err := f.submissions.FindByID(ctx, r.SubmissionID)
+			if err != nil {
+				return nil, err
+			}
+			r.Submission = sub
+			return &r, nil
+		}
+	}
+	return nil, fmt.Errorf("record not found")
+}
+
+func (f *fakePeerReviewRepo) FindByReviewer(ctx context.Context, reviewerID string) ([]models.PeerReview, error) {
+	var out []models.PeerReview
+	for _, r := range f.reviews {
+		if r.ReviewerID == reviewerID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePeerReviewRepo) FindBySubmission(ctx context.Context, submissionID string) ([]models.PeerReview, error) {
+	var out []models.PeerReview
+	for _, r := range f.reviews {
+		if r.SubmissionID == submissionID {
+			r.Reviewer = &models.User{DisplayName: "Reviewer " + r.ReviewerID}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePeerReviewRepo) Submit(ctx context.Context, review *models.PeerReview) (bool, error) {
+	for i := range f.reviews {
+		if f.reviews[i].ID == review.ID && f.reviews[i].Status == models.PeerReviewAssigned {
+			f.reviews[i].Status = review.Status
+			f.reviews[i].Comment = review.Comment
+			f.reviews[i].SubmittedAt = review.SubmittedAt
+			f.reviews[i].Scores = review.Scores
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakePeerReviewRepo) SavePeerScore(ctx context.Context, submissionID string, score int) error {
+	for i := range f.submissions.submissions {
+		if f.submissions.submissions[i].ID == submissionID {
+			f.submissions.submissions[i].PeerScore = &score
+		}
+	}
+	return nil
+}
+
+type peerReviewFixture struct {
+	svc         *PeerReviewService
+	grading     *GradingService
+	tasks       *TaskService
+	repo        *fakePeerReviewRepo
+	submissions *fakeSubmissionRepo
+	listener    *countingListener
+}
+
+func newPeerReviewFixture(t *testing.T) *peerReviewFixture {
+	t.Helper()
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["proof"] = &models.Task{
+		ID: "proof", AuthorID: "author", Status: models.TaskStatusPublished,
+		AnswerType: models.AnswerTypeText, GradingMode: models.GradingModeManual,
+	}
+	tasks.byID["auto"] = &models.Task{ID: "auto", AuthorID: "author", Status: models.TaskStatusPublished, CorrectAnswer: "42"}
+
+	submissions := newFakeSubmissionRepo()
+	submissions.tasks = tasks
+	taskService := NewTaskService(tasks, submissions, newTestRedis(t))
+	listener := &countingListener{}
+	taskService.AddListener(listener)
+
+	grading := NewGradingService(submissions, newFakeAssignmentRepo(), newFakeClassroomRepo(), taskService)
+	repo := newFakePeerReviewRepo(submissions)
+
+	return &peerReviewFixture{
+		svc:         NewPeerReviewService(repo, submissions, tasks, grading),
+		grading:     grading,
+		tasks:       taskService,
+		repo:        repo,
+		submissions: submissions,
+		listener:    listener,
+	}
+}
+
+func (f *peerReviewFixture) submit(t *testing.T, users ...string) {
+	t.Helper()
+	for _, u := range users {
+		require.NoError(t, f.tasks.Submit(context.Background(), &models.Submission{TaskID: "proof", UserID: u, Answer: "proof by " + u}))
+	}
+}
+
+func (f *peerReviewFixture) saveRubric(t *testing.T, peers int) *models.Rubric {
+	t.Helper()
+	rubric := &models.Rubric{TaskID: "proof", PeerReviews: peers, Criteria: []models.RubricCriterion{
+		{Title: "Correctness", MaxPoints: 6},
+		{Title: " Clarity ", MaxPoints: 4},
+	}}
+	require.NoError(t, f.svc.SaveRubric(context.Background(), "author", "Teacher", rubric))
+	return rubric
+}
+
+func TestPickReviewers(t *testing.T) {
+	pool := []string{"a", "b", "c", "d"}
+	load := map[string]int{"b": 2}
+
+	picked := pickReviewers(pool, load, "a", map[string]bool{"c": true}, 2)
+	assert.Equal(t, []string{"d", "b"}, picked, "author and existing reviewers are skipped, least loaded first")
+
+	assert.Len(t, pickReviewers(pool, nil, "a", nil, 10), 3)
+}
+
+func TestPeerScore(t *testing.T) {
+	reviews := []models.PeerReview{
+		{Scores: []models.PeerReviewScore{{Points: 6}, {Points: 4}}},
+		{Scores: []models.PeerReviewScore{{Points: 3}, {Points: 2}}},
+	}
+	assert.Equal(t, 75, peerScore(reviews, 10))
+	assert.Equal(t, 0, peerScore(nil, 10))
+}
+
+func TestPeerReviewService_SaveRubric(t *testing.T) {
+	ctx := context.Background()
+	f := newPeerReviewFixture(t)
+
+	rubric := f.saveRubric(t, 2)
+	assert.Equal(t, "Clarity", rubric.Criteria[1].Title)
+	assert.Equal(t, 2, rubric.Criteria[1].Position)
+
+	saved, err := f.svc.GetRubric(ctx, "proof")
+	require.NoError(t, err)
+	assert.Equal(t, 10, saved.MaxPoints())
+
+	_, err = f.svc.GetRubric(ctx, "auto")
+	assert.ErrorIs(t, err, ErrRubricNotFound)
+
+	err = f.svc.SaveRubric(ctx, "other", "Teacher", &models.Rubric{TaskID: "proof", PeerReviews: 2, Criteria: rubric.Criteria})
+	assert.ErrorIs(t, err, ErrPeerReviewForbidden)
+
+	err = f.svc.SaveRubric(ctx, "author", "Teacher", &models.Rubric{TaskID: "auto", PeerReviews: 2, Criteria: rubric.Criteria})
+	assert.ErrorIs(t, err, ErrInvalidRubric, "auto-graded tasks have no peer review")
+
+	err = f.svc.SaveRubric(ctx, "author", "Teacher", &models.Rubric{TaskID: "proof", PeerReviews: 2})
+	assert.ErrorIs(t, err, ErrInvalidRubric)
+
+	err = f.svc.SaveRubric(ctx, "author", "Teacher", &models.Rubric{TaskID: "proof", PeerReviews: 2,
+		Criteria: []models.RubricCriterion{{Title: "Too much", MaxPoints: 1000}}})
+	assert.ErrorIs(t, err, ErrInvalidRubric)
+
+	// после распределения рецензий рубрику менять нельзя
+	f.submit(t, "s1", "s2", "s3")
+	_, err = f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	require.NoError(t, err)
+	err = f.svc.SaveRubric(ctx, "admin", "Admin", &models.Rubric{TaskID: "proof", PeerReviews: 1, Criteria: rubric.Criteria})
+	assert.ErrorIs(t, err, ErrRubricLocked)
+}
+
+func TestPeerReviewService_AssignReviewers(t *testing.T) {
+	ctx := context.Background()
+	f := newPeerReviewFixture(t)
+
+	_, err := f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	assert.ErrorIs(t, err, ErrPeerReviewDisabled)
+
+	f.saveRubric(t, 2)
+	f.submit(t, "s1", "s2")
+	_, err = f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	assert.ErrorIs(t, err, ErrNotEnoughPeers)
+
+	// повторная отправка учитывается только последней
+	f.submit(t, "s3", "s4", "s1")
+	_, err = f.svc.AssignReviewers(ctx, "proof", "s1", "Student")
+	assert.ErrorIs(t, err, ErrPeerReviewForbidden)
+
+	assigned, err := f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	require.NoError(t, err)
+	assert.Equal(t, 8, assigned)
+
+	authors := make(map[string]string)
+	for _, s := range f.submissions.submissions {
+		authors[s.ID] = s.UserID
+	}
+	perSubmission := make(map[string]int)
+	perReviewer := make(map[string]int)
+	for _, r := range f.repo.reviews {
+		assert.NotEqual(t, authors[r.SubmissionID], r.ReviewerID, "no self review")
+		assert.NotEqual(t, f.submissions.submissions[0].ID, r.SubmissionID, "only the latest answer is reviewed")
+		perSubmission[r.SubmissionID]++
+		perReviewer[r.ReviewerID]++
+	}
+	assert.Len(t, perSubmission, 4)
+	for _, n := range perSubmission {
+		assert.Equal(t, 2, n)
+	}
+	for _, n := range perReviewer {
+		assert.Equal(t, 2, n, "reviews are spread evenly")
+	}
+
+	again, err := f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	require.NoError(t, err)
+	assert.Zero(t, again)
+
+	f.submit(t, "s5")
+	again, err = f.svc.AssignReviewers(ctx, "proof", "admin", "Admin")
+	require.NoError(t, err)
+	assert.Equal(t, 2, again)
+}
+
+func TestPeerReviewService_SubmitAndAggregate(t *testing.T) {
+	ctx := context.Background()
+	f := newPeerReviewFixture(t)
+
+	rubric := f.saveRubric(t, 2)
+	f.submit(t, "s1", "s2", "s3")
+	_, err := f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	require.NoError(t, err)
+
+	target := f.submissions.submissions[0]
+	var reviews []models.PeerReview
+	for _, r := range f.repo.reviews {
+		if r.SubmissionID == target.ID {
+			reviews = append(reviews, r)
+		}
+	}
+	require.Len(t, reviews, 2)
+
+	review, gotRubric, err := f.svc.GetReview(ctx, reviews[0].ID, reviews[0].ReviewerID)
+	require.NoError(t, err)
+	assert.Equal(t, target.Answer, review.Submission.Answer)
+	assert.Len(t, gotRubric.Criteria, 2)
+
+	_, _, err = f.svc.GetReview(ctx, reviews[0].ID, target.UserID)
+	assert.ErrorIs(t, err, ErrPeerReviewForbidden)
+
+	c1, c2 := rubric.Criteria[0].ID, rubric.Criteria[1].ID
+
+	_, err = f.svc.SubmitReview(ctx, reviews[0].ID, reviews[0].ReviewerID, map[string]int{c1: 6}, "")
+	assert.ErrorIs(t, err, ErrInvalidPeerReview, "every criterion must be scored")
+
+	_, err = f.svc.SubmitReview(ctx, reviews[0].ID, reviews[0].ReviewerID, map[string]int{c1: 7, c2: 4}, "")
+	assert.ErrorIs(t, err, ErrInvalidPeerReview)
+
+	submitted, err := f.svc.SubmitReview(ctx, reviews[0].ID, reviews[0].ReviewerID, map[string]int{c1: 6, c2: 4}, " Clear proof ")
+	require.NoError(t, err)
+	assert.Equal(t, models.PeerReviewSubmitted, submitted.Status)
+	assert.Equal(t, "Clear proof", submitted.Comment)
+
+	_, err = f.svc.SubmitReview(ctx, reviews[0].ID, reviews[0].ReviewerID, map[string]int{c1: 6, c2: 4}, "")
+	assert.ErrorIs(t, err, ErrPeerReviewSubmitted)
+
+	// одна рецензия из двух — оценка ещё не выставлена
+	assert.Equal(t, models.SubmissionStatusPendingReview, f.submissions.submissions[0].Status)
+	assert.Empty(t, f.listener.submissions)
+
+	// автор видит только отправленные рецензии и без имён
+	received, err := f.svc.GetSubmissionReviews(ctx, target.ID, target.UserID, "Student")
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Empty(t, received[0].ReviewerID)
+	assert.Nil(t, received[0].Reviewer)
+
+	_, err = f.svc.GetSubmissionReviews(ctx, target.ID, reviews[0].ReviewerID, "Student")
+	assert.ErrorIs(t, err, ErrPeerReviewForbidden)
+
+	_, err = f.svc.SubmitReview(ctx, reviews[1].ID, reviews[1].ReviewerID, map[string]int{c1: 2, c2: 2}, "")
+	require.NoError(t, err)
+
+	graded := f.submissions.submissions[0]
+	assert.Equal(t, models.SubmissionStatusGraded, graded.Status)
+	require.NotNil(t, graded.PeerScore)
+	assert.Equal(t, 70, *graded.PeerScore)
+	assert.Equal(t, 70, *graded.Score)
+	assert.Nil(t, graded.GradedBy)
+	assert.True(t, graded.IsCorrect)
+	require.Len(t, f.listener.submissions, 1)
+
+	teacherView, err := f.svc.GetSubmissionReviews(ctx, target.ID, "author", "Teacher")
+	require.NoError(t, err)
+	require.Len(t, teacherView, 2)
+	assert.NotEmpty(t, teacherView[0].ReviewerID)
+}
+
+func TestPeerReviewService_TeacherGradeWinsOverPeers(t *testing.T) {
+	ctx := context.Background()
+	f := newPeerReviewFixture(t)
+
+	rubric := f.saveRubric(t, 1)
+	f.submit(t, "s1", "s2")
+	_, err := f.svc.AssignReviewers(ctx, "proof", "author", "Teacher")
+	require.NoError(t, err)
+
+	target := f.submissions.submissions[0]
+	_, err = f.grading.Grade(ctx, target.ID, "author", "Teacher", 90, "")
+	require.NoError(t, err)
+
+	for _, r := range f.repo.reviews {
+		if r.SubmissionID == target.ID {
+			_, err = f.svc.SubmitReview(ctx, r.ID, r.ReviewerID, map[string]int{rubric.Criteria[0].ID: 0, rubric.Criteria[1].ID: 0}, "")
+			require.NoError(t, err)
+		}
+	}
+
+	graded := f.submissions.submissions[0]
+	require.NotNil(t, graded.PeerScore)
+	assert.Equal(t, 0, *graded.PeerScore)
+	assert.Equal(t, 90, *graded.Score, "peers do not replace a teacher's grade")
+	assert.Len(t, f.listener.submissions, 1)
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+)
+
+const (
+	PersonalAccessTokenPrefix = "pat_"
+
+	personalAccessTokenMaxDays   = 365
+	personalAccessTokenMaxActive = 20
+	// personalAccessTokenTouchInterval limits last used updates to one write
+	// per token and interval instead of one per request.
+	personalAccessTokenTouchInterval = time.Minute
+)
+
+var (
+	ErrAccessTokenNotFound     = errors.New("access token not found")
+	ErrInvalidAccessTokenScope = errors.New("unknown access token scope")
+	ErrInvalidAccessTokenTTL   = errors.New("access tokens expire after 1 to 365 days")
+	ErrTooManyAccessTokens     = errors.New("too many active access tokens, revoke one first")
+)
+
+// AccessTokenIdentity is who a personal access token acts for. Role is the
+// user's current role, so a demoted user's tokens lose the old privileges.
+type AccessTokenIdentity struct {
+	TokenID uuid.UUID
+	UserID  uuid.UUID
+	Role    models.UserRole
+	Token   *models.PersonalAccessToken
+}
+
+type PersonalAccessTokenService struct {
+	tokens repository.IPersonalAccessTokenRepository
+	users  repository.IUserRepository
+}
+
+func NewPersonalAccessTokenService(tokens repository.IPersonalAccessTokenRepository, users repository.IUserRepository) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{tokens: tokens, users: users}
+}
+
+func hashAccessToken(raw string) string {
+	hash := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(hash[:])
+}
+
+func generateAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func normalizeScopes(scopes []string) (string, error) {
+	seen := make(map[models.TokenScope]bool)
+	var out []string
+	for _, s := range scopes {
+		scope := models.TokenScope(strings.ToLower(strings.TrimSpace(s)))
+		if scope != models.TokenScopeRead && scope != models.TokenScopeWrite {
+			return "", ErrInvalidAccessTokenScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			out = append(out, string(scope))
+		}
+	}
+	if len(out) == 0 {
+		return "", ErrInvalidAccessTokenScope
+	}
+	return strings.Join(out, " "), nil
+}
+
+// Create issues a token. The raw token is returned only here; afterwards the
+// user sees just its prefix.
+func (s *PersonalAccessTokenService) Create(ctx context.Context, userID, name string, scopes []string, expiresInDays int) (*models.PersonalAccessToken, string, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "PersonalAccessTokenService.Create")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+
+	scopeList, err := normalizeScopes(scopes)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+	if expiresInDays < 1 || expiresInDays > personalAccessTokenMaxDays {
+		span.RecordError(ErrInvalidAccessTokenTTL)
+		return nil, "", ErrInvalidAccessTokenTTL
+	}
+
+	count, err := s.tokens.CountActiveByUser(ctx, uid)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+	if count >= personalAccessTokenMaxActive {
+		span.RecordError(ErrTooManyAccessTokens)
+		return nil, "", ErrTooManyAccessTokens
+	}
+
+	raw, err := generateAccessToken()
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+
+	token := &models.PersonalAccessToken{
+		UserID:      uid,
+		Name:        strings.TrimSpace(name),
+		TokenHash:   hashAccessToken(raw),
+		TokenPrefix: raw[:len(PersonalAccessTokenPrefix)+8],
+		Scopes:      scopeList,
+		ExpiresAt:   time.Now().Add(time.Duration(expiresInDays) * 24 * time.Hour),
+	}
+	if err := s.tokens.Create(ctx, token); err != nil {
+		span.RecordError(err)
+		return nil, "", err
+	}
+
+	return token, raw, nil
+}
+
+func (s *PersonalAccessTokenService) List(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "PersonalAccessTokenService.List")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	tokens, err := s.tokens.FindActiveByUser(ctx, uid)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, userID, tokenID string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "PersonalAccessTokenService.Revoke")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	id, err := uuid.Parse(tokenID)
+	if err != nil {
+		return ErrAccessTokenNotFound
+	}
+
+	revoked, err := s.tokens.Revoke(ctx, uid, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !revoked {
+		return ErrAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func (s *PersonalAccessTokenService) RevokeAll(ctx context.Context, userID string) error {
+	ctx, span := otel.Tracer("auth").Start(ctx, "PersonalAccessTokenService.RevokeAll")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.tokens.RevokeAllForUser(ctx, uid); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate resolves a raw pat_ token. Revoked, expired and unknown tokens
+// all return ErrInvalidAccessToken.
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, raw string) (*AccessTokenIdentity, error) {
+	ctx, span := otel.Tracer("auth").Start(ctx, "PersonalAccessTokenService.Authenticate")
+	defer span.End()
+
+	if !strings.HasPrefix(raw, PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidAccessToken
+	}
+
+	token, err := s.tokens.FindByHash(ctx, hashAccessToken(raw))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if token == nil || token.RevokedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, ErrInvalidAccessToken
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID.String())
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalAccessTokenTouchInterval {
+		// a failed update must not fail the request
+		if err := s.tokens.TouchLastUsed(ctx, token.ID, now); err != nil {
+			span.RecordError(err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return &AccessTokenIdentity{
+		TokenID: token.ID,
+		UserID:  user.ID,
+		Role:    user.Role,
+		Token:   token,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+)
+
+type fakeAccessTokenRepo struct {
+	repository.IPersonalAccessTokenRepository
+	byID    map[uuid.UUID]*models.PersonalAccessToken
+	touched int
+}
+
+func newFakeAccessTokenRepo() *fakeAccessTokenRepo {
+	return &fakeAccessTokenRepo{byID: make(map[uuid.UUID]*models.PersonalAccessToken)}
+}
+
+func (f *fakeAccessTokenRepo) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	f.byID[token.ID] = token
+	return nil
+}
+func (f *fakeAccessTokenRepo) FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	for _, t := range f.byID {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeAccessTokenRepo) active(userID uuid.UUID) []models.PersonalAccessToken {
+	var out []models.PersonalAccessToken
+	for _, t := range f.byID {
+		if t.UserID == userID && t.RevokedAt == nil && t.ExpiresAt.After(time.Now()) {
+			out = append(out, *t)
+		}
+	}
+	return out
+}
+func (f *fakeAccessTokenRepo) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	return f.active(userID), nil
+}
+func (f *fakeAccessTokenRepo) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return int64(len(f.active(userID))), nil
+}
+func (f *fakeAccessTokenRepo) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	t, ok := f.byID[id]
+	if !ok || t.UserID != userID || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	return true, nil
+}
+func (f *fakeAccessTokenRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	for _, t := range f.byID {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+func (f *fakeAccessTokenRepo) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	f.touched++
+	f.byID[id].LastUsedAt = &at
+	return nil
+}
+
+func newAccessTokenFixture(t *testing.T) (*PersonalAccessTokenService, *fakeAccessTokenRepo, *models.User) {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	user := &models.User{Email: "teacher@example.com", Role: models.UserRoleTeacher, Status: "ACTIVE"}
+	require.NoError(t, users.Create(context.Background(), user))
+
+	tokens := newFakeAccessTokenRepo()
+	return NewPersonalAccessTokenService(tokens, users), tokens, user
+}
+
+func TestPersonalAccessTokenService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens, user := newAccessTokenFixture(t)
+
+	token, raw, err := svc.Create(ctx, user.ID.String(), " gradebook sync ", []string{"read", "READ", "write"}, 30)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(raw, "pat_"))
+	assert.True(t, strings.HasPrefix(raw, token.TokenPrefix))
+	assert.Equal(t, "gradebook sync", token.Name)
+	assert.Equal(t, "read write", token.Scopes)
+	// хранится только хеш
+	assert.Equal(t, hashAccessToken(raw), token.TokenHash)
+	assert.NotEqual(t, raw, token.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), token.ExpiresAt, time.Minute)
+
+	identity, err := svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, identity.UserID)
+	assert.Equal(t, models.UserRoleTeacher, identity.Role)
+	assert.True(t, identity.Token.HasScope(models.TokenScopeWrite))
+	require.NotNil(t, token.LastUsedAt)
+
+	// время последнего использования пишется не чаще раза в минуту
+	_, err = svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tokens.touched)
+
+	_, err = svc.Authenticate(ctx, raw+"x")
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+	_, err = svc.Authenticate(ctx, "not-a-pat")
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+}
+
+func TestPersonalAccessTokenService_Create_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc, _, user := newAccessTokenFixture(t)
+
+	_, _, err := svc.Create(ctx, user.ID.String(), "ci", []string{"admin"}, 30)
+	assert.ErrorIs(t, err, ErrInvalidAccessTokenScope)
+	_, _, err = svc.Create(ctx, user.ID.String(), "ci", nil, 30)
+	assert.ErrorIs(t, err, ErrInvalidAccessTokenScope)
+	_, _, err = svc.Create(ctx, user.ID.String(), "ci", []string{"read"}, 0)
+	assert.ErrorIs(t, err, ErrInvalidAccessTokenTTL)
+	_, _, err = svc.Create(ctx, user.ID.String(), "ci", []string{"read"}, 366)
+	assert.ErrorIs(t, err, ErrInvalidAccessTokenTTL)
+
+	for i := 0; i < personalAccessTokenMaxActive; i++ {
+		_, _, err = svc.Create(ctx, user.ID.String(), "ci", []string{"read"}, 1)
+		require.NoError(t, err)
+	}
+	_, _, err = svc.Create(ctx, user.ID.String(), "ci", []string{"read"}, 1)
+	assert.ErrorIs(t, err, ErrTooManyAccessTokens)
+}
+
+func TestPersonalAccessTokenService_RevokeAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens, user := newAccessTokenFixture(t)
+
+	first, firstRaw, err := svc.Create(ctx, user.ID.String(), "first", []string{"read"}, 7)
+	require.NoError(t, err)
+	second, secondRaw, err := svc.Create(ctx, user.ID.String(), "second", []string{"read"}, 7)
+	require.NoError(t, err)
+
+	// чужой токен отозвать нельзя
+	err = svc.Revoke(ctx, uuid.New().String(), first.ID.String())
+	assert.ErrorIs(t, err, ErrAccessTokenNotFound)
+	err = svc.Revoke(ctx, user.ID.String(), "not-a-uuid")
+	assert.ErrorIs(t, err, ErrAccessTokenNotFound)
+
+	require.NoError(t, svc.Revoke(ctx, user.ID.String(), first.ID.String()))
+	assert.ErrorIs(t, svc.Revoke(ctx, user.ID.String(), first.ID.String()), ErrAccessTokenNotFound)
+
+	_, err = svc.Authenticate(ctx, firstRaw)
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+
+	list, err := svc.List(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, second.ID, list[0].ID)
+
+	tokens.byID[second.ID].ExpiresAt = time.Now().Add(-time.Second)
+	_, err = svc.Authenticate(ctx, secondRaw)
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+
+	_, thirdRaw, err := svc.Create(ctx, user.ID.String(), "third", []string{"write"}, 7)
+	require.NoError(t, err)
+	require.NoError(t, svc.RevokeAll(ctx, user.ID.String()))
+	_, err = svc.Authenticate(ctx, thirdRaw)
+	assert.ErrorIs(t, err, ErrInvalidAccessToken)
+}
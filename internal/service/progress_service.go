@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+// masteryHalfLife is how long it takes for a solved task to lose half of the
+// recency bonus it contributes to mastery.
+const masteryHalfLife = 30 * 24 * time.Hour
+
+var difficultyWeights = map[models.Difficulty]float64{
+	models.DifficultyEasy:    1,
+	models.DifficultyMedium:  2,
+	models.DifficultyHard:    3,
+	models.DifficultyExtreme: 5,
+}
+
+type ProgressService struct {
+	topics      repository.ITopicRepository
+	tasks       repository.ITaskRepository
+	submissions repository.ISubmissionRepository
+}
+
+func NewProgressService(topics repository.ITopicRepository, tasks repository.ITaskRepository, submissions repository.ISubmissionRepository) *ProgressService {
+	return &ProgressService{
+		topics:      topics,
+		tasks:       tasks,
+		submissions: submissions,
+	}
+}
+
+type topicAggregate struct {
+	total     int
+	attempted int
+	solved    int
+	weight    float64
+	earned    float64
+}
+
+func (a *topicAggregate) add(b topicAggregate) {
+	a.total += b.total
+	a.attempted += b.attempted
+	a.solved += b.solved
+	a.weight += b.weight
+	a.earned += b.earned
+}
+
+func difficultyWeight(d models.Difficulty) float64 {
+	if w, ok := difficultyWeights[d]; ok {
+		return w
+	}
+	return 1
+}
+
+// recencyFactor scales a solve between 1 (just solved) and 0.5 (solved long ago).
+func recencyFactor(solvedAt time.Time, now time.Time) float64 {
+	age := now.Sub(solvedAt)
+	if age < 0 {
+		age = 0
+	}
+	decay := math.Pow(0.5, float64(age)/float64(masteryHalfLife))
+	return 0.5 + 0.5*decay
+}
+
+func (s *ProgressService) GetUserProgress(ctx context.Context, userID string) ([]models.TopicProgress, error) {
+	ctx, span := otel.Tracer("progress").Start(ctx, "ProgressService.GetUserProgress")
+	defer span.End()
+
+	topics, rolled, err := s.buildProgress(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := make([]models.TopicProgress, 0, len(topics))
+	for _, t := range topics {
+		result = append(result, toTopicProgress(t, rolled[t.ID]))
+	}
+
+	return result, nil
+}
+
+func (s *ProgressService) GetTopicProgress(ctx context.Context, userID, topicID string) (*models.TopicProgress, error) {
+	ctx, span := otel.Tracer("progress").Start(ctx, "ProgressService.GetTopicProgress")
+	defer span.End()
+
+	topics, rolled, err := s.buildProgress(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	for _, t := range topics {
+		if t.ID == topicID {
+			p := toTopicProgress(t, rolled[t.ID])
+			return &p, nil
+		}
+	}
+
+	err = errors.New("topic not found")
+	span.RecordError(err)
+	return nil, err
+}
+
+func (s *ProgressService) buildProgress(ctx context.Context, userID string) ([]models.Topic, map[string]topicAggregate, error) {
+	_, topicsSpan := otel.Tracer("progress").Start(ctx, "Topics.FindAll")
+	topics, err := s.topics.FindAll(ctx)
+	topicsSpan.End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, tasksSpan := otel.Tracer("progress").Start(ctx, "Tasks.GetAll")
+	tasks, err := s.tasks.GetAll(ctx)
+	tasksSpan.End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, statsSpan := otel.Tracer("progress").Start(ctx, "Submissions.GetStatsByUser")
+	stats, err := s.submissions.GetStatsByUser(ctx, userID)
+	statsSpan.End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return topics, rollUpProgress(topics, tasks, stats, time.Now()), nil
+}
+
+func rollUpProgress(topics []models.Topic, tasks []models.Task, stats []models.TaskAttemptStats, now time.Time) map[string]topicAggregate {
+	byTask := make(map[string]models.TaskAttemptStats, len(stats))
+	for _, st := range stats {
+		byTask[st.TaskID] = st
+	}
+
+	direct := make(map[string]topicAggregate, len(topics))
+	for _, task := range tasks {
+		if task.Status != models.TaskStatusPublished {
+			continue
+		}
+
+		agg := direct[task.TopicID]
+		w := difficultyWeight(task.Difficulty)
+		agg.total++
+		agg.weight += w
+
+		if st, ok := byTask[task.ID]; ok {
+			agg.attempted++
+			if st.Solved {
+				agg.solved++
+				solvedAt := st.LastAttemptAt
+				if st.LastSolvedAt != nil {
+					solvedAt = *st.LastSolvedAt
+				}
+				agg.earned += w * recencyFactor(solvedAt, now)
+			}
+		}
+		direct[task.TopicID] = agg
+	}
+
+	children := make(map[string][]string)
+	for _, t := range topics {
+		if t.ParentID != nil {
+			children[*t.ParentID] = append(children[*t.ParentID], t.ID)
+		}
+	}
+
+	rolled := make(map[string]topicAggregate, len(topics))
+	var visit func(id string, path map[string]bool) topicAggregate
+	visit = func(id string, path map[string]bool) topicAggregate {
+		if agg, ok := rolled[id]; ok {
+			return agg
+		}
+		path[id] = true
+		agg := direct[id]
+		for _, child := range children[id] {
+			if path[child] {
+				continue
+			}
+			agg.add(visit(child, path))
+		}
+		delete(path, id)
+		rolled[id] = agg
+		return agg
+	}
+
+	for _, t := range topics {
+		visit(t.ID, map[string]bool{})
+	}
+
+	return rolled
+}
+
+func toTopicProgress(t models.Topic, agg topicAggregate) models.TopicProgress {
+	mastery := 0.0
+	if agg.weight > 0 {
+		mastery = math.Round(agg.earned/agg.weight*1000) / 1000
+	}
+
+	return models.TopicProgress{
+		TopicID:   t.ID,
+		Title:     t.Title,
+		ParentID:  t.ParentID,
+		Total:     agg.total,
+		Attempted: agg.attempted,
+		Solved:    agg.solved,
+		Mastery:   mastery,
+	}
+}
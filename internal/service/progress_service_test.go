@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+func TestRecencyFactor(t *testing.T) {
+	now := time.Now()
+
+	assert.InDelta(t, 1.0, recencyFactor(now, now), 0.0001)
+	assert.InDelta(t, 0.75, recencyFactor(now.Add(-masteryHalfLife), now), 0.0001)
+	assert.InDelta(t, 0.5, recencyFactor(now.Add(-100*masteryHalfLife), now), 0.0001)
+}
+
+func TestProgressService_GetTopicProgress_RollsUpChildren(t *testing.T) {
+	ctx := context.Background()
+	parentID := "algebra"
+
+	topics := newFakeTopicRepo()
+	topics.topics = []models.Topic{
+		{ID: "algebra", Title: "Algebra"},
+		{ID: "equations", Title: "Equations", ParentID: &parentID},
+		{ID: "geometry", Title: "Geometry"},
+	}
+
+	tasks := newFakeTaskRepo()
+	tasks.all = []models.Task{
+		{ID: "t1", TopicID: "algebra", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished},
+		{ID: "t2", TopicID: "equations", Difficulty: models.DifficultyHard, Status: models.TaskStatusPublished},
+		{ID: "t3", TopicID: "equations", Difficulty: models.DifficultyMedium, Status: models.TaskStatusPublished},
+		{ID: "t4", TopicID: "equations", Difficulty: models.DifficultyMedium, Status: models.TaskStatusDraft},
+		{ID: "t5", TopicID: "geometry", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished},
+	}
+
+	now := time.Now()
+	submissions := newFakeSubmissionRepo()
+	submissions.stats = []models.TaskAttemptStats{
+		{TaskID: "t1", Attempts: 1, Solved: true, LastAttemptAt: now, LastSolvedAt: &now},
+		{TaskID: "t2", Attempts: 3, Solved: true, LastAttemptAt: now, LastSolvedAt: &now},
+		{TaskID: "t3", Attempts: 2, Solved: false, LastAttemptAt: now},
+	}
+
+	svc := NewProgressService(topics, tasks, submissions)
+
+	progress, err := svc.GetTopicProgress(ctx, "user-1", "algebra")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, progress.Total)
+	assert.Equal(t, 3, progress.Attempted)
+	assert.Equal(t, 2, progress.Solved)
+	// easy(1) + hard(3) solved out of easy(1) + hard(3) + medium(2)
+	assert.InDelta(t, 4.0/6.0, progress.Mastery, 0.01)
+
+	child, err := svc.GetTopicProgress(ctx, "user-1", "equations")
+	require.NoError(t, err)
+	assert.Equal(t, 2, child.Total)
+	assert.Equal(t, 1, child.Solved)
+
+	_, err = svc.GetTopicProgress(ctx, "user-1", "missing")
+	assert.EqualError(t, err, "topic not found")
+}
+
+func TestProgressService_GetUserProgress_HandlesParentCycles(t *testing.T) {
+	ctx := context.Background()
+	a, b := "a", "b"
+
+	topics := newFakeTopicRepo()
+	topics.topics = []models.Topic{
+		{ID: "a", ParentID: &b},
+		{ID: "b", ParentID: &a},
+	}
+
+	tasks := newFakeTaskRepo()
+	tasks.all = []models.Task{
+		{ID: "t1", TopicID: "a", Status: models.TaskStatusPublished},
+	}
+
+	svc := NewProgressService(topics, tasks, newFakeSubmissionRepo())
+
+	progress, err := svc.GetUserProgress(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	assert.Equal(t, 0.0, progress[0].Mastery)
+}
+
+func TestRecommendTask_DifficultyFollowsRecentResults(t *testing.T) {
+	now := time.Now()
+	topics := []models.Topic{{ID: "algebra", Title: "Algebra"}}
+	tasks := []models.Task{
+		{ID: "e1", TopicID: "algebra", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished},
+		{ID: "e2", TopicID: "algebra", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished},
+		{ID: "m1", TopicID: "algebra", Difficulty: models.DifficultyMedium, Status: models.TaskStatusPublished},
+		{ID: "h1", TopicID: "algebra", Difficulty: models.DifficultyHard, Status: models.TaskStatusPublished},
+	}
+
+	// новичку предлагается лёгкая задача
+	rec := recommendTask(topics, tasks, nil, nil, "", now)
+	require.NotNil(t, rec)
+	assert.Equal(t, models.DifficultyEasy, rec.Task.Difficulty)
+	assert.Contains(t, rec.Reasons[0], "just starting")
+
+	// три верных ответа подряд на лёгких задачах — сложность растёт
+	stats := []models.TaskAttemptStats{{TaskID: "e1", Attempts: 1, Solved: true, LastAttemptAt: now}}
+	recent := []models.Submission{
+		{TaskID: "e1", IsCorrect: true},
+		{TaskID: "e1", IsCorrect: true},
+		{TaskID: "e1", IsCorrect: true},
+	}
+	rec = recommendTask(topics, tasks, stats, recent, "", now)
+	require.NotNil(t, rec)
+	assert.Equal(t, "m1", rec.Task.ID)
+	assert.Equal(t, models.DifficultyMedium, rec.TargetDifficulty)
+
+	// серия ошибок на сложной задаче — сложность снижается, недавняя попытка не повторяется сразу
+	stats = []models.TaskAttemptStats{{TaskID: "h1", Attempts: 3, LastAttemptAt: now}}
+	recent = []models.Submission{
+		{TaskID: "h1", IsCorrect: false},
+		{TaskID: "h1", IsCorrect: false},
+		{TaskID: "h1", IsCorrect: false},
+	}
+	rec = recommendTask(topics, tasks, stats, recent, "", now)
+	require.NotNil(t, rec)
+	assert.Equal(t, "m1", rec.Task.ID)
+	assert.Contains(t, rec.Reasons[0], "goes down")
+}
+
+func TestRecommendTask_RespectsPrerequisites(t *testing.T) {
+	now := time.Now()
+	topics := []models.Topic{
+		{ID: "fractions", Title: "Fractions"},
+		{ID: "equations", Title: "Equations", Prerequisites: []models.TopicPrerequisite{{TopicID: "equations", PrerequisiteID: "fractions"}}},
+	}
+	tasks := []models.Task{
+		{ID: "f1", TopicID: "fractions", Difficulty: models.DifficultyMedium, Status: models.TaskStatusPublished},
+		{ID: "f2", TopicID: "fractions", Difficulty: models.DifficultyHard, Status: models.TaskStatusPublished},
+		{ID: "q1", TopicID: "equations", Difficulty: models.DifficultyEasy, Status: models.TaskStatusPublished},
+	}
+
+	rec := recommendTask(topics, tasks, nil, nil, "", now)
+	require.NotNil(t, rec)
+	assert.Equal(t, "fractions", rec.Task.TopicID, "equations stay locked until fractions are covered")
+
+	stats := []models.TaskAttemptStats{
+		{TaskID: "f1", Attempts: 1, Solved: true, LastAttemptAt: now.Add(-2 * time.Hour)},
+		{TaskID: "f2", Attempts: 1, Solved: true, LastAttemptAt: now.Add(-2 * time.Hour)},
+	}
+	rec = recommendTask(topics, tasks, stats, nil, "", now)
+	require.NotNil(t, rec)
+	assert.Equal(t, "q1", rec.Task.ID)
+	assert.Contains(t, rec.Reasons, "you have covered its prerequisite topics")
+
+	stats = append(stats, models.TaskAttemptStats{TaskID: "q1", Attempts: 1, Solved: true, LastAttemptAt: now})
+	assert.Nil(t, recommendTask(topics, tasks, stats, nil, "", now))
+}
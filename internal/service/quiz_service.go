@@ -0,0 +1,789 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+)
+
+// quizDeadlinesKey is a sorted set of in-progress session IDs scored by their
+// expiry time; the expiry worker auto-submits sessions whose score has passed.
+const quizDeadlinesKey = "quiz:deadlines"
+
+var (
+	ErrQuizNotFound        = errors.New("quiz not found")
+	ErrQuizSessionNotFound = errors.New("quiz session not found")
+	ErrQuizForbidden       = errors.New("you don't have access to this quiz")
+	ErrQuizNotOpen         = errors.New("quiz is not open")
+	ErrQuizSessionExpired  = errors.New("time limit for this quiz is over")
+	ErrQuizSessionClosed   = errors.New("quiz session is already submitted")
+	ErrQuizTaskNotFound    = errors.New("task is not part of this quiz session")
+	ErrInvalidQuiz         = errors.New("invalid quiz")
+)
+
+type QuizService struct {
+	quizzes    repository.IQuizRepository
+	classrooms repository.IClassroomRepository
+	tasks      *TaskService
+	redis      *redis.Client
+}
+
+func NewQuizService(quizzes repository.IQuizRepository, classrooms repository.IClassroomRepository, tasks *TaskService, rdb *redis.Client) *QuizService {
+	return &QuizService{
+		quizzes:    quizzes,
+		classrooms: classrooms,
+		tasks:      tasks,
+		redis:      rdb,
+	}
+}
+
+func (s *QuizService) findQuiz(ctx context.Context, id string) (*models.Quiz, error) {
+	quiz, err := s.quizzes.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuizNotFound
+	}
+	return quiz, err
+}
+
+func validateQuiz(q *models.Quiz, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return errors.New("quiz must contain at least one task")
+	}
+	if q.TimeLimitSeconds <= 0 {
+		return errors.New("time limit must be positive")
+	}
+	if q.ClosesAt != nil && q.ClosesAt.Before(q.OpensAt) {
+		return errors.New("close date must be after open date")
+	}
+	switch q.Mode {
+	case models.QuizModeFixed:
+		q.DrawCount = 0
+	case models.QuizModeRandom:
+		if q.DrawCount < 1 || q.DrawCount > len(taskIDs) {
+			return errors.New("draw count must be between 1 and the pool size")
+		}
+	default:
+		return errors.New("unknown quiz mode")
+	}
+	return nil
+}
+
+func (s *QuizService) member(ctx context.Context, classroomID, userID, role string) (*models.ClassroomMember, error) {
+	if isAdmin(role) {
+		if _, err := s.classrooms.FindByID(ctx, classroomID); err != nil {
+			return nil, err
+		}
+		return &models.ClassroomMember{ClassroomID: classroomID, UserID: userID, Role: models.ClassroomRoleOwner}, nil
+	}
+
+	member, err := s.classrooms.FindMember(ctx, classroomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member == nil {
+		return nil, ErrQuizForbidden
+	}
+
+	return member, nil
+}
+
+func (s *QuizService) requireClassroomTeacher(ctx context.Context, classroomID, userID, role string) error {
+	member, err := s.member(ctx, classroomID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if !member.IsTeacher() {
+		return ErrQuizForbidden
+	}
+
+	return nil
+}
+
+func (s *QuizService) buildTasks(ctx context.Context, quizID string, taskIDs []string) ([]models.QuizTask, error) {
+	published, err := s.tasks.publishedTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.QuizTask, 0, len(published))
+	for _, task := range published {
+		if task.GradingMode == models.GradingModeManual {
+			return nil, errors.New("task is graded manually: " + task.ID)
+		}
+
+		tasks = append(tasks, models.QuizTask{
+			QuizID:   quizID,
+			TaskID:   task.ID,
+			Position: len(tasks) + 1,
+		})
+	}
+
+	return tasks, nil
+}
+
+func (s *QuizService) CreateQuiz(ctx context.Context, userID, role string, quiz *models.Quiz, taskIDs []string) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.CreateQuiz")
+	defer span.End()
+
+	if err := s.requireClassroomTeacher(ctx, quiz.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if quiz.OpensAt.IsZero() {
+		quiz.OpensAt = time.Now()
+	}
+	if quiz.Mode == "" {
+		quiz.Mode = models.QuizModeFixed
+	}
+
+	if err := validateQuiz(quiz, taskIDs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuiz, err)
+	}
+
+	tasks, err := s.buildTasks(ctx, "", taskIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuiz, err)
+	}
+	if quiz.Mode == models.QuizModeRandom && quiz.DrawCount > len(tasks) {
+		return fmt.Errorf("%w: draw count must be between 1 and the pool size", ErrInvalidQuiz)
+	}
+
+	quiz.AuthorID = userID
+	quiz.ResultsReleased = false
+	quiz.Tasks = tasks
+
+	if err := s.quizzes.Create(ctx, quiz); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *QuizService) UpdateQuiz(ctx context.Context, userID, role string, quiz *models.Quiz, taskIDs []string) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.UpdateQuiz")
+	defer span.End()
+
+	existing, err := s.findQuiz(ctx, quiz.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, existing.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if quiz.OpensAt.IsZero() {
+		quiz.OpensAt = existing.OpensAt
+	}
+	if quiz.Mode == "" {
+		quiz.Mode = existing.Mode
+	}
+
+	if err := validateQuiz(quiz, taskIDs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuiz, err)
+	}
+
+	tasks, err := s.buildTasks(ctx, existing.ID, taskIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuiz, err)
+	}
+	if quiz.Mode == models.QuizModeRandom && quiz.DrawCount > len(tasks) {
+		return fmt.Errorf("%w: draw count must be between 1 and the pool size", ErrInvalidQuiz)
+	}
+
+	quiz.ClassroomID = existing.ClassroomID
+	quiz.AuthorID = existing.AuthorID
+	quiz.ResultsReleased = existing.ResultsReleased
+	quiz.CreatedAt = existing.CreatedAt
+	quiz.Tasks = tasks
+
+	if err := s.quizzes.Update(ctx, quiz); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *QuizService) DeleteQuiz(ctx context.Context, userID, role, id string) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.DeleteQuiz")
+	defer span.End()
+
+	existing, err := s.findQuiz(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, existing.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.quizzes.Delete(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetQuiz returns the quiz; students only see it once opened and never see
+// the task list, which is revealed through their session.
+func (s *QuizService) GetQuiz(ctx context.Context, userID, role, id string) (*models.Quiz, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.GetQuiz")
+	defer span.End()
+
+	quiz, err := s.findQuiz(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	member, err := s.member(ctx, quiz.ClassroomID, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if !member.IsTeacher() {
+		if time.Now().Before(quiz.OpensAt) {
+			return nil, ErrQuizNotOpen
+		}
+		quiz.Tasks = nil
+	}
+
+	return quiz, nil
+}
+
+func (s *QuizService) GetClassroomQuizzes(ctx context.Context, userID, role, classroomID string) ([]models.Quiz, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.GetClassroomQuizzes")
+	defer span.End()
+
+	member, err := s.member(ctx, classroomID, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	quizzes, err := s.quizzes.FindByClassroom(ctx, classroomID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if member.IsTeacher() {
+		return quizzes, nil
+	}
+
+	now := time.Now()
+	visible := make([]models.Quiz, 0, len(quizzes))
+	for _, q := range quizzes {
+		if !now.Before(q.OpensAt) {
+			q.Tasks = nil
+			visible = append(visible, q)
+		}
+	}
+
+	return visible, nil
+}
+
+func (s *QuizService) ReleaseResults(ctx context.Context, userID, role, id string) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.ReleaseResults")
+	defer span.End()
+
+	quiz, err := s.findQuiz(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, quiz.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.quizzes.ReleaseResults(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetResults returns all sessions of the quiz with scores; classroom teachers only.
+func (s *QuizService) GetResults(ctx context.Context, userID, role, id string) ([]models.QuizSession, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.GetResults")
+	defer span.End()
+
+	quiz, err := s.findQuiz(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, quiz.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// close sessions whose timers have not fired yet
+	if err := s.expireOverdue(ctx, quiz.ID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	sessions, err := s.quizzes.FindSessionsByQuiz(ctx, quiz.ID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *QuizService) expireOverdue(ctx context.Context, quizID string) error {
+	sessions, err := s.quizzes.FindSessionsByQuiz(ctx, quizID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if session.IsFinished() || now.Before(session.ExpiresAt) {
+			continue
+		}
+
+		full, err := s.quizzes.FindSessionByID(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.finish(ctx, full, models.QuizSessionExpired, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func drawQuizTasks(quiz *models.Quiz) []models.QuizAnswer {
+	pool := quiz.Tasks
+	if quiz.Mode == models.QuizModeRandom && quiz.DrawCount < len(pool) {
+		drawn := make([]models.QuizTask, 0, quiz.DrawCount)
+		for _, i := range rand.Perm(len(pool))[:quiz.DrawCount] {
+			drawn = append(drawn, pool[i])
+		}
+		pool = drawn
+	}
+
+	answers := make([]models.QuizAnswer, 0, len(pool))
+	for _, t := range pool {
+		answers = append(answers, models.QuizAnswer{
+			TaskID:   t.TaskID,
+			Position: len(answers) + 1,
+		})
+	}
+
+	return answers
+}
+
+// StartSession starts the caller's attempt or returns the existing one; every
+// student gets a single session per quiz.
+func (s *QuizService) StartSession(ctx context.Context, quizID, userID, role string) (*models.QuizSession, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.StartSession")
+	defer span.End()
+
+	quiz, err := s.findQuiz(ctx, quizID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	member, err := s.member(ctx, quiz.ClassroomID, userID, role)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if member.IsTeacher() {
+		return nil, ErrQuizForbidden
+	}
+
+	existing, err := s.currentSession(ctx, quizID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	now := time.Now()
+	if now.Before(quiz.OpensAt) || (quiz.ClosesAt != nil && !now.Before(*quiz.ClosesAt)) {
+		return nil, ErrQuizNotOpen
+	}
+
+	expiresAt := now.Add(time.Duration(quiz.TimeLimitSeconds) * time.Second)
+	if quiz.ClosesAt != nil && quiz.ClosesAt.Before(expiresAt) {
+		expiresAt = *quiz.ClosesAt
+	}
+
+	session := &models.QuizSession{
+		QuizID:    quiz.ID,
+		UserID:    userID,
+		Status:    models.QuizSessionInProgress,
+		StartedAt: now,
+		ExpiresAt: expiresAt,
+		Answers:   drawQuizTasks(quiz),
+	}
+
+	_, createSpan := otel.Tracer("quiz").Start(ctx, "Quizzes.CreateSession")
+	err = s.quizzes.CreateSession(ctx, session)
+	createSpan.End()
+	if err != nil {
+		// concurrent start: another request has created the session
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return s.currentSession(ctx, quizID, userID)
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.scheduleExpiry(ctx, session); err != nil {
+		span.RecordError(err)
+	}
+
+	return s.quizzes.FindSessionByID(ctx, session.ID)
+}
+
+// currentSession loads the caller's session and auto-submits it if the time
+// limit has passed but the expiry worker has not handled it yet.
+func (s *QuizService) currentSession(ctx context.Context, quizID, userID string) (*models.QuizSession, error) {
+	session, err := s.quizzes.FindSession(ctx, quizID, userID)
+	if err != nil || session == nil {
+		return session, err
+	}
+
+	now := time.Now()
+	if !session.IsFinished() && !now.Before(session.ExpiresAt) {
+		if err := s.finish(ctx, session, models.QuizSessionExpired, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// GetMySession returns the caller's session and whether graded results may be
+// shown. Until the teacher releases results, correctness is stripped.
+func (s *QuizService) GetMySession(ctx context.Context, quizID, userID string) (*models.QuizSession, bool, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.GetMySession")
+	defer span.End()
+
+	session, err := s.currentSession(ctx, quizID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, false, err
+	}
+
+	if session == nil {
+		return nil, false, ErrQuizSessionNotFound
+	}
+
+	showResults := session.IsFinished() && session.Quiz != nil && session.Quiz.ResultsReleased
+	if !showResults {
+		hideQuizResults(session)
+	}
+
+	return session, showResults, nil
+}
+
+// GetStudentSession returns a student's session with results; classroom teachers only.
+func (s *QuizService) GetStudentSession(ctx context.Context, userID, role, quizID, studentID string) (*models.QuizSession, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.GetStudentSession")
+	defer span.End()
+
+	quiz, err := s.findQuiz(ctx, quizID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.requireClassroomTeacher(ctx, quiz.ClassroomID, userID, role); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	session, err := s.currentSession(ctx, quizID, studentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, ErrQuizSessionNotFound
+	}
+
+	return session, nil
+}
+
+func hideQuizResults(session *models.QuizSession) {
+	session.CorrectCount = 0
+	session.Score = 0
+	for i := range session.Answers {
+		session.Answers[i].IsCorrect = false
+		if task := session.Answers[i].Task; task != nil {
+			task.CorrectAnswer = ""
+			task.OfficialSolution = ""
+		}
+	}
+}
+
+// SaveAnswer autosaves an answer while the session is running.
+func (s *QuizService) SaveAnswer(ctx context.Context, quizID, userID, taskID, answer string) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.SaveAnswer")
+	defer span.End()
+
+	session, err := s.currentSession(ctx, quizID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if session == nil {
+		return ErrQuizSessionNotFound
+	}
+
+	switch session.Status {
+	case models.QuizSessionExpired:
+		return ErrQuizSessionExpired
+	case models.QuizSessionSubmitted:
+		return ErrQuizSessionClosed
+	}
+
+	found := false
+	for _, a := range session.Answers {
+		if a.TaskID == taskID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrQuizTaskNotFound
+	}
+
+	if err := s.quizzes.SaveAnswer(ctx, session.ID, taskID, answer); err != nil {
+		// the session was finished between the read and the write
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrQuizSessionClosed
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// Submit finishes the caller's session before the time limit and reports
+// whether graded results may be shown.
+func (s *QuizService) Submit(ctx context.Context, quizID, userID string) (*models.QuizSession, bool, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.Submit")
+	defer span.End()
+
+	session, err := s.currentSession(ctx, quizID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, false, err
+	}
+
+	if session == nil {
+		return nil, false, ErrQuizSessionNotFound
+	}
+
+	switch session.Status {
+	case models.QuizSessionExpired:
+		return nil, false, ErrQuizSessionExpired
+	case models.QuizSessionSubmitted:
+		return nil, false, ErrQuizSessionClosed
+	}
+
+	if err := s.finish(ctx, session, models.QuizSessionSubmitted, time.Now()); err != nil {
+		span.RecordError(err)
+		return nil, false, err
+	}
+
+	showResults := session.Quiz != nil && session.Quiz.ResultsReleased
+	if !showResults {
+		hideQuizResults(session)
+	}
+
+	return session, showResults, nil
+}
+
+func gradeQuizSession(session *models.QuizSession) {
+	correct := 0
+	for i := range session.Answers {
+		a := &session.Answers[i]
+		a.IsCorrect = a.Task != nil && a.Answer != "" && checkAnswer(a.Task, a.Answer)
+		if a.IsCorrect {
+			correct++
+		}
+	}
+
+	session.CorrectCount = correct
+	session.Score = 0
+	if len(session.Answers) > 0 {
+		session.Score = math.Round(float64(correct)/float64(len(session.Answers))*1000) / 10
+	}
+}
+
+// finish grades the session and closes it. If another request or the worker
+// finished it first, the stored session is reloaded instead.
+func (s *QuizService) finish(ctx context.Context, session *models.QuizSession, status models.QuizSessionStatus, now time.Time) error {
+	gradeQuizSession(session)
+
+	submittedAt := now
+	if status == models.QuizSessionExpired && session.ExpiresAt.Before(now) {
+		submittedAt = session.ExpiresAt
+	}
+	session.Status = status
+	session.SubmittedAt = &submittedAt
+
+	finished, err := s.quizzes.FinishSession(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	s.redis.ZRem(ctx, quizDeadlinesKey, session.ID)
+
+	if !finished {
+		stored, err := s.quizzes.FindSessionByID(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		*session = *stored
+	}
+
+	return nil
+}
+
+func (s *QuizService) scheduleExpiry(ctx context.Context, session *models.QuizSession) error {
+	return s.redis.ZAdd(ctx, quizDeadlinesKey, redis.Z{
+		Score:  float64(session.ExpiresAt.Unix()),
+		Member: session.ID,
+	}).Err()
+}
+
+// RestoreTimers re-registers expiry timers for all in-progress sessions, so
+// that sessions survive a Redis flush or an API restart.
+func (s *QuizService) RestoreTimers(ctx context.Context) error {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.RestoreTimers")
+	defer span.End()
+
+	sessions, err := s.quizzes.FindInProgressSessions(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for i := range sessions {
+		if err := s.scheduleExpiry(ctx, &sessions[i]); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpireDue auto-submits sessions whose timers have fired and returns how
+// many were handled. A session is claimed by removing it from the set, so
+// several API instances can run the worker concurrently.
+func (s *QuizService) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := otel.Tracer("quiz").Start(ctx, "QuizService.ExpireDue")
+	defer span.End()
+
+	ids, err := s.redis.ZRangeByScore(ctx, quizDeadlinesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprint(now.Unix()),
+	}).Result()
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	handled := 0
+	for _, id := range ids {
+		claimed, err := s.redis.ZRem(ctx, quizDeadlinesKey, id).Result()
+		if err != nil {
+			span.RecordError(err)
+			return handled, err
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		session, err := s.quizzes.FindSessionByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			span.RecordError(err)
+			s.scheduleExpiry(ctx, &models.QuizSession{ID: id, ExpiresAt: now})
+			return handled, err
+		}
+
+		if session.IsFinished() {
+			continue
+		}
+
+		if err := s.finish(ctx, session, models.QuizSessionExpired, now); err != nil {
+			span.RecordError(err)
+			s.scheduleExpiry(ctx, session)
+			return handled, err
+		}
+		handled++
+	}
+
+	return handled, nil
+}
+
+// RunExpiryWorker restores timers and then polls for expired sessions until
+// ctx is cancelled.
+func (s *QuizService) RunExpiryWorker(ctx context.Context, interval time.Duration) {
+	if err := s.RestoreTimers(ctx); err != nil {
+		log.Printf("quiz: failed to restore timers: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.ExpireDue(ctx, now); err != nil {
+				log.Printf("quiz: failed to expire sessions: %v", err)
+			}
+		}
+	}
+}
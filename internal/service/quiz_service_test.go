@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"learning-platform/internal/models"
+)
+
+type fakeQuizRepo struct {
+	quizzes  map[string]*models.Quiz
+	sessions map[string]*models.QuizSession
+	tasks    *fakeTaskRepo
+}
+
+func newFakeQuizRepo(tasks *fakeTaskRepo) *fakeQuizRepo {
+	return &fakeQuizRepo{
+		quizzes:  make(map[string]*models.Quiz),
+		sessions: make(map[string]*models.QuizSession),
+		tasks:    tasks,
+	}
+}
+
+func (f *fakeQuizRepo) Create(ctx context.Context, quiz *models.Quiz) error {
+	if quiz.ID == "" {
+		quiz.ID = uuid.NewString()
+	}
+	f.quizzes[quiz.ID] = quiz
+	return nil
+}
+
+func (f *fakeQuizRepo) FindByID(ctx context.Context, id string) (*models.Quiz, error) {
+	q, ok := f.quizzes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *q
+	cp.Tasks = append([]models.QuizTask(nil), q.Tasks...)
+	return &cp, nil
+}
+
+func (f *fakeQuizRepo) FindByClassroom(ctx context.Context, classroomID string) ([]models.Quiz, error) {
+	var out []models.Quiz
+	for _, q := range f.quizzes {
+		if q.ClassroomID == classroomID {
+			out = append(out, *q)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuizRepo) Update(ctx context.Context, quiz *models.Quiz) error {
+	f.quizzes[quiz.ID] = quiz
+	return nil
+}
+
+func (f *fakeQuizRepo) Delete(ctx context.Context, id string) error {
+	delete(f.quizzes, id)
+	return nil
+}
+
+func (f *fakeQuizRepo) ReleaseResults(ctx context.Context, id string) error {
+	f.quizzes[id].ResultsReleased = true
+	return nil
+}
+
+func (f *fakeQuizRepo) CreateSession(ctx context.Context, session *models.QuizSession) error {
+	session.ID = uuid.NewString()
+	for i := range session.Answers {
+		session.Answers[i].SessionID = session.ID
+	}
+	cp := *session
+	cp.Answers = append([]models.QuizAnswer(nil), session.Answers...)
+	f.sessions[session.ID] = &cp
+	return nil
+}
+
+// load возвращает копию сессии с подгруженными квизом и задачами, как делает репозиторий
+func (f *fakeQuizRepo) load(s *models.QuizSession) *models.QuizSession {
+	cp := *s
+	cp.Answers = append([]models.QuizAnswer(nil), s.Answers...)
+	if q, ok := f.quizzes[s.QuizID]; ok {
+		quiz := *q
+		cp.Quiz = &quiz
+	}
+	for i := range cp.Answers {
+		if t, ok := f.tasks.byID[cp.Answers[i].TaskID]; ok {
+			task := *t
+			cp.Answers[i].Task = &task
+		}
+	}
+	return &cp
+}
+
+func (f *fakeQuizRepo) FindSession(ctx context.Context, quizID, userID string) (*models.QuizSession, error) {
+	for _, s := range f.sessions {
+		if s.QuizID == quizID && s.UserID == userID {
+			return f.load(s), nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeQuizRepo) FindSessionByID(ctx context.Context, id string) (*models.QuizSession, error) {
+	s, ok := f.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.load(s), nil
+}
+
+func (f *fakeQuizRepo) FindSessionsByQuiz(ctx context.Context, quizID string) ([]models.QuizSession, error) {
+	var out []models.QuizSession
+	for _, s := range f.sessions {
+		if s.QuizID == quizID {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuizRepo) FindInProgressSessions(ctx context.Context) ([]models.QuizSession, error) {
+	var out []models.QuizSession
+	for _, s := range f.sessions {
+		if !s.IsFinished() {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuizRepo) SaveAnswer(ctx context.Context, sessionID, taskID, answer string) error {
+	s, ok := f.sessions[sessionID]
+	if !ok || s.IsFinished() {
+		return gorm.ErrRecordNotFound
+	}
+	for i := range s.Answers {
+		if s.Answers[i].TaskID == taskID {
+			s.Answers[i].Answer = answer
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (f *fakeQuizRepo) FinishSession(ctx context.Context, session *models.QuizSession) (bool, error) {
+	s := f.sessions[session.ID]
+	if s.IsFinished() {
+		return false, nil
+	}
+	s.Status = session.Status
+	s.SubmittedAt = session.SubmittedAt
+	s.CorrectCount = session.CorrectCount
+	s.Score = session.Score
+	for i := range s.Answers {
+		for _, a := range session.Answers {
+			if a.TaskID == s.Answers[i].TaskID {
+				s.Answers[i].IsCorrect = a.IsCorrect
+			}
+		}
+	}
+	return true, nil
+}
+
+type quizFixture struct {
+	svc       *QuizService
+	repo      *fakeQuizRepo
+	rdb       *redis.Client
+	classroom *models.Classroom
+	quiz      *models.Quiz
+}
+
+func newQuizFixture(t *testing.T, mode models.QuizMode, drawCount int) *quizFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	classrooms := newFakeClassroomRepo()
+	classroom := &models.Classroom{Name: "7A", OwnerID: "teacher"}
+	require.NoError(t, classrooms.Create(ctx, classroom))
+	classrooms.members[classroom.ID]["student"] = &models.ClassroomMember{
+		ClassroomID: classroom.ID, UserID: "student", Role: models.ClassroomRoleStudent,
+	}
+
+	tasks := newFakeTaskRepo()
+	for id, answer := range map[string]string{"t1": "1", "t2": "2", "t3": "3"} {
+		tasks.byID[id] = &models.Task{ID: id, Status: models.TaskStatusPublished, CorrectAnswer: answer}
+	}
+
+	rdb := newTestRedis(t)
+	repo := newFakeQuizRepo(tasks)
+	svc := NewQuizService(repo, classrooms, NewTaskService(tasks, newFakeSubmissionRepo(), rdb), rdb)
+
+	quiz := &models.Quiz{
+		ClassroomID:      classroom.ID,
+		Title:            "Exam",
+		Mode:             mode,
+		DrawCount:        drawCount,
+		TimeLimitSeconds: 600,
+	}
+	require.NoError(t, svc.CreateQuiz(ctx, "teacher", "Teacher", quiz, []string{"t1", "t2", "t3"}))
+
+	return &quizFixture{svc: svc, repo: repo, rdb: rdb, classroom: classroom, quiz: quiz}
+}
+
+func TestQuizService_CreateQuiz_Validation(t *testing.T) {
+	ctx := context.Background()
+	f := newQuizFixture(t, models.QuizModeFixed, 0)
+
+	quiz := &models.Quiz{ClassroomID: f.classroom.ID, Title: "Bad", Mode: models.QuizModeRandom, DrawCount: 5, TimeLimitSeconds: 60}
+	err := f.svc.CreateQuiz(ctx, "teacher", "Teacher", quiz, []string{"t1", "t2"})
+	assert.ErrorIs(t, err, ErrInvalidQuiz)
+
+	quiz = &models.Quiz{ClassroomID: f.classroom.ID, Title: "Student", TimeLimitSeconds: 60}
+	err = f.svc.CreateQuiz(ctx, "student", "Student", quiz, []string{"t1"})
+	assert.ErrorIs(t, err, ErrQuizForbidden)
+
+	// ученик не видит список задач квиза
+	got, err := f.svc.GetQuiz(ctx, "student", "Student", f.quiz.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Tasks)
+}
+
+func TestQuizService_RandomDraw(t *testing.T) {
+	ctx := context.Background()
+	f := newQuizFixture(t, models.QuizModeRandom, 2)
+
+	session, err := f.svc.StartSession(ctx, f.quiz.ID, "student", "Student")
+	require.NoError(t, err)
+	require.Len(t, session.Answers, 2)
+	assert.NotEqual(t, session.Answers[0].TaskID, session.Answers[1].TaskID)
+
+	// повторный старт возвращает ту же сессию
+	again, err := f.svc.StartSession(ctx, f.quiz.ID, "student", "Student")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, again.ID)
+
+	_, err = f.svc.StartSession(ctx, f.quiz.ID, "teacher", "Teacher")
+	assert.ErrorIs(t, err, ErrQuizForbidden)
+}
+
+func TestQuizService_SubmitHidesResultsUntilRelease(t *testing.T) {
+	ctx := context.Background()
+	f := newQuizFixture(t, models.QuizModeFixed, 0)
+
+	_, err := f.svc.StartSession(ctx, f.quiz.ID, "student", "Student")
+	require.NoError(t, err)
+
+	require.NoError(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "t1", "1"))
+	require.NoError(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "t2", "wrong"))
+	assert.ErrorIs(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "other", "1"), ErrQuizTaskNotFound)
+
+	session, showResults, err := f.svc.Submit(ctx, f.quiz.ID, "student")
+	require.NoError(t, err)
+	assert.False(t, showResults)
+	assert.Equal(t, models.QuizSessionSubmitted, session.Status)
+	assert.Zero(t, session.Score)
+	for _, a := range session.Answers {
+		assert.Empty(t, a.Task.CorrectAnswer)
+	}
+
+	assert.ErrorIs(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "t1", "2"), ErrQuizSessionClosed)
+
+	require.NoError(t, f.svc.ReleaseResults(ctx, "teacher", "Teacher", f.quiz.ID))
+
+	session, showResults, err = f.svc.GetMySession(ctx, f.quiz.ID, "student")
+	require.NoError(t, err)
+	assert.True(t, showResults)
+	assert.Equal(t, 1, session.CorrectCount)
+	assert.Equal(t, 33.3, session.Score)
+	assert.Equal(t, "1", session.Answers[0].Task.CorrectAnswer)
+}
+
+func TestQuizService_ExpiryWorker(t *testing.T) {
+	ctx := context.Background()
+	f := newQuizFixture(t, models.QuizModeFixed, 0)
+
+	session, err := f.svc.StartSession(ctx, f.quiz.ID, "student", "Student")
+	require.NoError(t, err)
+	require.NoError(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "t3", "3"))
+
+	score, err := f.rdb.ZScore(ctx, quizDeadlinesKey, session.ID).Result()
+	require.NoError(t, err)
+	assert.Equal(t, float64(session.ExpiresAt.Unix()), score)
+
+	handled, err := f.svc.ExpireDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, handled)
+
+	// после перезапуска таймеры восстанавливаются из базы
+	f.rdb.Del(ctx, quizDeadlinesKey)
+	require.NoError(t, f.svc.RestoreTimers(ctx))
+
+	handled, err = f.svc.ExpireDue(ctx, session.ExpiresAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+
+	stored := f.repo.sessions[session.ID]
+	assert.Equal(t, models.QuizSessionExpired, stored.Status)
+	assert.Equal(t, 1, stored.CorrectCount)
+	assert.Equal(t, session.ExpiresAt, *stored.SubmittedAt)
+
+	n, _ := f.rdb.ZCard(ctx, quizDeadlinesKey).Result()
+	assert.Zero(t, n)
+
+	_, _, err = f.svc.Submit(ctx, f.quiz.ID, "student")
+	assert.ErrorIs(t, err, ErrQuizSessionExpired)
+}
+
+func TestQuizService_LazyExpiry(t *testing.T) {
+	ctx := context.Background()
+	f := newQuizFixture(t, models.QuizModeFixed, 0)
+
+	session, err := f.svc.StartSession(ctx, f.quiz.ID, "student", "Student")
+	require.NoError(t, err)
+
+	// воркер ещё не сработал, но время вышло — сохранение отклоняется
+	f.repo.sessions[session.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+	assert.ErrorIs(t, f.svc.SaveAnswer(ctx, f.quiz.ID, "student", "t1", "1"), ErrQuizSessionExpired)
+	assert.Equal(t, models.QuizSessionExpired, f.repo.sessions[session.ID].Status)
+}
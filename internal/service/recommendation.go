@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// recentResultsWindow is how many latest submissions drive the target difficulty.
+	recentResultsWindow = 10
+	// prerequisiteSolvedShare is the share of a prerequisite topic's tasks that
+	// must be solved before dependent topics are recommended.
+	prerequisiteSolvedShare = 0.6
+	// retryCooldown keeps a task that was just attempted out of the recommendation.
+	retryCooldown = time.Hour
+)
+
+var difficultyOrder = []models.Difficulty{
+	models.DifficultyEasy,
+	models.DifficultyMedium,
+	models.DifficultyHard,
+	models.DifficultyExtreme,
+}
+
+func difficultyRank(d models.Difficulty) int {
+	for i, o := range difficultyOrder {
+		if o == d {
+			return i
+		}
+	}
+	return 1
+}
+
+// targetDifficulty picks the difficulty level of the next task from the level
+// and accuracy of the latest submissions.
+func targetDifficulty(recent []models.Submission, tasks map[string]*models.Task) (int, string) {
+	if len(recent) == 0 {
+		return 0, "you are just starting, so we begin with an easy task"
+	}
+
+	level := -1
+	correct := 0
+	for _, sub := range recent {
+		if sub.IsCorrect {
+			correct++
+		}
+		if task, ok := tasks[sub.TaskID]; ok && level < 0 {
+			level = difficultyRank(task.Difficulty)
+		}
+	}
+	if level < 0 {
+		level = 0
+	}
+
+	accuracy := float64(correct) / float64(len(recent))
+	switch {
+	case len(recent) >= 3 && accuracy >= 0.8 && level < len(difficultyOrder)-1:
+		return level + 1, fmt.Sprintf("you solved %d of your last %d attempts, so the difficulty goes up", correct, len(recent))
+	case accuracy <= 0.4 && level > 0:
+		return level - 1, fmt.Sprintf("you solved only %d of your last %d attempts, so the difficulty goes down", correct, len(recent))
+	default:
+		return level, fmt.Sprintf("the difficulty matches your recent results (%d of %d solved)", correct, len(recent))
+	}
+}
+
+func prerequisitesMet(topic *models.Topic, rolled map[string]topicAggregate) bool {
+	for _, id := range topic.PrerequisiteIDs() {
+		agg := rolled[id]
+		if agg.total > 0 && float64(agg.solved)/float64(agg.total) < prerequisiteSolvedShare {
+			return false
+		}
+	}
+	return true
+}
+
+func topicSubtree(topics []models.Topic, rootID string) map[string]bool {
+	children := make(map[string][]string)
+	for _, t := range topics {
+		if t.ParentID != nil {
+			children[*t.ParentID] = append(children[*t.ParentID], t.ID)
+		}
+	}
+
+	subtree := map[string]bool{}
+	queue := []string{rootID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if subtree[id] {
+			continue
+		}
+		subtree[id] = true
+		queue = append(queue, children[id]...)
+	}
+	return subtree
+}
+
+// recommendTask scores unsolved published tasks from unlocked topics. Tasks
+// close to the target difficulty win; topics already in progress, earlier
+// unsolved attempts and weaker topics get a bonus. An empty topicID means
+// every topic is eligible.
+func recommendTask(topics []models.Topic, tasks []models.Task, stats []models.TaskAttemptStats, recent []models.Submission, topicID string, now time.Time) *models.TaskRecommendation {
+	byTask := make(map[string]models.TaskAttemptStats, len(stats))
+	for _, st := range stats {
+		byTask[st.TaskID] = st
+	}
+
+	tasksByID := make(map[string]*models.Task, len(tasks))
+	for i := range tasks {
+		tasksByID[tasks[i].ID] = &tasks[i]
+	}
+
+	topicsByID := make(map[string]*models.Topic, len(topics))
+	for i := range topics {
+		topicsByID[topics[i].ID] = &topics[i]
+	}
+
+	var scope map[string]bool
+	if topicID != "" {
+		scope = topicSubtree(topics, topicID)
+	}
+
+	rolled := rollUpProgress(topics, tasks, stats, now)
+	target, difficultyReason := targetDifficulty(recent, tasksByID)
+
+	pick := func(cooldown time.Duration) (*models.Task, float64) {
+		var best *models.Task
+		bestScore := math.Inf(-1)
+
+		for i := range tasks {
+			task := &tasks[i]
+			if task.Status != models.TaskStatusPublished {
+				continue
+			}
+			if scope != nil && !scope[task.TopicID] {
+				continue
+			}
+
+			st, attempted := byTask[task.ID]
+			if st.Solved {
+				continue
+			}
+			if attempted && now.Sub(st.LastAttemptAt) < cooldown {
+				continue
+			}
+
+			topic, ok := topicsByID[task.TopicID]
+			if !ok || !prerequisitesMet(topic, rolled) {
+				continue
+			}
+
+			progress := toTopicProgress(*topic, rolled[topic.ID])
+			score := -2 * math.Abs(float64(difficultyRank(task.Difficulty)-target))
+			score += 1 - progress.Mastery
+			if progress.Attempted > 0 {
+				score += 1.5
+			}
+			if attempted {
+				score += 1
+			}
+
+			if score > bestScore {
+				best, bestScore = task, score
+			}
+		}
+
+		return best, bestScore
+	}
+
+	task, _ := pick(retryCooldown)
+	if task == nil {
+		task, _ = pick(0)
+	}
+	if task == nil {
+		return nil
+	}
+
+	topic := topicsByID[task.TopicID]
+	progress := toTopicProgress(*topic, rolled[topic.ID])
+
+	reasons := []string{difficultyReason}
+	if difficultyRank(task.Difficulty) != target {
+		reasons = append(reasons, fmt.Sprintf("no unsolved %s task is available, so the closest difficulty was chosen",
+			strings.ToLower(string(difficultyOrder[target]))))
+	}
+	if progress.Attempted > 0 {
+		reasons = append(reasons, fmt.Sprintf("you are working on \"%s\" (%d of %d tasks solved)", topic.Title, progress.Solved, progress.Total))
+	} else {
+		reasons = append(reasons, fmt.Sprintf("\"%s\" is a new topic for you", topic.Title))
+	}
+	if len(topic.Prerequisites) > 0 {
+		reasons = append(reasons, "you have covered its prerequisite topics")
+	}
+	if st, ok := byTask[task.ID]; ok {
+		reasons = append(reasons, fmt.Sprintf("you tried this task %d time(s) but have not solved it yet", st.Attempts))
+	}
+
+	return &models.TaskRecommendation{
+		Task:             task,
+		TopicTitle:       topic.Title,
+		TargetDifficulty: difficultyOrder[target],
+		TopicMastery:     progress.Mastery,
+		Reasons:          reasons,
+	}
+}
+
+// RecommendNextTask picks the next task for the user, optionally within a
+// topic and its subtopics. It returns nil when nothing is left to solve.
+func (s *ProgressService) RecommendNextTask(ctx context.Context, userID, topicID string) (*models.TaskRecommendation, error) {
+	ctx, span := otel.Tracer("progress").Start(ctx, "ProgressService.RecommendNextTask")
+	defer span.End()
+
+	_, topicsSpan := otel.Tracer("progress").Start(ctx, "Topics.FindAll")
+	topics, err := s.topics.FindAll(ctx)
+	topicsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, tasksSpan := otel.Tracer("progress").Start(ctx, "Tasks.GetAll")
+	tasks, err := s.tasks.GetAll(ctx)
+	tasksSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, statsSpan := otel.Tracer("progress").Start(ctx, "Submissions.GetStatsByUser")
+	stats, err := s.submissions.GetStatsByUser(ctx, userID)
+	statsSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, recentSpan := otel.Tracer("progress").Start(ctx, "Submissions.GetRecentByUser")
+	recent, err := s.submissions.GetRecentByUser(ctx, userID, recentResultsWindow)
+	recentSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return recommendTask(topics, tasks, stats, recent, topicID, time.Now()), nil
+}
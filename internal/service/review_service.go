@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+
+	// SM-2 answer quality for a correct and an incorrect review.
+	reviewQualityCorrect   = 4
+	reviewQualityIncorrect = 1
+
+	DefaultReviewQueueSize = 20
+	MaxReviewQueueSize     = 100
+)
+
+type ReviewService struct {
+	reviews repository.IReviewRepository
+}
+
+func NewReviewService(reviews repository.IReviewRepository) *ReviewService {
+	return &ReviewService{reviews: reviews}
+}
+
+// applyReview updates the schedule with the SM-2 algorithm for an answer of
+// the given quality (0-5) reviewed at now.
+func applyReview(s *models.ReviewSchedule, quality int, now time.Time) {
+	if quality < 3 {
+		s.Repetitions = 0
+		s.IntervalDays = 1
+		s.Lapses++
+	} else {
+		s.Repetitions++
+		switch s.Repetitions {
+		case 1:
+			s.IntervalDays = 1
+		case 2:
+			s.IntervalDays = 6
+		default:
+			s.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.EaseFactor))
+		}
+	}
+
+	q := float64(5 - quality)
+	s.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if s.EaseFactor < minEaseFactor {
+		s.EaseFactor = minEaseFactor
+	}
+
+	s.LastReviewedAt = now
+	s.DueAt = now.AddDate(0, 0, s.IntervalDays)
+}
+
+// OnSubmission schedules a task for review once it is first solved and moves
+// the schedule on every later review. Correct answers submitted before the
+// task is due are not reviews and leave the schedule as is; a wrong answer
+// is always a lapse.
+func (s *ReviewService) OnSubmission(ctx context.Context, submission *models.Submission) error {
+	ctx, span := otel.Tracer("review").Start(ctx, "ReviewService.OnSubmission")
+	defer span.End()
+
+	schedule, err := s.reviews.Find(ctx, submission.UserID, submission.TaskID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	now := time.Now()
+
+	if schedule == nil {
+		if !submission.IsCorrect {
+			return nil
+		}
+		schedule = &models.ReviewSchedule{
+			UserID:     submission.UserID,
+			TaskID:     submission.TaskID,
+			EaseFactor: defaultEaseFactor,
+		}
+	} else if submission.IsCorrect && now.Before(schedule.DueAt) {
+		return nil
+	}
+
+	quality := reviewQualityIncorrect
+	if submission.IsCorrect {
+		quality = reviewQualityCorrect
+	}
+	applyReview(schedule, quality, now)
+
+	if err := s.reviews.Save(ctx, schedule); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetQueue returns tasks due for review by the end of the current day.
+func (s *ReviewService) GetQueue(ctx context.Context, userID string, limit int) ([]models.ReviewSchedule, error) {
+	ctx, span := otel.Tracer("review").Start(ctx, "ReviewService.GetQueue")
+	defer span.End()
+
+	if limit <= 0 || limit > MaxReviewQueueSize {
+		limit = DefaultReviewQueueSize
+	}
+
+	now := time.Now()
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	schedules, err := s.reviews.FindDue(ctx, userID, endOfDay, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeReviewRepo struct {
+	schedules map[string]*models.ReviewSchedule
+}
+
+func newFakeReviewRepo() *fakeReviewRepo {
+	return &fakeReviewRepo{schedules: make(map[string]*models.ReviewSchedule)}
+}
+
+func (f *fakeReviewRepo) Find(ctx context.Context, userID, taskID string) (*models.ReviewSchedule, error) {
+	s, ok := f.schedules[userID+"/"+taskID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (f *fakeReviewRepo) Save(ctx context.Context, schedule *models.ReviewSchedule) error {
+	cp := *schedule
+	f.schedules[schedule.UserID+"/"+schedule.TaskID] = &cp
+	return nil
+}
+
+func (f *fakeReviewRepo) FindDue(ctx context.Context, userID string, before time.Time, limit int) ([]models.ReviewSchedule, error) {
+	var out []models.ReviewSchedule
+	for _, s := range f.schedules {
+		if s.UserID == userID && s.DueAt.Before(before) && len(out) < limit {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func TestApplyReview_SM2Intervals(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := &models.ReviewSchedule{EaseFactor: defaultEaseFactor}
+
+	applyReview(s, reviewQualityCorrect, now)
+	assert.Equal(t, 1, s.IntervalDays)
+	applyReview(s, reviewQualityCorrect, now)
+	assert.Equal(t, 6, s.IntervalDays)
+	applyReview(s, reviewQualityCorrect, now)
+	assert.Equal(t, 15, s.IntervalDays)
+	assert.Equal(t, now.AddDate(0, 0, 15), s.DueAt)
+	assert.InDelta(t, 2.5, s.EaseFactor, 1e-9, "quality 4 keeps the ease factor")
+
+	applyReview(s, reviewQualityIncorrect, now)
+	assert.Equal(t, 0, s.Repetitions)
+	assert.Equal(t, 1, s.IntervalDays)
+	assert.Equal(t, 1, s.Lapses)
+	assert.InDelta(t, 1.96, s.EaseFactor, 1e-9)
+
+	// фактор лёгкости не опускается ниже 1.3
+	for i := 0; i < 5; i++ {
+		applyReview(s, reviewQualityIncorrect, now)
+	}
+	assert.Equal(t, minEaseFactor, s.EaseFactor)
+}
+
+func TestReviewService_OnSubmission(t *testing.T) {
+	ctx := context.Background()
+
+	tasks := newFakeTaskRepo()
+	tasks.byID["t1"] = &models.Task{ID: "t1", CorrectAnswer: "42"}
+
+	reviews := newFakeReviewRepo()
+	svc := NewReviewService(reviews)
+	taskService := NewTaskService(tasks, newFakeSubmissionRepo(), newTestRedis(t))
+	taskService.AddListener(svc)
+
+	// неверный ответ по ещё не решённой задаче ничего не планирует
+	_, err := taskService.SubmitAnswer(ctx, "t1", "u1", "0")
+	require.NoError(t, err)
+	assert.Empty(t, reviews.schedules)
+
+	_, err = taskService.SubmitAnswer(ctx, "t1", "u1", "42")
+	require.NoError(t, err)
+	s := reviews.schedules["u1/t1"]
+	require.NotNil(t, s)
+	assert.Equal(t, 1, s.Repetitions)
+	due := s.DueAt
+
+	// верный ответ до срока повторения расписание не сдвигает
+	_, err = taskService.SubmitAnswer(ctx, "t1", "u1", "42")
+	require.NoError(t, err)
+	assert.Equal(t, 1, reviews.schedules["u1/t1"].Repetitions)
+	assert.Equal(t, due, reviews.schedules["u1/t1"].DueAt)
+
+	// срок наступил — повторение засчитывается
+	reviews.schedules["u1/t1"].DueAt = time.Now().Add(-time.Hour)
+	_, err = taskService.SubmitAnswer(ctx, "t1", "u1", "42")
+	require.NoError(t, err)
+	assert.Equal(t, 2, reviews.schedules["u1/t1"].Repetitions)
+	assert.Equal(t, 6, reviews.schedules["u1/t1"].IntervalDays)
+
+	_, err = taskService.SubmitAnswer(ctx, "t1", "u1", "0")
+	require.NoError(t, err)
+	assert.Equal(t, 0, reviews.schedules["u1/t1"].Repetitions)
+	assert.Equal(t, 1, reviews.schedules["u1/t1"].Lapses)
+
+	// после ошибки задача вернётся в очередь только завтра
+	queue, err := svc.GetQueue(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	reviews.schedules["u1/t1"].DueAt = time.Now().Add(-time.Minute)
+	queue, err = svc.GetQueue(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Len(t, queue, 1)
+}
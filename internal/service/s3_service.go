@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
@@ -79,4 +80,30 @@ func (s *S3Service) UploadFile(ctx context.Context, file *multipart.FileHeader)
 	log.Printf("[S3] File uploaded successfully: %s", url)
 
 	return url, nil
-}
\ No newline at end of file
+}
+
+// UploadBytes stores generated content under the given key.
+func (s *S3Service) UploadBytes(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	ctx, span := otel.Tracer("s3").Start(ctx, "S3.UploadBytes")
+	defer span.End()
+
+	log.Printf("[S3] Uploading %d bytes to bucket=%s key=%s", len(data), s.BucketName, key)
+
+	uploader := manager.NewUploader(s.Client)
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.BucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("[S3] Upload failed: %v", err)
+		return "", err
+	}
+
+	url := fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.BucketName, key)
+	log.Printf("[S3] File uploaded successfully: %s", url)
+
+	return url, nil
+}
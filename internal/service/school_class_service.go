@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+)
+
+var ErrInvalidSchoolClass = errors.New("invalid school class")
+
+type SchoolClassService struct {
+	repo  repository.ISchoolClassRepository
+	redis *redis.Client
+}
+
+func NewSchoolClassService(repo repository.ISchoolClassRepository, rdb *redis.Client) *SchoolClassService {
+	return &SchoolClassService{
+		repo:  repo,
+		redis: rdb,
+	}
+}
+
+func (s *SchoolClassService) GetAll(ctx context.Context) ([]models.SchoolClass, error) {
+	ctx, span := otel.Tracer("school_class").Start(ctx, "SchoolClassService.GetAll")
+	defer span.End()
+
+	cacheKey := "school_classes:all"
+	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
+		var classes []models.SchoolClass
+		if err := json.Unmarshal([]byte(cached), &classes); err == nil {
+			return classes, nil
+		}
+	}
+
+	classes, err := s.repo.FindAll(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	data, _ := json.Marshal(classes)
+	s.redis.Set(ctx, cacheKey, data, 10*time.Minute)
+
+	return classes, nil
+}
+
+func (s *SchoolClassService) GetByID(ctx context.Context, id string) (*models.SchoolClass, error) {
+	ctx, span := otel.Tracer("school_class").Start(ctx, "SchoolClassService.GetByID")
+	defer span.End()
+
+	class, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return class, nil
+}
+
+// normalizeSchoolClassCode brings a class code to the form classes are
+// stored under, so codes typed by hand still match.
+func normalizeSchoolClassCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *SchoolClassService) Create(ctx context.Context, class *models.SchoolClass) error {
+	ctx, span := otel.Tracer("school_class").Start(ctx, "SchoolClassService.Create")
+	defer span.End()
+
+	class.Code = normalizeSchoolClassCode(class.Code)
+
+	err := s.repo.Create(ctx, class)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			e := errors.New("school class code already exists")
+			span.RecordError(e)
+			return e
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	s.redis.Del(context.Background(), "school_classes:all")
+	return nil
+}
+
+func (s *SchoolClassService) Update(ctx context.Context, class *models.SchoolClass) error {
+	ctx, span := otel.Tracer("school_class").Start(ctx, "SchoolClassService.Update")
+	defer span.End()
+
+	class.Code = normalizeSchoolClassCode(class.Code)
+
+	err := s.repo.Update(ctx, class)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			e := errors.New("school class code already exists")
+			span.RecordError(e)
+			return e
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	s.redis.Del(context.Background(), "school_classes:all", "topics:all")
+	return nil
+}
+
+func (s *SchoolClassService) Delete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("school_class").Start(ctx, "SchoolClassService.Delete")
+	defer span.End()
+
+	err := s.repo.Delete(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			e := errors.New("school class is used by topics")
+			span.RecordError(e)
+			return e
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	s.redis.Del(context.Background(), "school_classes:all")
+	return nil
+}
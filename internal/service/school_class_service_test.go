@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-platform/internal/models"
+)
+
+type fakeSchoolClassRepo struct {
+	classes      []models.SchoolClass
+	findAllCalls int
+}
+
+func newFakeSchoolClassRepo(codes ...string) *fakeSchoolClassRepo {
+	repo := &fakeSchoolClassRepo{}
+	for _, code := range codes {
+		repo.classes = append(repo.classes, models.SchoolClass{
+			ID:       "class-" + code,
+			Code:     code,
+			Title:    code,
+			IsActive: true,
+		})
+	}
+	return repo
+}
+
+func (f *fakeSchoolClassRepo) Create(ctx context.Context, class *models.SchoolClass) error {
+	if class.ID == "" {
+		class.ID = "class-" + class.Code
+	}
+	f.classes = append(f.classes, *class)
+	return nil
+}
+
+func (f *fakeSchoolClassRepo) FindAll(ctx context.Context) ([]models.SchoolClass, error) {
+	f.findAllCalls++
+	return f.classes, nil
+}
+
+func (f *fakeSchoolClassRepo) FindByID(ctx context.Context, id string) (*models.SchoolClass, error) {
+	for i := range f.classes {
+		if f.classes[i].ID == id {
+			return &f.classes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeSchoolClassRepo) FindByCode(ctx context.Context, code string) (*models.SchoolClass, error) {
+	for i := range f.classes {
+		if f.classes[i].Code == code {
+			return &f.classes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeSchoolClassRepo) Update(ctx context.Context, class *models.SchoolClass) error {
+	for i := range f.classes {
+		if f.classes[i].ID == class.ID {
+			f.classes[i] = *class
+		}
+	}
+	return nil
+}
+
+func (f *fakeSchoolClassRepo) Delete(ctx context.Context, id string) error {
+	out := make([]models.SchoolClass, 0, len(f.classes))
+	for _, c := range f.classes {
+		if c.ID != id {
+			out = append(out, c)
+		}
+	}
+	f.classes = out
+	return nil
+}
+
+func TestSchoolClassService_GetAll_UsesCache(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	repo := newFakeSchoolClassRepo("SEVEN", "EIGHT")
+
+	svc := NewSchoolClassService(repo, rdb)
+
+	classes, err := svc.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, classes, 2)
+
+	classes, err = svc.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, classes, 2)
+	assert.Equal(t, 1, repo.findAllCalls)
+}
+
+func TestSchoolClassService_Create_NormalizesCodeAndInvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	rdb := newTestRedis(t)
+	repo := newFakeSchoolClassRepo()
+
+	svc := NewSchoolClassService(repo, rdb)
+	require.NoError(t, rdb.Set(ctx, "school_classes:all", "[]", 0).Err())
+
+	class := &models.SchoolClass{Code: " grade_1 ", Title: "1st grade", IsActive: true}
+	require.NoError(t, svc.Create(ctx, class))
+
+	assert.Equal(t, "GRADE_1", class.Code)
+	_, err := rdb.Get(ctx, "school_classes:all").Result()
+	assert.Error(t, err)
+}
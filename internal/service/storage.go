@@ -0,0 +1,9 @@
+package service
+
+import "context"
+
+// FileStorage stores generated files and returns their public URL.
+// *S3Service implements it.
+type FileStorage interface {
+	UploadBytes(ctx context.Context, key, contentType string, data []byte) (string, error)
+}
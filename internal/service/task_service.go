@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"encoding/json"
 	"learning-platform/internal/models"
@@ -10,20 +11,40 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
+// SubmissionListener is notified after a submission has been graded, either
+// automatically on submit or by a teacher for MANUAL tasks. The submission
+// passed to listeners has its Task loaded.
+type SubmissionListener interface {
+	OnSubmission(ctx context.Context, submission *models.Submission) error
+}
+
+var ErrInvalidGradingMode = errors.New("manual grading is only available for TEXT answers")
+
 type TaskService struct {
-	taskRepo repository.ITaskRepository
-	redis    *redis.Client
+	taskRepo    repository.ITaskRepository
+	submissions repository.ISubmissionRepository
+	redis       *redis.Client
+	listeners   []SubmissionListener
 }
 
-func NewTaskService(repo repository.ITaskRepository, rdb *redis.Client) *TaskService {
+func NewTaskService(repo repository.ITaskRepository, submissions repository.ISubmissionRepository, rdb *redis.Client) *TaskService {
 	return &TaskService{
-		taskRepo: repo,
-		redis:    rdb,
+		taskRepo:    repo,
+		submissions: submissions,
+		redis:       rdb,
 	}
 }
 
+// AddListener registers a listener for saved submissions. Listener errors are
+// recorded on the span but do not fail the submission.
+func (s *TaskService) AddListener(l SubmissionListener) {
+	s.listeners = append(s.listeners, l)
+}
+
 func (s *TaskService) GetAllTasks(ctx context.Context) ([]models.Task, error) {
 	ctx, span := otel.Tracer("task").Start(ctx, "TaskService.GetAllTasks")
 	defer span.End()
@@ -80,10 +101,26 @@ func (s *TaskService) PublishTask(ctx context.Context, id string) (*models.Task,
 	return task, nil
 }
 
+// validateGradingMode defaults the grading mode to AUTO and only allows
+// manual grading for free-text answers.
+func validateGradingMode(task *models.Task) error {
+	if task.GradingMode == "" {
+		task.GradingMode = models.GradingModeAuto
+	}
+	if task.GradingMode == models.GradingModeManual && task.AnswerType != models.AnswerTypeText {
+		return ErrInvalidGradingMode
+	}
+	return nil
+}
+
 func (s *TaskService) CreateTask(ctx context.Context, task *models.Task) error {
 	ctx, span := otel.Tracer("task").Start(ctx, "TaskService.CreateTask")
 	defer span.End()
 
+	if err := validateGradingMode(task); err != nil {
+		return err
+	}
+
 	err := s.taskRepo.Create(ctx, task)
 	if err != nil {
 		span.RecordError(err)
@@ -106,6 +143,37 @@ func (s *TaskService) GetTaskById(ctx context.Context, id string) (*models.Task,
 	return task, nil
 }
 
+// publishedTasks loads the tasks of an assignment or a quiz in the given
+// order, skipping repeated IDs. Every task has to exist and be published.
+func (s *TaskService) publishedTasks(ctx context.Context, ids []string) ([]*models.Task, error) {
+	seen := make(map[string]bool, len(ids))
+	tasks := make([]*models.Task, 0, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		task, err := s.GetTaskById(ctx, id)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if task == nil {
+			return nil, errors.New("task not found: " + id)
+		}
+
+		if task.Status != models.TaskStatusPublished {
+			return nil, errors.New("task is not published: " + id)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
 func (s *TaskService) GetTasksByTopic(ctx context.Context, topicID string) ([]models.Task, error) {
 	ctx, span := otel.Tracer("task").Start(ctx, "TaskService.GetTasksByTopic")
 	defer span.End()
@@ -119,10 +187,27 @@ func (s *TaskService) GetTasksByTopic(ctx context.Context, topicID string) ([]mo
 	return tasks, nil
 }
 
+func (s *TaskService) GetTasksBySchoolClass(ctx context.Context, schoolClass string) ([]models.Task, error) {
+	ctx, span := otel.Tracer("task").Start(ctx, "TaskService.GetTasksBySchoolClass")
+	defer span.End()
+
+	tasks, err := s.taskRepo.GetBySchoolClass(ctx, schoolClass)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
 func (s *TaskService) UpdateTask(ctx context.Context, task *models.Task) error {
 	ctx, span := otel.Tracer("task").Start(ctx, "TaskService.UpdateTask")
 	defer span.End()
 
+	if err := validateGradingMode(task); err != nil {
+		return err
+	}
+
 	err := s.taskRepo.Update(ctx, task)
 	if err != nil {
 		span.RecordError(err)
@@ -158,17 +243,70 @@ func (s *TaskService) GetTasksByAuthor(ctx context.Context, authorID string) ([]
 	return tasks, nil
 }
 
-func (s *TaskService) SubmitAnswer(ctx context.Context, id string, userAnswer string) (bool, error) {
-    ctx, span := otel.Tracer("task").Start(ctx, "TaskService.SubmitAnswer")
+func (s *TaskService) SubmitAnswer(ctx context.Context, id string, userID string, userAnswer string) (bool, error) {
+    submission := &models.Submission{
+        TaskID: id,
+        UserID: userID,
+        Answer: userAnswer,
+    }
+
+    if err := s.Submit(ctx, submission); err != nil {
+        return false, err
+    }
+
+    return submission.IsCorrect, nil
+}
+
+// Submit checks the answer and records the submission. Callers fill in
+// TaskID, UserID and Answer plus any assignment context; IsCorrect and Status
+// are set here. Answers to MANUAL tasks are left pending review.
+func (s *TaskService) Submit(ctx context.Context, submission *models.Submission) error {
+    ctx, span := otel.Tracer("task").Start(ctx, "TaskService.Submit")
     defer span.End()
 
-    task, err := s.taskRepo.GetByID(ctx, id)
+    task, err := s.taskRepo.GetByID(ctx, submission.TaskID)
     if err != nil {
         span.RecordError(err)
-        return false, err
+        return err
+    }
+
+    if task.GradingMode == models.GradingModeManual {
+        submission.Status = models.SubmissionStatusPendingReview
+        submission.IsCorrect = false
+    } else {
+        submission.Status = models.SubmissionStatusGraded
+        submission.IsCorrect = checkAnswer(task, submission.Answer)
     }
 
-    isCorrect := task.CorrectAnswer == userAnswer
+    _, saveSpan := otel.Tracer("task").Start(ctx, "Submissions.Save")
+    err = s.submissions.Create(ctx, submission)
+    saveSpan.End()
+
+    if err != nil {
+        span.RecordError(err)
+        return err
+    }
 
-    return isCorrect, nil
-}
\ No newline at end of file
+    submission.Task = task
+    if submission.Status == models.SubmissionStatusGraded {
+        s.NotifyListeners(ctx, submission)
+    }
+
+    return nil
+}
+
+// NotifyListeners passes a graded submission with its Task loaded to the
+// submission listeners. Submit calls it for auto-graded answers; manually
+// graded ones are passed on once a teacher grades them.
+func (s *TaskService) NotifyListeners(ctx context.Context, submission *models.Submission) {
+    span := trace.SpanFromContext(ctx)
+    for _, l := range s.listeners {
+        if err := l.OnSubmission(ctx, submission); err != nil {
+            span.RecordError(err)
+        }
+    }
+}
+
+func checkAnswer(task *models.Task, answer string) bool {
+    return task.CorrectAnswer == answer
+}
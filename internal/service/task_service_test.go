@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"learning-platform/internal/models"
 )
@@ -63,13 +66,27 @@ func (f *fakeTaskRepo) Create(ctx context.Context, task *models.Task) error {
 }
 
 func (f *fakeTaskRepo) GetByID(ctx context.Context, id string) (*models.Task, error) {
-	return f.byID[id], nil
+	task, ok := f.byID[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return task, nil
 }
 
 func (f *fakeTaskRepo) GetByTopic(ctx context.Context, topicID string) ([]models.Task, error) {
 	return f.byTopic[topicID], nil
 }
 
+func (f *fakeTaskRepo) GetBySchoolClass(ctx context.Context, schoolClass string) ([]models.Task, error) {
+	var out []models.Task
+	for _, t := range f.byID {
+		if t.Topic != nil && t.Topic.SchoolClass == schoolClass && t.Status == models.TaskStatusPublished {
+			out = append(out, *t)
+		}
+	}
+	return out, nil
+}
+
 func (f *fakeTaskRepo) Update(ctx context.Context, task *models.Task) error {
 	if existing, ok := f.byID[task.ID]; ok && existing != nil {
 		*existing = *task
@@ -86,6 +103,146 @@ func (f *fakeTaskRepo) GetByAuthor(ctx context.Context, authorID string) ([]mode
 	return f.byAuthor[authorID], nil
 }
 
+type fakeSubmissionRepo struct {
+	submissions []models.Submission
+	stats       []models.TaskAttemptStats
+	// tasks, when set, is used to load Task in FindByID
+	tasks *fakeTaskRepo
+}
+
+func newFakeSubmissionRepo() *fakeSubmissionRepo {
+	return &fakeSubmissionRepo{}
+}
+
+func (f *fakeSubmissionRepo) Create(ctx context.Context, submission *models.Submission) error {
+	if submission.ID == "" {
+		submission.ID = fmt.Sprintf("sub-%d", len(f.submissions)+1)
+	}
+	f.submissions = append(f.submissions, *submission)
+	return nil
+}
+
+func (f *fakeSubmissionRepo) GetStatsByUser(ctx context.Context, userID string) ([]models.TaskAttemptStats, error) {
+	return f.stats, nil
+}
+
+func (f *fakeSubmissionRepo) GetRecentByUser(ctx context.Context, userID string, limit int) ([]models.Submission, error) {
+	var out []models.Submission
+	for i := len(f.submissions) - 1; i >= 0 && len(out) < limit; i-- {
+		if f.submissions[i].UserID == userID && f.submissions[i].Status != models.SubmissionStatusPendingReview {
+			out = append(out, f.submissions[i])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSubmissionRepo) CountCorrect(ctx context.Context, userID, taskID string) (int64, error) {
+	var n int64
+	for _, sub := range f.submissions {
+		if sub.UserID == userID && sub.TaskID == taskID && sub.IsCorrect {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeSubmissionRepo) GetFirstSolves(ctx context.Context, userID string) ([]models.SolvedTask, error) {
+	return nil, nil
+}
+
+func (f *fakeSubmissionRepo) GetAssignmentStats(ctx context.Context, assignmentIDs []string, userID string) ([]models.AssignmentTaskStats, error) {
+	wanted := make(map[string]bool, len(assignmentIDs))
+	for _, id := range assignmentIDs {
+		wanted[id] = true
+	}
+
+	byKey := make(map[string]*models.AssignmentTaskStats)
+	var order []string
+	for _, sub := range f.submissions {
+		if sub.AssignmentID == nil || !wanted[*sub.AssignmentID] || (userID != "" && sub.UserID != userID) {
+			continue
+		}
+		key := *sub.AssignmentID + "/" + sub.UserID + "/" + sub.TaskID
+		st, ok := byKey[key]
+		if !ok {
+			st = &models.AssignmentTaskStats{AssignmentID: *sub.AssignmentID, UserID: sub.UserID, TaskID: sub.TaskID}
+			byKey[key] = st
+			order = append(order, key)
+		}
+		st.Attempts++
+		st.Solved = st.Solved || sub.IsCorrect
+		st.SolvedOnTime = st.SolvedOnTime || (sub.IsCorrect && !sub.IsLate)
+	}
+
+	result := make([]models.AssignmentTaskStats, 0, len(order))
+	for _, key := range order {
+		result = append(result, *byKey[key])
+	}
+	return result, nil
+}
+
+func (f *fakeSubmissionRepo) FindByID(ctx context.Context, id string) (*models.Submission, error) {
+	for _, sub := range f.submissions {
+		if sub.ID == id {
+			if f.tasks != nil {
+				sub.Task = f.tasks.byID[sub.TaskID]
+			}
+			return &sub, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *fakeSubmissionRepo) FindByUserAndTask(ctx context.Context, userID, taskID string) ([]models.Submission, error) {
+	var out []models.Submission
+	for i := len(f.submissions) - 1; i >= 0; i-- {
+		if f.submissions[i].UserID == userID && f.submissions[i].TaskID == taskID {
+			out = append(out, f.submissions[i])
+		}
+	}
+	return out, nil
+}
+
+// FindPendingReview only filters by author; assignment access is checked by
+// the grading service tests through Grade.
+func (f *fakeSubmissionRepo) FindPendingReview(ctx context.Context, graderID, taskID string) ([]models.Submission, error) {
+	var out []models.Submission
+	for _, sub := range f.submissions {
+		if sub.Status != models.SubmissionStatusPendingReview || (taskID != "" && sub.TaskID != taskID) {
+			continue
+		}
+		if graderID != "" && f.tasks != nil {
+			if task := f.tasks.byID[sub.TaskID]; task == nil || task.AuthorID != graderID {
+				continue
+			}
+		}
+		out = append(out, sub)
+	}
+	return out, nil
+}
+
+func (f *fakeSubmissionRepo) Grade(ctx context.Context, submission *models.Submission) (bool, error) {
+	for i := range f.submissions {
+		if f.submissions[i].ID == submission.ID && f.submissions[i].Status == models.SubmissionStatusPendingReview {
+			f.submissions[i] = *submission
+			f.submissions[i].Task = nil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeSubmissionRepo) Regrade(ctx context.Context, submission *models.Submission) error {
+	for i := range f.submissions {
+		if f.submissions[i].ID == submission.ID {
+			f.submissions[i] = *submission
+			f.submissions[i].Task = nil
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
 func newTestRedis(t *testing.T) *redis.Client {
 	mr, err := miniredis.Run()
 	require.NoError(t, err)
@@ -117,7 +274,7 @@ func TestTaskService_GetAllTasks_UsesCache(t *testing.T) {
 	}
 	repo.all = []models.Task{task1, task2}
 
-	svc := NewTaskService(repo, rdb)
+	svc := NewTaskService(repo, newFakeSubmissionRepo(), rdb)
 
 	tasks1, err := svc.GetAllTasks(ctx)
 	require.NoError(t, err)
@@ -152,7 +309,7 @@ func TestTaskService_PublishTask_InvalidatesCache(t *testing.T) {
 	}
 	repo.byID[taskID] = task
 
-	svc := NewTaskService(repo, rdb)
+	svc := NewTaskService(repo, newFakeSubmissionRepo(), rdb)
 
 	data, _ := json.Marshal([]models.Task{*task})
 	require.NoError(t, rdb.Set(ctx, "tasks:all", data, 10*time.Minute).Err())
@@ -171,7 +328,7 @@ func TestTaskService_Create_Update_Delete_InvalidatesCache(t *testing.T) {
 	ctx := context.Background()
 	rdb := newTestRedis(t)
 	repo := newFakeTaskRepo()
-	svc := NewTaskService(repo, rdb)
+	svc := NewTaskService(repo, newFakeSubmissionRepo(), rdb)
 
 	task := &models.Task{
 		ID:       "task-1",
@@ -198,3 +355,24 @@ func TestTaskService_Create_Update_Delete_InvalidatesCache(t *testing.T) {
 	_, err = rdb.Get(ctx, "tasks:all").Result()
 	assert.Error(t, err, "после DeleteTask кеш должен быть удалён")
 }
+
+func TestTaskService_SubmitAnswer_RecordsSubmission(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTaskRepo()
+	repo.byID["task-1"] = &models.Task{ID: "task-1", CorrectAnswer: "42"}
+	submissions := newFakeSubmissionRepo()
+
+	svc := NewTaskService(repo, submissions, newTestRedis(t))
+
+	correct, err := svc.SubmitAnswer(ctx, "task-1", "user-1", "41")
+	require.NoError(t, err)
+	assert.False(t, correct)
+
+	correct, err = svc.SubmitAnswer(ctx, "task-1", "user-1", "42")
+	require.NoError(t, err)
+	assert.True(t, correct)
+
+	require.Len(t, submissions.submissions, 2)
+	assert.Equal(t, "user-1", submissions.submissions[1].UserID)
+	assert.True(t, submissions.submissions[1].IsCorrect)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"learning-platform/internal/models"
 	"learning-platform/internal/repository"
@@ -11,22 +12,107 @@ import (
 	"time"
 )
 
+var ErrInvalidPrerequisites = errors.New("invalid topic prerequisites")
+
 type TopicService struct {
-	repo repository.ITopicRepository
-	redis *redis.Client
+	repo    repository.ITopicRepository
+	classes repository.ISchoolClassRepository
+	redis   *redis.Client
 }
 
-func NewTopicService(repo repository.ITopicRepository, rdb *redis.Client) *TopicService {
+func NewTopicService(repo repository.ITopicRepository, classes repository.ISchoolClassRepository, rdb *redis.Client) *TopicService {
   return &TopicService{
-    repo:  repo,
-    redis: rdb,
+    repo:    repo,
+    classes: classes,
+    redis:   rdb,
   }
 }
 
+func (s *TopicService) validateSchoolClass(ctx context.Context, code string) error {
+	class, err := s.classes.FindByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if class == nil || !class.IsActive {
+		return ErrInvalidSchoolClass
+	}
+	return nil
+}
+
+// validatePrerequisites checks that prerequisites exist and do not form a cycle
+// through the topic being saved.
+func (s *TopicService) validatePrerequisites(ctx context.Context, topic *models.Topic) error {
+	if len(topic.Prerequisites) == 0 {
+		return nil
+	}
+
+	topics, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	graph := make(map[string][]string, len(topics))
+	for _, t := range topics {
+		graph[t.ID] = t.PrerequisiteIDs()
+	}
+
+	seen := make(map[string]bool, len(topic.Prerequisites))
+	for i := range topic.Prerequisites {
+		id := topic.Prerequisites[i].PrerequisiteID
+		if _, ok := graph[id]; !ok || id == topic.ID || seen[id] {
+			return ErrInvalidPrerequisites
+		}
+		seen[id] = true
+		topic.Prerequisites[i].TopicID = topic.ID
+	}
+
+	if topic.ID == "" {
+		return nil
+	}
+	graph[topic.ID] = topic.PrerequisiteIDs()
+
+	visited := make(map[string]bool)
+	var reaches func(id string) bool
+	reaches = func(id string) bool {
+		if id == topic.ID {
+			return true
+		}
+		if visited[id] {
+			return false
+		}
+		visited[id] = true
+		for _, next := range graph[id] {
+			if reaches(next) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, id := range graph[topic.ID] {
+		if reaches(id) {
+			return ErrInvalidPrerequisites
+		}
+	}
+
+	return nil
+}
+
 func (s *TopicService) CreateTopic(ctx context.Context, topic *models.Topic) error {
 	ctx, span := otel.Tracer("topic").Start(ctx, "TopicService.CreateTopic")
 	defer span.End()
 
+	topic.SchoolClass = normalizeSchoolClassCode(topic.SchoolClass)
+	if err := s.validateSchoolClass(ctx, topic.SchoolClass); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.validatePrerequisites(ctx, topic); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
 	err := s.repo.Create(ctx, topic)
 	if err != nil {
 		span.RecordError(err)
@@ -62,6 +148,19 @@ func (s *TopicService) GetAllTopics(ctx context.Context) ([]models.Topic, error)
   	return topics, nil
 }
 
+func (s *TopicService) GetTopicsBySchoolClass(ctx context.Context, schoolClass string) ([]models.Topic, error) {
+	ctx, span := otel.Tracer("topic").Start(ctx, "TopicService.GetTopicsBySchoolClass")
+	defer span.End()
+
+	topics, err := s.repo.FindBySchoolClass(ctx, schoolClass)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return topics, nil
+}
+
 func (s *TopicService) GetTopicById(ctx context.Context, id string) (*models.Topic, error) {
 	ctx, span := otel.Tracer("topic").Start(ctx, "TopicService.GetTopicById")
 	defer span.End()
@@ -79,7 +178,33 @@ func (s *TopicService) UpdateTopic(ctx context.Context, topic *models.Topic) err
 	ctx, span := otel.Tracer("topic").Start(ctx, "TopicService.UpdateTopic")
 	defer span.End()
 
-	err := s.repo.Update(ctx, topic)
+	existing, err := s.repo.FindByID(ctx, topic.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	topic.SchoolClass = normalizeSchoolClassCode(topic.SchoolClass)
+	// topics of a deactivated class can still be edited, they just can't
+	// be moved into one
+	if existing == nil || existing.SchoolClass != topic.SchoolClass {
+		if err := s.validateSchoolClass(ctx, topic.SchoolClass); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	// prerequisites are kept unless the caller sets them explicitly
+	if topic.Prerequisites == nil {
+		if existing != nil {
+			topic.Prerequisites = existing.Prerequisites
+		}
+	} else if err := s.validatePrerequisites(ctx, topic); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.repo.Update(ctx, topic)
 	if err != nil {
 		span.RecordError(err)
 		return err
@@ -36,6 +36,16 @@ func (f *fakeTopicRepo) FindAll(ctx context.Context) ([]models.Topic, error) {
 	return f.topics, nil
 }
 
+func (f *fakeTopicRepo) FindBySchoolClass(ctx context.Context, schoolClass string) ([]models.Topic, error) {
+	out := make([]models.Topic, 0)
+	for _, t := range f.topics {
+		if t.SchoolClass == schoolClass {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
 func (f *fakeTopicRepo) FindByID(ctx context.Context, id string) (*models.Topic, error) {
 	for i := range f.topics {
 		if f.topics[i].ID == id {
@@ -76,7 +86,7 @@ func TestTopicService_GetAllTopics_UsesCache(t *testing.T) {
 		{ID: "topic-2", Title: "Topic 2"},
 	}
 
-	svc := NewTopicService(repo, redisClient)
+	svc := NewTopicService(repo, newFakeSchoolClassRepo("SEVEN"), redisClient)
 
 	topics1, err := svc.GetAllTopics(ctx)
 	require.NoError(t, err)
@@ -99,11 +109,12 @@ func TestTopicService_Create_Update_Delete_InvalidatesCache(t *testing.T) {
 	ctx := context.Background()
 	redisClient := newTestRedis(t)
 	repo := newFakeTopicRepo()
-	svc := NewTopicService(repo, redisClient)
+	svc := NewTopicService(repo, newFakeSchoolClassRepo("SEVEN"), redisClient)
 
 	topic := &models.Topic{
-		ID:    "topic-1",
-		Title: "Initial",
+		ID:          "topic-1",
+		Title:       "Initial",
+		SchoolClass: "SEVEN",
 	}
 
 	err := svc.CreateTopic(ctx, topic)
@@ -130,3 +141,89 @@ func TestTopicService_Create_Update_Delete_InvalidatesCache(t *testing.T) {
 	_, err = redisClient.Get(ctx, "topics:all").Result()
 	assert.Error(t, err, "после DeleteTopic кеш topics:all должен быть очищен")
 }
+
+func TestTopicService_CreateTopic_RejectsUnknownSchoolClass(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTopicRepo()
+	classes := newFakeSchoolClassRepo("SEVEN")
+	classes.classes = append(classes.classes, models.SchoolClass{ID: "class-old", Code: "OLD", IsActive: false})
+	svc := NewTopicService(repo, classes, newTestRedis(t))
+
+	err := svc.CreateTopic(ctx, &models.Topic{Title: "Algebra", SchoolClass: "TWELVE"})
+	assert.ErrorIs(t, err, ErrInvalidSchoolClass)
+
+	err = svc.CreateTopic(ctx, &models.Topic{Title: "Algebra", SchoolClass: "OLD"})
+	assert.ErrorIs(t, err, ErrInvalidSchoolClass)
+
+	assert.Empty(t, repo.topics)
+}
+
+func TestTopicService_CreateTopic_NormalizesSchoolClass(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTopicRepo()
+	svc := NewTopicService(repo, newFakeSchoolClassRepo("SEVEN"), newTestRedis(t))
+
+	// коды классов хранятся в верхнем регистре без пробелов
+	topic := &models.Topic{Title: "Algebra", SchoolClass: " seven "}
+	require.NoError(t, svc.CreateTopic(ctx, topic))
+	assert.Equal(t, "SEVEN", topic.SchoolClass)
+}
+
+func TestTopicService_UpdateTopic_DeactivatedSchoolClass(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTopicRepo()
+	repo.topics = []models.Topic{{ID: "topic-1", Title: "Algebra", SchoolClass: "OLD"}}
+	classes := newFakeSchoolClassRepo("SEVEN")
+	classes.classes = append(classes.classes, models.SchoolClass{ID: "class-old", Code: "OLD", IsActive: false})
+	svc := NewTopicService(repo, classes, newTestRedis(t))
+
+	// тему выключенного класса можно править, не перенося её
+	require.NoError(t, svc.UpdateTopic(ctx, &models.Topic{ID: "topic-1", Title: "Algebra I", SchoolClass: "OLD"}))
+	assert.Equal(t, "Algebra I", repo.topics[0].Title)
+
+	require.NoError(t, svc.UpdateTopic(ctx, &models.Topic{ID: "topic-1", Title: "Algebra I", SchoolClass: "SEVEN"}))
+
+	// а перенести в выключенный класс нельзя
+	err := svc.UpdateTopic(ctx, &models.Topic{ID: "topic-1", Title: "Algebra I", SchoolClass: "OLD"})
+	assert.ErrorIs(t, err, ErrInvalidSchoolClass)
+	assert.Equal(t, "SEVEN", repo.topics[0].SchoolClass)
+}
+
+func TestTopicService_GetTopicsBySchoolClass(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTopicRepo()
+	repo.topics = []models.Topic{
+		{ID: "topic-1", SchoolClass: "SEVEN"},
+		{ID: "topic-2", SchoolClass: "EIGHT"},
+	}
+	svc := NewTopicService(repo, newFakeSchoolClassRepo("SEVEN", "EIGHT"), newTestRedis(t))
+
+	topics, err := svc.GetTopicsBySchoolClass(ctx, "EIGHT")
+	require.NoError(t, err)
+	require.Len(t, topics, 1)
+	assert.Equal(t, "topic-2", topics[0].ID)
+}
+
+func TestTopicService_Prerequisites(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTopicRepo()
+	repo.topics = []models.Topic{
+		{ID: "fractions", SchoolClass: "SEVEN"},
+		{ID: "equations", SchoolClass: "SEVEN", Prerequisites: []models.TopicPrerequisite{{TopicID: "equations", PrerequisiteID: "fractions"}}},
+	}
+	svc := NewTopicService(repo, newFakeSchoolClassRepo("SEVEN"), newTestRedis(t))
+
+	err := svc.CreateTopic(ctx, &models.Topic{SchoolClass: "SEVEN", Prerequisites: []models.TopicPrerequisite{{PrerequisiteID: "missing"}}})
+	assert.ErrorIs(t, err, ErrInvalidPrerequisites)
+
+	// fractions -> equations -> fractions образует цикл
+	err = svc.UpdateTopic(ctx, &models.Topic{ID: "fractions", SchoolClass: "SEVEN", Prerequisites: []models.TopicPrerequisite{{PrerequisiteID: "equations"}}})
+	assert.ErrorIs(t, err, ErrInvalidPrerequisites)
+
+	// без списка пререквизитов обновление их сохраняет
+	require.NoError(t, svc.UpdateTopic(ctx, &models.Topic{ID: "equations", Title: "Equations", SchoolClass: "SEVEN"}))
+	assert.Equal(t, []string{"fractions"}, repo.topics[1].PrerequisiteIDs())
+
+	require.NoError(t, svc.UpdateTopic(ctx, &models.Topic{ID: "equations", SchoolClass: "SEVEN", Prerequisites: []models.TopicPrerequisite{}}))
+	assert.Empty(t, repo.topics[1].PrerequisiteIDs())
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"learning-platform/internal/kafka"
+	"learning-platform/internal/models"
+)
+
+const emailChangeTTL = 15 * time.Minute
+
+var (
+	ErrEmailTaken             = errors.New("email already taken")
+	ErrEmailUnchanged         = errors.New("this is already your email")
+	ErrNoPendingEmailChange   = errors.New("no pending email change")
+	ErrInvalidEmailChangeCode = errors.New("invalid or expired code")
+)
+
+func (s *UserService) findUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// RequestEmailChange emails a code to the new address and tells the current
+// address about the request. The email changes once ConfirmEmailChange gets
+// the code; until then the user keeps signing in with the current one.
+func (s *UserService) RequestEmailChange(ctx context.Context, userID, newEmail string) (*models.EmailChange, error) {
+	ctx, span := otel.Tracer("user").Start(ctx, "UserService.RequestEmailChange")
+	defer span.End()
+
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		span.RecordError(ErrEmailUnchanged)
+		return nil, ErrEmailUnchanged
+	}
+
+	existing, err := s.users.FindByEmail(ctx, newEmail)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if existing != nil {
+		span.RecordError(ErrEmailTaken)
+		return nil, ErrEmailTaken
+	}
+
+	if err := s.limiter.CodeSent(ctx, models.VerificationPurposeEmailChange, newEmail); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	change := &models.EmailChange{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		Code:      code,
+		ExpiresAt: time.Now().Add(emailChangeTTL),
+	}
+	if err := s.emailChanges.Replace(ctx, change); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, kSpan := otel.Tracer("kafka").Start(ctx, "Kafka.SendEmailChangeCode")
+	s.emailProducer.SendAsync(kafka.EmailMessage{
+		Email:   newEmail,
+		Subject: "Confirm your new email address",
+		Code:    code,
+	})
+	s.emailProducer.SendAsync(kafka.EmailMessage{
+		Email:   user.Email,
+		Subject: "Security alert: email change requested",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to change the email of your account to %s. "+
+			"Nothing changes until the code we sent to that address is entered.\n\n"+
+			"If this wasn't you, change your password and cancel the request in your profile.",
+			user.DisplayName, newEmail),
+	})
+	kSpan.End()
+
+	return change, nil
+}
+
+// ConfirmEmailChange switches to the new email. Wrong codes count towards
+// the verification attempt limit; once it is used up the change has to be
+// requested again.
+func (s *UserService) ConfirmEmailChange(ctx context.Context, userID, code string) (*models.User, error) {
+	ctx, span := otel.Tracer("user").Start(ctx, "UserService.ConfirmEmailChange")
+	defer span.End()
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	change, err := s.emailChanges.FindByUser(ctx, user.ID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if change == nil || !change.ExpiresAt.After(time.Now()) {
+		span.RecordError(ErrNoPendingEmailChange)
+		return nil, ErrNoPendingEmailChange
+	}
+
+	if subtle.ConstantTimeCompare([]byte(change.Code), []byte(strings.TrimSpace(code))) != 1 {
+		if !s.limiter.VerificationFailed(ctx, models.VerificationPurposeEmailChange, change.NewEmail) {
+			span.RecordError(ErrInvalidEmailChangeCode)
+			return nil, ErrInvalidEmailChangeCode
+		}
+
+		if err := s.emailChanges.Delete(ctx, user.ID); err != nil {
+			span.RecordError(err)
+		}
+		err := &RateLimitError{
+			Code:    RateLimitCodeAttemptsExceeded,
+			Message: "too many wrong codes, request the email change again",
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	oldEmail := user.Email
+	if err := s.emailChanges.Apply(ctx, change); err != nil {
+		// the address was registered after the change was requested
+		if strings.Contains(err.Error(), "duplicate key value") {
+			s.emailChanges.Delete(ctx, user.ID)
+			span.RecordError(ErrEmailTaken)
+			return nil, ErrEmailTaken
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	_, kSpan := otel.Tracer("kafka").Start(ctx, "Kafka.SendEmailChangedNotice")
+	s.emailProducer.SendAsync(kafka.EmailMessage{
+		Email:   oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email of your account was changed to %s. "+
+			"This address no longer receives messages about the account.\n\n"+
+			"If this wasn't you, contact support.",
+			user.DisplayName, change.NewEmail),
+	})
+	kSpan.End()
+
+	updated, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// GetPendingEmailChange returns the unconfirmed email change, or nil.
+func (s *UserService) GetPendingEmailChange(ctx context.Context, userID string) (*models.EmailChange, error) {
+	ctx, span := otel.Tracer("user").Start(ctx, "UserService.GetPendingEmailChange")
+	defer span.End()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	change, err := s.emailChanges.FindByUser(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if change == nil || !change.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	return change, nil
+}
+
+func (s *UserService) CancelEmailChange(ctx context.Context, userID string) error {
+	ctx, span := otel.Tracer("user").Start(ctx, "UserService.CancelEmailChange")
+	defer span.End()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.emailChanges.Delete(ctx, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
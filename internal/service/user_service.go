@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"strings"
 
 	"learning-platform/internal/models"
 	"learning-platform/internal/repository"
@@ -12,12 +11,17 @@ import (
 	"go.opentelemetry.io/otel"
 )
 
+var ErrInvalidTimezone = errors.New("unknown timezone")
+
 type UserService struct {
-	users repository.IUserRepository
+	users         repository.IUserRepository
+	emailChanges  repository.IEmailChangeRepository
+	limiter       *AuthLimiter
+	emailProducer EmailSender
 }
 
-func NewUserService(users repository.IUserRepository) *UserService {
-	return &UserService{users: users}
+func NewUserService(users repository.IUserRepository, emailChanges repository.IEmailChangeRepository, limiter *AuthLimiter, emailProducer EmailSender) *UserService {
+	return &UserService{users: users, emailChanges: emailChanges, limiter: limiter, emailProducer: emailProducer}
 }
 
 func (s *UserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
@@ -46,7 +50,9 @@ func (s *UserService) FindByID(ctx context.Context, id string) (*models.User, er
 	return user, nil
 }
 
-func (s *UserService) Update(ctx context.Context, id string, email, displayName *string, avatarURL *string) (*models.User, error) {
+// Update changes the profile fields. The email is changed with
+// RequestEmailChange instead, since the new address has to be confirmed.
+func (s *UserService) Update(ctx context.Context, id string, displayName *string, avatarURL *string) (*models.User, error) {
 	ctx, span := otel.Tracer("user").Start(ctx, "UserService.Update")
 	defer span.End()
 
@@ -63,9 +69,6 @@ func (s *UserService) Update(ctx context.Context, id string, email, displayName
 
 	updates := map[string]interface{}{}
 
-	if email != nil {
-		updates["email"] = *email
-	}
 	if displayName != nil {
 		updates["displayName"] = *displayName
 	}
@@ -82,11 +85,6 @@ func (s *UserService) Update(ctx context.Context, id string, email, displayName
 	spanUpdate.End()
 
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key value") {
-			e := errors.New("email already taken")
-			span.RecordError(e)
-			return nil, e
-		}
 		span.RecordError(err)
 		return nil, err
 	}
@@ -100,6 +98,33 @@ func (s *UserService) Update(ctx context.Context, id string, email, displayName
 	return updated, nil
 }
 
+// SetTimezone sets the IANA timezone used for the user's daily streak.
+func (s *UserService) SetTimezone(ctx context.Context, id, timezone string) (*models.User, error) {
+	ctx, span := otel.Tracer("user").Start(ctx, "UserService.SetTimezone")
+	defer span.End()
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+
+	if err := s.users.Update(ctx, id, map[string]interface{}{"timezone": loc.String()}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
 func (s *UserService) BanProfile(
 	ctx context.Context,
 	id string,
@@ -2,13 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"learning-platform/internal/models"
+	"learning-platform/internal/repository"
 )
 
 func strPtr(s string) *string { return &s }
@@ -30,7 +33,7 @@ func TestUserService_GetAllUsers(t *testing.T) {
 	require.NoError(t, repo.Create(ctx, u1))
 	require.NoError(t, repo.Create(ctx, u2))
 
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil, nil, nil)
 
 	users, err := svc.GetAllUsers(ctx)
 	require.NoError(t, err)
@@ -48,7 +51,7 @@ func TestUserService_FindByID_Success(t *testing.T) {
 
 	require.NoError(t, repo.Create(ctx, u))
 
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil, nil, nil)
 
 	found, err := svc.FindByID(ctx, u.ID.String())
 	require.NoError(t, err)
@@ -59,7 +62,7 @@ func TestUserService_FindByID_Success(t *testing.T) {
 func TestUserService_FindByID_NotFound(t *testing.T) {
 	ctx := context.Background()
 	repo := newFakeUserRepo()
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil, nil, nil)
 
 	found, err := svc.FindByID(ctx, uuid.New().String())
 	require.NoError(t, err)
@@ -79,19 +82,168 @@ func TestUserService_Update_Success(t *testing.T) {
 
 	require.NoError(t, repo.Create(ctx, u))
 
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, nil, nil, nil)
 
-	newEmail := "new@mail.com"
 	newName := "New Name"
 	newAvatar := "new.png"
 
-	updated, err := svc.Update(ctx, u.ID.String(), &newEmail, &newName, &newAvatar)
+	updated, err := svc.Update(ctx, u.ID.String(), &newName, &newAvatar)
 	require.NoError(t, err)
 	require.NotNil(t, updated)
 
-	assert.Equal(t, "new@mail.com", updated.Email)
+	assert.Equal(t, "old@mail.com", updated.Email)
 	assert.Equal(t, "New Name", updated.DisplayName)
 	require.NotNil(t, updated.AvatarURL)
 	assert.Equal(t, "new.png", *updated.AvatarURL)
 
 }
+
+type fakeEmailChangeRepo struct {
+	repository.IEmailChangeRepository
+	users    *fakeUserRepo
+	byUser   map[uuid.UUID]*models.EmailChange
+	applyErr error
+}
+
+func (f *fakeEmailChangeRepo) Replace(ctx context.Context, change *models.EmailChange) error {
+	f.byUser[change.UserID] = change
+	return nil
+}
+func (f *fakeEmailChangeRepo) FindByUser(ctx context.Context, userID uuid.UUID) (*models.EmailChange, error) {
+	return f.byUser[userID], nil
+}
+func (f *fakeEmailChangeRepo) Delete(ctx context.Context, userID uuid.UUID) error {
+	delete(f.byUser, userID)
+	return nil
+}
+func (f *fakeEmailChangeRepo) Apply(ctx context.Context, change *models.EmailChange) error {
+	if f.applyErr != nil {
+		return f.applyErr
+	}
+	user := f.users.byID[change.UserID.String()]
+	delete(f.users.byEmail, user.Email)
+	user.Email = change.NewEmail
+	f.users.byEmail[user.Email] = user
+	delete(f.byUser, change.UserID)
+	return nil
+}
+
+func newEmailChangeFixture(t *testing.T) (*UserService, *fakeEmailChangeRepo, *fakeEmailSender, *models.User) {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	user := &models.User{Email: "old@mail.com", DisplayName: "Anna", Status: "ACTIVE"}
+	require.NoError(t, users.Create(context.Background(), user))
+
+	changes := &fakeEmailChangeRepo{users: users, byUser: make(map[uuid.UUID]*models.EmailChange)}
+	email := &fakeEmailSender{}
+	return NewUserService(users, changes, nil, email), changes, email, user
+}
+
+func TestUserService_EmailChange(t *testing.T) {
+	ctx := context.Background()
+	svc, changes, email, user := newEmailChangeFixture(t)
+
+	change, err := svc.RequestEmailChange(ctx, user.ID.String(), " new@mail.com ")
+	require.NoError(t, err)
+	assert.Equal(t, "new@mail.com", change.NewEmail)
+
+	// до подтверждения email не меняется
+	assert.Equal(t, "old@mail.com", user.Email)
+
+	require.Len(t, email.sent, 2)
+	assert.Equal(t, "new@mail.com", email.sent[0].Email)
+	assert.Equal(t, change.Code, email.sent[0].Code)
+	assert.Equal(t, "old@mail.com", email.sent[1].Email)
+	assert.Empty(t, email.sent[1].Code, "старый адрес получает уведомление без кода")
+	assert.Contains(t, email.sent[1].Body, "new@mail.com")
+
+	pending, err := svc.GetPendingEmailChange(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+
+	wrong := "000000"
+	if change.Code == wrong {
+		wrong = "111111"
+	}
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), wrong)
+	assert.ErrorIs(t, err, ErrInvalidEmailChangeCode)
+
+	updated, err := svc.ConfirmEmailChange(ctx, user.ID.String(), change.Code)
+	require.NoError(t, err)
+	assert.Equal(t, "new@mail.com", updated.Email)
+	assert.Empty(t, changes.byUser)
+
+	require.Len(t, email.sent, 3)
+	assert.Equal(t, "old@mail.com", email.sent[2].Email)
+
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), change.Code)
+	assert.ErrorIs(t, err, ErrNoPendingEmailChange)
+}
+
+func TestUserService_RequestEmailChange_Rejected(t *testing.T) {
+	ctx := context.Background()
+	svc, _, email, user := newEmailChangeFixture(t)
+
+	other := &models.User{Email: "taken@mail.com"}
+	require.NoError(t, svc.users.Create(ctx, other))
+
+	_, err := svc.RequestEmailChange(ctx, user.ID.String(), "OLD@mail.com")
+	assert.ErrorIs(t, err, ErrEmailUnchanged)
+
+	_, err = svc.RequestEmailChange(ctx, user.ID.String(), "taken@mail.com")
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	assert.Empty(t, email.sent)
+}
+
+func TestUserService_ConfirmEmailChange_ExpiredAndTaken(t *testing.T) {
+	ctx := context.Background()
+	svc, changes, _, user := newEmailChangeFixture(t)
+
+	change, err := svc.RequestEmailChange(ctx, user.ID.String(), "new@mail.com")
+	require.NoError(t, err)
+
+	change.ExpiresAt = time.Now().Add(-time.Second)
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), change.Code)
+	assert.ErrorIs(t, err, ErrNoPendingEmailChange)
+
+	// адрес заняли, пока ждали подтверждения
+	change.ExpiresAt = time.Now().Add(time.Minute)
+	changes.applyErr = errors.New(`ERROR: duplicate key value violates unique constraint "users_email_key"`)
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), change.Code)
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	assert.Empty(t, changes.byUser)
+	assert.Equal(t, "old@mail.com", user.Email)
+}
+
+func TestUserService_EmailChange_Limits(t *testing.T) {
+	ctx := context.Background()
+	svc, changes, _, user := newEmailChangeFixture(t)
+	svc.limiter = NewAuthLimiter(newTestRedis(t))
+
+	change, err := svc.RequestEmailChange(ctx, user.ID.String(), "new@mail.com")
+	require.NoError(t, err)
+
+	_, err = svc.RequestEmailChange(ctx, user.ID.String(), "new@mail.com")
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitCodeResendThrottled, limited.Code)
+
+	wrong := "000000"
+	if change.Code == wrong {
+		wrong = "111111"
+	}
+	for i := 1; i < MaxVerificationAttempts; i++ {
+		_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), wrong)
+		require.ErrorIs(t, err, ErrInvalidEmailChangeCode)
+	}
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), wrong)
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitCodeAttemptsExceeded, limited.Code)
+
+	// после исчерпания попыток правильный код уже не помогает
+	assert.Empty(t, changes.byUser)
+	_, err = svc.ConfirmEmailChange(ctx, user.ID.String(), change.Code)
+	assert.ErrorIs(t, err, ErrNoPendingEmailChange)
+}
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS trg_update_school_classes ON school_classes;
+
+DROP INDEX IF EXISTS idx_topics_school_class;
+
+ALTER TABLE topics
+    DROP CONSTRAINT IF EXISTS fk_topics_school_class;
+
+CREATE TYPE school_class AS ENUM ('SEVEN', 'EIGHT', 'NINE', 'TEN', 'ELEVEN');
+
+ALTER TABLE topics
+    ALTER COLUMN school_class TYPE school_class USING school_class::school_class;
+
+DROP TABLE IF EXISTS school_classes;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS school_classes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(64) NOT NULL UNIQUE,
+    title TEXT NOT NULL,
+    education_system VARCHAR(64) NOT NULL DEFAULT 'SCHOOL',
+    sort_order INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+INSERT INTO school_classes (code, title, education_system, sort_order) VALUES
+    ('SEVEN', '7th grade', 'SCHOOL', 7),
+    ('EIGHT', '8th grade', 'SCHOOL', 8),
+    ('NINE', '9th grade', 'SCHOOL', 9),
+    ('TEN', '10th grade', 'SCHOOL', 10),
+    ('ELEVEN', '11th grade', 'SCHOOL', 11)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE topics
+    ALTER COLUMN school_class TYPE VARCHAR(64) USING school_class::text;
+
+ALTER TABLE topics
+    ADD CONSTRAINT fk_topics_school_class FOREIGN KEY (school_class)
+        REFERENCES school_classes (code) ON UPDATE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_topics_school_class ON topics(school_class);
+
+DROP TYPE IF EXISTS school_class;
+
+CREATE TRIGGER trg_update_school_classes
+BEFORE UPDATE ON school_classes
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
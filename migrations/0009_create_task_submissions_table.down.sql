@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_submissions_task;
+DROP INDEX IF EXISTS idx_task_submissions_user_task;
+DROP TABLE IF EXISTS task_submissions;
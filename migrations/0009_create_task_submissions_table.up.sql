@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    answer TEXT NOT NULL,
+    is_correct BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_submissions_user_task ON task_submissions(user_id, task_id);
+CREATE INDEX idx_task_submissions_task ON task_submissions(task_id);
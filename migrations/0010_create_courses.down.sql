@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS trg_update_course_modules ON course_modules;
+DROP TRIGGER IF EXISTS trg_update_courses ON courses;
+
+DROP TABLE IF EXISTS course_item_completions;
+DROP TABLE IF EXISTS course_enrollments;
+DROP TABLE IF EXISTS course_items;
+DROP TABLE IF EXISTS course_modules;
+DROP TABLE IF EXISTS courses;
+
+DROP TYPE IF EXISTS course_item_type;
+DROP TYPE IF EXISTS course_status;
@@ -0,0 +1,79 @@
+CREATE TYPE course_status AS ENUM ('DRAFT', 'PUBLISHED', 'ARCHIVED');
+CREATE TYPE course_item_type AS ENUM ('TOPIC', 'TASK', 'READING');
+
+
+CREATE TABLE courses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title TEXT NOT NULL,
+    description_md TEXT NOT NULL DEFAULT '',
+    status course_status NOT NULL DEFAULT 'DRAFT',
+    school_class VARCHAR(64) NULL REFERENCES school_classes (code) ON UPDATE CASCADE,
+    author_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+
+CREATE TABLE course_modules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    requires_previous BOOLEAN NOT NULL DEFAULT FALSE,
+    unlock_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+
+CREATE TABLE course_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    module_id UUID NOT NULL REFERENCES course_modules (id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    item_type course_item_type NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    body_md TEXT NOT NULL DEFAULT '',
+    topic_id UUID NULL REFERENCES topics (id) ON DELETE CASCADE,
+    task_id UUID NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_course_item_ref CHECK (
+        (item_type = 'TOPIC' AND topic_id IS NOT NULL) OR
+        (item_type = 'TASK' AND task_id IS NOT NULL) OR
+        (item_type = 'READING')
+    )
+);
+
+
+CREATE TABLE course_enrollments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_course_enrollment UNIQUE (course_id, user_id)
+);
+
+
+CREATE TABLE course_item_completions (
+    item_id UUID NOT NULL REFERENCES course_items (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    completed_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (item_id, user_id)
+);
+
+
+CREATE INDEX idx_courses_author ON courses(author_id);
+CREATE INDEX idx_course_modules_course ON course_modules(course_id, position);
+CREATE INDEX idx_course_items_module ON course_items(module_id, position);
+CREATE INDEX idx_course_enrollments_user ON course_enrollments(user_id);
+
+
+CREATE TRIGGER trg_update_courses
+BEFORE UPDATE ON courses
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER trg_update_course_modules
+BEFORE UPDATE ON course_modules
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trg_update_classrooms ON classrooms;
+
+DROP TABLE IF EXISTS classroom_members;
+DROP TABLE IF EXISTS classrooms;
+
+DROP TYPE IF EXISTS classroom_member_role;
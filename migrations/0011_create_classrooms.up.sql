@@ -0,0 +1,32 @@
+CREATE TYPE classroom_member_role AS ENUM ('OWNER', 'TEACHER', 'STUDENT');
+
+
+CREATE TABLE classrooms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    school_class VARCHAR(64) NULL REFERENCES school_classes (code) ON UPDATE CASCADE,
+    join_code VARCHAR(16) NOT NULL UNIQUE,
+    owner_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+
+CREATE TABLE classroom_members (
+    classroom_id UUID NOT NULL REFERENCES classrooms (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role classroom_member_role NOT NULL DEFAULT 'STUDENT',
+    joined_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (classroom_id, user_id)
+);
+
+
+CREATE INDEX idx_classrooms_owner ON classrooms(owner_id);
+CREATE INDEX idx_classroom_members_user ON classroom_members(user_id, role);
+
+
+CREATE TRIGGER trg_update_classrooms
+BEFORE UPDATE ON classrooms
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
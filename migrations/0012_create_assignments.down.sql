@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS trg_update_assignments ON assignments;
+
+DROP INDEX IF EXISTS idx_task_submissions_assignment;
+
+ALTER TABLE task_submissions
+    DROP COLUMN IF EXISTS is_late,
+    DROP COLUMN IF EXISTS assignment_id;
+
+DROP TABLE IF EXISTS assignment_tasks;
+DROP TABLE IF EXISTS assignments;
+
+DROP TYPE IF EXISTS assignment_late_policy;
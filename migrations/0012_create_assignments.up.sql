@@ -0,0 +1,44 @@
+CREATE TYPE assignment_late_policy AS ENUM ('ACCEPT', 'PENALTY', 'REJECT');
+
+
+CREATE TABLE assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    classroom_id UUID NOT NULL REFERENCES classrooms (id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    opens_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    due_at TIMESTAMPTZ NOT NULL,
+    closes_at TIMESTAMPTZ NULL,
+    late_policy assignment_late_policy NOT NULL DEFAULT 'ACCEPT',
+    late_penalty INT NOT NULL DEFAULT 0,
+    author_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_assignment_due CHECK (due_at >= opens_at),
+    CONSTRAINT chk_assignment_closes CHECK (closes_at IS NULL OR closes_at >= due_at),
+    CONSTRAINT chk_assignment_penalty CHECK (late_penalty BETWEEN 0 AND 100)
+);
+
+
+CREATE TABLE assignment_tasks (
+    assignment_id UUID NOT NULL REFERENCES assignments (id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (assignment_id, task_id)
+);
+
+
+ALTER TABLE task_submissions
+    ADD COLUMN assignment_id UUID NULL REFERENCES assignments (id) ON DELETE SET NULL,
+    ADD COLUMN is_late BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+CREATE INDEX idx_assignments_classroom ON assignments(classroom_id, due_at);
+CREATE INDEX idx_task_submissions_assignment ON task_submissions(assignment_id, user_id) WHERE assignment_id IS NOT NULL;
+
+
+CREATE TRIGGER trg_update_assignments
+BEFORE UPDATE ON assignments
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+ALTER TABLE assignments
+    DROP CONSTRAINT IF EXISTS chk_assignment_weight,
+    DROP COLUMN IF EXISTS weight;
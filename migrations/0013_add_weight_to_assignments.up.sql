@@ -0,0 +1,3 @@
+ALTER TABLE assignments
+    ADD COLUMN weight DOUBLE PRECISION NOT NULL DEFAULT 1,
+    ADD CONSTRAINT chk_assignment_weight CHECK (weight >= 0);
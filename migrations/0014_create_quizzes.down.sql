@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS trg_update_quiz_answers ON quiz_answers;
+DROP TRIGGER IF EXISTS trg_update_quizzes ON quizzes;
+
+DROP TABLE IF EXISTS quiz_answers;
+DROP TABLE IF EXISTS quiz_sessions;
+DROP TABLE IF EXISTS quiz_tasks;
+DROP TABLE IF EXISTS quizzes;
+
+DROP TYPE IF EXISTS quiz_session_status;
+DROP TYPE IF EXISTS quiz_mode;
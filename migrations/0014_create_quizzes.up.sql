@@ -0,0 +1,72 @@
+CREATE TYPE quiz_mode AS ENUM ('FIXED', 'RANDOM');
+CREATE TYPE quiz_session_status AS ENUM ('IN_PROGRESS', 'SUBMITTED', 'EXPIRED');
+
+
+CREATE TABLE quizzes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    classroom_id UUID NOT NULL REFERENCES classrooms (id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    mode quiz_mode NOT NULL DEFAULT 'FIXED',
+    draw_count INT NOT NULL DEFAULT 0,
+    time_limit_seconds INT NOT NULL,
+    opens_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closes_at TIMESTAMPTZ NULL,
+    results_released BOOLEAN NOT NULL DEFAULT FALSE,
+    author_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_quiz_time_limit CHECK (time_limit_seconds > 0),
+    CONSTRAINT chk_quiz_draw_count CHECK (draw_count >= 0),
+    CONSTRAINT chk_quiz_closes CHECK (closes_at IS NULL OR closes_at >= opens_at)
+);
+
+
+CREATE TABLE quiz_tasks (
+    quiz_id UUID NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (quiz_id, task_id)
+);
+
+
+CREATE TABLE quiz_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quiz_id UUID NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status quiz_session_status NOT NULL DEFAULT 'IN_PROGRESS',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    submitted_at TIMESTAMPTZ NULL,
+    correct_count INT NOT NULL DEFAULT 0,
+    score DOUBLE PRECISION NOT NULL DEFAULT 0,
+
+    CONSTRAINT uq_quiz_session_user UNIQUE (quiz_id, user_id)
+);
+
+
+CREATE TABLE quiz_answers (
+    session_id UUID NOT NULL REFERENCES quiz_sessions (id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    answer TEXT NOT NULL DEFAULT '',
+    is_correct BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (session_id, task_id)
+);
+
+
+CREATE INDEX idx_quizzes_classroom ON quizzes(classroom_id, opens_at);
+CREATE INDEX idx_quiz_sessions_in_progress ON quiz_sessions(expires_at) WHERE status = 'IN_PROGRESS';
+
+
+CREATE TRIGGER trg_update_quizzes
+BEFORE UPDATE ON quizzes
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER trg_update_quiz_answers
+BEFORE UPDATE ON quiz_answers
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_update_review_schedules ON review_schedules;
+
+DROP TABLE IF EXISTS review_schedules;
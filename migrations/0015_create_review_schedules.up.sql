@@ -0,0 +1,23 @@
+CREATE TABLE review_schedules (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    repetitions INT NOT NULL DEFAULT 0,
+    interval_days INT NOT NULL DEFAULT 0,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    due_at TIMESTAMPTZ NOT NULL,
+    last_reviewed_at TIMESTAMPTZ NOT NULL,
+    lapses INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, task_id),
+    CONSTRAINT chk_review_ease CHECK (ease_factor >= 1.3)
+);
+
+
+CREATE INDEX idx_review_schedules_due ON review_schedules(user_id, due_at);
+
+
+CREATE TRIGGER trg_update_review_schedules
+BEFORE UPDATE ON review_schedules
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+CREATE TABLE topic_prerequisites (
+    topic_id UUID NOT NULL REFERENCES topics (id) ON DELETE CASCADE,
+    prerequisite_id UUID NOT NULL REFERENCES topics (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (topic_id, prerequisite_id),
+    CONSTRAINT chk_topic_prerequisite_self CHECK (topic_id <> prerequisite_id)
+);
+
+
+CREATE INDEX idx_topic_prerequisites_prerequisite ON topic_prerequisites(prerequisite_id);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trg_update_achievements ON achievements;
+
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS achievements;
+
+DROP TYPE IF EXISTS achievement_rule;
@@ -0,0 +1,39 @@
+CREATE TYPE achievement_rule AS ENUM ('SOLVED_TASKS', 'TOPIC_SOLVED_TASKS', 'STREAK_DAYS');
+
+
+CREATE TABLE achievements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(64) NOT NULL UNIQUE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    icon_url TEXT NULL,
+    rule achievement_rule NOT NULL,
+    threshold INT NOT NULL,
+    difficulty difficulty NULL,
+    topic_id UUID NULL REFERENCES topics (id) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_achievement_threshold CHECK (threshold > 0)
+);
+
+
+CREATE TABLE user_achievements (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    achievement_id UUID NOT NULL REFERENCES achievements (id) ON DELETE CASCADE,
+    unlocked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+
+CREATE TRIGGER trg_update_achievements
+BEFORE UPDATE ON achievements
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+
+INSERT INTO achievements (code, title, description, rule, threshold, difficulty) VALUES
+    ('first-extreme', 'Fearless', 'Solve your first EXTREME task', 'SOLVED_TASKS', 1, 'EXTREME'),
+    ('topic-10', 'Topic explorer', 'Solve 10 tasks in one topic', 'TOPIC_SOLVED_TASKS', 10, NULL),
+    ('streak-7', 'On fire', 'Solve tasks 7 days in a row', 'STREAK_DAYS', 7, NULL);
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_update_user_stats ON user_stats;
+
+DROP TABLE IF EXISTS streak_days;
+DROP TABLE IF EXISTS user_stats;
+
+DROP TYPE IF EXISTS streak_day_kind;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone;
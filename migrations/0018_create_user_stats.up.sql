@@ -0,0 +1,48 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+
+CREATE TYPE streak_day_kind AS ENUM ('ACTIVE', 'FROZEN');
+
+
+CREATE TABLE user_stats (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    xp BIGINT NOT NULL DEFAULT 0,
+    current_streak INT NOT NULL DEFAULT 0,
+    longest_streak INT NOT NULL DEFAULT 0,
+    last_active_date DATE NULL,
+    streak_freezes INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_user_stats_xp CHECK (xp >= 0),
+    CONSTRAINT chk_user_stats_freezes CHECK (streak_freezes >= 0)
+);
+
+
+CREATE TABLE streak_days (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    kind streak_day_kind NOT NULL,
+
+    PRIMARY KEY (user_id, day)
+);
+
+
+CREATE TRIGGER trg_update_user_stats
+BEFORE UPDATE ON user_stats
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+
+-- Backfill XP for tasks solved before XP existed; streaks start fresh.
+INSERT INTO user_stats (user_id, xp)
+SELECT s.user_id,
+       SUM(CASE t.difficulty
+               WHEN 'EASY' THEN 10
+               WHEN 'MEDIUM' THEN 20
+               WHEN 'HARD' THEN 30
+               WHEN 'EXTREME' THEN 50
+           END)
+FROM (SELECT DISTINCT user_id, task_id FROM task_submissions WHERE is_correct) s
+JOIN tasks t ON t.id = s.task_id
+GROUP BY s.user_id;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_update_daily_challenges ON daily_challenges;
+
+DROP INDEX IF EXISTS idx_task_submissions_task_created;
+
+DROP TABLE IF EXISTS daily_challenges;
@@ -0,0 +1,19 @@
+CREATE TABLE daily_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    school_class VARCHAR(64) NOT NULL REFERENCES school_classes (code) ON UPDATE CASCADE ON DELETE CASCADE,
+    day DATE NOT NULL,
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    selected_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_daily_challenges_class_day UNIQUE (school_class, day)
+);
+
+CREATE INDEX idx_daily_challenges_task ON daily_challenges(task_id);
+CREATE INDEX idx_task_submissions_task_created ON task_submissions(task_id, created_at);
+
+
+CREATE TRIGGER trg_update_daily_challenges
+BEFORE UPDATE ON daily_challenges
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_task_submissions_pending;
+
+ALTER TABLE task_submissions
+    DROP CONSTRAINT IF EXISTS chk_task_submissions_score,
+    DROP COLUMN IF EXISTS graded_at,
+    DROP COLUMN IF EXISTS graded_by,
+    DROP COLUMN IF EXISTS feedback,
+    DROP COLUMN IF EXISTS score,
+    DROP COLUMN IF EXISTS status;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS grading_mode;
+
+DROP TYPE IF EXISTS submission_status;
+DROP TYPE IF EXISTS grading_mode;
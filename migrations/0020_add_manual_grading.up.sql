@@ -0,0 +1,17 @@
+CREATE TYPE grading_mode AS ENUM ('AUTO', 'MANUAL');
+CREATE TYPE submission_status AS ENUM ('GRADED', 'PENDING_REVIEW');
+
+
+ALTER TABLE tasks
+    ADD COLUMN grading_mode grading_mode NOT NULL DEFAULT 'AUTO';
+
+ALTER TABLE task_submissions
+    ADD COLUMN status submission_status NOT NULL DEFAULT 'GRADED',
+    ADD COLUMN score INT NULL,
+    ADD COLUMN feedback TEXT NULL,
+    ADD COLUMN graded_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN graded_at TIMESTAMPTZ NULL,
+    ADD CONSTRAINT chk_task_submissions_score CHECK (score BETWEEN 0 AND 100);
+
+CREATE INDEX idx_task_submissions_pending ON task_submissions(created_at)
+    WHERE status = 'PENDING_REVIEW';
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS trg_update_task_rubrics ON task_rubrics;
+
+ALTER TABLE task_submissions
+    DROP CONSTRAINT IF EXISTS chk_task_submissions_peer_score,
+    DROP COLUMN IF EXISTS peer_score;
+
+DROP TABLE IF EXISTS peer_review_scores;
+DROP TABLE IF EXISTS peer_reviews;
+DROP TABLE IF EXISTS rubric_criteria;
+DROP TABLE IF EXISTS task_rubrics;
+
+DROP TYPE IF EXISTS peer_review_status;
@@ -0,0 +1,55 @@
+CREATE TYPE peer_review_status AS ENUM ('ASSIGNED', 'SUBMITTED');
+
+CREATE TABLE task_rubrics (
+    task_id UUID PRIMARY KEY REFERENCES tasks (id) ON DELETE CASCADE,
+    peer_reviews INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT chk_task_rubrics_peer_reviews CHECK (peer_reviews BETWEEN 0 AND 10)
+);
+
+CREATE TABLE rubric_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES task_rubrics (task_id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    max_points INT NOT NULL,
+    position INT NOT NULL,
+
+    CONSTRAINT chk_rubric_criteria_max_points CHECK (max_points > 0)
+);
+
+CREATE INDEX idx_rubric_criteria_task ON rubric_criteria(task_id, position);
+
+CREATE TABLE peer_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES task_submissions (id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status peer_review_status NOT NULL DEFAULT 'ASSIGNED',
+    comment TEXT NOT NULL DEFAULT '',
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    submitted_at TIMESTAMPTZ NULL,
+
+    CONSTRAINT uq_peer_reviews_submission_reviewer UNIQUE (submission_id, reviewer_id)
+);
+
+CREATE INDEX idx_peer_reviews_reviewer ON peer_reviews(reviewer_id, status);
+
+CREATE TABLE peer_review_scores (
+    review_id UUID NOT NULL REFERENCES peer_reviews (id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES rubric_criteria (id) ON DELETE CASCADE,
+    points INT NOT NULL,
+
+    PRIMARY KEY (review_id, criterion_id),
+    CONSTRAINT chk_peer_review_scores_points CHECK (points >= 0)
+);
+
+ALTER TABLE task_submissions
+    ADD COLUMN peer_score INT NULL,
+    ADD CONSTRAINT chk_task_submissions_peer_score CHECK (peer_score BETWEEN 0 AND 100);
+
+
+CREATE TRIGGER trg_update_task_rubrics
+BEFORE UPDATE ON task_rubrics
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
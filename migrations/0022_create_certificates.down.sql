@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS certificates;
+
+DROP TYPE IF EXISTS certificate_kind;
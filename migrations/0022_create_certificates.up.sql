@@ -0,0 +1,20 @@
+CREATE TYPE certificate_kind AS ENUM ('COURSE', 'TOPIC');
+
+
+-- subject_id and title are not foreign keys: a certificate stays valid
+-- after its course or topic is deleted.
+CREATE TABLE certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind certificate_kind NOT NULL,
+    subject_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    recipient_name TEXT NOT NULL,
+    pdf_url TEXT NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_certificates_subject UNIQUE (user_id, kind, subject_id)
+);
+
+CREATE INDEX idx_certificates_user ON certificates (user_id, issued_at DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_email_verifications_user_purpose;
+
+ALTER TABLE email_verifications
+    DROP COLUMN IF EXISTS purpose;
@@ -0,0 +1,7 @@
+-- Codes are bound to what they were sent for, so an email verification code
+-- cannot be used to reset a password and vice versa.
+ALTER TABLE email_verifications
+    ADD COLUMN IF NOT EXISTS purpose VARCHAR(32) NOT NULL DEFAULT 'VERIFY_EMAIL';
+
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user_purpose
+    ON email_verifications (user_id, purpose);
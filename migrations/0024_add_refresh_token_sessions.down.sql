@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_refresh_user_session;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS device_name,
+    DROP COLUMN IF EXISTS session_id;
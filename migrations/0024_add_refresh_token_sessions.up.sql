@@ -0,0 +1,15 @@
+-- A session is one sign-in on one device. Rotated refresh tokens keep the
+-- session id, so a session can be listed and revoked as a whole.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS session_id UUID,
+    ADD COLUMN IF NOT EXISTS device_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE refresh_tokens SET session_id = id WHERE session_id IS NULL;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN session_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_user_session ON refresh_tokens (user_id, session_id);
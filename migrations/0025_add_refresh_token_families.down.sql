@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS security_events;
+
+DROP TYPE IF EXISTS security_event_kind;
+
+DROP INDEX IF EXISTS idx_refresh_parent;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS parent_id;
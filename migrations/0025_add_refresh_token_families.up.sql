@@ -0,0 +1,22 @@
+-- Rotated refresh tokens link to the token they replaced. A session
+-- (session_id) is the token family.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS parent_id UUID NULL REFERENCES refresh_tokens (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_parent ON refresh_tokens (parent_id);
+
+
+CREATE TYPE security_event_kind AS ENUM ('REFRESH_TOKEN_REUSE');
+
+
+CREATE TABLE security_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind security_event_kind NOT NULL,
+    session_id UUID NULL,
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_security_events_user ON security_events (user_id, created_at DESC);
@@ -0,0 +1,21 @@
+-- TOTP two-factor authentication. The secret is stored when enrollment starts
+-- and only takes effect once totp_enabled_at is set by the confirmation step.
+-- totp_last_step is the last accepted time step, so a code cannot be replayed.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS totp_secret TEXT NULL,
+    ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Sessions started with a second factor. Admin routes require one.
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS two_factor BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+CREATE TABLE recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);